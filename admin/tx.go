@@ -11,6 +11,7 @@ import (
 	"github.com/bnb-chain/node/plugins/bridge"
 	"github.com/bnb-chain/node/plugins/dex/order"
 	list "github.com/bnb-chain/node/plugins/dex/types"
+	"github.com/bnb-chain/node/plugins/tokens/allowance"
 	"github.com/bnb-chain/node/plugins/tokens/burn"
 	"github.com/bnb-chain/node/plugins/tokens/freeze"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
@@ -45,6 +46,8 @@ var transferOnlyModeBlackList = []string{
 	bridge.BindMsg{}.Type(),
 	bridge.UnbindMsg{}.Type(),
 	bridge.TransferOutMsg{}.Type(),
+	allowance.ApproveMsg{}.Type(),
+	allowance.TransferFromMsg{}.Type(),
 }
 
 var TxBlackList = map[runtime.Mode][]string{
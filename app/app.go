@@ -7,6 +7,7 @@ import (
 	"io"
 	"math"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"sort"
 	"time"
@@ -41,6 +42,8 @@ import (
 	"github.com/bnb-chain/node/admin"
 	"github.com/bnb-chain/node/app/config"
 	"github.com/bnb-chain/node/app/pub"
+	"github.com/bnb-chain/node/app/pub/history"
+	"github.com/bnb-chain/node/app/pub/kline"
 	appsub "github.com/bnb-chain/node/app/pub/sub"
 	"github.com/bnb-chain/node/common"
 	"github.com/bnb-chain/node/common/runtime"
@@ -58,6 +61,7 @@ import (
 	migrate "github.com/bnb-chain/node/plugins/migrate"
 	tokenRecover "github.com/bnb-chain/node/plugins/recover"
 	"github.com/bnb-chain/node/plugins/tokens"
+	"github.com/bnb-chain/node/plugins/tokens/allowance"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
 	"github.com/bnb-chain/node/plugins/tokens/ownership"
 	"github.com/bnb-chain/node/plugins/tokens/seturi"
@@ -99,20 +103,21 @@ type BNBBeaconChain struct {
 	queryHandlers map[string]types.AbciQueryHandler
 
 	// keepers
-	CoinKeeper     bank.Keeper
-	DexKeeper      *dex.DexKeeper
-	AccountKeeper  auth.AccountKeeper
-	TokenMapper    tokens.Mapper
-	ValAddrCache   *ValAddrCache
-	stakeKeeper    stake.Keeper
-	slashKeeper    slashing.Keeper
-	govKeeper      gov.Keeper
-	timeLockKeeper timelock.Keeper
-	swapKeeper     swap.Keeper
-	oracleKeeper   oracle.Keeper
-	bridgeKeeper   bridge.Keeper
-	ibcKeeper      ibc.Keeper
-	scKeeper       sidechain.Keeper
+	CoinKeeper      bank.Keeper
+	DexKeeper       *dex.DexKeeper
+	AccountKeeper   auth.AccountKeeper
+	TokenMapper     tokens.Mapper
+	ValAddrCache    *ValAddrCache
+	stakeKeeper     stake.Keeper
+	slashKeeper     slashing.Keeper
+	govKeeper       gov.Keeper
+	timeLockKeeper  timelock.Keeper
+	allowanceKeeper allowance.Keeper
+	swapKeeper      swap.Keeper
+	oracleKeeper    oracle.Keeper
+	bridgeKeeper    bridge.Keeper
+	ibcKeeper       ibc.Keeper
+	scKeeper        sidechain.Keeper
 	// keeper to process param store and update
 	ParamHub *param.Keeper
 
@@ -198,6 +203,7 @@ func NewBNBBeaconChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseA
 		timelock.DefaultCodespace)
 
 	app.swapKeeper = swap.NewKeeper(cdc, common.AtomicSwapStoreKey, app.CoinKeeper, app.Pool, swap.DefaultCodespace)
+	app.allowanceKeeper = allowance.NewKeeper(cdc, common.AllowanceStoreKey, app.CoinKeeper, allowance.DefaultCodespace)
 	app.oracleKeeper = oracle.NewKeeper(cdc, common.OracleStoreKey, app.ParamHub.Subspace(oracle.DefaultParamSpace),
 		app.stakeKeeper, app.scKeeper, app.ibcKeeper, app.CoinKeeper, app.Pool)
 	app.bridgeKeeper = bridge.NewKeeper(cdc, common.BridgeStoreKey, app.AccountKeeper, app.TokenMapper, app.scKeeper, app.CoinKeeper,
@@ -212,16 +218,52 @@ func NewBNBBeaconChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseA
 		pub.Cfg = app.publicationConfig
 		pub.ToPublishCh = make(chan pub.BlockInfoToPublish, app.publicationConfig.PublicationChannelSize)
 		pub.ToPublishEventCh = make(chan *appsub.ToPublishEvent, app.publicationConfig.PublicationChannelSize)
+		// the missing heights are republished from the files of the local publisher, so without them
+		// the cursor is not enabled, and the msgs are published without sequences
+		if app.publicationConfig.PublishLocal {
+			cursorDB, err := dbm.NewGoLevelDB(pub.CursorDBName, ServerContext.Config.DBDir())
+			if err != nil {
+				panic(err)
+			}
+			pub.Cursor = pub.NewPublicationCursor(cursorDB, app.publicationConfig)
+		} else {
+			logger.Info("publication cursor is disabled as publishLocal is not set")
+		}
 
 		publishers := make([]pub.MarketDataPublisher, 0, 1)
+		// the publishers which the msgs missed before the restart are republished to from the local files
+		remotePublishers := make([]pub.MarketDataPublisher, 0, 1)
 		if app.publicationConfig.PublishKafka {
-			publishers = append(publishers, pub.NewKafkaMarketDataPublisher(app.Logger, ServerContext.Config.DBDir(), app.publicationConfig.StopOnKafkaFail))
+			remotePublishers = append(remotePublishers, pub.NewKafkaMarketDataPublisher(app.Logger, ServerContext.Config.DBDir(), app.publicationConfig.StopOnKafkaFail))
 		}
+		if app.publicationConfig.PublishHttp {
+			remotePublishers = append(remotePublishers, pub.NewHttpMarketDataPublisher(app.Logger, app.publicationConfig))
+		}
+		publishers = append(publishers, remotePublishers...)
 		if app.publicationConfig.PublishLocal {
 			publishers = append(publishers, pub.NewLocalMarketDataPublisher(ServerContext.Config.RootDir, app.Logger, app.publicationConfig))
+			app.republishLocalMarketData(remotePublishers)
 		}
 
-		if len(publishers) == 0 {
+		if app.publicationConfig.AggregateKline {
+			klineDB, err := dbm.NewGoLevelDB(kline.DBName, ServerContext.Config.DBDir())
+			if err != nil {
+				panic(err)
+			}
+			pub.Klines = kline.NewAggregator(klineDB)
+			app.RegisterQueryHandler(kline.AbciQueryPrefix, kline.NewAbciQueryHandler(pub.Klines))
+		}
+		if app.publicationConfig.IndexHistory {
+			historyDB, err := dbm.NewGoLevelDB(history.DBName, ServerContext.Config.DBDir())
+			if err != nil {
+				panic(err)
+			}
+			pub.History = history.NewIndex(historyDB)
+			app.RegisterQueryHandler(history.AbciQueryPrefix, history.NewAbciQueryHandler(pub.History))
+		}
+
+		// the market data can be indexed without any publisher
+		if len(publishers) == 0 && !app.publicationConfig.ShouldIndexAny() {
 			panic(fmt.Errorf("Cannot find any publisher in config, there might be some wrong configuration"))
 		} else {
 			if len(publishers) == 1 {
@@ -262,6 +304,7 @@ func NewBNBBeaconChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseA
 		common.OracleStoreKey,
 		common.IbcStoreKey,
 		common.ReconStoreKey,
+		common.AllowanceStoreKey,
 	)
 	app.SetAnteHandler(tx.NewAnteHandler(app.AccountKeeper))
 	app.SetPreChecker(tx.NewTxPreChecker())
@@ -308,6 +351,32 @@ func NewBNBBeaconChain(logger log.Logger, db dbm.DB, traceStore io.Writer, baseA
 	return app
 }
 
+// republishLocalMarketData republishes the msgs which have not been published by `publishers` before the restart,
+// read from the files of the local publisher
+func (app *BNBBeaconChain) republishLocalMarketData(publishers []pub.MarketDataPublisher) {
+	from, ok := pub.Cursor.RepublishFrom()
+	if !ok || len(publishers) == 0 {
+		return
+	}
+	files, err := pub.LocalMarketDataFiles(filepath.Join(ServerContext.Config.RootDir, "marketdata"))
+	if err != nil {
+		app.Logger.Error("failed to find the local market data to republish", "err", err)
+		return
+	}
+	var publisher pub.MarketDataPublisher = pub.NewAggregatedMarketDataPublisher(publishers...)
+	if len(publishers) == 1 {
+		publisher = publishers[0]
+	}
+	app.Logger.Info("republish the local market data", "from", from+1)
+	republished, err := pub.RepublishLocalMarketData(publisher, files)
+	if err != nil {
+		app.Logger.Error("failed to republish the local market data, it is republished again on the next restart",
+			"numOfMsgs", republished, "err", err)
+		return
+	}
+	app.Logger.Info("republished the local market data", "numOfMsgs", republished)
+}
+
 func (app *BNBBeaconChain) startPubSub(logger log.Logger) {
 	pubLogger := logger.With("module", "bnc_pubsub")
 	app.psServer = pubsub.NewServer(pubLogger)
@@ -365,6 +434,21 @@ func SetUpgradeConfig(upgradeConfig *config.UpgradeConfig) {
 	upgrade.Mgr.AddUpgradeHeight(upgrade.FirstSunset, upgradeConfig.FirstSunsetHeight)
 	upgrade.Mgr.AddUpgradeHeight(upgrade.SecondSunset, upgradeConfig.SecondSunsetHeight)
 	upgrade.Mgr.AddUpgradeHeight(upgrade.FinalSunset, upgradeConfig.FinalSunsetHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SwapIndexUpgrade, upgradeConfig.SwapIndexUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TokenAllowanceUpgrade, upgradeConfig.TokenAllowanceUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.MarketOrderUpgrade, upgradeConfig.MarketOrderUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.PostOnlyUpgrade, upgradeConfig.PostOnlyUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ConditionalOrderUpgrade, upgradeConfig.ConditionalOrderUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ReplaceOrderUpgrade, upgradeConfig.ReplaceOrderUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BatchOrderUpgrade, upgradeConfig.BatchOrderUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SelfTradePreventionUpgrade, upgradeConfig.SelfTradePreventionUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GoodTillOrderUpgrade, upgradeConfig.GoodTillOrderUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.IcebergOrderUpgrade, upgradeConfig.IcebergOrderUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ContinuousMatchingUpgrade, upgradeConfig.ContinuousMatchingUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.VestingTimeLockUpgrade, upgradeConfig.VestingTimeLockUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TimeLockTransferUpgrade, upgradeConfig.TimeLockTransferUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SwapPartialClaimUpgrade, upgradeConfig.SwapPartialClaimUpgradeHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SwapMultiAssetDepositUpgrade, upgradeConfig.SwapMultiAssetDepositUpgradeHeight)
 
 	// register store keys of upgrade
 	upgrade.Mgr.RegisterStoreKeys(upgrade.BEP9, common.TimeLockStoreKey.Name())
@@ -373,6 +457,7 @@ func SetUpgradeConfig(upgradeConfig *config.UpgradeConfig) {
 		common.SlashingStoreKey.Name(), common.BridgeStoreKey.Name(), common.OracleStoreKey.Name())
 	upgrade.Mgr.RegisterStoreKeys(upgrade.BEP128, common.StakeRewardStoreKey.Name())
 	upgrade.Mgr.RegisterStoreKeys(upgrade.BEP255, common.ReconStoreKey.Name())
+	upgrade.Mgr.RegisterStoreKeys(upgrade.TokenAllowanceUpgrade, common.AllowanceStoreKey.Name())
 
 	// register msg types of upgrade
 	upgrade.Mgr.RegisterMsgTypes(upgrade.BEP9,
@@ -443,6 +528,12 @@ func (app *BNBBeaconChain) initDex() {
 		app.publicationConfig.ShouldPublishAny())
 	app.DexKeeper.SubscribeParamChange(app.ParamHub)
 	app.DexKeeper.SetBUSDSymbol(app.dexConfig.BUSDSymbol)
+	if err := app.DexKeeper.SetOrderBookBackend(order.PairType.BEP2, app.dexConfig.BEP2OrderBook); err != nil {
+		panic(err)
+	}
+	if err := app.DexKeeper.SetOrderBookBackend(order.PairType.MINI, app.dexConfig.MiniOrderBook); err != nil {
+		panic(err)
+	}
 
 	// do not proceed if we are in a unit test and `CheckState` is unset.
 	if app.CheckState == nil {
@@ -477,7 +568,8 @@ func (app *BNBBeaconChain) initPlugins() {
 	app.initOracle()
 	app.initParamHub()
 	app.initBridge()
-	tokens.InitPlugin(app, app.TokenMapper, app.AccountKeeper, app.CoinKeeper, app.timeLockKeeper, app.swapKeeper)
+	tokens.InitPlugin(app, app.TokenMapper, app.AccountKeeper, app.CoinKeeper, app.timeLockKeeper, app.swapKeeper,
+		app.allowanceKeeper)
 	dex.InitPlugin(app, app.DexKeeper, app.TokenMapper, app.govKeeper)
 	account.InitPlugin(app, app.AccountKeeper)
 	bridge.InitPlugin(app, app.bridgeKeeper)
@@ -497,6 +589,7 @@ func (app *BNBBeaconChain) initPlugins() {
 	app.QueryRouter().AddRoute("slashing", slashing.NewQuerier(app.slashKeeper, app.Codec))
 	app.QueryRouter().AddRoute("timelock", timelock.NewQuerier(app.timeLockKeeper))
 	app.QueryRouter().AddRoute(swap.AtomicSwapRoute, swap.NewQuerier(app.swapKeeper))
+	app.QueryRouter().AddRoute(allowance.MsgRoute, allowance.NewQuerier(app.allowanceKeeper))
 	app.QueryRouter().AddRoute("param", paramHub.NewQuerier(app.ParamHub, app.Codec))
 	app.QueryRouter().AddRoute("sideChain", sidechain.NewQuerier(app.scKeeper))
 
@@ -924,6 +1017,7 @@ func (app *BNBBeaconChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock)
 			app.DexKeeper.MatchAndAllocateSymbols(ctx, nil, isBreatheBlock)
 		}
 	}
+	dex.EndBlocker(ctx, app.DexKeeper)
 
 	if isBreatheBlock {
 		// breathe block
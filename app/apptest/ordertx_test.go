@@ -1,6 +1,7 @@
 package apptest
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/types/fees"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 
+	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/common/utils"
 	o "github.com/bnb-chain/node/plugins/dex/order"
 	"github.com/bnb-chain/node/plugins/dex/types"
@@ -352,3 +354,160 @@ func Test_handleCancelOrder_CheckTx(t *testing.T) {
 	assert.Equal(int64(200e8), GetAvail(ctx, add, "BTC-000"))
 	assert.Equal(int64(0), GetLocked(ctx, add, "BTC-000"))
 }
+
+func Test_handleReplaceOrder_DeliverTx(t *testing.T) {
+	assert := assert.New(t)
+	testClient.cl.BeginBlockSync(abci.RequestBeginBlock{})
+	ctx := testApp.NewContext(sdk.RunTxModeDeliver, abci.Header{})
+	InitAccounts(ctx, testApp)
+	testApp.DexKeeper.ClearOrderBook("BTC-000_BNB")
+	tradingPair := types.NewTradingPair("BTC-000", "BNB", 1e8)
+	testApp.DexKeeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	testApp.DexKeeper.AddEngine(tradingPair)
+	testApp.DexKeeper.GetEngines()["BTC-000_BNB"].LastMatchHeight = -1
+	testApp.DexKeeper.FeeManager.UpdateConfig(newTestFeeConfig())
+
+	am := testApp.AccountKeeper
+	add := Account(0).GetAddress()
+	add2 := Account(1).GetAddress()
+	oid := genOrderID(add, 0, ctx, am)
+	oid2 := genOrderID(add2, 0, ctx, am)
+
+	newMsg := o.NewNewOrderMsg(add, oid, 1, "BTC-000_BNB", 100e8, 3e8)
+	res, e := testClient.DeliverTxSync(newMsg, testApp.Codec)
+	assert.Equal(uint32(0), res.Code)
+	assert.Nil(e)
+	newMsg = o.NewNewOrderMsg(add2, oid2, 1, "BTC-000_BNB", 100e8, 1e8)
+	res, e = testClient.DeliverTxSync(newMsg, testApp.Codec)
+	assert.Equal(uint32(0), res.Code)
+	assert.Nil(e)
+	assert.Equal(int64(200e8), GetAvail(ctx, add, "BNB"))
+	assert.Equal(int64(300e8), GetLocked(ctx, add, "BNB"))
+
+	msg := o.NewReplaceOrderMsg(add, genOrderID(add, 1, ctx, am), "BTC-000_BNB", oid, 100e8, 2e8)
+	res, e = testClient.DeliverTxSync(msg, testApp.Codec)
+	assert.Equal(uint32(sdk.ErrMsgNotSupported("").ABCICode()), res.Code)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ReplaceOrderUpgrade, -1)
+	defer upgrade.Mgr.AddUpgradeHeight(upgrade.ReplaceOrderUpgrade, 0)
+
+	msg = o.NewReplaceOrderMsg(add2, genOrderID(add2, 1, ctx, am), "BTC-000_BNB", oid, 100e8, 1e8)
+	res, e = testClient.DeliverTxSync(msg, testApp.Codec)
+	assert.Regexp(".*does not belong to transaction sender.*", res.GetLog())
+
+	// same price and less qty, the order is amended in place and keeps its priority, free of charge
+	msg = o.NewReplaceOrderMsg(add, genOrderID(add, 1, ctx, am), "BTC-000_BNB", oid, 100e8, 2e8)
+	res, e = testClient.DeliverTxSync(msg, testApp.Codec)
+	assert.Equal(uint32(0), res.Code)
+	assert.Nil(e)
+	assert.Contains(string(res.Data), oid)
+	assert.Equal(int64(300e8), GetAvail(ctx, add, "BNB"))
+	assert.Equal(int64(200e8), GetLocked(ctx, add, "BNB"))
+	pl := testApp.DexKeeper.GetPriceLevel("BTC-000_BNB", o.Side.BUY, 100e8)
+	assert.Equal(2, len(pl.Orders))
+	assert.Equal(oid, pl.Orders[0].Id)
+	assert.Equal(int64(2e8), pl.Orders[0].Qty)
+	orderInfo, ok := testApp.DexKeeper.OrderExists("BTC-000_BNB", oid)
+	assert.True(ok)
+	assert.Equal(int64(2e8), orderInfo.Quantity)
+
+	// a new price, the order is canceled with the cancel fee and a new order is placed
+	newOid := genOrderID(add, 2, ctx, am)
+	msg = o.NewReplaceOrderMsg(add, newOid, "BTC-000_BNB", oid, 90e8, 3e8)
+	res, e = testClient.DeliverTxSync(msg, testApp.Codec)
+	assert.Equal(uint32(0), res.Code)
+	assert.Nil(e)
+	assert.Contains(string(res.Data), newOid)
+	assert.Equal(int64(230e8-2e4), GetAvail(ctx, add, "BNB"))
+	assert.Equal(int64(270e8), GetLocked(ctx, add, "BNB"))
+	_, ok = testApp.DexKeeper.OrderExists("BTC-000_BNB", oid)
+	assert.False(ok)
+	orderInfo, ok = testApp.DexKeeper.OrderExists("BTC-000_BNB", newOid)
+	assert.True(ok)
+	assert.Equal(int64(90e8), orderInfo.Price)
+	assert.Equal(int64(3e8), orderInfo.Quantity)
+
+	// the new order can not be placed, the replaced order is left untouched
+	msg = o.NewReplaceOrderMsg(add, genOrderID(add, 3, ctx, am), "BTC-000_BNB", newOid, 90e8, 10e8)
+	res, e = testClient.DeliverTxSync(msg, testApp.Codec)
+	assert.Regexp(".*do not have enough token to lock.*", res.GetLog())
+	_, ok = testApp.DexKeeper.OrderExists("BTC-000_BNB", newOid)
+	assert.True(ok)
+	assert.Equal(int64(230e8-2e4), GetAvail(ctx, add, "BNB"))
+	assert.Equal(int64(270e8), GetLocked(ctx, add, "BNB"))
+	fees.Pool.Clear()
+}
+
+func Test_handleBatchOrder_DeliverTx(t *testing.T) {
+	assert := assert.New(t)
+	testClient.cl.BeginBlockSync(abci.RequestBeginBlock{})
+	ctx := testApp.NewContext(sdk.RunTxModeDeliver, abci.Header{})
+	InitAccounts(ctx, testApp)
+	testApp.DexKeeper.ClearOrderBook("BTC-000_BNB")
+	tradingPair := types.NewTradingPair("BTC-000", "BNB", 1e8)
+	testApp.DexKeeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	testApp.DexKeeper.AddEngine(tradingPair)
+	testApp.DexKeeper.GetEngines()["BTC-000_BNB"].LastMatchHeight = -1
+	testApp.DexKeeper.FeeManager.UpdateConfig(newTestFeeConfig())
+
+	am := testApp.AccountKeeper
+	add := Account(0).GetAddress()
+	add2 := Account(1).GetAddress()
+	buy := func(seq int64, index int, price, qty int64) o.BatchOrder {
+		return o.BatchOrder{o.GenerateBatchOrderID(seq, index, add), "BTC-000_BNB", o.OrderType.LIMIT, o.Side.BUY, price, qty, o.TimeInForce.GTE, 0, 0, 0, 0, 0}
+	}
+
+	genOrderID(add, 0, ctx, am)
+	msg := o.NewBatchNewOrderMsg(add, []o.BatchOrder{buy(0, 0, 100e8, 2e8)})
+	res, _ := testClient.DeliverTxSync(msg, testApp.Codec)
+	assert.Equal(uint32(sdk.ErrMsgNotSupported("").ABCICode()), res.Code)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BatchOrderUpgrade, -1)
+	defer upgrade.Mgr.AddUpgradeHeight(upgrade.BatchOrderUpgrade, 0)
+
+	// the balance is locked cumulatively, the 3rd order can not be placed and the whole batch fails
+	msg = o.NewBatchNewOrderMsg(add, []o.BatchOrder{buy(0, 0, 100e8, 2e8), buy(0, 1, 100e8, 2e8), buy(0, 2, 100e8, 2e8)})
+	res, _ = testClient.DeliverTxSync(msg, testApp.Codec)
+	assert.Regexp(".*do not have enough token to lock.*", res.GetLog())
+	_, ok := testApp.DexKeeper.OrderExists("BTC-000_BNB", o.GenerateBatchOrderID(0, 0, add))
+	assert.False(ok)
+	assert.Equal(int64(500e8), GetAvail(ctx, add, "BNB"))
+	assert.Equal(int64(0), GetLocked(ctx, add, "BNB"))
+
+	// the 2nd order can not be inserted into the book, the 1st one is not inserted either
+	testApp.DexKeeper.PairMapper.AddTradingPair(ctx, types.NewTradingPair("ZZZ-000", "BNB", 1e8))
+	zzzBuy := buy(0, 1, 100e8, 1e8)
+	zzzBuy.Symbol = "ZZZ-000_BNB"
+	msg = o.NewBatchNewOrderMsg(add, []o.BatchOrder{buy(0, 0, 100e8, 1e8), zzzBuy})
+	res, _ = testClient.DeliverTxSync(msg, testApp.Codec)
+	assert.Regexp(".*match engine of symbol ZZZ-000_BNB doesn't exist.*", res.GetLog())
+	_, ok = testApp.DexKeeper.OrderExists("BTC-000_BNB", o.GenerateBatchOrderID(0, 0, add))
+	assert.False(ok)
+
+	genOrderID(add, 1, ctx, am)
+	msg = o.NewBatchNewOrderMsg(add, []o.BatchOrder{buy(1, 0, 100e8, 2e8), buy(1, 1, 90e8, 1e8)})
+	res, e := testClient.DeliverTxSync(msg, testApp.Codec)
+	assert.Equal(uint32(0), res.Code)
+	assert.Nil(e)
+	var response o.BatchNewOrderResponse
+	assert.Nil(json.Unmarshal(res.Data, &response))
+	assert.Equal([]string{o.GenerateBatchOrderID(1, 0, add), o.GenerateBatchOrderID(1, 1, add)}, response.OrderIDs)
+	assert.Equal(int64(210e8), GetAvail(ctx, add, "BNB"))
+	assert.Equal(int64(290e8), GetLocked(ctx, add, "BNB"))
+	buys, _, _ := getOrderBook("BTC-000_BNB")
+	assert.Equal(2, len(buys))
+
+	cancels := []o.BatchCancel{{"BTC-000_BNB", response.OrderIDs[0]}, {"BTC-000_BNB", response.OrderIDs[1]}}
+	cancelMsg := o.NewBatchCancelOrderMsg(add2, cancels)
+	res, _ = testClient.DeliverTxSync(cancelMsg, testApp.Codec)
+	assert.Regexp(".*does not belong to transaction sender.*", res.GetLog())
+
+	// each canceled order is charged the cancel fee
+	cancelMsg = o.NewBatchCancelOrderMsg(add, cancels)
+	res, e = testClient.DeliverTxSync(cancelMsg, testApp.Codec)
+	assert.Equal(uint32(0), res.Code)
+	assert.Nil(e)
+	assert.Equal(int64(500e8-4e4), GetAvail(ctx, add, "BNB"))
+	assert.Equal(int64(0), GetLocked(ctx, add, "BNB"))
+	buys, _, _ = getOrderBook("BTC-000_BNB")
+	assert.Equal(0, len(buys))
+	fees.Pool.Clear()
+}
@@ -150,6 +150,20 @@ func (app *BNBBeaconChain) processErrAbciResponseForPub(txBytes []byte) {
 				// The error on deliver should be rare and only impact witness publisher's performance
 				// OrderInfo must has been in keeper.orderInfosForPub
 				app.DexKeeper.UpdateOrderChangeSync(order.OrderChange{Id: msg.RefId, Tpe: order.FailedBlocking, MsgForFailedTx: msg}, msg.Symbol)
+			case order.ReplaceOrderMsg:
+				app.Logger.Info("failed to process ReplaceOrderMsg", "oid", msg.RefId)
+				// the replaced order is left untouched, OrderInfo must has been in keeper.orderInfosForPub
+				app.DexKeeper.UpdateOrderChangeSync(order.OrderChange{Id: msg.RefId, Tpe: order.FailedBlocking, MsgForFailedTx: msg}, msg.Symbol)
+			case order.BatchNewOrderMsg:
+				app.Logger.Info("failed to process BatchNewOrderMsg", "orders", len(msg.Orders))
+				for _, orderMsg := range msg.NewOrderMsgs() {
+					app.DexKeeper.UpdateOrderChangeSync(order.OrderChange{Id: orderMsg.Id, Tpe: order.FailedBlocking, MsgForFailedTx: orderMsg}, orderMsg.Symbol)
+				}
+			case order.BatchCancelOrderMsg:
+				app.Logger.Info("failed to process BatchCancelOrderMsg", "cancels", len(msg.Cancels))
+				for _, cancelMsg := range msg.CancelOrderMsgs() {
+					app.DexKeeper.UpdateOrderChangeSync(order.OrderChange{Id: cancelMsg.RefId, Tpe: order.FailedBlocking, MsgForFailedTx: cancelMsg}, cancelMsg.Symbol)
+				}
 			default:
 				// deliberately do nothing for message other than NewOrderMsg
 				// in future, we may publish fail status of send msg
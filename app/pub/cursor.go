@@ -0,0 +1,200 @@
+package pub
+
+import (
+	"encoding/binary"
+	"sync"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/app/config"
+)
+
+const (
+	CursorDBName = "publication"
+
+	cursorHeightPrefix    = "height/"
+	cursorSeqPrefix       = "seq/"
+	cursorHeightSeqPrefix = "heightseq/"
+)
+
+var (
+	// the topics published by Publish and PublishEvent, their cursors advance once a height is processed by the loop
+	blockMsgTypes = []msgType{executionResultTpe, accountsTpe, booksTpe, blockFeeTpe, transferTpe, blockTpe, sideProposalType}
+	eventMsgTypes = []msgType{stakingTpe, distributionTpe, slashingTpe, crossTransferTpe, mirrorTpe, breatheBlockTpe}
+)
+
+// PublicationCursor durably records, per topic, the last height published without gaps and the sequence of the
+// last message. The message of each height of a topic is assigned the next sequence once, so that the consumers
+// can detect the missing messages. Once a message fails to publish, the height of its topic stops advancing, and
+// the heights after it are republished from the files of the local publisher on restart (see RepublishLocalMarketData).
+// The market data of a height can't be derived from the block store without executing the block again, so the
+// cursor is only enabled along with the local publisher (publishLocal), whose files must be kept (localMaxAge)
+// long enough to cover the outages of the other publishers.
+type PublicationCursor struct {
+	db      dbm.DB
+	enabled map[msgType]bool
+
+	mtx     sync.Mutex
+	heights map[msgType]int64
+	seqs    map[msgType]int64
+	failed  map[msgType]bool
+	pending map[msgType][]int64 // the first heights of the batches of the topic waiting to be published, in order
+}
+
+func NewPublicationCursor(db dbm.DB, cfg *config.PublicationConfig) *PublicationCursor {
+	cursor := &PublicationCursor{
+		db:      db,
+		enabled: EnabledMsgTypes(cfg),
+		heights: make(map[msgType]int64),
+		seqs:    make(map[msgType]int64),
+		failed:  make(map[msgType]bool),
+		pending: make(map[msgType][]int64),
+	}
+	for _, tpe := range append(append([]msgType{}, blockMsgTypes...), eventMsgTypes...) {
+		if bz := db.Get(cursorKey(cursorHeightPrefix, tpe)); bz != nil {
+			cursor.heights[tpe] = int64(binary.BigEndian.Uint64(bz))
+		}
+		if bz := db.Get(cursorKey(cursorSeqPrefix, tpe)); bz != nil {
+			cursor.seqs[tpe] = int64(binary.BigEndian.Uint64(bz))
+		}
+	}
+	return cursor
+}
+
+// EnabledMsgTypes returns the topics which are published according to the config
+func EnabledMsgTypes(cfg *config.PublicationConfig) map[msgType]bool {
+	return map[msgType]bool{
+		executionResultTpe: cfg.PublishOrderUpdates,
+		accountsTpe:        cfg.PublishAccountBalance,
+		booksTpe:           cfg.PublishOrderBook,
+		blockFeeTpe:        cfg.PublishBlockFee,
+		transferTpe:        cfg.PublishTransfer,
+		blockTpe:           cfg.PublishBlock,
+		sideProposalType:   cfg.PublishSideProposal,
+		stakingTpe:         cfg.PublishStaking,
+		distributionTpe:    cfg.PublishDistributeReward,
+		slashingTpe:        cfg.PublishSlashing,
+		crossTransferTpe:   cfg.PublishCrossTransfer,
+		mirrorTpe:          cfg.PublishMirror,
+		breatheBlockTpe:    cfg.PublishBreatheBlock,
+	}
+}
+
+func cursorKey(prefix string, tpe msgType) []byte {
+	return []byte(prefix + tpe.String())
+}
+
+func cursorHeightSeqKey(tpe msgType, height int64) []byte {
+	return append([]byte(cursorHeightSeqPrefix+tpe.String()+"/"), cursorValue(height)...)
+}
+
+func cursorValue(v int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(v))
+	return bz
+}
+
+// Height returns the last height of the topic published without gaps, 0 if nothing is recorded
+func (c *PublicationCursor) Height(tpe msgType) int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.heights[tpe]
+}
+
+// Seq returns the sequence of the last message of the topic
+func (c *PublicationCursor) Seq(tpe msgType) int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.seqs[tpe]
+}
+
+// RepublishFrom returns the lowest height recorded among the enabled topics, the heights after which have to be
+// republished. It returns false if none of the enabled topics has been published, i.e. on the first start.
+func (c *PublicationCursor) RepublishFrom() (height int64, ok bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for tpe, enabled := range c.enabled {
+		if h, recorded := c.heights[tpe]; enabled && recorded && (!ok || h < height) {
+			height, ok = h, true
+		}
+	}
+	return
+}
+
+// next returns the sequence of the message of the topic at the height. The sequence is assigned once per height and
+// reused if the height is published again before it is recorded, i.e. when tendermint replays the blocks on
+// handshake. It returns false if the height has been published.
+func (c *PublicationCursor) next(tpe msgType, height int64) (int64, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if height <= c.heights[tpe] {
+		return 0, false
+	}
+	key := cursorHeightSeqKey(tpe, height)
+	if bz := c.db.Get(key); bz != nil {
+		return int64(binary.BigEndian.Uint64(bz)), true
+	}
+	c.seqs[tpe]++
+	batch := c.db.NewBatch()
+	defer batch.Close()
+	batch.Set(cursorKey(cursorSeqPrefix, tpe), cursorValue(c.seqs[tpe]))
+	batch.Set(key, cursorValue(c.seqs[tpe]))
+	batch.Write()
+	return c.seqs[tpe], true
+}
+
+// fail stops the height of the topic from advancing, until the heights are republished on restart
+func (c *PublicationCursor) fail(tpe msgType) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.failed[tpe] = true
+}
+
+// hold stops the height of the topic from advancing to the height until the batch started at it is released,
+// for the publishers which accept the msgs before they are published
+func (c *PublicationCursor) hold(tpe msgType, height int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.pending[tpe] = append(c.pending[tpe], height)
+}
+
+// release ends the hold of the earliest batch of the topic once it is published, or fails the topic with `err`
+func (c *PublicationCursor) release(tpe msgType, err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if pending := c.pending[tpe]; len(pending) != 0 {
+		c.pending[tpe] = pending[1:]
+	}
+	if err != nil {
+		c.failed[tpe] = true
+	}
+}
+
+// advance records the height as published for the enabled topics of `tpes` which have not failed, short of the
+// heights held by the pending batches. The sequences assigned to the recorded heights are no longer needed.
+func (c *PublicationCursor) advance(height int64, tpes []msgType) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	batch := c.db.NewBatch()
+	defer batch.Close()
+	for _, tpe := range tpes {
+		published := height
+		if pending := c.pending[tpe]; len(pending) != 0 && pending[0] <= published {
+			published = pending[0] - 1
+		}
+		if c.enabled[tpe] && !c.failed[tpe] && published > c.heights[tpe] {
+			c.deleteHeightSeqs(batch, tpe, published)
+			c.heights[tpe] = published
+			batch.Set(cursorKey(cursorHeightPrefix, tpe), cursorValue(published))
+		}
+	}
+	batch.Write()
+}
+
+func (c *PublicationCursor) deleteHeightSeqs(batch dbm.Batch, tpe msgType, toHeight int64) {
+	iter := c.db.Iterator(cursorHeightSeqKey(tpe, 0), cursorHeightSeqKey(tpe, toHeight+1))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		batch.Delete(iter.Key())
+	}
+}
@@ -0,0 +1,96 @@
+package pub
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/app/config"
+)
+
+type failingPublisher struct {
+	MockMarketDataPublisher
+	fail map[int64]bool
+	seqs []int64
+}
+
+func (publisher *failingPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64, seq int64) error {
+	if publisher.fail[height] {
+		return errors.New("failed")
+	}
+	publisher.seqs = append(publisher.seqs, seq)
+	return publisher.MockMarketDataPublisher.publish(msg, tpe, height, timestamp, seq)
+}
+
+func TestPublicationCursor(t *testing.T) {
+	db := dbm.NewMemDB()
+	cfg := &config.PublicationConfig{PublishBlockFee: true, PublishBlock: true}
+	Cursor = NewPublicationCursor(db, cfg)
+	defer func() { Cursor = nil }()
+	_, ok := Cursor.RepublishFrom()
+	require.False(t, ok)
+
+	publisher := &failingPublisher{MockMarketDataPublisher: *NewMockMarketDataPublisher(), fail: map[int64]bool{3: true}}
+	for height := int64(1); height <= 4; height++ {
+		publishBlockFee(publisher, height, height, BlockFee{Height: height})
+		Cursor.advance(height, blockMsgTypes)
+	}
+	// the sequence of the failed height is skipped, and the cursor stops before it
+	require.Equal(t, []int64{1, 2, 4}, publisher.seqs)
+	require.Equal(t, int64(2), Cursor.Height(blockFeeTpe))
+	require.Equal(t, int64(4), Cursor.Seq(blockFeeTpe))
+	require.Equal(t, int64(4), Cursor.Height(blockTpe))
+	require.Equal(t, int64(0), Cursor.Height(booksTpe))
+	height, ok := Cursor.RepublishFrom()
+	require.True(t, ok)
+	require.Equal(t, int64(2), height)
+
+	// restart
+	Cursor = NewPublicationCursor(db, cfg)
+	require.Equal(t, int64(2), Cursor.Height(blockFeeTpe))
+	require.Equal(t, int64(4), Cursor.Seq(blockFeeTpe))
+	publisher = &failingPublisher{MockMarketDataPublisher: *NewMockMarketDataPublisher()}
+	for height := int64(2); height <= 5; height++ {
+		publishBlockFee(publisher, height, height, BlockFee{Height: height})
+		publishBlock(publisher, height, height, &Block{})
+		Cursor.advance(height, blockMsgTypes)
+	}
+	// the published heights are skipped, the replayed ones reuse their sequences
+	require.Equal(t, []int64{3, 4, 5, 1}, publisher.seqs)
+	require.Equal(t, int64(5), Cursor.Seq(blockFeeTpe))
+	require.False(t, db.Has(cursorHeightSeqKey(blockFeeTpe, 3)))
+	require.Len(t, publisher.BlockFeePublished, 3)
+	require.Equal(t, int64(3), publisher.BlockFeePublished[0].Height)
+	require.Len(t, publisher.BlockPublished, 1)
+	require.Equal(t, int64(5), Cursor.Height(blockFeeTpe))
+	require.Equal(t, int64(5), Cursor.Height(blockTpe))
+
+	// a newly enabled topic has nothing to republish
+	cfg.PublishTransfer = true
+	Cursor = NewPublicationCursor(db, cfg)
+	height, ok = Cursor.RepublishFrom()
+	require.True(t, ok)
+	require.Equal(t, int64(5), height)
+}
+
+func TestWithSequence(t *testing.T) {
+	require.Equal(t, `{"Height":1}`, string(withSequence([]byte(`{"Height":1}`), 0)))
+	require.Equal(t, `{"Sequence":3,"Height":1}`, string(withSequence([]byte(`{"Height":1}`), 3)))
+	require.Equal(t, `{"Sequence":3}`, string(withSequence([]byte(`{}`), 3)))
+	require.Equal(t, `{"Sequence":3,"MsgType":"Books","BlockTime":100,"Height":1}`,
+		string(withSequence(withMsgType([]byte(`{"Height":1}`), booksTpe, 100), 3)))
+
+	var fee struct {
+		Sequence int64
+		Height   int64
+	}
+	bz, err := json.Marshal(BlockFee{Height: 2})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(withSequence(bz, 5), &fee))
+	require.Equal(t, int64(5), fee.Sequence)
+	require.Equal(t, int64(2), fee.Height)
+}
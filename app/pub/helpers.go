@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 
 	"github.com/bnb-chain/node/common/types"
 	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/tokens/allowance"
 	"github.com/bnb-chain/node/plugins/tokens/burn"
 	"github.com/bnb-chain/node/plugins/tokens/freeze"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
@@ -113,6 +115,31 @@ func GetBlockPublished(pool *sdk.Pool, header abci.Header, blockHash []byte) *Bl
 		case orderPkg.CancelOrderMsg:
 			orderId = msg.RefId
 			txAsset = msg.Symbol
+		case orderPkg.ReplaceOrderMsg:
+			var orderRes orderPkg.NewOrderResponse
+			err = json.Unmarshal([]byte(txRes.Data), &orderRes)
+			if err != nil {
+				Logger.Error("failed to get order id", "err", err)
+				return true
+			}
+			orderId = orderRes.OrderID
+			txAsset = msg.Symbol
+		case orderPkg.BatchNewOrderMsg:
+			var orderRes orderPkg.BatchNewOrderResponse
+			err = json.Unmarshal([]byte(txRes.Data), &orderRes)
+			if err != nil {
+				Logger.Error("failed to get order ids", "err", err)
+				return true
+			}
+			orderId = strings.Join(orderRes.OrderIDs, ",")
+			txAsset = msg.Orders[0].Symbol
+		case orderPkg.BatchCancelOrderMsg:
+			refIds := make([]string, len(msg.Cancels))
+			for i, cancel := range msg.Cancels {
+				refIds[i] = cancel.RefId
+			}
+			orderId = strings.Join(refIds, ",")
+			txAsset = msg.Cancels[0].Symbol
 		case bank.MsgSend:
 			// TODO for now there is no requirement to support multi send message, will support multi send in issue #680
 			txAsset = msg.Inputs[0].Coins[0].Denom
@@ -196,8 +223,14 @@ func GetTransferPublished(pool *sdk.Pool, height, blockTime int64) *Transfers {
 
 		msgs := stdTx.GetMsgs()
 		for _, m := range msgs {
-			msg, ok := m.(bank.MsgSend)
-			if !ok {
+			var msg bank.MsgSend
+			switch m := m.(type) {
+			case bank.MsgSend:
+				msg = m
+			case allowance.TransferFromMsg:
+				// a delegated transfer is published as a transfer from the owner
+				msg = bank.NewMsgSend([]bank.Input{bank.NewInput(m.Owner, m.Amount)}, []bank.Output{bank.NewOutput(m.To, m.Amount)})
+			default:
 				continue
 			}
 			receivers := make([]Receiver, 0, len(msg.Outputs))
@@ -361,6 +394,23 @@ func ExpireOrdersForPublish(
 	wg.Wait()
 }
 
+// ExpireGoodTillOrdersForPublish expires the good-till orders reaching their expiry at the block,
+// and publishes them as Expired along with the expire fees
+func ExpireGoodTillOrdersForPublish(dexKeeper *orderPkg.DexKeeper, ctx sdk.Context) {
+	expireHolderCh := make(chan orderPkg.ExpireHolder, TransferCollectionChannelSize)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go updateExpireFeeForPublish(dexKeeper, &wg, expireHolderCh)
+	var collectorForExpires = func(tran orderPkg.Transfer) {
+		if tran.IsExpire() {
+			expireHolderCh <- orderPkg.ExpireHolder{OrderId: tran.Oid, Reason: orderPkg.Expired, Fee: tran.Fee.String(), Symbol: tran.Symbol}
+		}
+	}
+	dexKeeper.ExpireGoodTillOrders(ctx, collectorForExpires)
+	close(expireHolderCh)
+	wg.Wait()
+}
+
 func DelistTradingPairForPublish(ctx sdk.Context, dexKeeper *orderPkg.DexKeeper, symbol string) {
 	expireHolderCh := make(chan orderPkg.ExpireHolder, TransferCollectionChannelSize)
 	wg := sync.WaitGroup{}
@@ -432,6 +482,13 @@ func filterChangedOrderBooksByOrders(
 	var buyQtyDiff = make(map[string]map[int64]int64)
 	var sellQtyDiff = make(map[string]map[int64]int64)
 	var allSymbols = make(map[string]struct{})
+	// price levels touched by amended orders, whose qty diff is unknown
+	type levelKey struct {
+		symbol string
+		side   int8
+		price  int64
+	}
+	var amendedLevels = make(map[levelKey]struct{})
 	for _, o := range ordersToPublish {
 		price := o.Price
 		symbol := o.Symbol
@@ -440,6 +497,9 @@ func filterChangedOrderBooksByOrders(
 			continue
 		}
 		allSymbols[symbol] = struct{}{}
+		if o.Status == orderPkg.Amended {
+			amendedLevels[levelKey{symbol, o.Side, price}] = struct{}{}
+		}
 		if _, ok := res[symbol]; !ok {
 			res[symbol] = orderPkg.ChangedPriceLevelsPerSymbol{Buys: make(map[int64]int64), Sells: make(map[int64]int64)}
 			buyQtyDiff[symbol] = make(map[int64]int64)
@@ -467,14 +527,14 @@ func filterChangedOrderBooksByOrders(
 	// filter touched but qty actually not changed price levels
 	for symbol, priceToQty := range buyQtyDiff {
 		for price, qty := range priceToQty {
-			if qty == 0 {
+			if _, amended := amendedLevels[levelKey{symbol, orderPkg.Side.BUY, price}]; qty == 0 && !amended {
 				delete(res[symbol].Buys, price)
 			}
 		}
 	}
 	for symbol, priceToQty := range sellQtyDiff {
 		for price, qty := range priceToQty {
-			if qty == 0 {
+			if _, amended := amendedLevels[levelKey{symbol, orderPkg.Side.SELL, price}]; qty == 0 && !amended {
 				delete(res[symbol].Sells, price)
 			}
 		}
@@ -504,7 +564,7 @@ func tradeToOrder(t *Trade, o *orderPkg.OrderInfo, timestamp int64, feeHolder or
 		t.Id,
 		owner.String(),
 		o.Side,
-		orderPkg.OrderType.LIMIT,
+		o.OrderType,
 		o.Price,
 		o.Quantity,
 		t.Price,
@@ -617,7 +677,7 @@ func collectOrders(orderChanges orderPkg.OrderChanges, orderInfos orderPkg.Order
 			orderToPublish := Order{
 				orderInfo.Symbol, o.Tpe, o.Id,
 				"", orderInfo.Sender.String(), orderInfo.Side,
-				orderPkg.OrderType.LIMIT, orderInfo.Price, orderInfo.Quantity,
+				orderInfo.OrderType, orderInfo.Price, orderInfo.Quantity,
 				0, 0, orderInfo.CumQty, "",
 				orderInfo.CreatedTimestamp, timestamp, orderInfo.TimeInForce,
 				orderPkg.NEW, orderInfo.TxHash, o.SingleFee,
@@ -0,0 +1,51 @@
+package pub
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/testutils"
+	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/tokens/allowance"
+)
+
+func TestFilterChangedOrderBooksByOrders_Triggered(t *testing.T) {
+	symbol := "XYZ-000_BNB"
+	latest := orderPkg.ChangedPriceLevelsMap{
+		symbol: {Buys: map[int64]int64{1e8: 3e8}, Sells: map[int64]int64{}},
+	}
+	ack := &Order{Symbol: symbol, Status: orderPkg.Ack, OrderId: "1", Side: orderPkg.Side.BUY,
+		OrderType: orderPkg.OrderType.STOPLIMIT, Price: 1e8, Qty: 3e8}
+
+	// an untriggered conditional order does not touch the book
+	changed := filterChangedOrderBooksByOrders([]*Order{ack}, latest)
+	require.Len(t, changed, 0)
+
+	// the triggered order adds its quantity to the price level
+	triggered := *ack
+	triggered.Status = orderPkg.Triggered
+	changed = filterChangedOrderBooksByOrders([]*Order{&triggered}, latest)
+	require.Equal(t, map[int64]int64{1e8: 3e8}, changed[symbol].Buys)
+	require.Len(t, changed[symbol].Sells, 0)
+
+	// placed and triggered in the same block
+	changed = filterChangedOrderBooksByOrders([]*Order{ack, &triggered}, latest)
+	require.Equal(t, map[int64]int64{1e8: 3e8}, changed[symbol].Buys)
+}
+
+func TestGetTransferPublished_TransferFrom(t *testing.T) {
+	_, spender := testutils.PrivAndAddr()
+	_, owner := testutils.PrivAndAddr()
+	_, to := testutils.PrivAndAddr()
+	msg := allowance.NewTransferFromMsg(spender, owner, to, sdk.Coins{sdk.NewCoin("BNB", 1e8)})
+	pool := &sdk.Pool{}
+	pool.AddTx(auth.StdTx{Msgs: []sdk.Msg{msg}, Memo: "123"}, "hash")
+
+	transfers := GetTransferPublished(pool, 10, 1000)
+	require.Equal(t, 1, transfers.Num)
+	require.Equal(t, Transfer{TxHash: "hash", Memo: "123", From: owner.String(),
+		To: []Receiver{{Addr: to.String(), Coins: []Coin{{"BNB", 1e8}}}}}, transfers.Transfers[0])
+}
@@ -0,0 +1,90 @@
+package history
+
+import (
+	"fmt"
+	"strconv"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	app "github.com/bnb-chain/node/common/types"
+)
+
+const AbciQueryPrefix = "history"
+
+func NewAbciQueryHandler(index *Index) app.AbciQueryHandler {
+	return func(app app.ChainApp, req abci.RequestQuery, path []string) (res *abci.ResponseQuery) {
+		// expects at least two query path segments.
+		if path[0] != AbciQueryPrefix || len(path) < 2 {
+			return nil
+		}
+		switch path[1] {
+		case "closedorders", "trades": // args: ["history", "closedorders" or "trades", <bech32Str>, <offset>, <limit>, <symbol>?]
+			if len(path) < 5 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log: fmt.Sprintf(
+						"%s %s query requires the address, offset and limit in the path",
+						AbciQueryPrefix, path[1]),
+				}
+			}
+			address := path[2]
+			if _, err := sdk.AccAddressFromBech32(address); err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "address is not valid",
+				}
+			}
+			offset, err := strconv.Atoi(path[3])
+			if err != nil || offset < 0 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "unable to parse offset",
+				}
+			}
+			limit, err := strconv.Atoi(path[4])
+			if err != nil || limit < 0 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "unable to parse limit",
+				}
+			}
+			var symbol string
+			if len(path) > 5 {
+				symbol = path[5]
+			}
+
+			var result interface{}
+			if path[1] == "closedorders" {
+				result, err = index.ClosedOrders(address, symbol, offset, limit)
+			} else {
+				result, err = index.Trades(address, symbol, offset, limit)
+			}
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(result)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		default:
+			return &abci.ResponseQuery{
+				Code: uint32(sdk.ABCICodeOK),
+				Info: fmt.Sprintf(
+					"Unknown `%s` query path: %v",
+					AbciQueryPrefix, path),
+			}
+		}
+	}
+}
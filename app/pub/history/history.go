@@ -0,0 +1,325 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+)
+
+const (
+	DBName = "history"
+
+	DefaultLimit = 100
+	MaxLimit     = 1000
+
+	orderPrefix  = "order/"
+	closedPrefix = "closed/"
+	tradePrefix  = "trade/"
+)
+
+var heightKey = []byte("height")
+
+// OrderUpdate is a change of an order in its lifecycle, the time is in nanoseconds as published
+type OrderUpdate struct {
+	Height        int64
+	Time          int64
+	Status        string
+	TradeId       string // for PartialFill and FullyFill
+	ExecutedPrice int64
+	ExecutedQty   int64
+	Fee           string // fee of this update
+}
+
+// Order is an order with its lifecycle, from Ack to FullyFill, Canceled, Expired and so on
+type Order struct {
+	OrderId      string
+	Symbol       string
+	Owner        string // bech32 address
+	Side         int8
+	OrderType    int8
+	TimeInForce  int8
+	Price        int64
+	Qty          int64
+	CumQty       int64
+	Status       string // the status of the last update
+	Fee          string // total fee of the order
+	CreationTime int64
+	UpdateTime   int64
+	TxHash       string
+	Updates      []OrderUpdate
+}
+
+// Trade is a trade indexed under both the buyer and the seller
+type Trade struct {
+	TradeId       string
+	Symbol        string
+	Height        int64
+	Time          int64
+	Price         int64
+	Qty           int64
+	BuyerOrderId  string
+	SellerOrderId string
+	Buyer         string // bech32 address
+	Seller        string // bech32 address
+	BuyerFee      string
+	SellerFee     string
+	TickType      int
+}
+
+// IsClosed returns whether the order is removed from the order book by the change, or has never been placed
+func IsClosed(status orderPkg.ChangeType) bool {
+	return !status.IsOpen() || status == orderPkg.FailedBlocking
+}
+
+// Index records the orders and the trades of every block into the db, and indexes the closed orders and
+// the trades by address, newest first.
+type Index struct {
+	db dbm.DB
+
+	mtx    sync.RWMutex
+	height int64
+}
+
+func NewIndex(db dbm.DB) *Index {
+	index := &Index{db: db}
+	if bz := db.Get(heightKey); bz != nil {
+		index.height = int64(binary.BigEndian.Uint64(bz))
+	}
+	return index
+}
+
+func int64Bytes(v int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(v))
+	return bz
+}
+
+func orderKey(orderId string) []byte {
+	return []byte(orderPrefix + orderId)
+}
+
+// closedKey sorts the closed orders of an address by the height they are closed
+func closedKey(owner string, height int64, orderId string) []byte {
+	return append(append([]byte(closedPrefix+owner+"/"), int64Bytes(height)...), orderId...)
+}
+
+// tradeKey sorts the trades of an address by the height and their index in the block
+func tradeKey(address string, height int64, idx int) []byte {
+	return append(append([]byte(tradePrefix+address+"/"), int64Bytes(height)...), int64Bytes(int64(idx))...)
+}
+
+// Height returns the last indexed height
+func (index *Index) Height() int64 {
+	index.mtx.RLock()
+	defer index.mtx.RUnlock()
+	return index.height
+}
+
+// AddBlock indexes the order changes and the trades of the block at the height, each order of `orders` carries
+// the update in its only element of Updates. The orders are processed in sequence, so the updates of an order
+// in a block are recorded in the given order.
+// The heights which have been indexed are skipped, i.e. when tendermint replays the blocks on handshake.
+func (index *Index) AddBlock(height int64, orders []Order, trades []Trade) error {
+	index.mtx.Lock()
+	defer index.mtx.Unlock()
+	if height <= index.height {
+		return nil
+	}
+
+	batch := index.db.NewBatch()
+	defer batch.Close()
+	changed := make(map[string]*Order)
+	for i := range orders {
+		update := orders[i]
+		if len(update.Updates) != 1 {
+			return fmt.Errorf("order %s should carry exactly one update", update.OrderId)
+		}
+		order, err := index.order(changed, update.OrderId)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			order = &Order{
+				OrderId:      update.OrderId,
+				Symbol:       update.Symbol,
+				Owner:        update.Owner,
+				Side:         update.Side,
+				OrderType:    update.OrderType,
+				TimeInForce:  update.TimeInForce,
+				Price:        update.Price,
+				CreationTime: update.CreationTime,
+				TxHash:       update.TxHash,
+				Updates:      make([]OrderUpdate, 0, 1),
+			}
+		}
+		if order.Fee, err = addFees(order.Fee, update.Updates[0].Fee); err != nil {
+			return err
+		}
+		order.Qty = update.Qty
+		order.CumQty = update.CumQty
+		order.Status = update.Status
+		order.UpdateTime = update.UpdateTime
+		order.Updates = append(order.Updates, update.Updates[0])
+		changed[order.OrderId] = order
+	}
+	for id, order := range changed {
+		bz, err := json.Marshal(order)
+		if err != nil {
+			return err
+		}
+		batch.Set(orderKey(id), bz)
+		if status, ok := parseStatus(order.Status); ok && IsClosed(status) {
+			batch.Set(closedKey(order.Owner, height, id), []byte{})
+		}
+	}
+
+	for i, trade := range trades {
+		bz, err := json.Marshal(trade)
+		if err != nil {
+			return err
+		}
+		if trade.Buyer != "" {
+			batch.Set(tradeKey(trade.Buyer, height, i), bz)
+		}
+		if trade.Seller != "" && trade.Seller != trade.Buyer {
+			batch.Set(tradeKey(trade.Seller, height, i), bz)
+		}
+	}
+	batch.Set(heightKey, int64Bytes(height))
+	batch.Write()
+	index.height = height
+	return nil
+}
+
+// order returns the order changed in the block, or the order in the db, nil if there isn't
+func (index *Index) order(changed map[string]*Order, orderId string) (*Order, error) {
+	if order, ok := changed[orderId]; ok {
+		return order, nil
+	}
+	bz := index.db.Get(orderKey(orderId))
+	if bz == nil {
+		return nil, nil
+	}
+	var order Order
+	if err := json.Unmarshal(bz, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func parseStatus(status string) (orderPkg.ChangeType, bool) {
+	for tpe := orderPkg.Ack; tpe.String() != "Unknown"; tpe++ {
+		if tpe.String() == status {
+			return tpe, true
+		}
+	}
+	return 0, false
+}
+
+// addFees sums up the serialized fees, e.g. "BNB:100;XYZ-000:2"
+func addFees(total string, fee string) (string, error) {
+	if fee == "" {
+		return total, nil
+	}
+	if total == "" {
+		return fee, nil
+	}
+	totalCoins, err := parseFee(total)
+	if err != nil {
+		return "", err
+	}
+	feeCoins, err := parseFee(fee)
+	if err != nil {
+		return "", err
+	}
+	return sdk.NewFee(totalCoins.Plus(feeCoins), sdk.FeeForProposer).String(), nil
+}
+
+func parseFee(fee string) (sdk.Coins, error) {
+	coins := make(sdk.Coins, 0)
+	for _, part := range strings.Split(fee, ";") {
+		denomAmount := strings.Split(part, ":")
+		if len(denomAmount) != 2 {
+			return nil, fmt.Errorf("invalid fee %q", fee)
+		}
+		amount, err := strconv.ParseInt(denomAmount[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee %q", fee)
+		}
+		coins = append(coins, sdk.NewCoin(denomAmount[0], amount))
+	}
+	return coins.Sort(), nil
+}
+
+// ClosedOrders returns the closed orders of the address, newest first. If `symbol` is not empty,
+// only the orders of the symbol are returned.
+func (index *Index) ClosedOrders(address string, symbol string, offset, limit int) ([]Order, error) {
+	limit = normalizeLimit(limit)
+	index.mtx.RLock()
+	defer index.mtx.RUnlock()
+	prefix := []byte(closedPrefix + address + "/")
+	it := index.db.ReverseIterator(prefix, sdk.PrefixEndBytes(prefix))
+	defer it.Close()
+	orders := make([]Order, 0)
+	for ; it.Valid() && len(orders) < limit; it.Next() {
+		orderId := string(it.Key()[len(prefix)+8:])
+		order, err := index.order(nil, orderId)
+		if err != nil {
+			return nil, err
+		}
+		if order == nil || (symbol != "" && order.Symbol != symbol) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		orders = append(orders, *order)
+	}
+	return orders, nil
+}
+
+// Trades returns the trades of the address, newest first. If `symbol` is not empty,
+// only the trades of the symbol are returned.
+func (index *Index) Trades(address string, symbol string, offset, limit int) ([]Trade, error) {
+	limit = normalizeLimit(limit)
+	index.mtx.RLock()
+	defer index.mtx.RUnlock()
+	prefix := []byte(tradePrefix + address + "/")
+	it := index.db.ReverseIterator(prefix, sdk.PrefixEndBytes(prefix))
+	defer it.Close()
+	trades := make([]Trade, 0)
+	for ; it.Valid() && len(trades) < limit; it.Next() {
+		var trade Trade
+		if err := json.Unmarshal(it.Value(), &trade); err != nil {
+			return nil, err
+		}
+		if symbol != "" && trade.Symbol != symbol {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		trades = append(trades, trade)
+	}
+	return trades, nil
+}
+
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	} else if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
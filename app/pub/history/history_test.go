@@ -0,0 +1,141 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/wire"
+)
+
+var (
+	buyer  = sdk.AccAddress("history-test-buyer01").String()
+	seller = sdk.AccAddress("history-test-seller1").String()
+)
+
+func orderUpdate(id, owner, symbol, status string, height, cumQty int64, tradeId, fee string) Order {
+	return Order{
+		OrderId: id, Symbol: symbol, Owner: owner, Side: 1, OrderType: 2, TimeInForce: 1,
+		Price: 1e8, Qty: 3e8, CumQty: cumQty, Status: status, CreationTime: 1, UpdateTime: height * 10, TxHash: "hash-" + id,
+		Updates: []OrderUpdate{{Height: height, Time: height * 10, Status: status, TradeId: tradeId, Fee: fee}},
+	}
+}
+
+func TestIndex_Orders(t *testing.T) {
+	db := dbm.NewMemDB()
+	index := NewIndex(db)
+	require.NoError(t, index.AddBlock(1, []Order{
+		orderUpdate("b-1", buyer, "XYZ-000_BNB", "Ack", 1, 0, "", ""),
+		orderUpdate("b-2", buyer, "ABC-000_BNB", "Ack", 1, 0, "", ""),
+		orderUpdate("b-3", buyer, "XYZ-000_BNB", "Ack", 1, 0, "", ""),
+		orderUpdate("b-1", buyer, "XYZ-000_BNB", "PartialFill", 1, 1e8, "1-0", "BNB:10"),
+	}, nil))
+	require.NoError(t, index.AddBlock(2, []Order{
+		orderUpdate("b-2", buyer, "ABC-000_BNB", "Canceled", 2, 0, "", "BNB:5"),
+		orderUpdate("b-1", buyer, "XYZ-000_BNB", "FullyFill", 2, 3e8, "2-0", "BNB:20;XYZ-000:1"),
+	}, nil))
+	require.NoError(t, index.AddBlock(3, []Order{
+		orderUpdate("b-4", buyer, "XYZ-000_BNB", "FailedBlocking", 3, 0, "", ""),
+	}, nil))
+	// the indexed heights are skipped
+	require.NoError(t, index.AddBlock(3, []Order{orderUpdate("b-3", buyer, "XYZ-000_BNB", "Expired", 3, 0, "", "")}, nil))
+	require.Error(t, index.AddBlock(4, []Order{{OrderId: "b-3"}}, nil))
+	require.Equal(t, int64(3), index.Height())
+
+	orders, err := index.ClosedOrders(buyer, "", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, orders, 3)
+	require.Equal(t, "b-4", orders[0].OrderId)
+	require.Equal(t, "FailedBlocking", orders[0].Status)
+	// the orders closed at the same height are sorted by order id, descending
+	require.Equal(t, "b-2", orders[1].OrderId)
+	require.Equal(t, "b-1", orders[2].OrderId)
+
+	fullyFilled := orders[2]
+	require.Equal(t, "FullyFill", fullyFilled.Status)
+	require.Equal(t, int64(3e8), fullyFilled.CumQty)
+	require.Equal(t, "BNB:30;XYZ-000:1", fullyFilled.Fee)
+	require.Equal(t, int64(20), fullyFilled.UpdateTime)
+	require.Len(t, fullyFilled.Updates, 3)
+	require.Equal(t, []string{"Ack", "PartialFill", "FullyFill"},
+		[]string{fullyFilled.Updates[0].Status, fullyFilled.Updates[1].Status, fullyFilled.Updates[2].Status})
+	require.Equal(t, "1-0", fullyFilled.Updates[1].TradeId)
+	require.Equal(t, int64(2), fullyFilled.Updates[2].Height)
+	require.Equal(t, "BNB:5", orders[1].Fee)
+
+	// pagination and symbol
+	orders, err = index.ClosedOrders(buyer, "", 2, 1)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, "b-1", orders[0].OrderId)
+	orders, err = index.ClosedOrders(buyer, "ABC-000_BNB", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, "b-2", orders[0].OrderId)
+	orders, err = index.ClosedOrders(seller, "", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, orders, 0)
+
+	// restart
+	index = NewIndex(db)
+	require.Equal(t, int64(3), index.Height())
+	require.NoError(t, index.AddBlock(4, []Order{orderUpdate("b-3", buyer, "XYZ-000_BNB", "Expired", 4, 0, "", "BNB:1")}, nil))
+	orders, err = index.ClosedOrders(buyer, "", 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, "b-3", orders[0].OrderId)
+	require.Len(t, orders[0].Updates, 2)
+
+	// the results are returned by amino in the abci query
+	cdc := wire.NewCodec()
+	bz, err := cdc.MarshalBinaryLengthPrefixed(orders)
+	require.NoError(t, err)
+	decoded := make([]Order, 0)
+	require.NoError(t, cdc.UnmarshalBinaryLengthPrefixed(bz, &decoded))
+	require.Equal(t, orders, decoded)
+}
+
+func TestIndex_Trades(t *testing.T) {
+	index := NewIndex(dbm.NewMemDB())
+	trade := func(id, symbol string) Trade {
+		return Trade{TradeId: id, Symbol: symbol, Price: 1e8, Qty: 1e8, Buyer: buyer, Seller: seller, BuyerFee: "BNB:1"}
+	}
+	require.NoError(t, index.AddBlock(1, nil, []Trade{trade("1-0", "XYZ-000_BNB"), trade("1-1", "ABC-000_BNB")}))
+	require.NoError(t, index.AddBlock(2, nil, []Trade{
+		trade("2-0", "XYZ-000_BNB"),
+		{TradeId: "2-1", Symbol: "XYZ-000_BNB", Buyer: seller, Seller: seller},
+	}))
+
+	trades, err := index.Trades(buyer, "", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, trades, 3)
+	require.Equal(t, []string{"2-0", "1-1", "1-0"}, []string{trades[0].TradeId, trades[1].TradeId, trades[2].TradeId})
+	require.Equal(t, "BNB:1", trades[0].BuyerFee)
+
+	trades, err = index.Trades(seller, "", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, trades, 4)
+	require.Equal(t, "2-1", trades[0].TradeId)
+
+	trades, err = index.Trades(seller, "XYZ-000_BNB", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	require.Equal(t, "2-0", trades[0].TradeId)
+}
+
+func TestAddFees(t *testing.T) {
+	fee, err := addFees("", "BNB:1")
+	require.NoError(t, err)
+	require.Equal(t, "BNB:1", fee)
+	fee, err = addFees("BNB:1", "")
+	require.NoError(t, err)
+	require.Equal(t, "BNB:1", fee)
+	fee, err = addFees("XYZ-000:2;BNB:1", "BNB:3;ABC-000:1")
+	require.NoError(t, err)
+	require.Equal(t, "ABC-000:1;BNB:4;XYZ-000:2", fee)
+	_, err = addFees("BNB:1", "BNB")
+	require.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package kline
+
+import (
+	"fmt"
+	"strconv"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	app "github.com/bnb-chain/node/common/types"
+)
+
+const AbciQueryPrefix = "kline"
+
+func NewAbciQueryHandler(aggregator *Aggregator) app.AbciQueryHandler {
+	return func(app app.ChainApp, req abci.RequestQuery, path []string) (res *abci.ResponseQuery) {
+		// expects at least two query path segments.
+		if path[0] != AbciQueryPrefix || len(path) < 2 {
+			return nil
+		}
+		var result interface{}
+		switch path[1] {
+		case "klines": // args: ["kline", "klines", <symbol>, <interval>, <startTime>, <endTime>, <limit>]
+			if len(path) < 7 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "klines query requires the symbol, interval, start time, end time and limit in the path",
+				}
+			}
+			var args [3]int64
+			for i := range args {
+				v, err := strconv.ParseInt(path[4+i], 10, 64)
+				if err != nil || v < 0 {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeInternal),
+						Log:  fmt.Sprintf("unable to parse %q", path[4+i]),
+					}
+				}
+				args[i] = v
+			}
+			candles, err := aggregator.Klines(path[2], path[3], args[0], args[1], int(args[2]))
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			result = candles
+		case "ticker": // args: ["kline", "ticker", <symbol>?]
+			if len(path) < 3 || path[2] == "" {
+				tickers, err := aggregator.Tickers()
+				if err != nil {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeInternal),
+						Log:  err.Error(),
+					}
+				}
+				result = tickers
+				break
+			}
+			ticker, ok, err := aggregator.Ticker(path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			// the query will return empty if the symbol has never been traded
+			if !ok {
+				return &abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK)}
+			}
+			result = []Ticker{ticker}
+		default:
+			return &abci.ResponseQuery{
+				Code: uint32(sdk.ABCICodeOK),
+				Info: fmt.Sprintf(
+					"Unknown `%s` query path: %v",
+					AbciQueryPrefix, path),
+			}
+		}
+
+		bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(result)
+		if err != nil {
+			return &abci.ResponseQuery{
+				Code: uint32(sdk.CodeInternal),
+				Log:  err.Error(),
+			}
+		}
+		return &abci.ResponseQuery{
+			Code:  uint32(sdk.ABCICodeOK),
+			Value: bz,
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package kline
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+const (
+	DBName = "kline"
+
+	DefaultLimit = 500
+	MaxLimit     = 1000
+
+	tickerWindow = 24 * 60 * 60 * 1000 // 24h in milliseconds
+
+	candlePrefix = "candle/"
+	symbolPrefix = "symbol/"
+)
+
+var (
+	heightKey = []byte("height")
+	timeKey   = []byte("time")
+)
+
+type Interval struct {
+	Name     string
+	Duration int64 // in milliseconds
+}
+
+// Intervals are aligned to the unix epoch, i.e. the daily candles open at 00:00 UTC
+var Intervals = []Interval{
+	{"1m", 60 * 1000},
+	{"5m", 5 * 60 * 1000},
+	{"1h", 60 * 60 * 1000},
+	{"1d", 24 * 60 * 60 * 1000},
+}
+
+func ParseInterval(name string) (Interval, bool) {
+	for _, interval := range Intervals {
+		if interval.Name == name {
+			return interval, true
+		}
+	}
+	return Interval{}, false
+}
+
+// Trade is the part of a pub.Trade aggregated into the klines
+type Trade struct {
+	Symbol string
+	Price  int64
+	Qty    int64
+}
+
+// Candle is the OHLCV of a symbol in an interval, all times are in milliseconds
+type Candle struct {
+	Symbol      string
+	Interval    string
+	OpenTime    int64
+	CloseTime   int64 // inclusive, i.e. OpenTime + duration - 1
+	Open        int64
+	High        int64
+	Low         int64
+	Close       int64
+	Volume      int64   // in base asset
+	QuoteVolume sdk.Int // in quote asset, which may exceed int64
+	NumOfTrades int64
+}
+
+func (c *Candle) add(trade Trade) {
+	if c.NumOfTrades == 0 {
+		c.Open, c.High, c.Low = trade.Price, trade.Price, trade.Price
+	}
+	if trade.Price > c.High {
+		c.High = trade.Price
+	}
+	if trade.Price < c.Low {
+		c.Low = trade.Price
+	}
+	c.Close = trade.Price
+	c.Volume += trade.Qty
+	c.QuoteVolume = c.QuoteVolume.Add(sdk.NewIntFromBigInt(utils.CalBigNotional(trade.Price, trade.Qty)))
+	c.NumOfTrades++
+}
+
+// Ticker is the rolling 24h statistics of a symbol, ending at the time of the last aggregated block.
+// The prices are zero if there is no trade in the window.
+type Ticker struct {
+	Symbol      string
+	OpenTime    int64
+	CloseTime   int64
+	Open        int64
+	High        int64
+	Low         int64
+	Close       int64
+	PriceChange int64
+	Volume      int64
+	QuoteVolume sdk.Int
+	NumOfTrades int64
+}
+
+// Aggregator builds the candles of the trades of every block and persists them into the db,
+// the 24h tickers are computed from the 1m candles on query.
+type Aggregator struct {
+	db dbm.DB
+
+	mtx     sync.RWMutex
+	height  int64
+	time    int64
+	symbols map[string]struct{}
+	current map[string]*Candle // the latest candle of each symbol and interval
+}
+
+func NewAggregator(db dbm.DB) *Aggregator {
+	a := &Aggregator{
+		db:      db,
+		symbols: make(map[string]struct{}),
+		current: make(map[string]*Candle),
+	}
+	if bz := db.Get(heightKey); bz != nil {
+		a.height = int64(binary.BigEndian.Uint64(bz))
+	}
+	if bz := db.Get(timeKey); bz != nil {
+		a.time = int64(binary.BigEndian.Uint64(bz))
+	}
+	it := dbm.IteratePrefix(db, []byte(symbolPrefix))
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		a.symbols[strings.TrimPrefix(string(it.Key()), symbolPrefix)] = struct{}{}
+	}
+	return a
+}
+
+func int64Bytes(v int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(v))
+	return bz
+}
+
+func candleKeyPrefix(symbol, interval string) []byte {
+	return []byte(fmt.Sprintf("%s%s/%s/", candlePrefix, symbol, interval))
+}
+
+func candleKey(symbol, interval string, openTime int64) []byte {
+	return append(candleKeyPrefix(symbol, interval), int64Bytes(openTime)...)
+}
+
+// Height returns the last aggregated height
+func (a *Aggregator) Height() int64 {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return a.height
+}
+
+// AddTrades aggregates the trades of the block at the height, `timestamp` is the block time in milliseconds.
+// The heights which have been aggregated are skipped, i.e. when tendermint replays the blocks on handshake.
+func (a *Aggregator) AddTrades(height int64, timestamp int64, trades []Trade) error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if height <= a.height {
+		return nil
+	}
+
+	batch := a.db.NewBatch()
+	defer batch.Close()
+	changed := make(map[string]*Candle)
+	for _, trade := range trades {
+		if _, ok := a.symbols[trade.Symbol]; !ok {
+			a.symbols[trade.Symbol] = struct{}{}
+			batch.Set([]byte(symbolPrefix+trade.Symbol), []byte{})
+		}
+		for _, interval := range Intervals {
+			openTime := timestamp - timestamp%interval.Duration
+			key := string(candleKey(trade.Symbol, interval.Name, openTime))
+			candle, err := a.candle(key, trade.Symbol, interval, openTime)
+			if err != nil {
+				return err
+			}
+			candle.add(trade)
+			changed[key] = candle
+		}
+	}
+	for key, candle := range changed {
+		bz, err := json.Marshal(candle)
+		if err != nil {
+			return err
+		}
+		batch.Set([]byte(key), bz)
+	}
+	batch.Set(heightKey, int64Bytes(height))
+	batch.Set(timeKey, int64Bytes(timestamp))
+	batch.Write()
+	a.height, a.time = height, timestamp
+	return nil
+}
+
+// candle returns the candle of the key from the cache or the db, or a new one if there isn't
+func (a *Aggregator) candle(key, symbol string, interval Interval, openTime int64) (*Candle, error) {
+	cacheKey := symbol + "/" + interval.Name
+	if candle, ok := a.current[cacheKey]; ok && candle.OpenTime == openTime {
+		return candle, nil
+	}
+	candle := &Candle{
+		Symbol:      symbol,
+		Interval:    interval.Name,
+		OpenTime:    openTime,
+		CloseTime:   openTime + interval.Duration - 1,
+		QuoteVolume: sdk.ZeroInt(),
+	}
+	if bz := a.db.Get([]byte(key)); bz != nil {
+		if err := json.Unmarshal(bz, candle); err != nil {
+			return nil, err
+		}
+	}
+	a.current[cacheKey] = candle
+	return candle, nil
+}
+
+// Klines returns the candles of the symbol opened within [startTime, endTime] in ascending order, zero means
+// unbounded. If `startTime` is not specified, the latest candles are returned. Intervals without trades are omitted.
+func (a *Aggregator) Klines(symbol string, interval string, startTime, endTime int64, limit int) ([]Candle, error) {
+	if _, ok := ParseInterval(interval); !ok {
+		return nil, fmt.Errorf("invalid interval %q", interval)
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	} else if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	prefix := candleKeyPrefix(symbol, interval)
+	start, end := prefix, sdk.PrefixEndBytes(prefix)
+	if startTime > 0 {
+		start = candleKey(symbol, interval, startTime)
+	}
+	if endTime > 0 {
+		end = candleKey(symbol, interval, endTime+1)
+	}
+
+	var it dbm.Iterator
+	if startTime > 0 {
+		it = a.db.Iterator(start, end)
+	} else {
+		it = a.db.ReverseIterator(start, end)
+	}
+	defer it.Close()
+	candles := make([]Candle, 0)
+	for ; it.Valid() && len(candles) < limit; it.Next() {
+		var candle Candle
+		if err := json.Unmarshal(it.Value(), &candle); err != nil {
+			return nil, err
+		}
+		candles = append(candles, candle)
+	}
+	if startTime <= 0 {
+		sort.Slice(candles, func(i, j int) bool { return candles[i].OpenTime < candles[j].OpenTime })
+	}
+	return candles, nil
+}
+
+// Ticker returns the 24h ticker of the symbol, false if the symbol has never been traded
+func (a *Aggregator) Ticker(symbol string) (Ticker, bool, error) {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	if _, ok := a.symbols[symbol]; !ok {
+		return Ticker{}, false, nil
+	}
+	ticker, err := a.ticker(symbol)
+	return ticker, true, err
+}
+
+// Tickers returns the 24h tickers of all the traded symbols, sorted by symbol
+func (a *Aggregator) Tickers() ([]Ticker, error) {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	symbols := make([]string, 0, len(a.symbols))
+	for symbol := range a.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	tickers := make([]Ticker, 0, len(symbols))
+	for _, symbol := range symbols {
+		ticker, err := a.ticker(symbol)
+		if err != nil {
+			return nil, err
+		}
+		tickers = append(tickers, ticker)
+	}
+	return tickers, nil
+}
+
+func (a *Aggregator) ticker(symbol string) (Ticker, error) {
+	minute := Intervals[0]
+	ticker := Ticker{
+		Symbol:      symbol,
+		OpenTime:    a.time - tickerWindow,
+		CloseTime:   a.time,
+		QuoteVolume: sdk.ZeroInt(),
+	}
+	// the 1m candle containing the start of the window is excluded, so the window is at most 24h
+	start := ticker.OpenTime - ticker.OpenTime%minute.Duration + minute.Duration
+	it := a.db.Iterator(candleKey(symbol, minute.Name, start), sdk.PrefixEndBytes(candleKeyPrefix(symbol, minute.Name)))
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		var candle Candle
+		if err := json.Unmarshal(it.Value(), &candle); err != nil {
+			return Ticker{}, err
+		}
+		if ticker.NumOfTrades == 0 {
+			ticker.Open, ticker.High, ticker.Low = candle.Open, candle.High, candle.Low
+		}
+		if candle.High > ticker.High {
+			ticker.High = candle.High
+		}
+		if candle.Low < ticker.Low {
+			ticker.Low = candle.Low
+		}
+		ticker.Close = candle.Close
+		ticker.Volume += candle.Volume
+		ticker.QuoteVolume = ticker.QuoteVolume.Add(candle.QuoteVolume)
+		ticker.NumOfTrades += candle.NumOfTrades
+	}
+	ticker.PriceChange = ticker.Close - ticker.Open
+	return ticker, nil
+}
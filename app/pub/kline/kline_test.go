@@ -0,0 +1,139 @@
+package kline
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+const (
+	symbol = "XYZ-000_BNB"
+	minute = int64(60 * 1000)
+	day    = int64(18500) * 24 * 60 * minute // 2020-08-26 00:00:00 UTC
+)
+
+func TestAggregator_Klines(t *testing.T) {
+	db := dbm.NewMemDB()
+	a := NewAggregator(db)
+	require.NoError(t, a.AddTrades(1, day+10*1000, []Trade{{symbol, 1e8, 2e8}, {symbol, 3e8, 1e8}}))
+	require.NoError(t, a.AddTrades(2, day+20*1000, []Trade{{symbol, 2e8, 1e8}, {"ABC-000_BNB", 5e8, 1e8}}))
+	require.NoError(t, a.AddTrades(3, day+minute+1, []Trade{{symbol, 4e8, 1e8}}))
+	require.NoError(t, a.AddTrades(4, day+6*minute, nil))
+	require.NoError(t, a.AddTrades(5, day+6*minute+1, []Trade{{symbol, 1e7, 1e8}}))
+	// the aggregated heights are skipped
+	require.NoError(t, a.AddTrades(5, day+6*minute+1, []Trade{{symbol, 1e7, 1e8}}))
+	require.Equal(t, int64(5), a.Height())
+
+	candles, err := a.Klines(symbol, "1m", 0, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, candles, 3)
+	require.Equal(t, Candle{
+		Symbol: symbol, Interval: "1m", OpenTime: day, CloseTime: day + minute - 1,
+		Open: 1e8, High: 3e8, Low: 1e8, Close: 2e8, Volume: 4e8, QuoteVolume: sdk.NewInt(7e8), NumOfTrades: 3,
+	}, candles[0])
+	require.Equal(t, day+minute, candles[1].OpenTime)
+	require.Equal(t, day+6*minute, candles[2].OpenTime)
+
+	candles, err = a.Klines(symbol, "5m", 0, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+	require.Equal(t, int64(4e8), candles[0].Close)
+	require.Equal(t, int64(4), candles[0].NumOfTrades)
+
+	candles, err = a.Klines(symbol, "1d", 0, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	require.Equal(t, int64(4e8), candles[0].High)
+	require.Equal(t, int64(1e7), candles[0].Low)
+	require.Equal(t, int64(1e7), candles[0].Close)
+	require.Equal(t, int64(6e8), candles[0].Volume)
+	require.Equal(t, day+24*60*minute-1, candles[0].CloseTime)
+
+	// the latest candles without start time
+	candles, err = a.Klines(symbol, "1m", 0, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+	require.Equal(t, day+minute, candles[0].OpenTime)
+	require.Equal(t, day+6*minute, candles[1].OpenTime)
+
+	// the candles opened within the time range
+	candles, err = a.Klines(symbol, "1m", day+1, day+6*minute, 0)
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+	require.Equal(t, day+minute, candles[0].OpenTime)
+	candles, err = a.Klines(symbol, "1m", day, day+5*minute, 1)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	require.Equal(t, day, candles[0].OpenTime)
+
+	candles, err = a.Klines("NONE-000_BNB", "1m", 0, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, candles, 0)
+	_, err = a.Klines(symbol, "2m", 0, 0, 0)
+	require.Error(t, err)
+
+	// restart
+	a = NewAggregator(db)
+	require.Equal(t, int64(5), a.Height())
+	require.NoError(t, a.AddTrades(6, day+6*minute+2, []Trade{{symbol, 2e7, 1e8}}))
+	candles, err = a.Klines(symbol, "1m", 0, 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), candles[0].NumOfTrades)
+	require.Equal(t, int64(1e7), candles[0].Open)
+	require.Equal(t, int64(2e7), candles[0].Close)
+}
+
+func TestAggregator_Ticker(t *testing.T) {
+	a := NewAggregator(dbm.NewMemDB())
+	_, ok, err := a.Ticker(symbol)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, a.AddTrades(1, day, []Trade{{symbol, 1e8, 1e8}}))
+	require.NoError(t, a.AddTrades(2, day+40*minute, []Trade{{symbol, 3e8, 1e8}, {"ABC-000_BNB", 5e8, 1e8}}))
+	require.NoError(t, a.AddTrades(3, day+24*60*minute-1, []Trade{{symbol, 2e8, 2e8}}))
+
+	ticker, ok, err := a.Ticker(symbol)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, Ticker{
+		Symbol: symbol, OpenTime: day - 1, CloseTime: day + 24*60*minute - 1,
+		Open: 1e8, High: 3e8, Low: 1e8, Close: 2e8, PriceChange: 1e8, Volume: 4e8, QuoteVolume: sdk.NewInt(8e8), NumOfTrades: 3,
+	}, ticker)
+
+	// the trades older than 24h are excluded
+	require.NoError(t, a.AddTrades(4, day+24*60*minute+30*minute, nil))
+	tickers, err := a.Tickers()
+	require.NoError(t, err)
+	require.Len(t, tickers, 2)
+	require.Equal(t, "ABC-000_BNB", tickers[0].Symbol)
+	require.Equal(t, int64(1), tickers[0].NumOfTrades)
+	require.Equal(t, symbol, tickers[1].Symbol)
+	require.Equal(t, int64(2), tickers[1].NumOfTrades)
+	require.Equal(t, int64(3e8), tickers[1].Open)
+	require.Equal(t, int64(-1e8), tickers[1].PriceChange)
+}
+
+func TestAggregator_QuoteVolumeOverflow(t *testing.T) {
+	db := dbm.NewMemDB()
+	a := NewAggregator(db)
+	// the notional of each trade is 8.1e29, far beyond int64
+	require.NoError(t, a.AddTrades(1, day, []Trade{{symbol, 9e18, 9e18}, {symbol, 9e18, 9e18}}))
+
+	expected, ok := sdk.NewIntFromString("1620000000000000000000000000000")
+	require.True(t, ok)
+	candles, err := a.Klines(symbol, "1m", 0, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	require.True(t, expected.Equal(candles[0].QuoteVolume))
+
+	// the volume is kept after restart
+	a = NewAggregator(db)
+	ticker, ok, err := a.Ticker(symbol)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, expected.Equal(ticker.QuoteVolume))
+}
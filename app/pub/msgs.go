@@ -73,21 +73,8 @@ func (this msgType) String() string {
 // This field is used to generate last component of key in kafka message and helps consumers
 // figure out which version of writer schema to use.
 // This allows consumers be deployed independently (in advance) with publisher
-var latestSchemaVersions = map[msgType]int{
-	accountsTpe:        1,
-	booksTpe:           0,
-	executionResultTpe: 1,
-	blockFeeTpe:        0,
-	transferTpe:        1,
-	blockTpe:           0,
-	stakingTpe:         0,
-	distributionTpe:    1,
-	slashingTpe:        0,
-	crossTransferTpe:   0,
-	mirrorTpe:          0,
-	sideProposalType:   0,
-	breatheBlockTpe:    0,
-}
+// The versions are the latest snapshots in pub/schemas
+var latestSchemaVersions = loadLatestSchemaVersions()
 
 type AvroOrJsonMsg interface {
 	ToNativeMap() map[string]interface{}
@@ -230,6 +217,33 @@ func (msg *Trade) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// UnmarshalJSON converts the bech32 addresses back, i.e. to read the msgs of the local publisher
+func (msg *Trade) UnmarshalJSON(bz []byte) (err error) {
+	type Alias Trade
+	aux := &struct {
+		*Alias
+		SAddr string
+		BAddr string
+	}{Alias: (*Alias)(msg)}
+	if err = json.Unmarshal(bz, aux); err != nil {
+		return err
+	}
+	if msg.SAddr, err = accAddressFromJson(aux.SAddr); err != nil {
+		return err
+	}
+	msg.BAddr, err = accAddressFromJson(aux.BAddr)
+	return err
+}
+
+// accAddressFromJson returns the string wrapper of the bytes of the bech32 address
+func accAddressFromJson(bech32 string) (string, error) {
+	if bech32 == "" {
+		return "", nil
+	}
+	addr, err := sdk.AccAddressFromBech32(bech32)
+	return string(addr), err
+}
+
 func (msg *Trade) String() string {
 	return fmt.Sprintf("Trade: %v", msg.toNativeMap())
 }
@@ -315,13 +329,23 @@ func (msg *Order) String() string {
 func (msg *Order) effectQtyToOrderBook() int64 {
 	switch msg.Status {
 	case orderPkg.Ack:
+		if orderPkg.IsConditionalOrderType(msg.OrderType) {
+			// untriggered conditional orders are held outside of the order book
+			return 0
+		}
+		return msg.Qty
+	case orderPkg.Triggered:
 		return msg.Qty
 	case orderPkg.FullyFill, orderPkg.PartialFill:
 		return -msg.LastExecutedQty
-	case orderPkg.Expired, orderPkg.IocExpire, orderPkg.IocNoFill, orderPkg.Canceled, orderPkg.FailedMatching:
+	case orderPkg.Expired, orderPkg.IocExpire, orderPkg.IocNoFill, orderPkg.Canceled, orderPkg.FailedMatching, orderPkg.PostOnlyRejected,
+		orderPkg.SelfTradePrevented:
 		return msg.CumQty - msg.Qty // deliberated be negative value
 	case orderPkg.FailedBlocking:
 		return 0
+	case orderPkg.Amended:
+		// the qty reduced is unknown here, the price level is published anyway in filterChangedOrderBooksByOrders
+		return 0
 	default:
 		Logger.Error("does not supported order status", "order", msg.String())
 		return 0
@@ -584,6 +608,19 @@ func (msg *Account) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (msg *Account) UnmarshalJSON(bz []byte) (err error) {
+	type Alias Account
+	aux := &struct {
+		*Alias
+		Owner string
+	}{Alias: (*Alias)(msg)}
+	if err = json.Unmarshal(bz, aux); err != nil {
+		return err
+	}
+	msg.Owner, err = accAddressFromJson(aux.Owner)
+	return err
+}
+
 func (msg *Account) String() string {
 	return fmt.Sprintf("Account of: %s, fee: %s, num of balance changes: %d", msg.Owner, msg.Fee, len(msg.Balances))
 }
@@ -664,6 +701,26 @@ func (msg BlockFee) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (msg *BlockFee) UnmarshalJSON(bz []byte) error {
+	type Alias BlockFee
+	aux := &struct {
+		*Alias
+		Validators []string
+	}{Alias: (*Alias)(msg)}
+	if err := json.Unmarshal(bz, aux); err != nil {
+		return err
+	}
+	msg.Validators = make([]string, len(aux.Validators))
+	for id, val := range aux.Validators {
+		addr, err := accAddressFromJson(val)
+		if err != nil {
+			return err
+		}
+		msg.Validators[id] = addr
+	}
+	return nil
+}
+
 func (msg BlockFee) String() string {
 	return fmt.Sprintf("Blockfee at height: %d, fee: %s, validators: %v", msg.Height, msg.Fee, msg.Validators)
 }
@@ -0,0 +1,412 @@
+package pub
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The protobuf messages of the msg types are derived from their avro schemas, so that both encodings carry the
+// same content and evolve together:
+//  1. the field numbers are the positions of the fields in the avro records starting from 1, so the fields must only
+//     be appended to the avro records, TestProtoDefinitions rejects the definitions renumbering the committed ones
+//  2. a nullable primitive is an optional field, a nullable record is a message field
+//  3. an array is a repeated field, a map is a map from string, the arrays in arrays or maps are wrapped by
+//     messages named by their items suffixed with "List", with the items in the field 1
+//
+// The definitions generated by protoDefinition are put into pub/proto for the consumers to generate their bindings,
+// they are checked against the avro schemas by protobuf_test.go. The msgs implementing protoMsg are encoded without
+// their native maps.
+
+const (
+	protoPackagePrefix = "marketdata."
+	protoGoPackage     = "github.com/bnb-chain/node/app/pub/proto/"
+)
+
+var protoScalars = map[string]string{
+	"boolean": "bool",
+	"int":     "int32",
+	"long":    "int64",
+	"float":   "float",
+	"double":  "double",
+	"bytes":   "bytes",
+	"string":  "string",
+}
+
+type protoMessage struct {
+	name   string
+	fields []*protoField
+}
+
+type protoType struct {
+	scalar  string // the avro primitive, empty for messages
+	message *protoMessage
+}
+
+func (t protoType) String() string {
+	if t.message != nil {
+		return t.message.name
+	}
+	return protoScalars[t.scalar]
+}
+
+type protoField struct {
+	name     string
+	number   protowire.Number
+	typ      protoType // the type of the value for map fields
+	optional bool
+	nullable bool // whether the avro type is a union with null, whose values are wrapped
+	repeated bool
+	isMap    bool
+}
+
+// protoCodec encodes the native maps of a msg type into protobuf
+type protoCodec struct {
+	tpe      msgType
+	root     *protoMessage
+	messages []*protoMessage // in the definition order
+}
+
+func newProtoCodec(tpe msgType, avroSchema string) (*protoCodec, error) {
+	schema, err := parseAvroSchema(avroSchema)
+	if err != nil {
+		return nil, err
+	}
+	c := &protoCodec{tpe: tpe}
+	messages := make(map[string]*protoMessage)
+	typ, err := c.convert(schema, messages, schema.root, tpe.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the protobuf message of %s: %v", tpe.String(), err)
+	}
+	if typ.message == nil {
+		return nil, fmt.Errorf("the schema of %s is not a record", tpe.String())
+	}
+	c.root = typ.message
+	return c, nil
+}
+
+func (c *protoCodec) convert(schema *avroSchema, messages map[string]*protoMessage, node interface{}, path string) (protoType, error) {
+	node = schema.resolve(node)
+	switch avroType(node) {
+	case "record":
+		def := node.(map[string]interface{})
+		name := avroShortName(def["name"].(string))
+		if msg, ok := messages[name]; ok {
+			return protoType{message: msg}, nil
+		}
+		msg := &protoMessage{name: name}
+		messages[name] = msg
+		c.messages = append(c.messages, msg)
+		for i, f := range def["fields"].([]interface{}) {
+			fieldDef := f.(map[string]interface{})
+			field, err := c.convertField(schema, messages, fieldDef["name"].(string), protowire.Number(i+1), fieldDef["type"], path)
+			if err != nil {
+				return protoType{}, err
+			}
+			msg.fields = append(msg.fields, field)
+		}
+		return protoType{message: msg}, nil
+	case "array", "map":
+		// the nested collections are wrapped by messages
+		field, err := c.convertField(schema, messages, "items", 1, node, path)
+		if err != nil {
+			return protoType{}, err
+		}
+		name := field.typ.String()
+		if field.isMap {
+			name += "Map"
+		}
+		name = strings.ToUpper(name[:1]) + name[1:] + "List"
+		if msg, ok := messages[name]; ok {
+			if len(msg.fields) != 1 || *msg.fields[0] != *field {
+				return protoType{}, fmt.Errorf("%s: conflicting message %s", path, name)
+			}
+			return protoType{message: msg}, nil
+		}
+		msg := &protoMessage{name: name, fields: []*protoField{field}}
+		messages[name] = msg
+		c.messages = append(c.messages, msg)
+		return protoType{message: msg}, nil
+	default:
+		if t := avroType(node); protoScalars[t] != "" {
+			return protoType{scalar: t}, nil
+		}
+		return protoType{}, fmt.Errorf("%s: unsupported avro type %v", path, node)
+	}
+}
+
+func (c *protoCodec) convertField(schema *avroSchema, messages map[string]*protoMessage, name string, number protowire.Number, node interface{}, path string) (*protoField, error) {
+	path = path + "." + name
+	field := &protoField{name: name, number: number}
+	node = schema.resolve(node)
+	if union, ok := node.([]interface{}); ok {
+		var branches []interface{}
+		for _, branch := range union {
+			if branch != "null" {
+				branches = append(branches, branch)
+			}
+		}
+		if len(branches) != 1 {
+			return nil, fmt.Errorf("%s: only the unions of null and another type are supported", path)
+		}
+		field.nullable = len(union) > 1
+		field.optional = field.nullable
+		node = schema.resolve(branches[0])
+	}
+
+	var err error
+	switch avroType(node) {
+	case "array":
+		field.repeated = true
+		field.typ, err = c.convert(schema, messages, node.(map[string]interface{})["items"], path)
+	case "map":
+		field.isMap = true
+		field.typ, err = c.convert(schema, messages, node.(map[string]interface{})["values"], path)
+	default:
+		field.typ, err = c.convert(schema, messages, node, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the collections and messages always have presence
+	if field.repeated || field.isMap || field.typ.message != nil {
+		field.optional = false
+	}
+	return field, nil
+}
+
+// protoDefinition returns the .proto definition of the msg type
+func (c *protoCodec) protoDefinition() string {
+	pkg := strings.ToLower(c.tpe.String())
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated from the avro schema of %s in app/pub/schemas.go. DO NOT EDIT.\n", c.tpe.String())
+	b.WriteString("// The field numbers are the positions of the fields in the avro records.\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s%s;\n\n", protoPackagePrefix, pkg)
+	fmt.Fprintf(&b, "option go_package = \"%s%s\";\n", protoGoPackage, pkg)
+	for _, msg := range c.messages {
+		fmt.Fprintf(&b, "\nmessage %s {\n", msg.name)
+		for _, f := range msg.fields {
+			b.WriteString("  ")
+			switch {
+			case f.isMap:
+				fmt.Fprintf(&b, "map<string, %s>", f.typ)
+			case f.repeated:
+				fmt.Fprintf(&b, "repeated %s", f.typ)
+			case f.optional:
+				fmt.Fprintf(&b, "optional %s", f.typ)
+			default:
+				b.WriteString(f.typ.String())
+			}
+			fmt.Fprintf(&b, " %s = %d;\n", f.name, f.number)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func (c *protoCodec) marshal(native map[string]interface{}) ([]byte, error) {
+	return appendProtoMessage(nil, c.root, native)
+}
+
+func appendProtoMessage(b []byte, msg *protoMessage, native map[string]interface{}) ([]byte, error) {
+	var err error
+	for _, f := range msg.fields {
+		value, ok := native[f.name]
+		if !ok || value == nil {
+			continue
+		}
+		// the value of a union is wrapped by a map from the name of the branch
+		if union, ok := value.(map[string]interface{}); ok && f.nullable && len(union) == 1 {
+			for _, v := range union {
+				value = v
+			}
+			if value == nil {
+				continue
+			}
+		}
+		if b, err = appendProtoField(b, f, value); err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", msg.name, f.name, err)
+		}
+	}
+	return b, nil
+}
+
+func appendProtoField(b []byte, f *protoField, value interface{}) ([]byte, error) {
+	switch {
+	case f.isMap:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("expects a map from string, got %T", value)
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			entry := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), k)
+			entry, err := appendProtoValue(entry, 2, f.typ, rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())).Interface(), true)
+			if err != nil {
+				return nil, err
+			}
+			b = protowire.AppendBytes(protowire.AppendTag(b, f.number, protowire.BytesType), entry)
+		}
+		return b, nil
+	case f.repeated:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("expects a slice, got %T", value)
+		}
+		if rv.Len() == 0 {
+			return b, nil
+		}
+		// the repeated scalars except strings and bytes are packed
+		if f.typ.message == nil && f.typ.scalar != "string" && f.typ.scalar != "bytes" {
+			var packed []byte
+			var err error
+			for i := 0; i < rv.Len(); i++ {
+				if packed, err = appendProtoScalar(packed, f.typ.scalar, rv.Index(i).Interface()); err != nil {
+					return nil, err
+				}
+			}
+			return protowire.AppendBytes(protowire.AppendTag(b, f.number, protowire.BytesType), packed), nil
+		}
+		var err error
+		for i := 0; i < rv.Len(); i++ {
+			if b, err = appendProtoValue(b, f.number, f.typ, rv.Index(i).Interface(), true); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	default:
+		return appendProtoValue(b, f.number, f.typ, value, f.optional)
+	}
+}
+
+// appendProtoValue appends the tag and the value, the zero scalars are skipped unless `presence`
+func appendProtoValue(b []byte, number protowire.Number, typ protoType, value interface{}, presence bool) ([]byte, error) {
+	if typ.message != nil {
+		native, err := protoNativeMap(value)
+		if err != nil {
+			return nil, err
+		}
+		bz, err := appendProtoMessage(nil, typ.message, native)
+		if err != nil {
+			return nil, err
+		}
+		return protowire.AppendBytes(protowire.AppendTag(b, number, protowire.BytesType), bz), nil
+	}
+	if !presence && reflect.ValueOf(value).IsZero() {
+		return b, nil
+	}
+	var wireType protowire.Type
+	switch typ.scalar {
+	case "string", "bytes":
+		wireType = protowire.BytesType
+	case "float":
+		wireType = protowire.Fixed32Type
+	case "double":
+		wireType = protowire.Fixed64Type
+	default:
+		wireType = protowire.VarintType
+	}
+	return appendProtoScalar(protowire.AppendTag(b, number, wireType), typ.scalar, value)
+}
+
+func protoNativeMap(value interface{}) (map[string]interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case []interface{}, []map[string]interface{}, []string:
+		// the items of a wrapper message
+		return map[string]interface{}{"items": v}, nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map {
+		return map[string]interface{}{"items": value}, nil
+	}
+	return nil, fmt.Errorf("expects a record, got %T", value)
+}
+
+func appendProtoScalar(b []byte, scalar string, value interface{}) ([]byte, error) {
+	switch scalar {
+	case "string", "bytes":
+		switch v := value.(type) {
+		case string:
+			return protowire.AppendString(b, v), nil
+		case []byte:
+			return protowire.AppendBytes(b, v), nil
+		}
+	case "boolean":
+		if v, ok := value.(bool); ok {
+			return protowire.AppendVarint(b, protowire.EncodeBool(v)), nil
+		}
+	case "int", "long":
+		if v, ok := protoInt(value); ok {
+			if scalar == "int" {
+				// int32 is sign extended as int64
+				v = int64(int32(v))
+			}
+			return protowire.AppendVarint(b, uint64(v)), nil
+		}
+	case "float", "double":
+		var f float64
+		switch v := value.(type) {
+		case float32:
+			f = float64(v)
+		case float64:
+			f = v
+		default:
+			i, ok := protoInt(value)
+			if !ok {
+				return nil, fmt.Errorf("expects a %s, got %T", scalar, value)
+			}
+			f = float64(i)
+		}
+		if scalar == "float" {
+			return protowire.AppendFixed32(b, math.Float32bits(float32(f))), nil
+		}
+		return protowire.AppendFixed64(b, math.Float64bits(f)), nil
+	}
+	return nil, fmt.Errorf("expects a %s, got %T", scalar, value)
+}
+
+func protoInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	}
+	return 0, false
+}
@@ -0,0 +1,215 @@
+package pub
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// protoMsg is implemented by the msgs published in every block, they are encoded into protobuf directly
+// rather than by building and walking their native maps. The field numbers are the ones in pub/proto,
+// TestProtoMsgs checks the encodings are the same as the ones derived from the avro schemas
+type protoMsg interface {
+	appendProto(b []byte) []byte
+}
+
+var (
+	_ protoMsg = (*ExecutionResults)(nil)
+	_ protoMsg = (*Books)(nil)
+	_ protoMsg = (*Accounts)(nil)
+)
+
+// the zero scalars are skipped as done by appendProtoValue
+
+func appendProtoVarint(b []byte, number protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	return protowire.AppendVarint(protowire.AppendTag(b, number, protowire.VarintType), uint64(v))
+}
+
+// appendProtoInt32 appends the avro int, which is sign extended as int64
+func appendProtoInt32(b []byte, number protowire.Number, v int) []byte {
+	return appendProtoVarint(b, number, int64(int32(v)))
+}
+
+func appendProtoString(b []byte, number protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	return protowire.AppendString(protowire.AppendTag(b, number, protowire.BytesType), v)
+}
+
+// appendProtoEmbedded appends the message field, which is present even if empty
+func appendProtoEmbedded(b []byte, number protowire.Number, msg []byte) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(b, number, protowire.BytesType), msg)
+}
+
+func (msg *ExecutionResults) appendProto(b []byte) []byte {
+	b = appendProtoVarint(b, 1, msg.Height)
+	b = appendProtoVarint(b, 2, msg.Timestamp)
+	b = appendProtoInt32(b, 3, msg.NumOfMsgs)
+	if msg.Trades.NumOfMsgs > 0 {
+		b = appendProtoEmbedded(b, 4, msg.Trades.appendProto(nil))
+	}
+	if msg.Orders.NumOfMsgs > 0 {
+		b = appendProtoEmbedded(b, 5, msg.Orders.appendProto(nil))
+	}
+	if msg.Proposals.NumOfMsgs > 0 {
+		b = appendProtoEmbedded(b, 6, msg.Proposals.appendProto(nil))
+	}
+	if msg.StakeUpdates.NumOfMsgs > 0 {
+		b = appendProtoEmbedded(b, 7, msg.StakeUpdates.appendProto(nil))
+	}
+	return b
+}
+
+func (msg *trades) appendProto(b []byte) []byte {
+	b = appendProtoInt32(b, 1, msg.NumOfMsgs)
+	for _, trade := range msg.Trades {
+		b = appendProtoEmbedded(b, 2, trade.appendProto(nil))
+	}
+	return b
+}
+
+func (msg *Trade) appendProto(b []byte) []byte {
+	b = appendProtoString(b, 1, msg.Symbol)
+	b = appendProtoString(b, 2, msg.Id)
+	b = appendProtoVarint(b, 3, msg.Price)
+	b = appendProtoVarint(b, 4, msg.Qty)
+	b = appendProtoString(b, 5, msg.Sid)
+	b = appendProtoString(b, 6, msg.Bid)
+	b = appendProtoString(b, 7, msg.Sfee)
+	b = appendProtoString(b, 8, msg.Bfee)
+	b = appendProtoString(b, 9, sdk.AccAddress(msg.SAddr).String())
+	b = appendProtoString(b, 10, sdk.AccAddress(msg.BAddr).String())
+	b = appendProtoVarint(b, 11, msg.SSrc)
+	b = appendProtoVarint(b, 12, msg.BSrc)
+	b = appendProtoString(b, 13, msg.SSingleFee)
+	b = appendProtoString(b, 14, msg.BSingleFee)
+	b = appendProtoInt32(b, 15, msg.TickType)
+	return b
+}
+
+func (msg *Orders) appendProto(b []byte) []byte {
+	b = appendProtoInt32(b, 1, msg.NumOfMsgs)
+	for _, o := range msg.Orders {
+		b = appendProtoEmbedded(b, 2, o.appendProto(nil))
+	}
+	return b
+}
+
+func (msg *Order) appendProto(b []byte) []byte {
+	b = appendProtoString(b, 1, msg.Symbol)
+	b = appendProtoString(b, 2, msg.Status.String())
+	b = appendProtoString(b, 3, msg.OrderId)
+	b = appendProtoString(b, 4, msg.TradeId)
+	b = appendProtoString(b, 5, msg.Owner)
+	b = appendProtoInt32(b, 6, int(msg.Side))
+	b = appendProtoInt32(b, 7, int(msg.OrderType))
+	b = appendProtoVarint(b, 8, msg.Price)
+	b = appendProtoVarint(b, 9, msg.Qty)
+	b = appendProtoVarint(b, 10, msg.LastExecutedPrice)
+	b = appendProtoVarint(b, 11, msg.LastExecutedQty)
+	b = appendProtoVarint(b, 12, msg.CumQty)
+	b = appendProtoString(b, 13, msg.Fee)
+	b = appendProtoVarint(b, 14, msg.OrderCreationTime)
+	b = appendProtoVarint(b, 15, msg.TransactionTime)
+	b = appendProtoInt32(b, 16, int(msg.TimeInForce))
+	b = appendProtoString(b, 17, msg.CurrentExecutionType.String())
+	b = appendProtoString(b, 18, msg.TxHash)
+	b = appendProtoString(b, 19, msg.SingleFee)
+	return b
+}
+
+func (msg *Proposals) appendProto(b []byte) []byte {
+	b = appendProtoInt32(b, 1, msg.NumOfMsgs)
+	for _, p := range msg.Proposals {
+		b = appendProtoEmbedded(b, 2, p.appendProto(nil))
+	}
+	return b
+}
+
+func (msg *Proposal) appendProto(b []byte) []byte {
+	b = appendProtoVarint(b, 1, msg.Id)
+	b = appendProtoString(b, 2, msg.Status.String())
+	return b
+}
+
+func (msg *StakeUpdates) appendProto(b []byte) []byte {
+	b = appendProtoInt32(b, 1, msg.NumOfMsgs)
+	for _, p := range msg.CompletedUnbondingDelegations {
+		b = appendProtoEmbedded(b, 2, p.appendProto(nil))
+	}
+	return b
+}
+
+func (msg *CompletedUnbondingDelegation) appendProto(b []byte) []byte {
+	b = appendProtoString(b, 1, msg.Validator.String())
+	b = appendProtoString(b, 2, msg.Delegator.String())
+	b = appendProtoEmbedded(b, 3, msg.Amount.appendProto(nil))
+	return b
+}
+
+func (msg Coin) appendProto(b []byte) []byte {
+	b = appendProtoString(b, 1, msg.Denom)
+	b = appendProtoVarint(b, 2, msg.Amount)
+	return b
+}
+
+func (msg *Books) appendProto(b []byte) []byte {
+	b = appendProtoVarint(b, 1, msg.Height)
+	b = appendProtoVarint(b, 2, msg.Timestamp)
+	b = appendProtoInt32(b, 3, msg.NumOfMsgs)
+	if msg.NumOfMsgs > 0 {
+		for _, book := range msg.Books {
+			b = appendProtoEmbedded(b, 4, book.appendProto(nil))
+		}
+	}
+	return b
+}
+
+func (msg *OrderBookDelta) appendProto(b []byte) []byte {
+	b = appendProtoString(b, 1, msg.Symbol)
+	for _, buy := range msg.Buys {
+		b = appendProtoEmbedded(b, 2, buy.appendProto(nil))
+	}
+	for _, sell := range msg.Sells {
+		b = appendProtoEmbedded(b, 3, sell.appendProto(nil))
+	}
+	return b
+}
+
+func (msg *PriceLevel) appendProto(b []byte) []byte {
+	b = appendProtoVarint(b, 1, msg.Price)
+	b = appendProtoVarint(b, 2, msg.LastQty)
+	return b
+}
+
+func (msg *Accounts) appendProto(b []byte) []byte {
+	b = appendProtoVarint(b, 1, msg.Height)
+	b = appendProtoInt32(b, 2, msg.NumOfMsgs)
+	if msg.NumOfMsgs > 0 {
+		for _, a := range msg.Accounts {
+			b = appendProtoEmbedded(b, 3, a.appendProto(nil))
+		}
+	}
+	return b
+}
+
+func (msg *Account) appendProto(b []byte) []byte {
+	b = appendProtoString(b, 1, sdk.AccAddress(msg.Owner).String())
+	b = appendProtoString(b, 2, msg.Fee)
+	b = appendProtoVarint(b, 3, msg.Sequence)
+	for _, balance := range msg.Balances {
+		b = appendProtoEmbedded(b, 4, balance.appendProto(nil))
+	}
+	return b
+}
+
+func (msg *AssetBalance) appendProto(b []byte) []byte {
+	b = appendProtoString(b, 1, msg.Asset)
+	b = appendProtoVarint(b, 2, msg.Free)
+	b = appendProtoVarint(b, 3, msg.Frozen)
+	b = appendProtoVarint(b, 4, msg.Locked)
+	return b
+}
@@ -0,0 +1,316 @@
+package pub
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
+)
+
+var updateProto = flag.Bool("update-proto", false, "regenerate the protobuf definitions in pub/proto")
+
+func protoDefinitionPath(tpe msgType) string {
+	return filepath.Join("proto", strings.TrimSuffix(strings.TrimPrefix(schemaSnapshotName(tpe, 0), "schemas/"), "0.avsc")+".proto")
+}
+
+// This test ensures the protobuf definitions are consistent with the avro schemas,
+// run `go test ./app/pub -run TestProtoDefinitions -update-proto` to regenerate them
+func TestProtoDefinitions(t *testing.T) {
+	for tpe, schema := range avroSchemas {
+		codec, err := newProtoCodec(tpe, schema)
+		require.NoError(t, err)
+		bz, err := os.ReadFile(protoDefinitionPath(tpe))
+		if *updateProto && os.IsNotExist(err) {
+			bz, err = nil, nil
+		}
+		require.NoError(t, err)
+		// the consumers decode the fields by their numbers, which must not change once published
+		require.NoError(t, checkProtoNumbers(string(bz), codec), protoDefinitionPath(tpe))
+		if *updateProto {
+			require.NoError(t, os.WriteFile(protoDefinitionPath(tpe), []byte(codec.protoDefinition()), 0644))
+			continue
+		}
+		require.Equal(t, string(bz), codec.protoDefinition(), "%s is outdated", protoDefinitionPath(tpe))
+		// the definition is valid
+		protoMessageDescriptor(t, codec)
+	}
+}
+
+var protoFieldRegexp = regexp.MustCompile(`(\w+) = (\d+);$`)
+
+// checkProtoNumbers checks the fields of the codec keep their numbers in the committed definition,
+// and the numbers of the removed fields are not reused
+func checkProtoNumbers(committed string, codec *protoCodec) error {
+	numbers := make(map[string]map[string]protowire.Number)
+	var message string
+	for _, line := range strings.Split(committed, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "message ") {
+			message = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, "message ")), " {")
+			numbers[message] = make(map[string]protowire.Number)
+		} else if m := protoFieldRegexp.FindStringSubmatch(line); m != nil && message != "" {
+			n, err := strconv.Atoi(m[2])
+			if err != nil {
+				return err
+			}
+			numbers[message][m[1]] = protowire.Number(n)
+		}
+	}
+	for _, msg := range codec.messages {
+		fields := numbers[msg.name]
+		for _, f := range msg.fields {
+			if n, ok := fields[f.name]; ok && n != f.number {
+				return fmt.Errorf("%s.%s is renumbered from %d to %d", msg.name, f.name, n, f.number)
+			}
+			for name, n := range fields {
+				if n == f.number && name != f.name {
+					return fmt.Errorf("%s.%s reuses the number %d of %s", msg.name, f.name, n, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func TestCheckProtoNumbers(t *testing.T) {
+	definition := func(fields string) *protoCodec {
+		codec, err := newProtoCodec(blockFeeTpe, `{"type": "record", "name": "BlockFee", "fields": [`+fields+`]}`)
+		require.NoError(t, err)
+		return codec
+	}
+	committed := definition(`{"name": "height", "type": "long"}, {"name": "fee", "type": "string"}`).protoDefinition()
+
+	// appending a field keeps the numbers
+	require.NoError(t, checkProtoNumbers(committed, definition(`{"name": "height", "type": "long"}, {"name": "fee", "type": "string"}, {"name": "validators", "type": "string"}`)))
+	// inserting a field renumbers the following ones
+	require.EqualError(t, checkProtoNumbers(committed, definition(`{"name": "height", "type": "long"}, {"name": "validators", "type": "string"}, {"name": "fee", "type": "string"}`)),
+		"BlockFee.validators reuses the number 2 of fee")
+	require.EqualError(t, checkProtoNumbers(committed, definition(`{"name": "fee", "type": "string"}, {"name": "height", "type": "long"}`)),
+		"BlockFee.fee is renumbered from 2 to 1")
+	// removing a field frees its number for the next one
+	require.EqualError(t, checkProtoNumbers(committed, definition(`{"name": "height", "type": "long"}, {"name": "validators", "type": "string"}`)),
+		"BlockFee.validators reuses the number 2 of fee")
+}
+
+// protoMessageDescriptor builds the descriptor of the root message, as protoc does from the definition
+func protoMessageDescriptor(t *testing.T, codec *protoCodec) *dynamicpb.Message {
+	pkg := protoPackagePrefix + strings.ToLower(codec.tpe.String())
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String(protoDefinitionPath(codec.tpe)),
+		Package: proto.String(pkg),
+		Syntax:  proto.String("proto3"),
+	}
+	fieldType := func(fd *descriptorpb.FieldDescriptorProto, typ protoType) {
+		if typ.message != nil {
+			fd.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+			fd.TypeName = proto.String("." + pkg + "." + typ.message.name)
+		} else {
+			fd.Type = descriptorpb.FieldDescriptorProto_Type(descriptorpb.FieldDescriptorProto_Type_value["TYPE_"+strings.ToUpper(typ.String())]).Enum()
+		}
+	}
+	for _, msg := range codec.messages {
+		d := &descriptorpb.DescriptorProto{Name: proto.String(msg.name)}
+		for _, f := range msg.fields {
+			fd := &descriptorpb.FieldDescriptorProto{
+				Name:   proto.String(f.name),
+				Number: proto.Int32(int32(f.number)),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			}
+			fieldType(fd, f.typ)
+			if f.repeated {
+				fd.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			}
+			if f.isMap {
+				entryName := strings.ToUpper(f.name[:1]) + f.name[1:] + "Entry"
+				key := &descriptorpb.FieldDescriptorProto{Name: proto.String("key"), Number: proto.Int32(1),
+					Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()}
+				value := &descriptorpb.FieldDescriptorProto{Name: proto.String("value"), Number: proto.Int32(2),
+					Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+				fieldType(value, f.typ)
+				d.NestedType = append(d.NestedType, &descriptorpb.DescriptorProto{
+					Name:    proto.String(entryName),
+					Field:   []*descriptorpb.FieldDescriptorProto{key, value},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				})
+				fd.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+				fd.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+				fd.TypeName = proto.String("." + pkg + "." + msg.name + "." + entryName)
+			}
+			if f.optional {
+				d.OneofDecl = append(d.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + f.name)})
+				fd.OneofIndex = proto.Int32(int32(len(d.OneofDecl) - 1))
+				fd.Proto3Optional = proto.Bool(true)
+			}
+			d.Field = append(d.Field, fd)
+		}
+		file.MessageType = append(file.MessageType, d)
+	}
+	fileDesc, err := protodesc.NewFile(file, nil)
+	require.NoError(t, err)
+	return dynamicpb.NewMessage(fileDesc.Messages().ByName(protoreflect.Name(codec.root.name)))
+}
+
+// protoDecode decodes the protobuf binary into json by the descriptor built from the codec
+func protoDecode(t *testing.T, codec *protoCodec, bz []byte) map[string]interface{} {
+	msg := protoMessageDescriptor(t, codec)
+	require.NoError(t, proto.Unmarshal(bz, msg))
+	js, err := protojson.Marshal(msg)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(js, &decoded))
+	return decoded
+}
+
+func TestProtobufMarshaling(t *testing.T) {
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	publisher.protobufTopics = map[string]bool{Cfg.OrderUpdatesTopic: true, Cfg.StakingTopic: true, Cfg.BlockFeeTopic: true}
+	publisher.schemaIds = map[msgType]int32{executionResultTpe: 1}
+
+	results := ExecutionResults{
+		Height: 42, Timestamp: 100, NumOfMsgs: 2,
+		Trades: trades{NumOfMsgs: 1, Trades: []*Trade{{
+			Id: "42-0", Symbol: "NNB_BNB", Price: 100, Qty: 100, Sid: "s-1", Bid: "b-1", TickType: 1,
+			Sfee: "BNB:8", Bfee: "BNB:10", SSingleFee: "BNB:8", BSingleFee: "BNB:10", SAddr: "s", BAddr: "b"}}},
+		Proposals: Proposals{NumOfMsgs: 1, Proposals: []*Proposal{{1, Failed}}},
+	}
+	bz, err := publisher.marshal(&results, executionResultTpe)
+	require.NoError(t, err)
+	decoded := protoDecode(t, publisher.protoCodecs[executionResultTpe], bz)
+	require.Equal(t, "42", decoded["height"])
+	require.Equal(t, float64(2), decoded["numOfMsgs"])
+	trade := decoded["trades"].(map[string]interface{})["trades"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "42-0", trade["id"])
+	require.Equal(t, "100", trade["price"])
+	require.Equal(t, float64(1), trade["tickType"])
+	// the zero values are omitted
+	require.NotContains(t, trade, "ssrc")
+	require.NotContains(t, decoded, "orders")
+	require.Equal(t, Failed.String(), decoded["proposals"].(map[string]interface{})["proposals"].([]interface{})[0].(map[string]interface{})["status"])
+
+	valAddr := sdk.ValAddress("protobuf-test-valadr")
+	delAddr := sdk.AccAddress("protobuf-test-deladr")
+	staking := StakingMsg{
+		NumOfMsgs: 2, Height: 20, Timestamp: 1000,
+		Validators:        []*Validator{{FeeAddr: delAddr, OperatorAddr: valAddr, Status: 1, DelegatorShares: sdk.NewDecWithoutFra(10000)}},
+		RemovedValidators: map[string][]sdk.ValAddress{"chain-id-1": {valAddr, valAddr}},
+		DelegateEvents:    map[string][]*DelegateEvent{"chain-id-1": {{delAddr, valAddr, Coin{Denom: "BNB", Amount: 99999999}, "0xadkjgege"}}},
+	}
+	bz, err = publisher.marshal(&staking, stakingTpe)
+	require.NoError(t, err)
+	decoded = protoDecode(t, publisher.protoCodecs[stakingTpe], bz)
+	require.Equal(t, valAddr.String(), decoded["validators"].([]interface{})[0].(map[string]interface{})["operatorAddr"])
+	require.Equal(t, []interface{}{valAddr.String(), valAddr.String()},
+		decoded["removedValidators"].(map[string]interface{})["chain-id-1"].(map[string]interface{})["items"])
+	event := decoded["delegateEvents"].(map[string]interface{})["chain-id-1"].(map[string]interface{})["items"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "99999999", event["amount"].(map[string]interface{})["amount"])
+	require.Equal(t, "0xadkjgege", event["txHash"])
+	require.NotContains(t, decoded, "delegations")
+
+	fee := BlockFee{1, "BNB:1000", []string{"bnc1", "bnc2"}}
+	bz, err = publisher.marshal(&fee, blockFeeTpe)
+	require.NoError(t, err)
+	decoded = protoDecode(t, publisher.protoCodecs[blockFeeTpe], bz)
+	require.Equal(t, "BNB:1000", decoded["fee"])
+	require.Len(t, decoded["validators"], 2)
+
+	// the other topics are still encoded in avro
+	publisher.protobufTopics = nil
+	bz, err = publisher.marshal(&results, executionResultTpe)
+	require.NoError(t, err)
+	require.Equal(t, schemaRegistryMagicByte, bz[0])
+}
+
+// This test ensures the msgs encoded directly are encoded the same as from their native maps
+func TestProtoMsgs(t *testing.T) {
+	valAddr := sdk.ValAddress("protobuf-test-valadr")
+	delAddr := sdk.AccAddress("protobuf-test-deladr")
+	for tpe, msgs := range map[msgType][]AvroOrJsonMsg{
+		executionResultTpe: {
+			&ExecutionResults{},
+			&ExecutionResults{Height: 42, Timestamp: 100, NumOfMsgs: 1, Orders: Orders{NumOfMsgs: 1, Orders: []*Order{{}}}},
+			busyExecutionResults(2),
+			&ExecutionResults{Height: 42, Timestamp: 100, NumOfMsgs: 3,
+				Trades:    trades{NumOfMsgs: 1, Trades: []*Trade{{Id: "42-0", SSrc: 1, BSrc: -1, Sfee: "BNB:8", Bfee: "BNB:10"}}},
+				Proposals: Proposals{NumOfMsgs: 1, Proposals: []*Proposal{{1, Failed}}},
+				StakeUpdates: StakeUpdates{NumOfMsgs: 1, CompletedUnbondingDelegations: []*CompletedUnbondingDelegation{
+					{Validator: valAddr, Delegator: delAddr, Amount: Coin{Denom: "BNB", Amount: 100}}}}},
+		},
+		booksTpe: {
+			&Books{},
+			&Books{Height: 42, Timestamp: 100, NumOfMsgs: 2, Books: []OrderBookDelta{
+				{Symbol: "NNB_BNB", Buys: []PriceLevel{{100, 0}, {90, 10}}},
+				{Symbol: "XYZ_BNB", Sells: []PriceLevel{{110, -1}}}}},
+		},
+		accountsTpe: {
+			&Accounts{},
+			&Accounts{Height: 42, NumOfMsgs: 2, Accounts: []Account{
+				{Owner: string(delAddr), Fee: "BNB:8", Sequence: 3, Balances: []*AssetBalance{{"BNB", 100, 10, 1}, {}}},
+				{Owner: string(delAddr)}}},
+		},
+	} {
+		codec, err := newProtoCodec(tpe, avroSchemas[tpe])
+		require.NoError(t, err)
+		for _, msg := range msgs {
+			expected, err := codec.marshal(msg.ToNativeMap())
+			require.NoError(t, err)
+			require.Equal(t, expected, msg.(protoMsg).appendProto(nil), "%s", msg)
+		}
+	}
+}
+
+// busyExecutionResults returns the execution results of a block with the trades each filling two orders
+func busyExecutionResults(numOfTrades int) *ExecutionResults {
+	results := &ExecutionResults{Height: 42, Timestamp: 100, NumOfMsgs: 3 * numOfTrades,
+		Trades: trades{NumOfMsgs: numOfTrades}, Orders: Orders{NumOfMsgs: 2 * numOfTrades}}
+	for i := 0; i < numOfTrades; i++ {
+		results.Trades.Trades = append(results.Trades.Trades, &Trade{
+			Id: fmt.Sprintf("42-%d", i), Symbol: "NNB_BNB", Price: 100, Qty: 100, Sid: fmt.Sprintf("s-%d", i),
+			Bid: fmt.Sprintf("b-%d", i), TickType: 1, SSingleFee: "BNB:8", BSingleFee: "BNB:10", SAddr: "s", BAddr: "b"})
+		for _, id := range []string{fmt.Sprintf("s-%d", i), fmt.Sprintf("b-%d", i)} {
+			results.Orders.Orders = append(results.Orders.Orders, &Order{
+				Symbol: "NNB_BNB", Status: orderPkg.FullyFill, OrderId: id, TradeId: fmt.Sprintf("42-%d", i), Owner: "s",
+				Side: 1, OrderType: 2, Price: 100, Qty: 100, LastExecutedPrice: 100, LastExecutedQty: 100, CumQty: 100,
+				Fee: "BNB:8", OrderCreationTime: 100, TransactionTime: 100, TimeInForce: 1, TxHash: "hash", SingleFee: "BNB:8"})
+		}
+	}
+	return results
+}
+
+// BenchmarkMarshal compares the protobuf encoding with the avro one on the execution results of a busy block
+func BenchmarkMarshal(b *testing.B) {
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	publisher.schemaIds = map[msgType]int32{executionResultTpe: 1}
+	results := busyExecutionResults(1000)
+
+	for _, encoding := range []string{"avro", "protobuf"} {
+		if encoding == "protobuf" {
+			publisher.protobufTopics = map[string]bool{Cfg.OrderUpdatesTopic: true}
+		}
+		b.Run(encoding, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := publisher.marshal(results, executionResultTpe); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 	tmlog "github.com/tendermint/tendermint/libs/log"
 
 	"github.com/bnb-chain/node/app/config"
+	"github.com/bnb-chain/node/app/pub/history"
+	"github.com/bnb-chain/node/app/pub/kline"
 	"github.com/bnb-chain/node/app/pub/sub"
 	orderPkg "github.com/bnb-chain/node/plugins/dex/order"
 )
@@ -34,13 +36,34 @@ var (
 	IsLive            bool
 
 	ToPublishEventCh chan *sub.ToPublishEvent
+
+	Cursor  *PublicationCursor // nil if the sequences and the published heights are not recorded, i.e. in tests
+	Klines  *kline.Aggregator  // nil if the trades are not aggregated into klines
+	History *history.Index     // nil if the orders and the trades are not indexed by address
 )
 
 type MarketDataPublisher interface {
-	publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64)
+	// seq is the sequence of the msg in its topic, 0 if not assigned
+	publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64, seq int64) error
 	Stop()
 }
 
+// publishMsg publishes the msg with the sequence of its height in its topic. The msg is skipped if its height has been
+// published, which happens when tendermint replays the blocks on handshake.
+func publishMsg(publisher MarketDataPublisher, msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64) {
+	var seq int64
+	if Cursor != nil {
+		var ok bool
+		if seq, ok = Cursor.next(tpe, height); !ok {
+			Logger.Debug("skip the published height", "type", tpe.String(), "height", height)
+			return
+		}
+	}
+	if err := publisher.publish(msg, tpe, height, timestamp, seq); err != nil && Cursor != nil {
+		Cursor.fail(tpe)
+	}
+}
+
 func PublishEvent(
 	publisher MarketDataPublisher,
 	Logger tmlog.Logger,
@@ -264,7 +287,7 @@ func PublishEvent(
 				RedelegateEvents:     redelegateEventsMap,
 				ElectedValidators:    electedValidatorsMap,
 			}
-			publisher.publish(&msg, stakingTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+			publishMsg(publisher, &msg, stakingTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 		}
 
 		if cfg.PublishDistributeReward {
@@ -311,7 +334,7 @@ func PublishEvent(
 				Timestamp:     toPublish.Timestamp.Unix(),
 				Distributions: distributions,
 			}
-			publisher.publish(&distributionMsg, distributionTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+			publishMsg(publisher, &distributionMsg, distributionTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 		}
 
 		if cfg.PublishSlashing {
@@ -350,7 +373,7 @@ func PublishEvent(
 				Timestamp: toPublish.Timestamp.Unix(),
 				SlashData: slashData,
 			}
-			publisher.publish(&slashMsg, slashingTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+			publishMsg(publisher, &slashMsg, slashingTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 
 		}
 
@@ -384,7 +407,7 @@ func PublishEvent(
 				Timestamp: toPublish.Timestamp.Unix(),
 				Transfers: crossTransfers,
 			}
-			publisher.publish(&crossTransferMsg, crossTransferTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+			publishMsg(publisher, &crossTransferMsg, crossTransferTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 		}
 
 		if cfg.PublishMirror {
@@ -418,7 +441,7 @@ func PublishEvent(
 				Timestamp: toPublish.Timestamp.Unix(),
 				Mirrors:   mirrors,
 			}
-			publisher.publish(&mirrorsMsg, mirrorTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+			publishMsg(publisher, &mirrorsMsg, mirrorTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 
 		}
 
@@ -427,7 +450,11 @@ func PublishEvent(
 				Height:    toPublish.Height,
 				Timestamp: toPublish.Timestamp.UnixNano(),
 			}
-			publisher.publish(&breatheBlockMsg, breatheBlockTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+			publishMsg(publisher, &breatheBlockMsg, breatheBlockTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+		}
+
+		if Cursor != nil {
+			Cursor.advance(toPublish.Height, eventMsgTypes)
 		}
 	}
 }
@@ -561,6 +588,18 @@ func Publish(
 				}
 			}
 
+			if Klines != nil {
+				Timer(Logger, "aggregate klines", func() {
+					aggregateKlines(marketData.height, marketData.timestamp, marketData.tradesToPublish)
+				})
+			}
+
+			if History != nil {
+				Timer(Logger, "index order and trade history", func() {
+					indexHistory(marketData.height, marketData.timestamp, ordersToPublish, marketData.tradesToPublish)
+				})
+			}
+
 			if metrics != nil {
 				metrics.PublicationHeight.Set(float64(marketData.height))
 				blockInterval := time.Since(lastPublishedTime)
@@ -572,6 +611,9 @@ func Publish(
 		if metrics != nil {
 			metrics.PublishTotalTimeMs.Set(float64(publishTotalTime))
 		}
+		if Cursor != nil {
+			Cursor.advance(marketData.height, blockMsgTypes)
+		}
 	}
 }
 
@@ -586,6 +628,74 @@ func addClosedOrder(closedToPublish []*Order, toRemoveOrderIdCh chan OrderSymbol
 	}
 }
 
+// aggregateKlines aggregates the trades into Klines, `timestamp` is the block time in nanoseconds
+func aggregateKlines(height int64, timestamp int64, tradesToPublish []*Trade) {
+	trades := make([]kline.Trade, len(tradesToPublish))
+	for i, t := range tradesToPublish {
+		trades[i] = kline.Trade{Symbol: t.Symbol, Price: t.Price, Qty: t.Qty}
+	}
+	if err := Klines.AddTrades(height, timestamp/int64(time.Millisecond), trades); err != nil {
+		Logger.Error("failed to aggregate klines", "height", height, "err", err)
+	}
+}
+
+// indexHistory indexes the orders and the trades into History, the addresses of the trades are assigned by
+// collectOrdersToPublish
+func indexHistory(height int64, timestamp int64, ordersToPublish []*Order, tradesToPublish []*Trade) {
+	orders := make([]history.Order, len(ordersToPublish))
+	for i, o := range ordersToPublish {
+		orders[i] = history.Order{
+			OrderId:      o.OrderId,
+			Symbol:       o.Symbol,
+			Owner:        o.Owner,
+			Side:         o.Side,
+			OrderType:    o.OrderType,
+			TimeInForce:  o.TimeInForce,
+			Price:        o.Price,
+			Qty:          o.Qty,
+			CumQty:       o.CumQty,
+			Status:       o.Status.String(),
+			CreationTime: o.OrderCreationTime,
+			UpdateTime:   o.TransactionTime,
+			TxHash:       o.TxHash,
+			Updates: []history.OrderUpdate{{
+				Height:        height,
+				Time:          o.TransactionTime,
+				Status:        o.Status.String(),
+				TradeId:       o.TradeId,
+				ExecutedPrice: o.LastExecutedPrice,
+				ExecutedQty:   o.LastExecutedQty,
+				Fee:           o.SingleFee,
+			}},
+		}
+	}
+	trades := make([]history.Trade, len(tradesToPublish))
+	for i, t := range tradesToPublish {
+		trades[i] = history.Trade{
+			TradeId:       t.Id,
+			Symbol:        t.Symbol,
+			Height:        height,
+			Time:          timestamp,
+			Price:         t.Price,
+			Qty:           t.Qty,
+			BuyerOrderId:  t.Bid,
+			SellerOrderId: t.Sid,
+			BuyerFee:      t.BSingleFee,
+			SellerFee:     t.SSingleFee,
+			TickType:      t.TickType,
+		}
+		if t.BAddr != "" {
+			trades[i].Buyer = sdk.AccAddress(t.BAddr).String()
+		}
+		if t.SAddr != "" {
+			trades[i].Seller = sdk.AccAddress(t.SAddr).String()
+		}
+	}
+	if err := History.AddBlock(height, orders, trades); err != nil {
+		Logger.Error("failed to index order and trade history", "height", height, "err", err)
+	}
+}
+
 func Stop(publisher MarketDataPublisher) {
 	if !IsLive {
 		Logger.Error("publication module has already been stopped")
@@ -621,7 +731,7 @@ func publishExecutionResult(publisher MarketDataPublisher, height int64, timesta
 		executionResultsMsg.StakeUpdates = *stakeUpdates
 	}
 
-	publisher.publish(&executionResultsMsg, executionResultTpe, height, timestamp)
+	publishMsg(publisher, &executionResultsMsg, executionResultTpe, height, timestamp)
 }
 
 func publishAccount(publisher MarketDataPublisher, height int64, timestamp int64, accountsToPublish map[string]Account, feeToPublish map[string]string) {
@@ -638,7 +748,7 @@ func publishAccount(publisher MarketDataPublisher, height int64, timestamp int64
 	}
 	accountsMsg := Accounts{height, numOfMsgs, accs}
 
-	publisher.publish(&accountsMsg, accountsTpe, height, timestamp)
+	publishMsg(publisher, &accountsMsg, accountsTpe, height, timestamp)
 }
 
 func publishOrderBookDelta(publisher MarketDataPublisher, height int64, timestamp int64, changedPriceLevels orderPkg.ChangedPriceLevelsMap) {
@@ -661,16 +771,16 @@ func publishOrderBookDelta(publisher MarketDataPublisher, height int64, timestam
 
 	books := Books{height, timestamp, len(deltas), deltas}
 
-	publisher.publish(&books, booksTpe, height, timestamp)
+	publishMsg(publisher, &books, booksTpe, height, timestamp)
 }
 
 func publishBlockFee(publisher MarketDataPublisher, height, timestamp int64, blockFee BlockFee) {
-	publisher.publish(blockFee, blockFeeTpe, height, timestamp)
+	publishMsg(publisher, blockFee, blockFeeTpe, height, timestamp)
 }
 
 func publishTransfers(publisher MarketDataPublisher, height, timestamp int64, transfers *Transfers) {
 	if transfers != nil {
-		publisher.publish(transfers, transferTpe, height, timestamp)
+		publishMsg(publisher, transfers, transferTpe, height, timestamp)
 	}
 }
 
@@ -678,13 +788,13 @@ func publishSideProposals(publisher MarketDataPublisher, height, timestamp int64
 	if sideProposals != nil {
 		sideProposals.Height = height
 		sideProposals.Timestamp = timestamp
-		publisher.publish(sideProposals, sideProposalType, height, timestamp)
+		publishMsg(publisher, sideProposals, sideProposalType, height, timestamp)
 	}
 }
 
 func publishBlock(publisher MarketDataPublisher, height, timestamp int64, block *Block) {
 	if block != nil {
-		publisher.publish(block, blockTpe, height, timestamp)
+		publishMsg(publisher, block, blockTpe, height, timestamp)
 	}
 }
 
@@ -4,10 +4,14 @@ type AggregatedMarketDataPublisher struct {
 	publishers []MarketDataPublisher
 }
 
-func (publisher *AggregatedMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64) {
+// publish returns the first error of the publishers, the msg is published by all the publishers regardless
+func (publisher *AggregatedMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64, seq int64) (err error) {
 	for _, pub := range publisher.publishers {
-		pub.publish(msg, tpe, height, timestamp)
+		if e := pub.publish(msg, tpe, height, timestamp, seq); e != nil && err == nil {
+			err = e
+		}
 	}
+	return
 }
 
 func (publisher *AggregatedMarketDataPublisher) Stop() {
@@ -0,0 +1,326 @@
+package pub
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkedin/goavro"
+
+	tmLogger "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/app/config"
+)
+
+const (
+	HttpEncodingJson = "json"
+	HttpEncodingAvro = "avro"
+
+	HttpEndpointSep = ";"
+
+	// headers of the requests posted by HttpMarketDataPublisher
+	HttpHeaderMsgType       = "X-Msg-Type"
+	HttpHeaderSchemaVersion = "X-Schema-Version"
+	HttpHeaderHeight        = "X-Height"
+	HttpHeaderNumOfMsgs     = "X-Num-Of-Msgs"
+	HttpHeaderSequence      = "X-Sequence"  // sequence of the first msg, the msgs of a batch have consecutive sequences
+	HttpHeaderTimestamp     = "X-Timestamp" // unix milliseconds when the request is sent
+	HttpHeaderSignature     = "X-Signature"
+
+	// the signed requests whose timestamps are further away from the time they are received are rejected
+	HttpSignatureMaxAge = 5 * time.Minute
+
+	httpContentTypeJson = "application/json"
+	httpContentTypeAvro = "avro/binary"
+
+	httpQueueSize = 64
+)
+
+type httpBatch struct {
+	tpe    msgType
+	msgs   []AvroOrJsonMsg
+	height int64
+	seq    int64
+}
+
+// Publish market data by POSTing them to http endpoints, for the services which can not run a kafka cluster.
+// The messages of a type are posted in batches, as a json array or an avro object container file,
+// with the type in the X-Msg-Type header. If a secret is configured, the X-Signature header carries
+// the hex encoded HMAC-SHA256 of the X-Timestamp header and the body (see SignHttpBody).
+// The batches are posted in order by a worker, and publish blocks once the queue of the worker is full. The heights
+// of the batches are held in the Cursor until they are posted, and the failed ones are republished on restart.
+type HttpMarketDataPublisher struct {
+	endpoints     []string
+	encoding      string
+	secret        []byte
+	batchSize     int
+	maxRetries    int
+	retryInterval time.Duration
+	client        *http.Client
+	codecs        map[msgType]*goavro.Codec
+
+	mtx     sync.Mutex // guards batches, and keeps the batches of a type queued in order
+	batches map[msgType]*httpBatch
+	queue   chan *httpBatch
+	stopped chan struct{} // closed once the queue is drained after Stop
+	ticker  *time.Ticker
+	done    chan struct{}
+
+	tmLogger tmLogger.Logger
+}
+
+func (publisher *HttpMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64, seq int64) error {
+	publisher.mtx.Lock()
+	defer publisher.mtx.Unlock()
+
+	batch, ok := publisher.batches[tpe]
+	if !ok {
+		batch = &httpBatch{tpe: tpe, msgs: make([]AvroOrJsonMsg, 0, publisher.batchSize), seq: seq}
+		publisher.batches[tpe] = batch
+		if Cursor != nil {
+			Cursor.hold(tpe, height)
+		}
+	}
+	batch.msgs = append(batch.msgs, msg)
+	batch.height = height
+	if len(batch.msgs) >= publisher.batchSize {
+		publisher.enqueue(batch)
+	}
+	return nil
+}
+
+// flushAll queues the batches which are not full yet, so that no message waits longer than the batch interval
+func (publisher *HttpMarketDataPublisher) flushAll() {
+	publisher.mtx.Lock()
+	defer publisher.mtx.Unlock()
+	for _, batch := range publisher.batches {
+		publisher.enqueue(batch)
+	}
+}
+
+// enqueue hands the batch over to the worker, the next msg of the type starts a new batch
+func (publisher *HttpMarketDataPublisher) enqueue(batch *httpBatch) {
+	delete(publisher.batches, batch.tpe)
+	publisher.queue <- batch
+}
+
+// postLoop posts the queued batches until the queue is closed, and releases their heights in the Cursor
+func (publisher *HttpMarketDataPublisher) postLoop() {
+	defer close(publisher.stopped)
+	for batch := range publisher.queue {
+		err := publisher.flush(batch)
+		if Cursor != nil {
+			Cursor.release(batch.tpe, err)
+		}
+	}
+}
+
+// flush returns the first error of the endpoints, the batch is posted to all the endpoints regardless
+func (publisher *HttpMarketDataPublisher) flush(batch *httpBatch) (err error) {
+	tpe := batch.tpe
+	body, err := publisher.marshal(batch.msgs, tpe)
+	if err != nil {
+		publisher.tmLogger.Error("failed to marshal msgs", "type", tpe.String(), "height", batch.height, "err", err)
+		return err
+	}
+	for _, endpoint := range publisher.endpoints {
+		if e := publisher.postWithRetry(endpoint, body, tpe, batch); e != nil {
+			publisher.tmLogger.Error("failed to publish msgs", "endpoint", endpoint, "type", tpe.String(),
+				"height", batch.height, "numOfMsgs", len(batch.msgs), "err", e)
+			if err == nil {
+				err = e
+			}
+		} else {
+			publisher.tmLogger.Debug("published", "endpoint", endpoint, "type", tpe.String(),
+				"height", batch.height, "numOfMsgs", len(batch.msgs))
+		}
+	}
+	return
+}
+
+func (publisher *HttpMarketDataPublisher) marshal(msgs []AvroOrJsonMsg, tpe msgType) ([]byte, error) {
+	if publisher.encoding != HttpEncodingAvro {
+		return json.Marshal(msgs)
+	}
+	codec, ok := publisher.codecs[tpe]
+	if !ok {
+		return nil, fmt.Errorf("doesn't support marshal avro msg tpe: %s", tpe.String())
+	}
+	var buf bytes.Buffer
+	writer, err := goavro.NewOCFWriter(goavro.OCFConfig{W: &buf, Codec: codec})
+	if err != nil {
+		return nil, err
+	}
+	natives := make([]interface{}, len(msgs))
+	for i, msg := range msgs {
+		natives[i] = msg.ToNativeMap()
+	}
+	if err := writer.Append(natives); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// postWithRetry retries on the connection errors and the 5xx responses with an exponential backoff,
+// the other responses are not retried as posting the same body again would not help
+func (publisher *HttpMarketDataPublisher) postWithRetry(endpoint string, body []byte, tpe msgType, batch *httpBatch) (err error) {
+	backOff := publisher.retryInterval
+	for retry := 0; ; retry++ {
+		var retryable bool
+		if retryable, err = publisher.post(endpoint, body, tpe, batch); err == nil || !retryable || retry >= publisher.maxRetries {
+			return
+		}
+		publisher.tmLogger.Error("encountered retryable error, retrying...", "endpoint", endpoint, "after", backOff, "err", err)
+		time.Sleep(backOff)
+		backOff <<= 1
+	}
+}
+
+func (publisher *HttpMarketDataPublisher) post(endpoint string, body []byte, tpe msgType, batch *httpBatch) (retryable bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	if publisher.encoding == HttpEncodingAvro {
+		req.Header.Set("Content-Type", httpContentTypeAvro)
+	} else {
+		req.Header.Set("Content-Type", httpContentTypeJson)
+	}
+	req.Header.Set(HttpHeaderMsgType, tpe.String())
+	req.Header.Set(HttpHeaderSchemaVersion, strconv.Itoa(latestSchemaVersions[tpe]))
+	req.Header.Set(HttpHeaderHeight, strconv.FormatInt(batch.height, 10))
+	req.Header.Set(HttpHeaderNumOfMsgs, strconv.Itoa(len(batch.msgs)))
+	if batch.seq != 0 {
+		req.Header.Set(HttpHeaderSequence, strconv.FormatInt(batch.seq, 10))
+	}
+	timestamp := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+	req.Header.Set(HttpHeaderTimestamp, timestamp)
+	if len(publisher.secret) != 0 {
+		req.Header.Set(HttpHeaderSignature, SignHttpBody(publisher.secret, timestamp, body))
+	}
+
+	resp, err := publisher.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	return resp.StatusCode >= http.StatusInternalServerError, fmt.Errorf("unexpected response status: %s", resp.Status)
+}
+
+// SignHttpBody returns the hex encoded HMAC-SHA256 of the timestamp and the body joined by ".",
+// which the receivers can use to verify the X-Signature header (see VerifyHttpSignature)
+func SignHttpBody(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyHttpSignature verifies the X-Signature header of a request posted by HttpMarketDataPublisher, the requests
+// whose X-Timestamp header is more than HttpSignatureMaxAge away from `now` are rejected as stale
+func VerifyHttpSignature(secret []byte, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get(HttpHeaderTimestamp)
+	millis, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp: %q", timestamp)
+	}
+	if age := now.Sub(time.Unix(0, millis*int64(time.Millisecond))); age > HttpSignatureMaxAge || age < -HttpSignatureMaxAge {
+		return fmt.Errorf("stale timestamp: %s", timestamp)
+	}
+	if !hmac.Equal([]byte(header.Get(HttpHeaderSignature)), []byte(SignHttpBody(secret, timestamp, body))) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// Stop posts the pending batches and waits for them
+func (publisher *HttpMarketDataPublisher) Stop() {
+	publisher.tmLogger.Debug("start to stop HttpMarketDataPublisher")
+	if publisher.ticker != nil {
+		publisher.ticker.Stop()
+		close(publisher.done)
+	}
+	publisher.flushAll()
+	close(publisher.queue)
+	<-publisher.stopped
+	publisher.tmLogger.Info("http publisher stopped")
+}
+
+func NewHttpMarketDataPublisher(
+	tmLogger tmLogger.Logger,
+	config *config.PublicationConfig) (publisher *HttpMarketDataPublisher) {
+	endpoints := make([]string, 0)
+	for _, endpoint := range strings.Split(config.HttpEndpoints, HttpEndpointSep) {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	if len(endpoints) == 0 {
+		panic(fmt.Errorf("httpEndpoints should not be empty when publishHttp is enabled"))
+	}
+	encoding := strings.ToLower(config.HttpEncoding)
+	if encoding != HttpEncodingJson && encoding != HttpEncodingAvro {
+		panic(fmt.Errorf("httpEncoding should be %s or %s: %s", HttpEncodingJson, HttpEncodingAvro, config.HttpEncoding))
+	}
+	batchSize := config.HttpBatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	publisher = &HttpMarketDataPublisher{
+		endpoints:     endpoints,
+		encoding:      encoding,
+		secret:        []byte(config.HttpSecret),
+		batchSize:     batchSize,
+		maxRetries:    config.HttpMaxRetries,
+		retryInterval: time.Duration(config.HttpRetryInterval) * time.Millisecond,
+		client:        &http.Client{Timeout: time.Duration(config.HttpTimeout) * time.Millisecond},
+		codecs:        make(map[msgType]*goavro.Codec),
+		batches:       make(map[msgType]*httpBatch),
+		queue:         make(chan *httpBatch, httpQueueSize),
+		stopped:       make(chan struct{}),
+		tmLogger:      tmLogger,
+	}
+	go publisher.postLoop()
+	if encoding == HttpEncodingAvro {
+		for tpe, schema := range avroSchemas {
+			codec, err := goavro.NewCodec(schema)
+			if err != nil {
+				tmLogger.Error("failed to initialize avro codec", "type", tpe.String(), "err", err)
+				panic(err)
+			}
+			publisher.codecs[tpe] = codec
+		}
+	}
+	if batchSize > 1 && config.HttpBatchInterval > 0 {
+		publisher.ticker = time.NewTicker(time.Duration(config.HttpBatchInterval) * time.Millisecond)
+		publisher.done = make(chan struct{})
+		go func() {
+			for {
+				select {
+				case <-publisher.ticker.C:
+					publisher.flushAll()
+				case <-publisher.done:
+					return
+				}
+			}
+		}()
+	}
+
+	tmLogger.Info("created http publisher", "endpoints", endpoints, "encoding", encoding, "batchSize", batchSize)
+	return publisher
+}
@@ -0,0 +1,186 @@
+package pub
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/app/config"
+)
+
+type httpRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newHttpTestServer(failures int) (*httptest.Server, func() []httpRequest) {
+	var mtx sync.Mutex
+	requests := make([]httpRequest, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, httpRequest{r.Header, body})
+	}))
+	return server, func() []httpRequest {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]httpRequest{}, requests...)
+	}
+}
+
+func newHttpTestConfig(endpoint string) *config.PublicationConfig {
+	return &config.PublicationConfig{
+		PublishHttp:    true,
+		HttpEndpoints:  endpoint,
+		HttpEncoding:   HttpEncodingJson,
+		HttpBatchSize:  1,
+		HttpMaxRetries: 3,
+		HttpTimeout:    1000,
+	}
+}
+
+func TestHttpPublisher_JsonBatch(t *testing.T) {
+	server, requests := newHttpTestServer(0)
+	defer server.Close()
+	cfg := newHttpTestConfig(server.URL)
+	cfg.HttpBatchSize = 2
+	cfg.HttpSecret = "secret"
+	publisher := NewHttpMarketDataPublisher(Logger, cfg)
+
+	require.NoError(t, publisher.publish(BlockFee{Height: 1, Fee: "BNB:100"}, blockFeeTpe, 1, 1, 1))
+	require.Len(t, requests(), 0)
+	require.NoError(t, publisher.publish(BlockFee{Height: 2, Fee: "BNB:200"}, blockFeeTpe, 2, 2, 2))
+	require.NoError(t, publisher.publish(BlockFee{Height: 3, Fee: "BNB:300"}, blockFeeTpe, 3, 3, 3))
+	// the pending message is posted on stop
+	publisher.Stop()
+
+	reqs := requests()
+	require.Len(t, reqs, 2)
+	require.Equal(t, msgType(blockFeeTpe).String(), reqs[0].header.Get(HttpHeaderMsgType))
+	require.Equal(t, "2", reqs[0].header.Get(HttpHeaderHeight))
+	require.Equal(t, "2", reqs[0].header.Get(HttpHeaderNumOfMsgs))
+	require.Equal(t, "1", reqs[1].header.Get(HttpHeaderNumOfMsgs))
+	require.Equal(t, "1", reqs[0].header.Get(HttpHeaderSequence))
+	require.Equal(t, "3", reqs[1].header.Get(HttpHeaderSequence))
+	require.Equal(t, SignHttpBody([]byte("secret"), reqs[0].header.Get(HttpHeaderTimestamp), reqs[0].body), reqs[0].header.Get(HttpHeaderSignature))
+	require.NoError(t, VerifyHttpSignature([]byte("secret"), reqs[0].header, reqs[0].body, time.Now()))
+
+	var fees []BlockFee
+	require.NoError(t, json.Unmarshal(reqs[0].body, &fees))
+	require.Equal(t, []BlockFee{{Height: 1, Fee: "BNB:100", Validators: []string{}}, {Height: 2, Fee: "BNB:200", Validators: []string{}}}, fees)
+}
+
+func TestHttpPublisher_Retry(t *testing.T) {
+	defer func() { Cursor = nil }()
+	server, requests := newHttpTestServer(2)
+	defer server.Close()
+	cfg := newHttpTestConfig(server.URL)
+	cfg.PublishBlockFee = true
+	Cursor = NewPublicationCursor(dbm.NewMemDB(), cfg)
+	publisher := NewHttpMarketDataPublisher(Logger, cfg)
+
+	require.NoError(t, publisher.publish(BlockFee{Height: 1, Fee: "BNB:100"}, blockFeeTpe, 1, 1, 0))
+	publisher.Stop()
+	require.Len(t, requests(), 1)
+	require.Empty(t, requests()[0].header.Get(HttpHeaderSignature))
+	require.Empty(t, requests()[0].header.Get(HttpHeaderSequence))
+	Cursor.advance(1, []msgType{blockFeeTpe})
+	require.Equal(t, int64(1), Cursor.Height(blockFeeTpe))
+
+	// gives up after the max retries, the height is not recorded
+	server, requests = newHttpTestServer(4)
+	defer server.Close()
+	cfg = newHttpTestConfig(server.URL)
+	cfg.PublishBlockFee = true
+	Cursor = NewPublicationCursor(dbm.NewMemDB(), cfg)
+	publisher = NewHttpMarketDataPublisher(Logger, cfg)
+	require.NoError(t, publisher.publish(BlockFee{Height: 1, Fee: "BNB:100"}, blockFeeTpe, 1, 1, 0))
+	publisher.Stop()
+	require.Len(t, requests(), 0)
+	Cursor.advance(1, []msgType{blockFeeTpe})
+	require.Equal(t, int64(0), Cursor.Height(blockFeeTpe))
+}
+
+func TestHttpPublisher_HoldCursor(t *testing.T) {
+	defer func() { Cursor = nil }()
+	server, requests := newHttpTestServer(0)
+	defer server.Close()
+	cfg := newHttpTestConfig(server.URL)
+	cfg.HttpBatchSize = 2
+	cfg.PublishBlockFee = true
+	Cursor = NewPublicationCursor(dbm.NewMemDB(), cfg)
+	publisher := NewHttpMarketDataPublisher(Logger, cfg)
+
+	// the heights waiting in the batch are not recorded as published
+	require.NoError(t, publisher.publish(BlockFee{Height: 1, Fee: "BNB:100"}, blockFeeTpe, 1, 1, 1))
+	Cursor.advance(1, []msgType{blockFeeTpe})
+	require.Equal(t, int64(0), Cursor.Height(blockFeeTpe))
+	require.NoError(t, publisher.publish(BlockFee{Height: 2, Fee: "BNB:200"}, blockFeeTpe, 2, 2, 2))
+	require.NoError(t, publisher.publish(BlockFee{Height: 3, Fee: "BNB:300"}, blockFeeTpe, 3, 3, 3))
+	require.Eventually(t, func() bool { return len(requests()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		Cursor.advance(3, []msgType{blockFeeTpe})
+		return Cursor.Height(blockFeeTpe) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	publisher.Stop()
+	Cursor.advance(3, []msgType{blockFeeTpe})
+	require.Equal(t, int64(3), Cursor.Height(blockFeeTpe))
+}
+
+func TestVerifyHttpSignature(t *testing.T) {
+	secret, body := []byte("secret"), []byte(`[{"Height":1}]`)
+	now := time.Now()
+	header := http.Header{}
+	require.Error(t, VerifyHttpSignature(secret, header, body, now))
+	timestamp := strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10)
+	header.Set(HttpHeaderTimestamp, timestamp)
+	header.Set(HttpHeaderSignature, SignHttpBody(secret, timestamp, body))
+	require.NoError(t, VerifyHttpSignature(secret, header, body, now))
+	require.Error(t, VerifyHttpSignature([]byte("other"), header, body, now))
+	require.Error(t, VerifyHttpSignature(secret, header, []byte(`[{"Height":2}]`), now))
+	require.Error(t, VerifyHttpSignature(secret, header, body, now.Add(HttpSignatureMaxAge+time.Second)))
+	// the signature covers the timestamp
+	header.Set(HttpHeaderTimestamp, strconv.FormatInt(now.UnixNano()/int64(time.Millisecond)+1, 10))
+	require.Error(t, VerifyHttpSignature(secret, header, body, now))
+}
+
+func TestHttpPublisher_Avro(t *testing.T) {
+	server, requests := newHttpTestServer(0)
+	defer server.Close()
+	cfg := newHttpTestConfig(server.URL)
+	cfg.HttpEncoding = HttpEncodingAvro
+	publisher := NewHttpMarketDataPublisher(Logger, cfg)
+
+	require.NoError(t, publisher.publish(BlockFee{Height: 1, Fee: "BNB:100", Validators: []string{"val"}}, blockFeeTpe, 1, 1, 0))
+	publisher.Stop()
+	reqs := requests()
+	require.Len(t, reqs, 1)
+	require.Equal(t, httpContentTypeAvro, reqs[0].header.Get("Content-Type"))
+
+	reader, err := goavro.NewOCFReader(bytes.NewReader(reqs[0].body))
+	require.NoError(t, err)
+	require.True(t, reader.Scan())
+	native, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), native.(map[string]interface{})["height"])
+	require.Equal(t, "BNB:100", native.(map[string]interface{})["fee"])
+	require.False(t, reader.Scan())
+}
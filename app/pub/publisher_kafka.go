@@ -21,6 +21,9 @@ import (
 const (
 	KafkaBrokerSep  = ";"
 	essentialLogDir = "essential"
+
+	// header of the kafka message carrying the sequence of the message in its topic
+	KafkaHeaderSequence = "seq"
 )
 
 type KafkaMarketDataPublisher struct {
@@ -38,7 +41,13 @@ type KafkaMarketDataPublisher struct {
 	sideProposalCodec     *goavro.Codec
 	breatheBlockCodec     *goavro.Codec
 
+	schemaIds map[msgType]int32 // the ids of the schemas in the schema registry, nil if not registered
+
+	protoCodecs    map[msgType]*protoCodec
+	protobufTopics map[string]bool // the topics whose messages are encoded in protobuf
+
 	failFast         bool
+	recordHeaders    bool                           // whether the kafka version supports record headers, which carry the sequences
 	essentialLogPath string                         // the path (default to db dir) we write essential file to make up data on kafka error
 	producers        map[string]sarama.SyncProducer // topic -> producer
 }
@@ -61,6 +70,7 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 
 	config = sarama.NewConfig()
 	config.Version = version
+	publisher.recordHeaders = version.IsAtLeast(sarama.V0_11_0_0)
 	if config.ClientID, err = os.Hostname(); err != nil {
 		return
 	}
@@ -222,11 +232,13 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 // 2. timestamp of message
 // 3. type of value (multiple types of messages can be published for one kafka topic)
 // 4. value's encoding schema version.
+// The sequence of the message in its topic is in the "seq" header, if assigned.
 func (publisher *KafkaMarketDataPublisher) prepareMessage(
 	topic string,
 	msgId string,
 	timeStamp int64,
 	msgTpe msgType,
+	seq int64,
 	message []byte) *sarama.ProducerMessage {
 	msg := &sarama.ProducerMessage{
 		Topic:     topic,
@@ -234,35 +246,42 @@ func (publisher *KafkaMarketDataPublisher) prepareMessage(
 		Key:       sarama.StringEncoder(fmt.Sprintf("%s_%d_%s_%d", msgId, timeStamp, msgTpe.String(), latestSchemaVersions[msgTpe])),
 		Value:     sarama.ByteEncoder(message),
 	}
+	if seq != 0 && publisher.recordHeaders {
+		msg.Headers = []sarama.RecordHeader{{Key: []byte(KafkaHeaderSequence), Value: []byte(strconv.FormatInt(seq, 10))}}
+	}
 
 	return msg
 }
 
-func (publisher *KafkaMarketDataPublisher) publish(avroMessage AvroOrJsonMsg, tpe msgType, height, timestamp int64) {
+func (publisher *KafkaMarketDataPublisher) publish(avroMessage AvroOrJsonMsg, tpe msgType, height, timestamp, seq int64) error {
 	topic := publisher.resolveTopic(tpe)
 
-	if msg, err := publisher.marshal(avroMessage, tpe); err == nil {
-		kafkaMsg := publisher.prepareMessage(topic, strconv.FormatInt(height, 10), timestamp, tpe, msg)
-		if partition, offset, err := publisher.publishWithRetry(kafkaMsg, topic); err == nil {
-			Logger.Info("published", "topic", topic, "msg", avroMessage.String(), "offset", offset, "partition", partition)
-		} else {
-			Logger.Error("failed to publish, tring to log essential message", "topic", topic, "msg", avroMessage.String(), "err", err)
-			if essMsg, ok := avroMessage.(EssMsg); ok {
-				publisher.publishEssentialMsg(essMsg, topic, tpe, height, timestamp)
-			}
-			if publisher.failFast {
-				panic(fmt.Sprintf("publish kafka message failed %v", err))
-			}
-		}
-	} else {
+	msg, err := publisher.marshal(avroMessage, tpe)
+	if err != nil {
 		Logger.Error("failed to publish", "topic", topic, "msg", avroMessage.String(), "err", err)
+		return err
 	}
+	kafkaMsg := publisher.prepareMessage(topic, strconv.FormatInt(height, 10), timestamp, tpe, seq, msg)
+	partition, offset, err := publisher.publishWithRetry(kafkaMsg, topic)
+	if err != nil {
+		Logger.Error("failed to publish, tring to log essential message", "topic", topic, "msg", avroMessage.String(), "err", err)
+		if essMsg, ok := avroMessage.(EssMsg); ok {
+			publisher.publishEssentialMsg(essMsg, topic, tpe, height, timestamp)
+		}
+		if publisher.failFast {
+			panic(fmt.Sprintf("publish kafka message failed %v", err))
+		}
+		return err
+	}
+	Logger.Info("published", "topic", topic, "msg", avroMessage.String(), "offset", offset, "partition", partition, "seq", seq)
+	return nil
 }
 
 func (publisher KafkaMarketDataPublisher) publishEssentialMsg(essMsg EssMsg, topic string, tpe msgType, height, timestamp int64) {
-	// First, publish an empty copy to make sure downstream service not hanging
+	// First, publish an empty copy to make sure downstream service not hanging.
+	// It carries no sequence, so that the consumers still see the gap of the sequences
 	if msg, err := publisher.marshal(essMsg.EmptyCopy(), tpe); err == nil {
-		kafkaMsg := publisher.prepareMessage(topic, strconv.FormatInt(height, 10), timestamp, tpe, msg)
+		kafkaMsg := publisher.prepareMessage(topic, strconv.FormatInt(height, 10), timestamp, tpe, 0, msg)
 		if partition, offset, err := publisher.publishWithRetry(kafkaMsg, topic); err == nil {
 			// deliberately be Error level to trigger logging service elastic search alert
 			Logger.Error("published empty msg", "topic", topic, "msg", essMsg.String(), "offset", offset, "partition", partition)
@@ -374,7 +393,20 @@ func (publisher *KafkaMarketDataPublisher) isRetryableErr(err error) bool {
 }
 
 func (publisher *KafkaMarketDataPublisher) marshal(msg AvroOrJsonMsg, tpe msgType) ([]byte, error) {
-	native := msg.ToNativeMap()
+	if publisher.protobufTopics[publisher.resolveTopic(tpe)] {
+		if m, ok := msg.(protoMsg); ok {
+			return m.appendProto(nil), nil
+		}
+		protoCodec, ok := publisher.protoCodecs[tpe]
+		if !ok {
+			return nil, fmt.Errorf("doesn't support marshal kafka msg tpe: %s", tpe.String())
+		}
+		bb, err := protoCodec.marshal(msg.ToNativeMap())
+		if err != nil {
+			Logger.Error("failed to serialize message", "msg", msg, "err", err)
+		}
+		return bb, err
+	}
 	var codec *goavro.Codec
 	switch tpe {
 	case accountsTpe:
@@ -406,11 +438,15 @@ func (publisher *KafkaMarketDataPublisher) marshal(msg AvroOrJsonMsg, tpe msgTyp
 	default:
 		return nil, fmt.Errorf("doesn't support marshal kafka msg tpe: %s", tpe.String())
 	}
-	bb, err := codec.BinaryFromNative(nil, native)
+	bb, err := codec.BinaryFromNative(nil, msg.ToNativeMap())
 	if err != nil {
 		Logger.Error("failed to serialize message", "msg", msg, "err", err)
+		return bb, err
+	}
+	if id, ok := publisher.schemaIds[tpe]; ok {
+		bb = withSchemaId(id, bb)
 	}
-	return bb, err
+	return bb, nil
 }
 
 func (publisher *KafkaMarketDataPublisher) initAvroCodecs() (err error) {
@@ -444,6 +480,24 @@ func (publisher *KafkaMarketDataPublisher) initAvroCodecs() (err error) {
 	return nil
 }
 
+func (publisher *KafkaMarketDataPublisher) initProtoCodecs() error {
+	publisher.protobufTopics = make(map[string]bool)
+	for _, topic := range strings.Split(Cfg.ProtobufTopics, KafkaBrokerSep) {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			publisher.protobufTopics[topic] = true
+		}
+	}
+	publisher.protoCodecs = make(map[msgType]*protoCodec, len(avroSchemas))
+	for tpe, schema := range avroSchemas {
+		codec, err := newProtoCodec(tpe, schema)
+		if err != nil {
+			return err
+		}
+		publisher.protoCodecs[tpe] = codec
+	}
+	return nil
+}
+
 func NewKafkaMarketDataPublisher(
 	logger log.Logger, dbDir string, failFast bool) (publisher *KafkaMarketDataPublisher) {
 
@@ -458,6 +512,19 @@ func NewKafkaMarketDataPublisher(
 		Logger.Error("failed to initialize avro codec", "err", err)
 		panic(err)
 	}
+	if err := publisher.initProtoCodecs(); err != nil {
+		Logger.Error("failed to initialize protobuf codec", "err", err)
+		panic(err)
+	}
+
+	if Cfg.SchemaRegistryUrl != "" {
+		schemaIds, err := NewSchemaRegistryClient(Cfg.SchemaRegistryUrl).RegisterSchemas()
+		if err != nil {
+			Logger.Error("failed to register avro schemas", "err", err)
+			panic(err)
+		}
+		publisher.schemaIds = schemaIds
+	}
 
 	if saramaCfg, err := publisher.newProducers(); err != nil {
 		logger.Error("failed to create new kafka producer", "err", err)
@@ -13,21 +13,48 @@ import (
 )
 
 // Publish market data to local marketdata dir in bnbchaind home
-// each message will be in json format one line in file
+// each message will be in json format one line in file, led by its sequence, msg type and block time,
+// so that the file can be replayed by `bnbchaind pub-replay`
 // file can be compressed and auto-rotated
 type LocalMarketDataPublisher struct {
 	producer *log.Logger
 	tmLogger tmLogger.Logger
 }
 
-func (publisher *LocalMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64) {
-	if jsonBytes, err := json.Marshal(msg); err == nil {
-		if err := publisher.producer.Output(2, fmt.Sprintln(string(jsonBytes))); err != nil {
+func (publisher *LocalMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64, seq int64) (err error) {
+	var jsonBytes []byte
+	if jsonBytes, err = json.Marshal(msg); err == nil {
+		if err = publisher.producer.Output(2, fmt.Sprintln(string(withSequence(withMsgType(jsonBytes, tpe, timestamp), seq)))); err != nil {
 			publisher.tmLogger.Error("failed to publish msg", "err", err, "height", height, "msg", msg.String())
 		}
 	} else {
 		publisher.tmLogger.Error("failed to publish msg", "err", err, "height", height, "msg", msg.String())
 	}
+	return
+}
+
+// withSequence adds the sequence as the first field of the json object
+func withSequence(jsonBytes []byte, seq int64) []byte {
+	if seq == 0 {
+		return jsonBytes
+	}
+	return withFields(jsonBytes, fmt.Sprintf("\"Sequence\":%d", seq))
+}
+
+// withMsgType adds the msg type and the block time (in nanoseconds) as the first fields of the json object
+func withMsgType(jsonBytes []byte, tpe msgType, timestamp int64) []byte {
+	return withFields(jsonBytes, fmt.Sprintf("\"%s\":%q,\"%s\":%d", localFieldMsgType, tpe.String(), localFieldBlockTime, timestamp))
+}
+
+func withFields(jsonBytes []byte, fields string) []byte {
+	if len(jsonBytes) < 2 || jsonBytes[0] != '{' {
+		return jsonBytes
+	}
+	prefix := "{" + fields
+	if jsonBytes[1] != '}' {
+		prefix += ","
+	}
+	return append([]byte(prefix), jsonBytes[1:]...)
 }
 
 func (publisher *LocalMarketDataPublisher) Stop() {
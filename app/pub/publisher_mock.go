@@ -18,7 +18,7 @@ type MockMarketDataPublisher struct {
 	MessagePublished uint32      // atomic integer used to determine the published messages
 }
 
-func (publisher *MockMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64) {
+func (publisher *MockMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64, seq int64) error {
 	publisher.Lock.Lock()
 	defer publisher.Lock.Unlock()
 
@@ -40,6 +40,7 @@ func (publisher *MockMarketDataPublisher) publish(msg AvroOrJsonMsg, tpe msgType
 	}
 
 	atomic.AddUint32(&publisher.MessagePublished, 1)
+	return nil
 }
 
 func (publisher *MockMarketDataPublisher) Stop() {
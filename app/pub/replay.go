@@ -0,0 +1,306 @@
+package pub
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	localFileName    = "marketdata.json"
+	localFileMaxLine = 256 * 1024 * 1024
+
+	// the leading fields of the lines of the local publisher
+	localFieldMsgType   = "MsgType"
+	localFieldBlockTime = "BlockTime"
+)
+
+// LocalMarketDataFiles returns the files of the local publisher in the marketdata dir, the rotated ones (which are
+// compressed or being compressed) ordered by their rotation time followed by the current one
+func LocalMarketDataFiles(dir string) ([]string, error) {
+	ext := filepath.Ext(localFileName)
+	prefix := strings.TrimSuffix(localFileName, ext) + "-"
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	rotated := make(map[string]string) // rotation time -> file
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		var rotation string
+		if strings.HasSuffix(name, ext) {
+			rotation = strings.TrimSuffix(name, ext)
+		} else if strings.HasSuffix(name, ext+".gz") {
+			rotation = strings.TrimSuffix(name, ext+".gz")
+			// the uncompressed one is complete while it is being compressed
+			if _, ok := rotated[rotation]; ok {
+				continue
+			}
+		} else {
+			continue
+		}
+		rotated[rotation] = filepath.Join(dir, name)
+	}
+
+	rotations := make([]string, 0, len(rotated))
+	for rotation := range rotated {
+		rotations = append(rotations, rotation)
+	}
+	sort.Strings(rotations)
+	files := make([]string, 0, len(rotations)+1)
+	for _, rotation := range rotations {
+		files = append(files, rotated[rotation])
+	}
+	if _, err := os.Stat(filepath.Join(dir, localFileName)); err == nil {
+		files = append(files, filepath.Join(dir, localFileName))
+	}
+	return files, nil
+}
+
+// ReplayLocalMarketData republishes the msgs in [fromHeight, toHeight] (toHeight <= 0 means no upper bound) read
+// from the files of the local publisher, with their original sequences. Only the msg types which are enabled in
+// the config and named in `msgTypes` (all of them if empty) are replayed, and each height of a msg type is replayed
+// once even if it was written more than once. It returns the number of the replayed msgs.
+func ReplayLocalMarketData(publisher MarketDataPublisher, files []string, fromHeight, toHeight int64, msgTypes []string) (int, error) {
+	enabled := EnabledMsgTypes(Cfg)
+	toReplay := make(map[msgType]bool)
+	for _, name := range msgTypes {
+		tpe, ok := parseMsgType(name)
+		if !ok {
+			return 0, fmt.Errorf("unknown msg type %q", name)
+		}
+		if !enabled[tpe] {
+			return 0, fmt.Errorf("%s is not published according to the publication config", tpe.String())
+		}
+		toReplay[tpe] = true
+	}
+	if len(msgTypes) == 0 {
+		toReplay = enabled
+	}
+
+	return replayLocalMarketData(publisher, files, func(tpe msgType, height int64) bool {
+		return toReplay[tpe] && height >= fromHeight && (toHeight <= 0 || height <= toHeight)
+	}, nil)
+}
+
+// RepublishLocalMarketData republishes the msgs after the published height of each enabled topic read from the
+// files of the local publisher, with their original sequences, and records the republished heights in the Cursor.
+// It is called on restart before the publication loops start. If it fails, none of the topics advance until the
+// next restart, which republishes them again. It returns the number of the republished msgs.
+func RepublishLocalMarketData(publisher MarketDataPublisher, files []string) (int, error) {
+	enabled := EnabledMsgTypes(Cfg)
+	republished, err := replayLocalMarketData(publisher, files, func(tpe msgType, height int64) bool {
+		return enabled[tpe] && height >= Cfg.FromHeightInclusive && height > Cursor.Height(tpe)
+	}, func(tpe msgType, height int64) {
+		Cursor.advance(height, []msgType{tpe})
+	})
+	if err != nil {
+		for tpe := range enabled {
+			Cursor.fail(tpe)
+		}
+	}
+	return republished, err
+}
+
+// replayLocalMarketData publishes the msgs accepted by `shouldReplay` read from the files, each height of a msg type
+// is published once. `onReplayed` is called after each msg is published if it is not nil.
+func replayLocalMarketData(publisher MarketDataPublisher, files []string,
+	shouldReplay func(tpe msgType, height int64) bool, onReplayed func(tpe msgType, height int64)) (int, error) {
+	replayed := 0
+	lastHeights := make(map[msgType]int64)
+	for _, file := range files {
+		err := readLocalMarketData(file, func(tpe msgType, height, timestamp, seq int64, line []byte) error {
+			if height <= lastHeights[tpe] || !shouldReplay(tpe, height) {
+				return nil
+			}
+			msg, err := decodeLocalMsg(tpe, line)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s at height %d: %v", tpe.String(), height, err)
+			}
+			if err := publisher.publish(msg, tpe, height, timestamp, seq); err != nil {
+				return fmt.Errorf("failed to replay %s at height %d: %v", tpe.String(), height, err)
+			}
+			lastHeights[tpe] = height
+			replayed++
+			if onReplayed != nil {
+				onReplayed(tpe, height)
+			}
+			return nil
+		})
+		if err != nil {
+			return replayed, fmt.Errorf("%s: %v", file, err)
+		}
+		Logger.Info("replayed local market data", "file", file, "numOfMsgs", replayed)
+	}
+	return replayed, nil
+}
+
+// readLocalMarketData calls `fn` with each msg in the file, the timestamp is the block time in nanoseconds
+func readLocalMarketData(file string, fn func(tpe msgType, height, timestamp, seq int64, line []byte) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	reader := io.Reader(f)
+	if strings.HasSuffix(file, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var lastHeight, lastTime int64
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 1024*1024), localFileMaxLine)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var header struct {
+			Sequence    int64
+			MsgType     string
+			BlockTime   int64
+			Height      int64
+			Timestamp   int64
+			CryptoBlock struct {
+				BlockHeight int64
+			}
+		}
+		if err := json.Unmarshal(line, &header); err != nil {
+			return err
+		}
+		var tpe msgType
+		if header.MsgType != "" {
+			var ok bool
+			if tpe, ok = parseMsgType(header.MsgType); !ok {
+				return fmt.Errorf("unknown msg type %q", header.MsgType)
+			}
+		} else {
+			// written before the msg type and the block time were recorded
+			if tpe, err = inferMsgType(line); err != nil {
+				return err
+			}
+			header.BlockTime = header.Timestamp
+		}
+		height := header.Height
+		if tpe == blockTpe {
+			height = header.CryptoBlock.BlockHeight
+		}
+		// some msgs do not carry the block time, which is the same as the other msgs of the height
+		if header.BlockTime == 0 && height == lastHeight {
+			header.BlockTime = lastTime
+		}
+		lastHeight, lastTime = height, header.BlockTime
+		if err := fn(tpe, height, header.BlockTime, header.Sequence, line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func parseMsgType(name string) (msgType, bool) {
+	for tpe := range avroSchemas {
+		if strings.EqualFold(tpe.String(), name) {
+			return tpe, true
+		}
+	}
+	return 0, false
+}
+
+// inferMsgType recognizes the msg type by the fields of the json object
+func inferMsgType(line []byte) (msgType, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return 0, err
+	}
+	has := func(field string) bool {
+		_, ok := fields[field]
+		return ok
+	}
+	switch {
+	case has("Trades"):
+		return executionResultTpe, nil
+	case has("Books"):
+		return booksTpe, nil
+	case has("Accounts"):
+		return accountsTpe, nil
+	case has("Fee") && has("Validators"):
+		return blockFeeTpe, nil
+	case has("CryptoBlock"):
+		return blockTpe, nil
+	case has("RemovedValidators"):
+		return stakingTpe, nil
+	case has("Distributions"):
+		return distributionTpe, nil
+	case has("SlashData"):
+		return slashingTpe, nil
+	case has("Mirrors"):
+		return mirrorTpe, nil
+	case has("Proposals"):
+		return sideProposalType, nil
+	case has("Transfers"):
+		// the cross transfers carry the chain ids
+		var transfers []map[string]json.RawMessage
+		if err := json.Unmarshal(fields["Transfers"], &transfers); err == nil && len(transfers) > 0 {
+			if _, ok := transfers[0]["ChainId"]; ok {
+				return crossTransferTpe, nil
+			}
+		}
+		return transferTpe, nil
+	case has("Height") && has("Timestamp") && len(fields) == 2, has("Sequence") && has("Height") && has("Timestamp") && len(fields) == 3:
+		return breatheBlockTpe, nil
+	}
+	return 0, fmt.Errorf("unknown msg: %.100s", line)
+}
+
+// decodeLocalMsg decodes the msg as the type published by the publication loop
+func decodeLocalMsg(tpe msgType, line []byte) (AvroOrJsonMsg, error) {
+	var msg AvroOrJsonMsg
+	switch tpe {
+	case accountsTpe:
+		msg = &Accounts{}
+	case booksTpe:
+		msg = &Books{}
+	case executionResultTpe:
+		msg = &ExecutionResults{}
+	case blockFeeTpe:
+		var blockFee BlockFee
+		err := json.Unmarshal(line, &blockFee)
+		return blockFee, err
+	case transferTpe:
+		msg = &Transfers{}
+	case blockTpe:
+		msg = &Block{}
+	case stakingTpe:
+		msg = &StakingMsg{}
+	case distributionTpe:
+		msg = &DistributionMsg{}
+	case slashingTpe:
+		msg = &SlashMsg{}
+	case crossTransferTpe:
+		msg = &CrossTransfers{}
+	case mirrorTpe:
+		msg = &Mirrors{}
+	case sideProposalType:
+		msg = &SideProposals{}
+	case breatheBlockTpe:
+		msg = &BreatheBlockMsg{}
+	default:
+		return nil, fmt.Errorf("doesn't support msg tpe: %s", tpe.String())
+	}
+	err := json.Unmarshal(line, msg)
+	return msg, err
+}
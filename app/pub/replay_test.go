@@ -0,0 +1,183 @@
+package pub
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/app/config"
+)
+
+type replayedMsg struct {
+	msg       AvroOrJsonMsg
+	tpe       msgType
+	height    int64
+	timestamp int64
+	seq       int64
+}
+
+type recordingPublisher struct {
+	replayed []replayedMsg
+}
+
+func (publisher *recordingPublisher) publish(msg AvroOrJsonMsg, tpe msgType, height int64, timestamp int64, seq int64) error {
+	publisher.replayed = append(publisher.replayed, replayedMsg{msg, tpe, height, timestamp, seq})
+	return nil
+}
+
+func (publisher *recordingPublisher) Stop() {}
+
+func TestReplayLocalMarketData(t *testing.T) {
+	cfg := Cfg
+	defer func() { Cfg = cfg }()
+	Cfg = &config.PublicationConfig{PublishOrderUpdates: true, PublishBlockFee: true, PublishAccountBalance: true, PublishBreatheBlock: true}
+
+	dir, err := os.MkdirTemp("", "replay")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	marketdata := filepath.Join(dir, "marketdata")
+	require.NoError(t, os.MkdirAll(marketdata, 0755))
+
+	// a compressed rotation written before the msg types were recorded
+	gzFile, err := os.Create(filepath.Join(marketdata, "marketdata-2020-01-01T00-00-00.000.json.gz"))
+	require.NoError(t, err)
+	gz := gzip.NewWriter(gzFile)
+	_, err = gz.Write([]byte(`{"Height":1,"Timestamp":1000,"NumOfMsgs":0,"Trades":{"NumOfMsgs":0,"Trades":null},"Orders":{"NumOfMsgs":0,"Orders":null}}
+{"Height":1,"Fee":"BNB:10","Validators":[]}
+{"Height":1,"Timestamp":1000}
+`))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, gzFile.Close())
+
+	buyer, seller := sdk.AccAddress("replay-test-buyer-01"), sdk.AccAddress("replay-test-seller-1")
+	validator := sdk.AccAddress("replay-test-validator")
+	publisher := NewLocalMarketDataPublisher(dir, Logger, Cfg)
+	results := &ExecutionResults{Height: 2, Timestamp: 2000, NumOfMsgs: 1, Trades: trades{NumOfMsgs: 1, Trades: []*Trade{{
+		Id: "2-0", Symbol: "XYZ-000_BNB", Price: 1e8, Qty: 1e8, SAddr: string(seller), BAddr: string(buyer)}}}}
+	require.NoError(t, publisher.publish(results, executionResultTpe, 2, 2000, 2))
+	require.NoError(t, publisher.publish(&Accounts{2, 1, []Account{{Owner: string(buyer), Fee: "BNB:1"}}}, accountsTpe, 2, 2000, 1))
+	require.NoError(t, publisher.publish(BlockFee{2, "BNB:1", []string{string(validator)}}, blockFeeTpe, 2, 2000, 2))
+	require.NoError(t, publisher.publish(&Books{Height: 2, Timestamp: 2000}, booksTpe, 2, 2000, 1))
+	// replayed on handshake
+	require.NoError(t, publisher.publish(results, executionResultTpe, 2, 2000, 2))
+	require.NoError(t, publisher.publish(&ExecutionResults{Height: 3, Timestamp: 3000}, executionResultTpe, 3, 3000, 3))
+	require.NoError(t, publisher.producer.Writer().(interface{ Close() error }).Close())
+
+	files, err := LocalMarketDataFiles(marketdata)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(marketdata, "marketdata-2020-01-01T00-00-00.000.json.gz"),
+		filepath.Join(marketdata, "marketdata.json"),
+	}, files)
+
+	recorder := &recordingPublisher{}
+	replayed, err := ReplayLocalMarketData(recorder, files, 1, 0, nil)
+	require.NoError(t, err)
+	require.Equal(t, 7, replayed)
+	// the books are not published according to the config
+	tpes := make([]msgType, 0)
+	for _, r := range recorder.replayed {
+		tpes = append(tpes, r.tpe)
+	}
+	require.Equal(t, []msgType{executionResultTpe, blockFeeTpe, breatheBlockTpe, executionResultTpe, accountsTpe, blockFeeTpe, executionResultTpe}, tpes)
+	require.Equal(t, int64(1000), recorder.replayed[1].timestamp)
+
+	trade := recorder.replayed[3].msg.(*ExecutionResults).Trades.Trades[0]
+	require.Equal(t, string(buyer), trade.BAddr)
+	require.Equal(t, string(seller), trade.SAddr)
+	require.Equal(t, int64(2), recorder.replayed[3].seq)
+	require.Equal(t, int64(2000), recorder.replayed[3].timestamp)
+	require.Equal(t, string(buyer), recorder.replayed[4].msg.(*Accounts).Accounts[0].Owner)
+	require.Equal(t, []string{string(validator)}, recorder.replayed[5].msg.(BlockFee).Validators)
+	require.Equal(t, int64(3), recorder.replayed[6].height)
+
+	// filtered by the height range and the msg types
+	recorder = &recordingPublisher{}
+	replayed, err = ReplayLocalMarketData(recorder, files, 2, 2, []string{"executionResults", "BlockFee"})
+	require.NoError(t, err)
+	require.Equal(t, 2, replayed)
+	require.Equal(t, msgType(executionResultTpe), recorder.replayed[0].tpe)
+	require.Equal(t, msgType(blockFeeTpe), recorder.replayed[1].tpe)
+
+	_, err = ReplayLocalMarketData(recorder, files, 1, 0, []string{"Books"})
+	require.Error(t, err)
+	_, err = ReplayLocalMarketData(recorder, files, 1, 0, []string{"Unknown"})
+	require.Error(t, err)
+}
+
+func TestRepublishLocalMarketData(t *testing.T) {
+	cfg := Cfg
+	defer func() { Cfg, Cursor = cfg, nil }()
+	Cfg = &config.PublicationConfig{PublishOrderUpdates: true, PublishBlockFee: true, FromHeightInclusive: 1}
+	db := dbm.NewMemDB()
+	Cursor = NewPublicationCursor(db, Cfg)
+
+	dir, err := os.MkdirTemp("", "republish")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	publisher := NewLocalMarketDataPublisher(dir, Logger, Cfg)
+	for height := int64(1); height <= 3; height++ {
+		require.NoError(t, publisher.publish(BlockFee{Height: height, Fee: "BNB:1"}, blockFeeTpe, height, height*1000, height))
+		require.NoError(t, publisher.publish(&ExecutionResults{Height: height}, executionResultTpe, height, height*1000, height))
+	}
+	require.NoError(t, publisher.producer.Writer().(interface{ Close() error }).Close())
+	files, err := LocalMarketDataFiles(filepath.Join(dir, "marketdata"))
+	require.NoError(t, err)
+
+	// the republish fails, the cursors stay for the next restart
+	Cursor.advance(1, blockMsgTypes)
+	Cursor.advance(2, []msgType{executionResultTpe})
+	failing := &failingPublisher{MockMarketDataPublisher: *NewMockMarketDataPublisher(), fail: map[int64]bool{3: true}}
+	republished, err := RepublishLocalMarketData(failing, files)
+	require.Error(t, err)
+	require.Equal(t, 1, republished)
+	require.Equal(t, []int64{2}, failing.seqs)
+	Cursor.advance(4, blockMsgTypes)
+	require.Equal(t, int64(2), Cursor.Height(blockFeeTpe))
+	require.Equal(t, int64(2), Cursor.Height(executionResultTpe))
+
+	// restart, the heights after the cursor of each topic are republished with their sequences
+	Cursor = NewPublicationCursor(db, Cfg)
+	recorder := &recordingPublisher{}
+	republished, err = RepublishLocalMarketData(recorder, files)
+	require.NoError(t, err)
+	require.Equal(t, 2, republished)
+	require.Equal(t, []replayedMsg{
+		{recorder.replayed[0].msg, blockFeeTpe, 3, 3000, 3},
+		{recorder.replayed[1].msg, executionResultTpe, 3, 3000, 3},
+	}, recorder.replayed)
+	require.Equal(t, int64(3), Cursor.Height(blockFeeTpe))
+	require.Equal(t, int64(3), Cursor.Height(executionResultTpe))
+}
+
+func TestInferMsgType(t *testing.T) {
+	for line, tpe := range map[string]msgType{
+		`{"Height":1,"NumOfMsgs":1,"Books":[]}`:                             booksTpe,
+		`{"Height":1,"NumOfMsgs":1,"Accounts":[]}`:                          accountsTpe,
+		`{"ChainID":"a","CryptoBlock":{"BlockHeight":1}}`:                   blockTpe,
+		`{"Height":1,"Num":1,"Timestamp":1,"Transfers":[{"TxHash":"a"}]}`:   transferTpe,
+		`{"Height":1,"Num":1,"Timestamp":1,"Transfers":[{"ChainId":"a"}]}`:  crossTransferTpe,
+		`{"Height":1,"Timestamp":1,"NumOfMsgs":1,"Proposals":[]}`:           sideProposalType,
+		`{"NumOfMsgs":1,"Height":1,"Timestamp":1,"RemovedValidators":null}`: stakingTpe,
+		`{"NumOfMsgs":1,"Height":1,"Timestamp":1,"Distributions":null}`:     distributionTpe,
+		`{"NumOfMsgs":1,"Height":1,"Timestamp":1,"SlashData":null}`:         slashingTpe,
+		`{"Height":1,"Num":1,"Timestamp":1,"Mirrors":[]}`:                   mirrorTpe,
+		`{"Sequence":3,"Height":1,"Timestamp":1}`:                           breatheBlockTpe,
+		`{"Height":1,"Timestamp":1,"NumOfMsgs":0,"Trades":{},"Orders":{}}`:  executionResultTpe,
+		`{"Height":1,"Fee":"BNB:1","Validators":["bnb1"]}`:                  blockFeeTpe,
+	} {
+		inferred, err := inferMsgType([]byte(line))
+		require.NoError(t, err, line)
+		require.Equal(t, tpe, inferred, line)
+	}
+	_, err := inferMsgType([]byte(`{"Height":1,"Timestamp":1,"Unknown":1}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,248 @@
+package pub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// avroPrimitives are the primitive types of avro, and the writer types each of them can read
+var avroPrimitives = map[string][]string{
+	"null":    {"null"},
+	"boolean": {"boolean"},
+	"int":     {"int"},
+	"long":    {"long", "int"},
+	"float":   {"float", "long", "int"},
+	"double":  {"double", "float", "long", "int"},
+	"bytes":   {"bytes", "string"},
+	"string":  {"string", "bytes"},
+}
+
+// avroSchema is a parsed avro schema with its named types (records, enums and fixed) indexed by full name
+type avroSchema struct {
+	root  interface{}
+	named map[string]map[string]interface{}
+}
+
+func parseAvroSchema(schema string) (*avroSchema, error) {
+	var root interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, err
+	}
+	s := &avroSchema{root: root, named: make(map[string]map[string]interface{})}
+	s.collectNamed(root, "")
+	return s, nil
+}
+
+func (s *avroSchema) collectNamed(node interface{}, namespace string) {
+	switch n := node.(type) {
+	case []interface{}:
+		for _, branch := range n {
+			s.collectNamed(branch, namespace)
+		}
+	case map[string]interface{}:
+		switch n["type"] {
+		case "record", "error", "enum", "fixed":
+			fullName := avroFullName(n, namespace)
+			s.named[fullName] = n
+			if idx := strings.LastIndex(fullName, "."); idx >= 0 {
+				namespace = fullName[:idx]
+			} else {
+				namespace = ""
+			}
+			if fields, ok := n["fields"].([]interface{}); ok {
+				for _, field := range fields {
+					if f, ok := field.(map[string]interface{}); ok {
+						s.collectNamed(f["type"], namespace)
+					}
+				}
+			}
+		case "array":
+			s.collectNamed(n["items"], namespace)
+		case "map":
+			s.collectNamed(n["values"], namespace)
+		default:
+			s.collectNamed(n["type"], namespace)
+		}
+	}
+}
+
+func avroFullName(n map[string]interface{}, namespace string) string {
+	name, _ := n["name"].(string)
+	if strings.Contains(name, ".") {
+		return name
+	}
+	if ns, ok := n["namespace"].(string); ok {
+		namespace = ns
+	}
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func avroShortName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// resolve returns the definition of the named type, or the node itself
+func (s *avroSchema) resolve(node interface{}) interface{} {
+	name, ok := node.(string)
+	if !ok {
+		return node
+	}
+	if _, primitive := avroPrimitives[name]; primitive {
+		return name
+	}
+	if def, ok := s.named[name]; ok {
+		return def
+	}
+	for fullName, def := range s.named {
+		if avroShortName(fullName) == name {
+			return def
+		}
+	}
+	return node
+}
+
+// avroType returns the type of the node, i.e. a primitive name, "record", "array" and so on, or "union"
+func avroType(node interface{}) string {
+	switch n := node.(type) {
+	case string:
+		return n
+	case []interface{}:
+		return "union"
+	case map[string]interface{}:
+		if t, ok := n["type"].(string); ok {
+			if t == "error" {
+				return "record"
+			}
+			return t
+		}
+		return avroType(n["type"])
+	}
+	return ""
+}
+
+// CheckBackwardCompatibility returns an error if the data written with the `writer` schema can not be read with
+// the `reader` schema according to the avro schema resolution, i.e. a new schema should be backward compatible
+// with the previous version, so that the consumers upgraded to the new schema can read the old messages.
+func CheckBackwardCompatibility(reader, writer string) error {
+	readerSchema, err := parseAvroSchema(reader)
+	if err != nil {
+		return fmt.Errorf("invalid reader schema: %v", err)
+	}
+	writerSchema, err := parseAvroSchema(writer)
+	if err != nil {
+		return fmt.Errorf("invalid writer schema: %v", err)
+	}
+	c := avroCompatChecker{readerSchema, writerSchema, make(map[string]bool)}
+	return c.check(readerSchema.root, writerSchema.root, "")
+}
+
+type avroCompatChecker struct {
+	reader, writer *avroSchema
+	checking       map[string]bool // the pairs of records being checked, for the recursive types
+}
+
+func (c avroCompatChecker) check(readerNode, writerNode interface{}, path string) error {
+	readerNode, writerNode = c.reader.resolve(readerNode), c.writer.resolve(writerNode)
+	readerType, writerType := avroType(readerNode), avroType(writerNode)
+
+	if writerType == "union" {
+		for _, branch := range writerNode.([]interface{}) {
+			if err := c.check(readerNode, branch, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if readerType == "union" {
+		for _, branch := range readerNode.([]interface{}) {
+			if c.check(branch, writerNode, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %s is not in the reader union", path, writerType)
+	}
+	if accepted, ok := avroPrimitives[readerType]; ok {
+		for _, t := range accepted {
+			if t == writerType {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %s can not be read as %s", path, writerType, readerType)
+	}
+	if readerType != writerType {
+		return fmt.Errorf("%s: %s can not be read as %s", path, writerType, readerType)
+	}
+
+	readerDef, writerDef := readerNode.(map[string]interface{}), writerNode.(map[string]interface{})
+	switch readerType {
+	case "array":
+		return c.check(readerDef["items"], writerDef["items"], path+"[]")
+	case "map":
+		return c.check(readerDef["values"], writerDef["values"], path+"{}")
+	case "fixed":
+		if readerDef["name"] != writerDef["name"] || readerDef["size"] != writerDef["size"] {
+			return fmt.Errorf("%s: fixed %v can not be read as %v", path, writerDef["name"], readerDef["name"])
+		}
+	case "enum":
+		if err := c.checkName(readerDef, writerDef, path); err != nil {
+			return err
+		}
+		if _, ok := readerDef["default"]; ok {
+			return nil
+		}
+		symbols := make(map[interface{}]bool)
+		for _, symbol := range readerDef["symbols"].([]interface{}) {
+			symbols[symbol] = true
+		}
+		for _, symbol := range writerDef["symbols"].([]interface{}) {
+			if !symbols[symbol] {
+				return fmt.Errorf("%s: symbol %v is missing in the reader enum", path, symbol)
+			}
+		}
+	case "record":
+		if err := c.checkName(readerDef, writerDef, path); err != nil {
+			return err
+		}
+		pair := fmt.Sprintf("%v/%v", readerDef["name"], writerDef["name"])
+		if c.checking[pair] {
+			return nil
+		}
+		c.checking[pair] = true
+		defer delete(c.checking, pair)
+
+		writerFields := make(map[string]map[string]interface{})
+		for _, field := range writerDef["fields"].([]interface{}) {
+			f := field.(map[string]interface{})
+			writerFields[f["name"].(string)] = f
+		}
+		for _, field := range readerDef["fields"].([]interface{}) {
+			f := field.(map[string]interface{})
+			name := f["name"].(string)
+			writerField, ok := writerFields[name]
+			if !ok {
+				if _, hasDefault := f["default"]; !hasDefault {
+					return fmt.Errorf("%s.%s: the added field has no default value", path, name)
+				}
+				continue
+			}
+			if err := c.check(f["type"], writerField["type"], path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkName compares the unqualified names, so that the namespaces can be changed
+func (c avroCompatChecker) checkName(readerDef, writerDef map[string]interface{}, path string) error {
+	readerName, _ := readerDef["name"].(string)
+	writerName, _ := writerDef["name"].(string)
+	if avroShortName(readerName) != avroShortName(writerName) {
+		return fmt.Errorf("%s: %s can not be read as %s", path, writerName, readerName)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package pub
+
+import (
+	"bytes"
+	"embed"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// the content type of the requests to a confluent compatible schema registry
+	schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+	// the first byte of the confluent wire format, followed by the 4 bytes big endian schema id and the avro binary
+	schemaRegistryMagicByte = byte(0)
+
+	schemaRegistryTimeout = 10 * time.Second
+)
+
+// schemaSnapshots are the schemas of every version, named by the msg type in lower camel case suffixed with
+// the version, e.g. executionResults1.avsc. A change of a schema in schemas.go should add a snapshot of the new
+// version, which is checked for backward compatibility against the previous one in schema_test.go.
+//
+//go:embed schemas/*.avsc
+var schemaSnapshots embed.FS
+
+func schemaSnapshotName(tpe msgType, version int) string {
+	name := tpe.String()
+	return fmt.Sprintf("schemas/%s%s%d.avsc", strings.ToLower(name[:1]), name[1:], version)
+}
+
+// loadLatestSchemaVersions returns the latest version of the snapshots of each msg type
+func loadLatestSchemaVersions() map[msgType]int {
+	versions := make(map[msgType]int)
+	for tpe := range avroSchemas {
+		version := -1
+		for {
+			if _, err := schemaSnapshots.Open(schemaSnapshotName(tpe, version+1)); err != nil {
+				break
+			}
+			version++
+		}
+		if version < 0 {
+			panic(fmt.Errorf("missing schema snapshot %s", schemaSnapshotName(tpe, 0)))
+		}
+		versions[tpe] = version
+	}
+	return versions
+}
+
+// schemaSubject returns the subject of the schema under the record name strategy, i.e. the full name of the
+// top level record, as the msgs of different types can be published into the same topic.
+func schemaSubject(schema string) (string, error) {
+	var record struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	}
+	if err := json.Unmarshal([]byte(schema), &record); err != nil {
+		return "", err
+	}
+	if record.Name == "" {
+		return "", fmt.Errorf("the schema is not a named record")
+	}
+	if record.Namespace == "" || strings.Contains(record.Name, ".") {
+		return record.Name, nil
+	}
+	return record.Namespace + "." + record.Name, nil
+}
+
+// SchemaRegistryClient registers the avro schemas to a confluent compatible schema registry
+type SchemaRegistryClient struct {
+	url    string
+	client *http.Client
+}
+
+func NewSchemaRegistryClient(registryUrl string) *SchemaRegistryClient {
+	return &SchemaRegistryClient{
+		url:    strings.TrimSuffix(registryUrl, "/"),
+		client: &http.Client{Timeout: schemaRegistryTimeout},
+	}
+}
+
+func (c *SchemaRegistryClient) post(path string, schema string, result interface{}) (status int, err error) {
+	body, err := json.Marshal(map[string]string{"schema": schema})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.client.Post(c.url+path, schemaRegistryContentType, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("schema registry responded %d: %s", resp.StatusCode, respBody)
+	}
+	return resp.StatusCode, json.Unmarshal(respBody, result)
+}
+
+// CheckCompatibility checks the schema against the latest version of the subject under the compatibility level
+// configured in the registry, a new subject is always compatible.
+func (c *SchemaRegistryClient) CheckCompatibility(subject string, schema string) error {
+	var result struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+	status, err := c.post(fmt.Sprintf("/compatibility/subjects/%s/versions/latest", url.PathEscape(subject)), schema, &result)
+	if status == http.StatusNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !result.IsCompatible {
+		return fmt.Errorf("the schema is incompatible with the latest version of subject %s", subject)
+	}
+	return nil
+}
+
+// Register registers the schema under the subject and returns its id, registering a registered schema returns
+// the same id.
+func (c *SchemaRegistryClient) Register(subject string, schema string) (int32, error) {
+	var result struct {
+		Id int32 `json:"id"`
+	}
+	if _, err := c.post(fmt.Sprintf("/subjects/%s/versions", url.PathEscape(subject)), schema, &result); err != nil {
+		return 0, err
+	}
+	return result.Id, nil
+}
+
+// RegisterSchemas checks and registers the schemas of all the msg types, and returns their ids
+func (c *SchemaRegistryClient) RegisterSchemas() (map[msgType]int32, error) {
+	ids := make(map[msgType]int32, len(avroSchemas))
+	for tpe, schema := range avroSchemas {
+		subject, err := schemaSubject(schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema of %s: %v", tpe.String(), err)
+		}
+		if err := c.CheckCompatibility(subject, schema); err != nil {
+			return nil, err
+		}
+		if ids[tpe], err = c.Register(subject, schema); err != nil {
+			return nil, fmt.Errorf("failed to register the schema of %s: %v", tpe.String(), err)
+		}
+		Logger.Info("registered schema", "type", tpe.String(), "subject", subject, "id", ids[tpe],
+			"version", latestSchemaVersions[tpe])
+	}
+	return ids, nil
+}
+
+// withSchemaId prefixes the avro binary with the magic byte and the schema id, i.e. the confluent wire format
+func withSchemaId(id int32, avroBinary []byte) []byte {
+	bz := make([]byte, 5, 5+len(avroBinary))
+	bz[0] = schemaRegistryMagicByte
+	binary.BigEndian.PutUint32(bz[1:], uint32(id))
+	return append(bz, avroBinary...)
+}
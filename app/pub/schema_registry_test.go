@@ -0,0 +1,100 @@
+package pub
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/linkedin/goavro"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSchemaRegistry implements the compatibility and register endpoints of a confluent schema registry
+type mockSchemaRegistry struct {
+	mtx          sync.Mutex
+	subjects     map[string]int32
+	incompatible bool
+}
+
+func (m *mockSchemaRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	var req struct {
+		Schema string `json:"schema"`
+	}
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != schemaRegistryContentType ||
+		json.NewDecoder(r.Body).Decode(&req) != nil || req.Schema == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/compatibility/subjects/"):
+		subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/compatibility/subjects/"), "/versions/latest")
+		if _, ok := m.subjects[subject]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code": 40401, "message": "Subject not found."}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]bool{"is_compatible": !m.incompatible})
+	case strings.HasPrefix(r.URL.Path, "/subjects/"):
+		subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+		if _, ok := m.subjects[subject]; !ok {
+			m.subjects[subject] = int32(len(m.subjects) + 1)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]int32{"id": m.subjects[subject]})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSchemaRegistryClient_RegisterSchemas(t *testing.T) {
+	registry := &mockSchemaRegistry{subjects: make(map[string]int32)}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	client := NewSchemaRegistryClient(server.URL + "/")
+	ids, err := client.RegisterSchemas()
+	require.NoError(t, err)
+	require.Len(t, ids, len(avroSchemas))
+	require.Equal(t, ids[booksTpe], registry.subjects["com.company.Books"])
+
+	// registering again returns the same ids
+	again, err := client.RegisterSchemas()
+	require.NoError(t, err)
+	require.Equal(t, ids, again)
+
+	registry.incompatible = true
+	_, err = client.RegisterSchemas()
+	require.Error(t, err)
+}
+
+func TestSchemaSubject(t *testing.T) {
+	subject, err := schemaSubject(booksSchema)
+	require.NoError(t, err)
+	require.Equal(t, "com.company.Books", subject)
+	subject, err = schemaSubject(`{"type": "record", "name": "a.B", "namespace": "c", "fields": []}`)
+	require.NoError(t, err)
+	require.Equal(t, "a.B", subject)
+	_, err = schemaSubject(`"string"`)
+	require.Error(t, err)
+}
+
+func TestMarshalWithSchemaId(t *testing.T) {
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	publisher.schemaIds = map[msgType]int32{blockFeeTpe: 258}
+	msg := BlockFee{1, "BNB:1000;BTC:10", []string{"bnc1", "bnc2"}}
+	bz, err := publisher.marshal(&msg, blockFeeTpe)
+	require.NoError(t, err)
+	require.Equal(t, schemaRegistryMagicByte, bz[0])
+	require.Equal(t, uint32(258), binary.BigEndian.Uint32(bz[1:5]))
+
+	codec, err := goavro.NewCodec(blockfeeSchema)
+	require.NoError(t, err)
+	_, remaining, err := codec.NativeFromBinary(bz[5:])
+	require.NoError(t, err)
+	require.Len(t, remaining, 0)
+}
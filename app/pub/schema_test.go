@@ -264,3 +264,70 @@ func TestSlashMarshaling(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// the historical schema changes before the compatibility check, which added fields without default values
+var incompatibleSchemaVersions = map[msgType]int{
+	accountsTpe:        1,
+	distributionTpe:    1,
+	executionResultTpe: 1,
+	transferTpe:        1,
+}
+
+func readSchemaSnapshot(t *testing.T, tpe msgType, version int) string {
+	bz, err := schemaSnapshots.ReadFile(schemaSnapshotName(tpe, version))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(bz)
+}
+
+// This test ensures a schema change is snapshotted as a new version, backward compatible with the previous one
+func TestSchemaSnapshots(t *testing.T) {
+	for tpe, schema := range avroSchemas {
+		var current, latest interface{}
+		assert.NoError(t, json.Unmarshal([]byte(schema), &current))
+		assert.NoError(t, json.Unmarshal([]byte(readSchemaSnapshot(t, tpe, latestSchemaVersions[tpe])), &latest))
+		assert.Equal(t, latest, current, "the schema of %s differs from its latest snapshot, please add a new version", tpe.String())
+
+		for version := 1; version <= latestSchemaVersions[tpe]; version++ {
+			err := CheckBackwardCompatibility(readSchemaSnapshot(t, tpe, version), readSchemaSnapshot(t, tpe, version-1))
+			if incompatibleSchemaVersions[tpe] == version {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err, "version %d of %s", version, tpe.String())
+			}
+		}
+	}
+}
+
+func TestCheckBackwardCompatibility(t *testing.T) {
+	record := func(fields string) string {
+		return `{"type": "record", "name": "Msg", "namespace": "org.binance.dex.model.avro", "fields": [` + fields + `]}`
+	}
+	for _, c := range []struct {
+		reader, writer string
+		compatible     bool
+	}{
+		{record(`{"name": "a", "type": "long"}`), record(`{"name": "a", "type": "long"}`), true},
+		{record(`{"name": "a", "type": "long"}`), record(`{"name": "a", "type": "int"}`), true},
+		{record(`{"name": "a", "type": "int"}`), record(`{"name": "a", "type": "long"}`), false},
+		{record(`{"name": "a", "type": "long"}, {"name": "b", "type": "string", "default": ""}`), record(`{"name": "a", "type": "long"}`), true},
+		{record(`{"name": "a", "type": "long"}, {"name": "b", "type": "string"}`), record(`{"name": "a", "type": "long"}`), false},
+		{record(`{"name": "a", "type": "long"}`), record(`{"name": "a", "type": "long"}, {"name": "b", "type": "string"}`), true},
+		{record(`{"name": "a", "type": ["null", "long"]}`), record(`{"name": "a", "type": "long"}`), true},
+		{record(`{"name": "a", "type": "long"}`), record(`{"name": "a", "type": ["null", "long"]}`), false},
+		{record(`{"name": "a", "type": {"type": "array", "items": {"type": "record", "name": "B", "fields": [{"name": "c", "type": "string"}]}}}`),
+			record(`{"name": "a", "type": {"type": "array", "items": {"type": "record", "name": "B", "fields": [{"name": "c", "type": "bytes"}]}}}`), true},
+		{record(`{"name": "a", "type": {"type": "record", "name": "B", "fields": [{"name": "c", "type": "string"}]}}, {"name": "d", "type": "B"}`),
+			record(`{"name": "a", "type": {"type": "record", "name": "B", "fields": [{"name": "c", "type": "string"}]}}, {"name": "d", "type": "long"}`), false},
+		{record(`{"name": "a", "type": {"type": "enum", "name": "E", "symbols": ["X", "Y"]}}`), record(`{"name": "a", "type": {"type": "enum", "name": "E", "symbols": ["X"]}}`), true},
+		{record(`{"name": "a", "type": {"type": "enum", "name": "E", "symbols": ["X"]}}`), record(`{"name": "a", "type": {"type": "enum", "name": "E", "symbols": ["X", "Y"]}}`), false},
+	} {
+		err := CheckBackwardCompatibility(c.reader, c.writer)
+		if c.compatible {
+			assert.NoError(t, err, "reader %s, writer %s", c.reader, c.writer)
+		} else {
+			assert.Error(t, err, "reader %s, writer %s", c.reader, c.writer)
+		}
+	}
+}
@@ -2,13 +2,32 @@ package pub
 
 // !!!!!NOTE!!!!!
 // consumers should aware of changes in this file
-// update app/pub/msgs.go `latestSchemaVersions`
-// put old version into pub/schemas with version suffixed to filename for tracking historical version
+// put the new version into pub/schemas with version suffixed to filename, which bumps `latestSchemaVersions`,
+// the new version is checked for backward compatibility against the previous one by schema_test.go
+// the protobuf definitions in pub/proto are derived from these schemas, regenerate them by
+// `go test ./app/pub -run TestProtoDefinitions -update-proto`
 
 // Backward compatibility:
 // 1. publisher add field, consumer should initialize two decoder with two publisher schema, choose which decoder should be used by `lastestSchemaVersion` component in kafka message Key
 // 2. consumer add field, consumer should initialize one decode with publisher schema and consumer schema. In which, consumer schema should define default value for added field
 
+// the schemas of the msg types, registered to the schema registry if configured
+var avroSchemas = map[msgType]string{
+	accountsTpe:        accountSchema,
+	booksTpe:           booksSchema,
+	executionResultTpe: executionResultSchema,
+	blockFeeTpe:        blockfeeSchema,
+	transferTpe:        transfersSchema,
+	blockTpe:           blockDatasSchema,
+	stakingTpe:         stakingSchema,
+	distributionTpe:    distributionSchema,
+	slashingTpe:        slashingSchema,
+	crossTransferTpe:   crossTransferSchema,
+	mirrorTpe:          mirrorSchema,
+	sideProposalType:   sideProposalsSchema,
+	breatheBlockTpe:    breatheBlockSchema,
+}
+
 const (
 	executionResultSchema = `
         {
@@ -20,6 +20,9 @@ func MakeCodec() *wire.Codec {
 	types.RegisterWire(cdc)
 	cdc.RegisterConcrete(order.NewOrderMsg{}, "dex/NewOrder", nil)
 	cdc.RegisterConcrete(order.CancelOrderMsg{}, "dex/CancelOrder", nil)
+	cdc.RegisterConcrete(order.ReplaceOrderMsg{}, "dex/ReplaceOrder", nil)
+	cdc.RegisterConcrete(order.BatchNewOrderMsg{}, "dex/BatchNewOrder", nil)
+	cdc.RegisterConcrete(order.BatchCancelOrderMsg{}, "dex/BatchCancelOrder", nil)
 
 	cdc.RegisterConcrete(order.OrderBookSnapshot{}, "dex/OrderBookSnapshot", nil)
 	cdc.RegisterConcrete(order.ActiveOrders{}, "dex/ActiveOrders", nil)
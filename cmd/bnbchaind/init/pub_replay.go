@@ -0,0 +1,107 @@
+package init
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/libs/cli"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/server"
+
+	configPkg "github.com/bnb-chain/node/app/config"
+	"github.com/bnb-chain/node/app/pub"
+)
+
+const (
+	flagFromHeight = "from"
+	flagTarget     = "target"
+	flagMsgTypes   = "msgTypes"
+	flagDir        = "dir"
+
+	replayTargetKafka = "kafka"
+	replayTargetHttp  = "http"
+)
+
+// PubReplayCmd republishes a height range of the market data written by the local publisher (`publishLocal`),
+// including the rotated and compressed files, to kafka or the http endpoints configured in app.toml
+func PubReplayCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pub-replay [files...]",
+		Short: "Republish the local market data files to kafka or the http endpoints",
+		Long: "Republish the msgs in a height range written by the local publisher to kafka or the http endpoints,\n" +
+			"with the topics, schemas and encodings of the [publication] section of app.toml.\n" +
+			"The files default to all the files in <home>/marketdata in the rotation order.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout))
+
+			config := ctx.Config
+			config.SetRoot(viper.GetString(cli.HomeFlag))
+			appCtx := configPkg.NewDefaultContext()
+			err := appCtx.ParseAppConfigInPlace()
+			if err != nil {
+				return err
+			}
+
+			fromHeight := viper.GetInt64(flagFromHeight)
+			toHeight := viper.GetInt64(flagToHeight)
+			if fromHeight <= 0 || (toHeight > 0 && toHeight < fromHeight) {
+				return fmt.Errorf("invalid height range [%d, %d]", fromHeight, toHeight)
+			}
+			var msgTypes []string
+			for _, msgType := range strings.Split(viper.GetString(flagMsgTypes), ",") {
+				if msgType = strings.TrimSpace(msgType); msgType != "" {
+					msgTypes = append(msgTypes, msgType)
+				}
+			}
+
+			files := args
+			if len(files) == 0 {
+				dir := viper.GetString(flagDir)
+				if dir == "" {
+					dir = filepath.Join(config.RootDir, "marketdata")
+				}
+				if files, err = pub.LocalMarketDataFiles(dir); err != nil {
+					return err
+				}
+				if len(files) == 0 {
+					return fmt.Errorf("no local market data in %s", dir)
+				}
+			}
+
+			pub.Logger = logger.With("module", "pub")
+			pub.Cfg = appCtx.PublicationConfig
+			var publisher pub.MarketDataPublisher
+			switch target := viper.GetString(flagTarget); target {
+			case replayTargetKafka:
+				publisher = pub.NewKafkaMarketDataPublisher(pub.Logger, config.DBDir(), false)
+			case replayTargetHttp:
+				publisher = pub.NewHttpMarketDataPublisher(pub.Logger, pub.Cfg)
+			default:
+				return fmt.Errorf("--%s should be %s or %s: %s", flagTarget, replayTargetKafka, replayTargetHttp, target)
+			}
+			defer publisher.Stop()
+
+			logger.Info("start replay", "from", fromHeight, "to", toHeight, "msgTypes", msgTypes, "files", len(files))
+			replayed, err := pub.ReplayLocalMarketData(publisher, files, fromHeight, toHeight, msgTypes)
+			if err != nil {
+				return err
+			}
+			logger.Info("replay finished", "numOfMsgs", replayed)
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64(flagFromHeight, 1, "the first height to replay")
+	cmd.Flags().Int64(flagToHeight, 0, "the last height to replay, default to the last one in the files")
+	cmd.Flags().String(flagTarget, replayTargetKafka, "where to replay to, kafka or http")
+	cmd.Flags().String(flagMsgTypes, "", "comma separated msg types to replay, e.g. ExecutionResults,Books, default to all the published ones")
+	cmd.Flags().String(flagDir, "", "the dir of the local market data files, default to <home>/marketdata")
+
+	return cmd
+}
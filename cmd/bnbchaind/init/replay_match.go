@@ -0,0 +1,298 @@
+package init
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/cli"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/node"
+	tmstore "github.com/tendermint/tendermint/store"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/bnb-chain/node/app"
+	configPkg "github.com/bnb-chain/node/app/config"
+	"github.com/bnb-chain/node/app/pub"
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/dex"
+	"github.com/bnb-chain/node/plugins/dex/order"
+)
+
+const (
+	flagToHeight  = "to"
+	flagDepth     = "depth"
+	flagOutput    = "output"
+	flagPublished = "published"
+)
+
+// ReplayMatchCmd replays the blocks after a breathe block onto its order book snapshot and writes what happened
+// to the order books at each height as json lines, which can be diffed against the published market data
+func ReplayMatchCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay-match",
+		Short: "Replay the matching from a breathe block and diff it against the published market data",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			logger := log.NewTMLogger(log.NewSyncWriter(os.Stderr))
+
+			config := ctx.Config
+			config.SetRoot(viper.GetString(cli.HomeFlag))
+			appCtx := configPkg.NewDefaultContext()
+			err := appCtx.ParseAppConfigInPlace()
+			if err != nil {
+				return err
+			}
+			app.SetUpgradeConfig(appCtx.BNBBeaconChainConfig.UpgradeConfig)
+
+			fromHeight := viper.GetInt64(flagHeight)
+			toHeight := viper.GetInt64(flagToHeight)
+			if fromHeight <= 0 {
+				return fmt.Errorf("--%s must be a breathe block height", flagHeight)
+			}
+
+			logger.Info("setup block db")
+			blockDB, err := node.DefaultDBProvider(&node.DBContext{ID: "blockstore", Config: config})
+			if err != nil {
+				return err
+			}
+			defer blockDB.Close()
+			blockStore := tmstore.NewBlockStore(blockDB)
+			if toHeight == 0 || toHeight > blockStore.Height() {
+				toHeight = blockStore.Height()
+			}
+
+			logger.Info("setup state db")
+			stateDB, err := node.DefaultDBProvider(&node.DBContext{ID: "state", Config: config})
+			if err != nil {
+				return err
+			}
+			defer stateDB.Close()
+
+			logger.Info("setup application db")
+			appDB, err := node.DefaultDBProvider(&node.DBContext{ID: "application", Config: config})
+			if err != nil {
+				return err
+			}
+			defer appDB.Close()
+
+			logger.Info("build cms")
+			cms := store.NewCommitMultiStore(appDB)
+			for _, name := range common.NonTransientStoreKeyNames {
+				cms.MountStoreWithDB(common.StoreKeyNameMap[name], sdk.StoreTypeIAVL, nil)
+			}
+			cms.MountStoreWithDB(common.TParamsStoreKey, sdk.StoreTypeTransient, nil)
+			cms.MountStoreWithDB(common.TStakeStoreKey, sdk.StoreTypeTransient, nil)
+
+			logger.Info("load latest version")
+			if err := cms.LoadLatestVersion(); err != nil {
+				return err
+			}
+			// the replay must never be written back to the application db
+			sdkCtx := sdk.NewContext(cms.CacheMultiStore(), abci.Header{}, sdk.RunTxModeDeliver, logger)
+
+			dexConfig := appCtx.BNBBeaconChainConfig.DexConfig
+			keeper := dex.NewDexKeeper(common.DexStoreKey,
+				auth.NewAccountKeeper(cdc, common.AccountStoreKey, types.ProtoAppAccount),
+				dex.NewTradingPairMapper(cdc, common.PairStoreKey), dex.DefaultCodespace,
+				appCtx.BNBBeaconChainConfig.BaseConfig.OrderKeeperConcurrency, cdc, false)
+			keeper.SetBUSDSymbol(dexConfig.BUSDSymbol)
+			if err := keeper.SetOrderBookBackend(order.PairType.BEP2, dexConfig.BEP2OrderBook); err != nil {
+				return err
+			}
+			if err := keeper.SetOrderBookBackend(order.PairType.MINI, dexConfig.MiniOrderBook); err != nil {
+				return err
+			}
+
+			logger.Info("load order book snapshot", "height", fromHeight)
+			if err := keeper.LoadOrderBookSnapshotAt(sdkCtx, fromHeight); err != nil {
+				return err
+			}
+
+			out := io.Writer(os.Stdout)
+			if path := viper.GetString(flagOutput); path != "" {
+				file, err := os.Create(path)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				out = file
+			}
+			writer := bufio.NewWriter(out)
+			defer writer.Flush()
+			encoder := json.NewEncoder(writer)
+
+			replayed := make(map[int64]order.ReplayedBlock)
+			diff := viper.GetString(flagPublished) != ""
+			logger.Info("start replay", "from", fromHeight, "to", toHeight)
+			err = keeper.ReplayMatch(sdkCtx, blockStore, stateDB, auth.DefaultTxDecoder(cdc), fromHeight, toHeight,
+				viper.GetInt(flagDepth), func(block order.ReplayedBlock) error {
+					if diff {
+						replayed[block.Height] = block
+					}
+					return encoder.Encode(block)
+				})
+			if err != nil || !diff {
+				return err
+			}
+
+			published, err := loadPublishedExecutionResults(viper.GetString(flagPublished), fromHeight, toHeight)
+			if err != nil {
+				return err
+			}
+			mismatches := diffReplayedBlocks(replayed, published)
+			for _, mismatch := range mismatches {
+				fmt.Fprintln(os.Stderr, mismatch)
+			}
+			if len(mismatches) != 0 {
+				return fmt.Errorf("%d mismatches against the published market data", len(mismatches))
+			}
+			logger.Info("replay matches the published market data")
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64(flagHeight, 0, "the breathe block height to replay from")
+	cmd.Flags().Int64(flagToHeight, 0, "the last height to replay, default to the latest block")
+	cmd.Flags().Int(flagDepth, 20, "the number of book levels to write per pair")
+	cmd.Flags().String(flagOutput, "", "the file to write the replayed blocks to, default to stdout")
+	cmd.Flags().String(flagPublished, "", "the published local market data file (marketdata.json or its .gz rotation) to diff against")
+	_ = cmd.MarkFlagRequired(flagHeight)
+
+	return cmd
+}
+
+// loadPublishedExecutionResults reads the execution results in (fromHeight, toHeight] from the local publisher output
+func loadPublishedExecutionResults(path string, fromHeight, toHeight int64) (map[int64]*pub.ExecutionResults, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := io.Reader(file)
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	return parsePublishedExecutionResults(reader, fromHeight, toHeight)
+}
+
+func parsePublishedExecutionResults(reader io.Reader, fromHeight, toHeight int64) (map[int64]*pub.ExecutionResults, error) {
+	results := make(map[int64]*pub.ExecutionResults)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 256*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(line, &fields); err != nil {
+			return nil, err
+		}
+		// other topics are published to the same file
+		if _, ok := fields["Trades"]; !ok {
+			continue
+		}
+		var result pub.ExecutionResults
+		if err := json.Unmarshal(line, &result); err != nil {
+			return nil, err
+		}
+		if result.Height > fromHeight && result.Height <= toHeight {
+			results[result.Height] = &result
+		}
+	}
+	return results, scanner.Err()
+}
+
+// diffReplayedBlocks compares the trades and the final status of the orders at each height
+func diffReplayedBlocks(replayed map[int64]order.ReplayedBlock, published map[int64]*pub.ExecutionResults) []string {
+	heights := make([]int64, 0, len(replayed))
+	for height := range replayed {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	mismatches := make([]string, 0)
+	for _, height := range heights {
+		block := replayed[height]
+		result, ok := published[height]
+		if !ok {
+			if len(block.Trades) != 0 || len(block.OrderChanges) != 0 {
+				mismatches = append(mismatches, fmt.Sprintf("height %d: not published", height))
+			}
+			continue
+		}
+
+		trades := make(map[string]int)
+		for _, t := range block.Trades {
+			trades[fmt.Sprintf("%s bid=%s sid=%s price=%d qty=%d", t.Symbol, t.Bid, t.Sid, t.Price, t.Qty)]++
+		}
+		for _, t := range result.Trades.Trades {
+			trades[fmt.Sprintf("%s bid=%s sid=%s price=%d qty=%d", t.Symbol, t.Bid, t.Sid, t.Price, t.Qty)]--
+		}
+		for _, trade := range sortedKeys(trades) {
+			if n := trades[trade]; n > 0 {
+				mismatches = append(mismatches, fmt.Sprintf("height %d: trade %s is only replayed", height, trade))
+			} else if n < 0 {
+				mismatches = append(mismatches, fmt.Sprintf("height %d: trade %s is only published", height, trade))
+			}
+		}
+
+		replayedStatus := make(map[string]order.ChangeType)
+		for _, change := range block.OrderChanges {
+			replayedStatus[change.OrderId] = change.Status
+		}
+		publishedStatus := make(map[string]order.ChangeType)
+		for _, o := range result.Orders.Orders {
+			// the failed txs are not replayed
+			if o.Status != order.FailedBlocking {
+				publishedStatus[o.OrderId] = o.Status
+			}
+		}
+		ids := make(map[string]int)
+		for id := range replayedStatus {
+			ids[id]++
+		}
+		for id := range publishedStatus {
+			ids[id]++
+		}
+		for _, id := range sortedKeys(ids) {
+			r, replayedOk := replayedStatus[id]
+			p, publishedOk := publishedStatus[id]
+			switch {
+			case !publishedOk:
+				mismatches = append(mismatches, fmt.Sprintf("height %d: order %s is only replayed as %s", height, id, r))
+			case !replayedOk:
+				mismatches = append(mismatches, fmt.Sprintf("height %d: order %s is only published as %s", height, id, p))
+			case r != p:
+				mismatches = append(mismatches, fmt.Sprintf("height %d: order %s is replayed as %s but published as %s", height, id, r, p))
+			}
+		}
+	}
+	return mismatches
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
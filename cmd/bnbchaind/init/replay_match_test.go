@@ -0,0 +1,60 @@
+package init
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/plugins/dex/order"
+)
+
+const publishedMarketData = `{"Height":2,"Timestamp":1,"NumOfMsgs":3,"Trades":{"NumOfMsgs":1,"Trades":[{"Id":"2-0","Symbol":"XYZ-000_BNB","Price":97000,"Qty":1000000,"Sid":"s1","Bid":"b1"}]},"Orders":{"NumOfMsgs":2,"Orders":[{"Symbol":"XYZ-000_BNB","Status":0,"OrderId":"b1"},{"Symbol":"XYZ-000_BNB","Status":5,"OrderId":"b1","CumQty":1000000},{"Symbol":"XYZ-000_BNB","Status":6,"OrderId":"s1","CumQty":1000000},{"Symbol":"XYZ-000_BNB","Status":7,"OrderId":"f1"}]}}
+{"Height":2,"Books":[]}
+{"Height":3,"Timestamp":2,"NumOfMsgs":1,"Trades":{"NumOfMsgs":0,"Trades":[]},"Orders":{"NumOfMsgs":1,"Orders":[{"Symbol":"XYZ-000_BNB","Status":1,"OrderId":"b2"}]}}
+{"Height":4,"Timestamp":3,"NumOfMsgs":0,"Trades":{"NumOfMsgs":0,"Trades":[]},"Orders":{"NumOfMsgs":0,"Orders":[]}}
+`
+
+func TestParsePublishedExecutionResults(t *testing.T) {
+	results, err := parsePublishedExecutionResults(strings.NewReader(publishedMarketData), 1, 3)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "s1", results[2].Trades.Trades[0].Sid)
+	require.Equal(t, order.FullyFill, results[2].Orders.Orders[2].Status)
+	require.Equal(t, order.Canceled, results[3].Orders.Orders[0].Status)
+}
+
+func TestDiffReplayedBlocks(t *testing.T) {
+	published, err := parsePublishedExecutionResults(strings.NewReader(publishedMarketData), 1, 3)
+	require.NoError(t, err)
+
+	replayed := map[int64]order.ReplayedBlock{
+		2: {
+			Height: 2,
+			Trades: []order.ReplayedTrade{{Symbol: "XYZ-000_BNB", Price: 97000, Qty: 1000000, Sid: "s1", Bid: "b1"}},
+			OrderChanges: []order.ReplayedOrderChange{
+				{Symbol: "XYZ-000_BNB", OrderId: "b1", Status: order.Ack},
+				{Symbol: "XYZ-000_BNB", OrderId: "b1", Status: order.PartialFill, CumQty: 1000000},
+				{Symbol: "XYZ-000_BNB", OrderId: "s1", Status: order.FullyFill, CumQty: 1000000},
+			},
+		},
+		3: {
+			Height:       3,
+			OrderChanges: []order.ReplayedOrderChange{{Symbol: "XYZ-000_BNB", OrderId: "b2", Status: order.Canceled}},
+		},
+	}
+	require.Empty(t, diffReplayedBlocks(replayed, published))
+
+	replayed[2].Trades[0].Qty = 2000000
+	replayed[3].OrderChanges[0].Status = order.Expired
+	replayed[4] = order.ReplayedBlock{
+		Height:       4,
+		OrderChanges: []order.ReplayedOrderChange{{Symbol: "XYZ-000_BNB", OrderId: "b3", Status: order.Ack}},
+	}
+	require.Equal(t, []string{
+		"height 2: trade XYZ-000_BNB bid=b1 sid=s1 price=97000 qty=1000000 is only published",
+		"height 2: trade XYZ-000_BNB bid=b1 sid=s1 price=97000 qty=2000000 is only replayed",
+		"height 3: order b2 is replayed as Expired but published as Canceled",
+		"height 4: not published",
+	}, diffReplayedBlocks(replayed, published))
+}
@@ -21,6 +21,7 @@ const (
 	IbcStoreName         = "ibc"
 	SideChainStoreName   = "sc"
 	ReconStoreName       = "recon"
+	AllowanceStoreName   = "allowance"
 
 	StakeTransientStoreName  = "transient_stake"
 	ParamsTransientStoreName = "transient_params"
@@ -46,6 +47,7 @@ var (
 	IbcStoreKey         = sdk.NewKVStoreKey(IbcStoreName)
 	SideChainStoreKey   = sdk.NewKVStoreKey(SideChainStoreName)
 	ReconStoreKey       = sdk.NewKVStoreKey(ReconStoreName)
+	AllowanceStoreKey   = sdk.NewKVStoreKey(AllowanceStoreName)
 
 	TStakeStoreKey  = sdk.NewTransientStoreKey(StakeTransientStoreName)
 	TParamsStoreKey = sdk.NewTransientStoreKey(ParamsTransientStoreName)
@@ -69,6 +71,7 @@ var (
 		BridgeStoreName:          BridgeStoreKey,
 		OracleStoreName:          OracleStoreKey,
 		ReconStoreName:           ReconStoreKey,
+		AllowanceStoreName:       AllowanceStoreKey,
 		StakeTransientStoreName:  TStakeStoreKey,
 		ParamsTransientStoreName: TParamsStoreKey,
 	}
@@ -92,6 +95,7 @@ var (
 		BridgeStoreName,
 		OracleStoreName,
 		ReconStoreName,
+		AllowanceStoreName,
 	}
 )
 
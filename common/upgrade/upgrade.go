@@ -53,6 +53,22 @@ const (
 	FirstSunset                 = sdk.FirstSunsetFork  // https://github.com/bnb-chain/BEPs/pull/333 BNB Chain Fusion
 	SecondSunset                = sdk.SecondSunsetFork // https://github.com/bnb-chain/BEPs/pull/333 BNB Chain Fusion
 	FinalSunset                 = sdk.FinalSunsetFork  // https://github.com/bnb-chain/BEPs/pull/333 BNB Chain Fusion
+
+	SwapIndexUpgrade             = "SwapIndexUpgrade"             // index atomic swaps by random number hash, status and expire height
+	TokenAllowanceUpgrade        = "TokenAllowanceUpgrade"        // approve/transferFrom of tokens by allowances
+	MarketOrderUpgrade           = "MarketOrderUpgrade"           // market orders priced at the protection band
+	PostOnlyUpgrade              = "PostOnlyUpgrade"              // post-only orders rejected when they would take liquidity
+	ConditionalOrderUpgrade      = "ConditionalOrderUpgrade"      // stop-limit and take-profit orders triggered by the last trade price
+	ReplaceOrderUpgrade          = "ReplaceOrderUpgrade"          // replace an order by a new one in one tx
+	BatchOrderUpgrade            = "BatchOrderUpgrade"            // place or cancel a batch of orders in one msg
+	SelfTradePreventionUpgrade   = "SelfTradePreventionUpgrade"   // cancel instead of trading the orders of the same sender
+	GoodTillOrderUpgrade         = "GoodTillOrderUpgrade"         // orders expiring at a given height or time
+	IcebergOrderUpgrade          = "IcebergOrderUpgrade"          // orders showing only a slice of their quantity in the order book
+	ContinuousMatchingUpgrade    = "ContinuousMatchingUpgrade"    // listing the trading pairs in the continuous matching mode
+	VestingTimeLockUpgrade       = "VestingTimeLockUpgrade"       // time locks released by a vesting schedule
+	TimeLockTransferUpgrade      = "TimeLockTransferUpgrade"      // time locks for a beneficiary and transfers of time lock records
+	SwapPartialClaimUpgrade      = "SwapPartialClaimUpgrade"      // claims of a part of the cross chain atomic swaps
+	SwapMultiAssetDepositUpgrade = "SwapMultiAssetDepositUpgrade" // deposits of the single chain atomic swaps asset by asset
 )
 
 func UpgradeBEP10(before func(), after func()) {
@@ -11,6 +11,7 @@ require (
 	github.com/go-kit/kit v0.10.0
 	github.com/google/btree v1.0.0
 	github.com/gorilla/mux v1.8.0
+	github.com/gorilla/websocket v1.5.0
 	github.com/hashicorp/golang-lru v0.5.5-0.20210104140557-80c98217689d
 	github.com/linkedin/goavro v0.0.0-20180427201934-fa8f6a30176c
 	github.com/mitchellh/go-homedir v1.1.0
@@ -25,6 +26,7 @@ require (
 	github.com/tendermint/tendermint v0.35.9
 	github.com/tidwall/gjson v1.14.3
 	go.uber.org/ratelimit v0.1.0
+	google.golang.org/protobuf v1.30.0
 )
 
 require (
@@ -65,7 +67,6 @@ require (
 	github.com/google/go-cmp v0.5.9 // indirect
 	github.com/google/gofuzz v1.2.0 // indirect
 	github.com/google/uuid v1.3.0 // indirect
-	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.0.1 // indirect
 	github.com/hashicorp/hcl v1.0.0 // indirect
 	github.com/herumi/bls-eth-go-binary v0.0.0-20210917013441-d37c07cfda4e // indirect
@@ -147,7 +148,6 @@ require (
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/genproto v0.0.0-20210624195500-8bfb893ecb84 // indirect
 	google.golang.org/grpc v1.40.0 // indirect
-	google.golang.org/protobuf v1.30.0 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/linkedin/goavro.v1 v1.0.5 // indirect
@@ -21,6 +21,8 @@ import (
 func ServeCommand(cdc *wire.Codec) *cobra.Command {
 	flagListenAddr := "laddr"
 	flagMaxOpenConnections := "max-open"
+	flagStreamSecret := "stream-secret"
+	flagStreamOrigins := "stream-origins"
 
 	cmd := &cobra.Command{
 		Use:   "api-server",
@@ -30,9 +32,14 @@ func ServeCommand(cdc *wire.Codec) *cobra.Command {
 				WithCodec(cdc).
 				WithAccountDecoder(types.GetAccountDecoder(cdc))
 			listenAddr := viper.GetString(flagListenAddr)
-			server := newServer(ctx, cdc).bindRoutes()
-			handler := server.router
 			logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "apiserv")
+			server := newServer(ctx, cdc, logger)
+			server.stream.secret = []byte(viper.GetString(flagStreamSecret))
+			server.stream.allowedOrigins = viper.GetStringSlice(flagStreamOrigins)
+			if len(server.stream.secret) == 0 {
+				logger.Info("the market data can not be posted to stream without --" + flagStreamSecret)
+			}
+			handler := server.bindRoutes().router
 			maxOpen := viper.GetInt(flagMaxOpenConnections)
 
 			cfg := &tmserver.Config{MaxOpenConnections: maxOpen}
@@ -65,6 +72,8 @@ func ServeCommand(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().String(sdk.FlagChainID, "", "The chain ID to connect to")
 	cmd.Flags().String(sdk.FlagNode, "tcp://localhost:26657", "Address of the node to connect to")
 	cmd.Flags().Int(flagMaxOpenConnections, 1000, "The number of maximum open connections")
+	cmd.Flags().String(flagStreamSecret, "", "The httpSecret of the node publishing the market data to stream, the publish route is disabled if empty")
+	cmd.Flags().StringSlice(flagStreamOrigins, nil, "Comma separated origins of the web pages allowed to connect to the streams besides the server itself, * to allow any")
 	cmd.Flags().Bool(sdk.FlagTrustNode, true, "Trust connected full node (don't verify proofs for responses)")
 
 	return cmd
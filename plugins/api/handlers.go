@@ -117,6 +117,30 @@ func (s *server) handleDexOpenOrdersReq(cdc *wire.Codec, ctx context.CLIContext)
 	return dexapi.OpenOrdersReqHandler(cdc, ctx)
 }
 
+func (s *server) handleClosedOrdersReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return hnd.ClosedOrdersReqHandler(cdc, ctx)
+}
+
+func (s *server) handleTradesReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return hnd.TradesReqHandler(cdc, ctx)
+}
+
+func (s *server) handleKlinesReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return hnd.KlinesReqHandler(cdc, ctx)
+}
+
+func (s *server) handleTicker24hrReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return hnd.Ticker24hrReqHandler(cdc, ctx)
+}
+
+func (s *server) handleStreamReq() http.HandlerFunc {
+	return s.stream.StreamReqHandler()
+}
+
+func (s *server) handleStreamPublishReq() http.HandlerFunc {
+	return s.stream.PublishReqHandler()
+}
+
 func (s *server) handleTokenReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
 	return tksapi.GetTokenReqHandler(cdc, ctx, false)
 }
@@ -161,10 +185,26 @@ func (s *server) handleTimeLockReq(cdc *wire.Codec, ctx context.CLIContext) http
 	return tksapi.GetTimeLockReqHandler(cdc, ctx)
 }
 
+func (s *server) handleAllowancesReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return tksapi.GetAllowancesReqHandler(cdc, ctx)
+}
+
+func (s *server) handleSpenderAllowancesReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return tksapi.GetSpenderAllowancesReqHandler(cdc, ctx)
+}
+
+func (s *server) handleAllowanceReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return tksapi.GetAllowanceReqHandler(cdc, ctx)
+}
+
 func (s *server) handleQuerySwapReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
 	return tksapi.QuerySwapReqHandler(cdc, ctx)
 }
 
+func (s *server) handleQuerySwapsReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return tksapi.QuerySwapsReqHandler(cdc, ctx)
+}
+
 func (s *server) handleQuerySwapIDsByCreatorReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
 	return tksapi.QuerySwapIDsByCreatorReqHandler(cdc, ctx)
 }
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/app/pub/history"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+// ClosedOrdersReqHandler queries for the closed orders of an address with their lifecycles, newest first,
+// indexed by the node with `indexHistory` enabled.
+func ClosedOrdersReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return historyReqHandler(cdc, ctx, "closedorders", func(bz []byte) (interface{}, error) {
+		orders := make([]history.Order, 0)
+		if len(bz) == 0 {
+			return orders, nil
+		}
+		err := cdc.UnmarshalBinaryLengthPrefixed(bz, &orders)
+		return orders, err
+	})
+}
+
+// TradesReqHandler queries for the trades of an address, newest first,
+// indexed by the node with `indexHistory` enabled.
+func TradesReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return historyReqHandler(cdc, ctx, "trades", func(bz []byte) (interface{}, error) {
+		trades := make([]history.Trade, 0)
+		if len(bz) == 0 {
+			return trades, nil
+		}
+		err := cdc.UnmarshalBinaryLengthPrefixed(bz, &trades)
+		return trades, err
+	})
+}
+
+func historyReqHandler(cdc *wire.Codec, ctx context.CLIContext, query string, decode func([]byte) (interface{}, error)) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, message string) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(message))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		address := r.FormValue("address")
+		if _, err := sdk.AccAddressFromBech32(address); err != nil {
+			throw(w, http.StatusBadRequest, "address is not a valid Bech32 address")
+			return
+		}
+		symbol := strings.ToUpper(r.FormValue("symbol"))
+		if symbol != "" {
+			if err := store.ValidatePairSymbol(symbol); err != nil {
+				throw(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		// offset and limit are optional
+		var args [2]int
+		for i, name := range []string{"offset", "limit"} {
+			if value := r.FormValue(name); value != "" {
+				v, err := strconv.Atoi(value)
+				if err != nil || v < 0 {
+					throw(w, http.StatusBadRequest, fmt.Sprintf("invalid %s %q", name, value))
+					return
+				}
+				args[i] = v
+			}
+		}
+
+		path := fmt.Sprintf("%s/%s/%s/%d/%d", history.AbciQueryPrefix, query, address, args[0], args[1])
+		if symbol != "" {
+			path += "/" + symbol
+		}
+		res, err := ctx.Query(path, nil)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, fmt.Sprintf("couldn't query %s. Error: %s", query, err.Error()))
+			return
+		}
+		result, err := decode(res)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, fmt.Sprintf("couldn't parse query result. Error: %s", err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(result)
+	}
+}
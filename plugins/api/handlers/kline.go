@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/app/pub/kline"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+// KlinesReqHandler queries for the candles of a symbol, aggregated by the node with `aggregateKline` enabled.
+func KlinesReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, message string) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(message))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := strings.ToUpper(r.FormValue("symbol"))
+		if err := store.ValidatePairSymbol(symbol); err != nil {
+			throw(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		interval := r.FormValue("interval")
+		if _, ok := kline.ParseInterval(interval); !ok {
+			throw(w, http.StatusBadRequest, fmt.Sprintf("invalid interval %q", interval))
+			return
+		}
+		// startTime, endTime and limit are optional
+		var args [3]int64
+		for i, name := range []string{"startTime", "endTime", "limit"} {
+			if value := r.FormValue(name); value != "" {
+				v, err := strconv.ParseInt(value, 10, 64)
+				if err != nil || v < 0 {
+					throw(w, http.StatusBadRequest, fmt.Sprintf("invalid %s %q", name, value))
+					return
+				}
+				args[i] = v
+			}
+		}
+
+		path := fmt.Sprintf("%s/klines/%s/%s/%d/%d/%d", kline.AbciQueryPrefix, symbol, interval, args[0], args[1], args[2])
+		res, err := ctx.Query(path, nil)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, fmt.Sprintf("couldn't query klines. Error: %s", err.Error()))
+			return
+		}
+		candles := make([]kline.Candle, 0)
+		if len(res) > 0 {
+			if err := cdc.UnmarshalBinaryLengthPrefixed(res, &candles); err != nil {
+				throw(w, http.StatusInternalServerError, fmt.Sprintf("couldn't parse query result. Error: %s", err.Error()))
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(candles)
+	}
+}
+
+// Ticker24hrReqHandler queries for the 24h ticker of a symbol, or the tickers of all the traded symbols if the
+// symbol is not specified.
+func Ticker24hrReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, message string) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(message))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := strings.ToUpper(r.FormValue("symbol"))
+		if symbol != "" {
+			if err := store.ValidatePairSymbol(symbol); err != nil {
+				throw(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		path := kline.AbciQueryPrefix + "/ticker"
+		if symbol != "" {
+			path += "/" + symbol
+		}
+		res, err := ctx.Query(path, nil)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, fmt.Sprintf("couldn't query tickers. Error: %s", err.Error()))
+			return
+		}
+		// the query will return empty if the symbol has never been traded
+		if len(res) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		tickers := make([]kline.Ticker, 0)
+		if err := cdc.UnmarshalBinaryLengthPrefixed(res, &tickers); err != nil {
+			throw(w, http.StatusInternalServerError, fmt.Sprintf("couldn't parse query result. Error: %s", err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if symbol != "" && len(tickers) > 0 {
+			_ = json.NewEncoder(w).Encode(tickers[0])
+		} else {
+			_ = json.NewEncoder(w).Encode(tickers)
+		}
+	}
+}
@@ -44,9 +44,32 @@ func (s *server) bindRoutes() *server {
 		Queries("address", "{address}", "symbol", "{symbol}").
 		Methods("GET")
 
+	// order and trade history, indexed by the node with `indexHistory` enabled
+	r.HandleFunc(prefix+"/orders/closed", s.handleClosedOrdersReq(s.cdc, s.ctx)).
+		Queries("address", "{address}").
+		Methods("GET")
+	r.HandleFunc(prefix+"/trades", s.handleTradesReq(s.cdc, s.ctx)).
+		Queries("address", "{address}").
+		Methods("GET")
+
 	r.HandleFunc(prefix+"/mini/markets", s.handleMiniPairsReq(s.cdc, s.ctx)).
 		Methods("GET")
 
+	// klines and tickers, aggregated by the node with `aggregateKline` enabled
+	r.HandleFunc(prefix+"/klines", s.handleKlinesReq(s.cdc, s.ctx)).
+		Queries("symbol", "{symbol}", "interval", "{interval}").
+		Methods("GET")
+	r.HandleFunc(prefix+"/ticker/24hr", s.handleTicker24hrReq(s.cdc, s.ctx)).
+		Methods("GET")
+
+	// market data streams
+	r.HandleFunc(prefix+"/stream", s.handleStreamReq()).
+		Methods("GET")
+	if len(s.stream.secret) != 0 {
+		r.HandleFunc(prefix+"/stream/publish", s.handleStreamPublishReq()).
+			Methods("POST")
+	}
+
 	// tokens routes
 	r.HandleFunc(prefix+"/tokens", s.handleTokensReq(s.cdc, s.ctx)).
 		Methods("GET")
@@ -77,6 +100,11 @@ func (s *server) bindRoutes() *server {
 	// time locks query
 	r.HandleFunc(prefix+"/timelock/timelocks/{address}", s.handleTimeLocksReq(s.cdc, s.ctx)).Methods("GET")
 	r.HandleFunc(prefix+"/timelock/timelock/{address}/{id}", s.handleTimeLockReq(s.cdc, s.ctx)).Methods("GET")
+	// allowances query
+	r.HandleFunc(prefix+"/allowance/allowances/{address}", s.handleAllowancesReq(s.cdc, s.ctx)).Methods("GET")
+	r.HandleFunc(prefix+"/allowance/spender/{address}", s.handleSpenderAllowancesReq(s.cdc, s.ctx)).Methods("GET")
+	r.HandleFunc(prefix+"/allowance/allowance/{owner}/{spender}/{symbol}", s.handleAllowanceReq(s.cdc, s.ctx)).Methods("GET")
+	r.HandleFunc(prefix+"/atomicswap", s.handleQuerySwapsReq(s.cdc, s.ctx)).Methods("GET")
 	r.HandleFunc(prefix+"/atomicswap/{swapID}", s.handleQuerySwapReq(s.cdc, s.ctx)).Methods("GET")
 	r.HandleFunc(prefix+"/atomicswap/creator/{creatorAddr}", s.handleQuerySwapIDsByCreatorReq(s.cdc, s.ctx)).
 		Queries("offset", "{offset:[0-9]+}", "limit", "{limit:[0-9]+}").
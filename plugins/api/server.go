@@ -7,6 +7,8 @@ import (
 	keyscli "github.com/cosmos/cosmos-sdk/client/keys"
 	"github.com/cosmos/cosmos-sdk/crypto/keys"
 
+	"github.com/tendermint/tendermint/libs/log"
+
 	"github.com/bnb-chain/node/common"
 	"github.com/bnb-chain/node/plugins/tokens"
 	"github.com/bnb-chain/node/wire"
@@ -30,10 +32,13 @@ type server struct {
 	tokens  tokens.Mapper
 
 	accStoreName string
+
+	// websocket streams of the market data posted by the node
+	stream *streamHub
 }
 
 // NewServer provides a new server structure.
-func newServer(ctx context.CLIContext, cdc *wire.Codec) *server {
+func newServer(ctx context.CLIContext, cdc *wire.Codec, logger log.Logger) *server {
 	kb, err := keyscli.GetKeyBase()
 	if err != nil {
 		panic(err)
@@ -47,5 +52,6 @@ func newServer(ctx context.CLIContext, cdc *wire.Codec) *server {
 		keyBase:      kb,
 		tokens:       tokens.NewMapper(cdc, common.TokenStoreKey),
 		accStoreName: common.AccountStoreName,
+		stream:       newStreamHub(cdc, ctx, logger.With("module", "stream")),
 	}
 }
@@ -0,0 +1,445 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/app/pub"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+// The api server streams the market data of the node to the websocket clients. The node POSTs the market data to
+// the api server with its http publisher (publishHttp = true, httpEncoding = "json", httpEndpoints pointing to
+// /api/v1/stream/publish, httpSecret the same as --stream-secret), then each update is sent to the clients
+// subscribed to its symbol or address. The publish route is not served without the secret.
+const (
+	StreamBooks    = "books"    // order book deltas of a symbol, with a snapshot on subscribe
+	StreamTrades   = "trades"   // trades of a symbol
+	StreamOrders   = "orders"   // order changes of an address
+	StreamAccounts = "accounts" // balance changes of an address
+
+	streamSubscribe   = "subscribe"
+	streamUnsubscribe = "unsubscribe"
+
+	// the msg types posted by the http publisher
+	streamMsgBooks            = "Books"
+	streamMsgExecutionResults = "ExecutionResults"
+	streamMsgAccounts         = "Accounts"
+
+	streamSnapshotLevels  = 100
+	streamMaxSubscription = 100
+	streamSendBufferSize  = 256
+	streamWriteWait       = 10 * time.Second
+	streamPingPeriod      = 30 * time.Second
+	streamMaxPostSize     = 100 * 1024 * 1024
+)
+
+// StreamRequest is sent by the clients to subscribe or unsubscribe the streams of the symbols or the address
+type StreamRequest struct {
+	Method  string   `json:"method"`
+	Topic   string   `json:"topic"`
+	Symbols []string `json:"symbols,omitempty"`
+	Address string   `json:"address,omitempty"`
+}
+
+// StreamMessage is an update of a stream. Seq increases by 1 with each update of the stream (topic and key),
+// so that the clients can detect the missing updates. A snapshot carries the seq of the last update sent before
+// it, the updates at or below the height of the snapshot are already applied to it.
+type StreamMessage struct {
+	Topic    string      `json:"topic"`
+	Key      string      `json:"key"` // symbol or address
+	Seq      int64       `json:"seq"`
+	Height   int64       `json:"height"`
+	Snapshot bool        `json:"snapshot,omitempty"`
+	Data     interface{} `json:"data"`
+}
+
+// StreamResponse answers a StreamRequest
+type StreamResponse struct {
+	Method string `json:"method"`
+	Topic  string `json:"topic"`
+	Error  string `json:"error,omitempty"`
+}
+
+type streamClient struct {
+	conn *websocket.Conn
+	send chan []byte
+
+	mtx  sync.Mutex
+	subs map[string]bool // stream key -> subscribed
+}
+
+func (c *streamClient) subscribed(key string) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.subs[key]
+}
+
+type streamHub struct {
+	secret         []byte   // verifies the signature of the posted market data
+	allowedOrigins []string // the origins of the browser clients besides the api server itself, "*" allows any
+	orderBook      func(symbol string) (*store.OrderBook, error)
+	upgrader       websocket.Upgrader
+	logger         log.Logger
+
+	mtx     sync.Mutex
+	seqs    map[string]int64 // stream key -> seq of the last update
+	clients map[*streamClient]struct{}
+}
+
+func newStreamHub(cdc *wire.Codec, ctx context.CLIContext, logger log.Logger) *streamHub {
+	h := &streamHub{
+		orderBook: func(symbol string) (*store.OrderBook, error) {
+			return store.GetOrderBook(cdc, ctx, symbol, streamSnapshotLevels)
+		},
+		logger:  logger,
+		seqs:    make(map[string]int64),
+		clients: make(map[*streamClient]struct{}),
+	}
+	h.upgrader.CheckOrigin = h.checkOrigin
+	return h
+}
+
+// checkOrigin accepts the clients which do not send the origin (i.e. not browsers), the pages served by the api
+// server itself and the allowed origins
+func (h *streamHub) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range h.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+func streamKey(topic, key string) string {
+	return topic + ":" + key
+}
+
+// broadcast sends the update to the clients subscribed to the stream. A client which can not keep up is dropped.
+func (h *streamHub) broadcast(topic, key string, height int64, data interface{}) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	sk := streamKey(topic, key)
+	h.seqs[sk]++
+	bz, err := json.Marshal(StreamMessage{Topic: topic, Key: key, Seq: h.seqs[sk], Height: height, Data: data})
+	if err != nil {
+		h.logger.Error("failed to marshal stream message", "stream", sk, "err", err)
+		return
+	}
+	for c := range h.clients {
+		if !c.subscribed(sk) {
+			continue
+		}
+		select {
+		case c.send <- bz:
+		default:
+			h.logger.Info("drop slow stream client", "remote", c.conn.RemoteAddr())
+			delete(h.clients, c)
+			close(c.send)
+		}
+	}
+}
+
+func (h *streamHub) register(c *streamClient) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.clients[c] = struct{}{}
+}
+
+func (h *streamHub) unregister(c *streamClient) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+// sendTo sends the message to the client unless it has been dropped
+func (h *streamHub) sendTo(c *streamClient, msg interface{}) {
+	bz, err := json.Marshal(msg)
+	if err != nil {
+		h.logger.Error("failed to marshal stream message", "err", err)
+		return
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if _, ok := h.clients[c]; !ok {
+		return
+	}
+	select {
+	case c.send <- bz:
+	default:
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+func (h *streamHub) subscribe(c *streamClient, req StreamRequest) error {
+	keys, err := streamRequestKeys(req)
+	if err != nil {
+		return err
+	}
+	c.mtx.Lock()
+	if req.Method == streamUnsubscribe {
+		for _, key := range keys {
+			delete(c.subs, streamKey(req.Topic, key))
+		}
+		c.mtx.Unlock()
+		return nil
+	}
+	if len(c.subs)+len(keys) > streamMaxSubscription {
+		c.mtx.Unlock()
+		return fmt.Errorf("at most %d subscriptions per connection", streamMaxSubscription)
+	}
+	for _, key := range keys {
+		c.subs[streamKey(req.Topic, key)] = true
+	}
+	c.mtx.Unlock()
+
+	if req.Topic == StreamBooks {
+		for _, symbol := range keys {
+			h.mtx.Lock()
+			seq := h.seqs[streamKey(StreamBooks, symbol)]
+			h.mtx.Unlock()
+			book, err := h.orderBook(symbol)
+			if err != nil {
+				return err
+			}
+			if book == nil {
+				return fmt.Errorf("no order book of %s", symbol)
+			}
+			h.sendTo(c, StreamMessage{Topic: StreamBooks, Key: symbol, Seq: seq, Height: book.Height, Snapshot: true, Data: book.Levels})
+		}
+	}
+	return nil
+}
+
+func streamRequestKeys(req StreamRequest) ([]string, error) {
+	if req.Method != streamSubscribe && req.Method != streamUnsubscribe {
+		return nil, fmt.Errorf("unknown method %q", req.Method)
+	}
+	switch req.Topic {
+	case StreamBooks, StreamTrades:
+		if len(req.Symbols) == 0 {
+			return nil, errors.New("symbols must not be empty")
+		}
+		keys := make([]string, len(req.Symbols))
+		for i, symbol := range req.Symbols {
+			if err := store.ValidatePairSymbol(symbol); err != nil {
+				return nil, err
+			}
+			keys[i] = strings.ToUpper(symbol)
+		}
+		return keys, nil
+	case StreamOrders, StreamAccounts:
+		if _, err := sdk.AccAddressFromBech32(req.Address); err != nil {
+			return nil, err
+		}
+		return []string{req.Address}, nil
+	default:
+		return nil, fmt.Errorf("unknown topic %q", req.Topic)
+	}
+}
+
+// StreamReqHandler upgrades the request to a websocket connection which serves the StreamRequests of the client
+func (h *streamHub) StreamReqHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := h.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			h.logger.Error("failed to upgrade stream connection", "err", err)
+			return
+		}
+		c := &streamClient{conn: conn, send: make(chan []byte, streamSendBufferSize), subs: make(map[string]bool)}
+		h.register(c)
+		go h.writeLoop(c)
+		h.readLoop(c)
+	}
+}
+
+func (h *streamHub) readLoop(c *streamClient) {
+	defer func() {
+		h.unregister(c)
+		_ = c.conn.Close()
+	}()
+	for {
+		var req StreamRequest
+		if err := c.conn.ReadJSON(&req); err != nil {
+			if _, ok := err.(*json.UnmarshalTypeError); ok {
+				h.sendTo(c, StreamResponse{Error: err.Error()})
+				continue
+			}
+			return
+		}
+		resp := StreamResponse{Method: req.Method, Topic: req.Topic}
+		if err := h.subscribe(c, req); err != nil {
+			resp.Error = err.Error()
+		}
+		h.sendTo(c, resp)
+	}
+}
+
+func (h *streamHub) writeLoop(c *streamClient) {
+	ticker := time.NewTicker(streamPingPeriod)
+	defer func() {
+		ticker.Stop()
+		_ = c.conn.Close()
+	}()
+	for {
+		select {
+		case bz, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if !ok {
+				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, bz); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// the fields of the published json market data which the streams are keyed by, the rest are passed through
+type publishedBooks struct {
+	Height int64
+	Books  []json.RawMessage
+}
+
+type publishedExecutionResults struct {
+	Height int64
+	Trades struct{ Trades []json.RawMessage }
+	Orders struct{ Orders []json.RawMessage }
+}
+
+type publishedAccounts struct {
+	Height   int64
+	Accounts []json.RawMessage
+}
+
+type publishedKeys struct {
+	Symbol string
+	Owner  string
+}
+
+// PublishReqHandler receives the market data posted by the http publisher of the node, signed with the secret
+func (h *streamHub) PublishReqHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, streamMaxPostSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(h.secret) == 0 {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		if err := pub.VerifyHttpSignature(h.secret, r.Header, body, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err := h.dispatch(r.Header.Get(pub.HttpHeaderMsgType), body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// dispatch broadcasts the posted json array of the msg type, the other msg types are ignored
+func (h *streamHub) dispatch(msgType string, body []byte) error {
+	switch msgType {
+	case streamMsgBooks:
+		var msgs []publishedBooks
+		if err := json.Unmarshal(body, &msgs); err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			if err := h.broadcastEach(StreamBooks, msg.Height, msg.Books, func(k publishedKeys) string { return k.Symbol }); err != nil {
+				return err
+			}
+		}
+	case streamMsgExecutionResults:
+		var msgs []publishedExecutionResults
+		if err := json.Unmarshal(body, &msgs); err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			if err := h.broadcastGrouped(StreamTrades, msg.Height, msg.Trades.Trades, func(k publishedKeys) string { return k.Symbol }); err != nil {
+				return err
+			}
+			if err := h.broadcastGrouped(StreamOrders, msg.Height, msg.Orders.Orders, func(k publishedKeys) string { return k.Owner }); err != nil {
+				return err
+			}
+		}
+	case streamMsgAccounts:
+		var msgs []publishedAccounts
+		if err := json.Unmarshal(body, &msgs); err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			if err := h.broadcastEach(StreamAccounts, msg.Height, msg.Accounts, func(k publishedKeys) string { return k.Owner }); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// broadcastEach sends each item as an update of its stream
+func (h *streamHub) broadcastEach(topic string, height int64, items []json.RawMessage, keyOf func(publishedKeys) string) error {
+	for _, item := range items {
+		var keys publishedKeys
+		if err := json.Unmarshal(item, &keys); err != nil {
+			return err
+		}
+		h.broadcast(topic, keyOf(keys), height, item)
+	}
+	return nil
+}
+
+// broadcastGrouped sends the items of a stream at the height as one update, in the published order
+func (h *streamHub) broadcastGrouped(topic string, height int64, items []json.RawMessage, keyOf func(publishedKeys) string) error {
+	grouped := make(map[string][]json.RawMessage)
+	order := make([]string, 0)
+	for _, item := range items {
+		var keys publishedKeys
+		if err := json.Unmarshal(item, &keys); err != nil {
+			return err
+		}
+		key := keyOf(keys)
+		if _, ok := grouped[key]; !ok {
+			order = append(order, key)
+		}
+		grouped[key] = append(grouped[key], item)
+	}
+	for _, key := range order {
+		h.broadcast(topic, key, height, grouped[key])
+	}
+	return nil
+}
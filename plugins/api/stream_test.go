@@ -0,0 +1,179 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/app/pub"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+var streamTestAddr = sdk.AccAddress("stream-test-address1").String()
+
+func newStreamTestServer(t *testing.T, secret string) (*httptest.Server, *websocket.Conn) {
+	hub := &streamHub{
+		secret: []byte(secret),
+		orderBook: func(symbol string) (*store.OrderBook, error) {
+			return &store.OrderBook{Height: 10, Levels: []store.OrderBookLevel{}}, nil
+		},
+		logger:  log.NewNopLogger(),
+		seqs:    make(map[string]int64),
+		clients: make(map[*streamClient]struct{}),
+	}
+	hub.upgrader.CheckOrigin = hub.checkOrigin
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stream", hub.StreamReqHandler())
+	mux.HandleFunc("/publish", hub.PublishReqHandler())
+	server := httptest.NewServer(mux)
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/stream", nil)
+	require.NoError(t, err)
+	return server, conn
+}
+
+func readStream(t *testing.T, conn *websocket.Conn) (msg StreamMessage) {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	require.NoError(t, conn.ReadJSON(&msg))
+	return
+}
+
+func readResponse(t *testing.T, conn *websocket.Conn) (resp StreamResponse) {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	require.NoError(t, conn.ReadJSON(&resp))
+	return
+}
+
+func postStream(t *testing.T, url, msgType, secret, body string) int {
+	req, err := http.NewRequest(http.MethodPost, url+"/publish", bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+	req.Header.Set(pub.HttpHeaderMsgType, msgType)
+	if secret != "" {
+		timestamp := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+		req.Header.Set(pub.HttpHeaderTimestamp, timestamp)
+		req.Header.Set(pub.HttpHeaderSignature, pub.SignHttpBody([]byte(secret), timestamp, []byte(body)))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestStream_Subscribe(t *testing.T) {
+	server, conn := newStreamTestServer(t, "secret")
+	defer server.Close()
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(StreamRequest{Method: streamSubscribe, Topic: "unknown"}))
+	resp := readResponse(t, conn)
+	require.Equal(t, `unknown topic "unknown"`, resp.Error)
+
+	// snapshot of the books
+	require.NoError(t, conn.WriteJSON(StreamRequest{Method: streamSubscribe, Topic: StreamBooks, Symbols: []string{"xyz-000_bnb"}}))
+	snapshot := readStream(t, conn)
+	require.True(t, snapshot.Snapshot)
+	require.Equal(t, "XYZ-000_BNB", snapshot.Key)
+	require.Equal(t, int64(10), snapshot.Height)
+	resp = readResponse(t, conn)
+	require.Empty(t, resp.Error)
+
+	require.NoError(t, conn.WriteJSON(StreamRequest{Method: streamSubscribe, Topic: StreamOrders, Address: streamTestAddr}))
+	resp = readResponse(t, conn)
+	require.Empty(t, resp.Error)
+
+	books := `[{"Height":11,"Books":[{"Symbol":"ABC-000_BNB","Buys":[],"Sells":[]},{"Symbol":"XYZ-000_BNB","Buys":[{"Price":1,"LastQty":2}],"Sells":[]}]}]`
+	require.Equal(t, http.StatusUnauthorized, postStream(t, server.URL, streamMsgBooks, "", books))
+	require.Equal(t, http.StatusOK, postStream(t, server.URL, streamMsgBooks, "secret", books))
+	update := readStream(t, conn)
+	require.Equal(t, StreamBooks, update.Topic)
+	require.Equal(t, "XYZ-000_BNB", update.Key)
+	require.Equal(t, int64(1), update.Seq)
+	require.Equal(t, int64(11), update.Height)
+	require.Equal(t, "XYZ-000_BNB", update.Data.(map[string]interface{})["Symbol"])
+
+	results := `[{"Height":12,"Trades":{"NumOfMsgs":0,"Trades":[]},"Orders":{"NumOfMsgs":3,"Orders":[` +
+		`{"Symbol":"XYZ-000_BNB","OrderId":"1","Owner":"` + streamTestAddr + `"},` +
+		`{"Symbol":"XYZ-000_BNB","OrderId":"2","Owner":"` + sdk.AccAddress("stream-test-address2").String() + `"},` +
+		`{"Symbol":"XYZ-000_BNB","OrderId":"3","Owner":"` + streamTestAddr + `"}]}}]`
+	require.Equal(t, http.StatusOK, postStream(t, server.URL, streamMsgExecutionResults, "secret", results))
+	update = readStream(t, conn)
+	require.Equal(t, StreamOrders, update.Topic)
+	require.Equal(t, int64(1), update.Seq)
+	require.Len(t, update.Data, 2)
+	require.Equal(t, "3", update.Data.([]interface{})[1].(map[string]interface{})["OrderId"])
+
+	// no more updates after unsubscribe
+	require.NoError(t, conn.WriteJSON(StreamRequest{Method: streamUnsubscribe, Topic: StreamBooks, Symbols: []string{"XYZ-000_BNB"}}))
+	resp = readResponse(t, conn)
+	require.Equal(t, streamUnsubscribe, resp.Method)
+	require.Equal(t, http.StatusOK, postStream(t, server.URL, streamMsgBooks, "secret", books))
+	require.Equal(t, http.StatusOK, postStream(t, server.URL, streamMsgExecutionResults, "secret", results))
+	update = readStream(t, conn)
+	require.Equal(t, StreamOrders, update.Topic)
+	require.Equal(t, int64(2), update.Seq)
+}
+
+func TestStream_InvalidPublish(t *testing.T) {
+	server, conn := newStreamTestServer(t, "")
+	// nothing can be posted without the secret
+	require.Equal(t, http.StatusUnauthorized, postStream(t, server.URL, streamMsgAccounts, "", `[{"Height":1}]`))
+	server.Close()
+	conn.Close()
+
+	server, conn = newStreamTestServer(t, "secret")
+	defer server.Close()
+	defer conn.Close()
+
+	require.Equal(t, http.StatusBadRequest, postStream(t, server.URL, streamMsgAccounts, "secret", `{"Height":1}`))
+	// other msg types are ignored
+	require.Equal(t, http.StatusOK, postStream(t, server.URL, "BlockFee", "secret", `[{"Height":1}]`))
+
+	require.NoError(t, conn.WriteJSON(StreamRequest{Method: streamSubscribe, Topic: StreamAccounts, Address: "bnb1"}))
+	resp := readResponse(t, conn)
+	require.NotEmpty(t, resp.Error)
+	require.NoError(t, conn.WriteJSON(StreamRequest{Method: streamSubscribe, Topic: StreamAccounts, Address: streamTestAddr}))
+	resp = readResponse(t, conn)
+	require.Empty(t, resp.Error)
+
+	accounts := `[{"Height":3,"NumOfMsgs":1,"Accounts":[{"Owner":"` + streamTestAddr + `","Sequence":1,"Balances":[]}]}]`
+	require.Equal(t, http.StatusOK, postStream(t, server.URL, streamMsgAccounts, "secret", accounts))
+	update := readStream(t, conn)
+	require.Equal(t, StreamAccounts, update.Topic)
+	require.Equal(t, streamTestAddr, update.Key)
+	require.Equal(t, int64(3), update.Height)
+}
+
+func TestStream_CheckOrigin(t *testing.T) {
+	hub := &streamHub{allowedOrigins: []string{"https://www.example.com"}}
+	request := func(origin string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/stream", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		return r
+	}
+	require.True(t, hub.checkOrigin(request("")))
+	require.True(t, hub.checkOrigin(request("http://localhost:8080")))
+	require.True(t, hub.checkOrigin(request("https://WWW.example.com")))
+	require.False(t, hub.checkOrigin(request("https://evil.example.com")))
+	hub.allowedOrigins = []string{"*"}
+	require.True(t, hub.checkOrigin(request("https://evil.example.com")))
+
+	// the browsers of the other origins can not connect
+	server, conn := newStreamTestServer(t, "secret")
+	defer server.Close()
+	defer conn.Close()
+	_, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/stream",
+		http.Header{"Origin": []string{"https://evil.example.com"}})
+	require.Equal(t, websocket.ErrBadHandshake, err)
+}
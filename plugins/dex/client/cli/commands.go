@@ -25,7 +25,8 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 			listMiniTradingPairCmd(cdc),
 			client.LineBreak,
 			newOrderCmd(cdc),
-			cancelOrderCmd(cdc))...)
+			cancelOrderCmd(cdc),
+			replaceOrderCmd(cdc))...)
 	dexCmd.AddCommand(
 		client.GetCommands(
 			showOrderBookCmd(cdc))...)
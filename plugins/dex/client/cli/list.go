@@ -19,6 +19,7 @@ const flagBaseAsset = "base-asset-symbol"
 const flagQuoteAsset = "quote-asset-symbol"
 const flagInitPrice = "init-price"
 const flagProposalId = "proposal-id"
+const flagMatchingMode = "matching-mode"
 
 func listTradingPairCmd(cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
@@ -59,6 +60,7 @@ func listTradingPairCmd(cdc *wire.Codec) *cobra.Command {
 			}
 
 			msg := dextypes.NewListMsg(from, proposalId, baseAsset, quoteAsset, initPrice)
+			msg.MatchingMode = viper.GetString(flagMatchingMode)
 			err = client.SendOrPrintTx(cliCtx, txbldr, msg)
 			if err != nil {
 				return err
@@ -72,6 +74,7 @@ func listTradingPairCmd(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().String(flagQuoteAsset, "", "symbol of the quote currency")
 	cmd.Flags().String(flagInitPrice, "", "init price for this pair")
 	cmd.Flags().Int64(flagProposalId, 0, "list proposal id")
+	cmd.Flags().String(flagMatchingMode, "", "matching mode of the pair, auction or continuous, auction by default")
 
 	return cmd
 }
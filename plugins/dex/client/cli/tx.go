@@ -29,11 +29,17 @@ const (
 	flagQty         = "qty"
 	flagSide        = "side"
 	flagTimeInForce = "tif"
+	flagOrderType   = "type"
+	flagStopPrice   = "stop-price"
+	flagStp         = "stp"
+	flagExpHeight   = "expire-height"
+	flagExpTime     = "expire-time"
+	flagDisplayQty  = "display-qty"
 )
 
 func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "order -l <pair> -s <side> -p <price> -q <qty> -t <timeInForce>",
+		Use:   "order -l <pair> -s <side> -p <price> -q <qty> -t <timeInForce> [--type <orderType>]",
 		Short: "Submit a new order",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx, txBldr := client.PrepareCtx(cdc)
@@ -50,12 +56,21 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 
 			symbol = strings.ToUpper(symbol)
 
-			priceStr := viper.GetString(flagPrice)
-			price, err := utils.ParsePrice(priceStr)
+			orderType, err := order.OrderTypeStringToOrderTypeCode(viper.GetString(flagOrderType))
 			if err != nil {
 				return err
 			}
 
+			// market orders are priced by the protection band of the match engine
+			var price int64
+			if orderType != order.OrderType.MARKET {
+				priceStr := viper.GetString(flagPrice)
+				price, err = utils.ParsePrice(priceStr)
+				if err != nil {
+					return err
+				}
+			}
+
 			qtyStr := viper.GetString(flagQty)
 			qty, err := utils.ParsePrice(qtyStr)
 			if err != nil {
@@ -66,6 +81,10 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			stp, err := order.StpStringToStpCode(viper.GetString(flagStp))
+			if err != nil {
+				return err
+			}
 			side := int8(viper.GetInt(flagSide))
 
 			// avoids an ugly panin sequence 0 with --dry
@@ -89,7 +108,23 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 				return err
 			}
 
+			msg.OrderType = orderType
 			msg.TimeInForce = tif
+			msg.SelfTradePrevention = stp
+			msg.ExpireHeight = viper.GetInt64(flagExpHeight)
+			msg.ExpireTime = viper.GetInt64(flagExpTime)
+			if displayQtyStr := viper.GetString(flagDisplayQty); displayQtyStr != "" {
+				msg.DisplayQuantity, err = utils.ParsePrice(displayQtyStr)
+				if err != nil {
+					return err
+				}
+			}
+			if order.IsConditionalOrderType(orderType) {
+				msg.StopPrice, err = utils.ParsePrice(viper.GetString(flagStopPrice))
+				if err != nil {
+					return err
+				}
+			}
 
 			err = client.SendOrPrintTx(cliCtx, txBldr, msg)
 			if err != nil {
@@ -104,7 +139,13 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().StringP(flagSide, "s", "", "side (buy as 1 or sell as 2) of the order")
 	cmd.Flags().StringP(flagPrice, "p", "", "price for the order")
 	cmd.Flags().StringP(flagQty, "q", "", "quantity for the order")
-	cmd.Flags().StringP(flagTimeInForce, "t", "gte", "TimeInForce for the order (gte or ioc)")
+	cmd.Flags().StringP(flagTimeInForce, "t", "gte", "TimeInForce for the order (gte, ioc or postonly)")
+	cmd.Flags().String(flagOrderType, "limit", "type of the order (limit, market, stoplimit or takeprofit), market orders must be ioc and take no price")
+	cmd.Flags().String(flagStopPrice, "", "trigger price of stoplimit and takeprofit orders")
+	cmd.Flags().String(flagStp, "none", "self-trade prevention of the order (none, cancelnewest, canceloldest or cancelboth)")
+	cmd.Flags().Int64(flagExpHeight, 0, "good-till-block, the order expires after the match of this height")
+	cmd.Flags().Int64(flagExpTime, 0, "good-till-time in unix seconds, the order expires after the match of the first block at or after it")
+	cmd.Flags().String(flagDisplayQty, "", "iceberg, the quantity shown in the order book at a time, the rest is hidden")
 	return cmd
 }
 
@@ -183,6 +224,62 @@ func cancelOrderCmd(cdc *wire.Codec) *cobra.Command {
 	return cmd
 }
 
+func replaceOrderCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replace -l <trading pair> -f <ref order id> -p <price> -q <qty>",
+		Short: "Cancel an order and place a new one at the given price and qty in one tx",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx, txBldr := client.PrepareCtx(cdc)
+			from, err := cliCtx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			symbol := viper.GetString(flagSymbol)
+			err = validatePairSymbol(symbol)
+			if err != nil {
+				return err
+			}
+			symbol = strings.ToUpper(symbol)
+			refId := viper.GetString(flagRefId)
+			if refId == "" {
+				return errors.New("please input reference order id")
+			}
+			price, err := utils.ParsePrice(viper.GetString(flagPrice))
+			if err != nil {
+				return err
+			}
+			qty, err := utils.ParsePrice(viper.GetString(flagQty))
+			if err != nil {
+				return err
+			}
+
+			if viper.GetBool(clientflag.FlagOffline) {
+				txBldr = txBldr.WithSequence(viper.GetInt64(clientflag.FlagSequence))
+			} else {
+				err = client.EnsureSequence(cliCtx, &txBldr)
+				if err != nil {
+					return err
+				}
+			}
+
+			id := order.GenerateOrderID(txBldr.Sequence+1, from)
+			msg := order.NewReplaceOrderMsg(from, id, symbol, refId, price, qty)
+			err = client.SendOrPrintTx(cliCtx, txBldr, msg)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Msg [%v] was sent.\n", msg)
+			return nil
+		},
+	}
+	cmd.Flags().StringP(flagSymbol, "l", "", "the listed trading pair, such as ADA_BNB")
+	cmd.Flags().StringP(flagRefId, "f", "", "id string of the order to replace")
+	cmd.Flags().StringP(flagPrice, "p", "", "price for the new order")
+	cmd.Flags().StringP(flagQty, "q", "", "quantity for the new order")
+	return cmd
+}
+
 func validatePairSymbol(symbol string) error {
 	return store.ValidatePairSymbol(symbol)
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
@@ -28,6 +29,12 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 		price   string
 		qty     string
 		tif     string
+		tpe     string
+		stopPx  string
+		stp     string
+		expH    string
+		expT    string
+		display string
 	}
 
 	type response struct {
@@ -49,7 +56,8 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 		if strings.TrimSpace(params.side) == "" {
 			return false
 		}
-		if strings.TrimSpace(params.price) == "" {
+		// market orders do not take a price
+		if strings.TrimSpace(params.price) == "" && !strings.EqualFold(strings.TrimSpace(params.tpe), "market") {
 			return false
 		}
 		if strings.TrimSpace(params.qty) == "" {
@@ -72,6 +80,12 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 			price:   r.FormValue("price"),
 			qty:     r.FormValue("qty"),
 			tif:     r.FormValue("tif"),
+			tpe:     r.FormValue("type"),
+			stopPx:  r.FormValue("stop_price"),
+			stp:     r.FormValue("stp"),
+			expH:    r.FormValue("expire_height"),
+			expT:    r.FormValue("expire_time"),
+			display: r.FormValue("display_qty"),
 		}
 
 		if !validateFormParams(params) {
@@ -110,10 +124,22 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 		}
 		pair := strings.ToUpper(params.pair)
 
-		price, err := utils.ParsePrice(params.price)
-		if err != nil {
-			throw(w, http.StatusInternalServerError, err)
-			return
+		orderType := order.OrderType.LIMIT
+		if strings.TrimSpace(params.tpe) != "" {
+			orderType, err = order.OrderTypeStringToOrderTypeCode(params.tpe)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+		}
+
+		var price int64
+		if orderType != order.OrderType.MARKET {
+			price, err = utils.ParsePrice(params.price)
+			if err != nil {
+				throw(w, http.StatusInternalServerError, err)
+				return
+			}
 		}
 
 		qty, err := utils.ParsePrice(params.qty)
@@ -141,6 +167,42 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 		if tif > -1 {
 			msg.TimeInForce = tif
 		}
+		msg.OrderType = orderType
+		if order.IsConditionalOrderType(orderType) {
+			msg.StopPrice, err = utils.ParsePrice(params.stopPx)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+		}
+		if strings.TrimSpace(params.stp) != "" {
+			msg.SelfTradePrevention, err = order.StpStringToStpCode(params.stp)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+		}
+		if strings.TrimSpace(params.expH) != "" {
+			msg.ExpireHeight, err = strconv.ParseInt(strings.TrimSpace(params.expH), 10, 64)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+		}
+		if strings.TrimSpace(params.expT) != "" {
+			msg.ExpireTime, err = strconv.ParseInt(strings.TrimSpace(params.expT), 10, 64)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+		}
+		if strings.TrimSpace(params.display) != "" {
+			msg.DisplayQuantity, err = utils.ParsePrice(strings.TrimSpace(params.display))
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+		}
 		msgs := []sdk.Msg{msg}
 
 		// build the tx
@@ -127,6 +127,8 @@ func handleList(ctx sdk.Context, keeper *order.DexKeeper, tokenMapper tokens.Map
 		lotSize = utils.CalcLotSize(msg.InitPrice)
 	}
 	pair := types.NewTradingPairWithLotSize(msg.BaseAssetSymbol, msg.QuoteAssetSymbol, msg.InitPrice, lotSize)
+	// the matching mode has been checked by ValidateBasic
+	pair.MatchingMode, _ = types.MatchingModeStringToCode(msg.MatchingMode)
 	err = keeper.PairMapper.AddTradingPair(ctx, pair)
 	if err != nil {
 		return sdk.ErrInternal(err.Error()).Result()
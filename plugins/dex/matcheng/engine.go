@@ -18,6 +18,8 @@ type MatchEng struct {
 	// in order to determine the trade price. Though it is saved as int64,
 	// it would be converted into a float when the match engine is created.
 	PriceLimitPct float64
+	// Continuous is true if the orders are matched on arrival by MatchContinuous, instead of by the call auction of Match
+	Continuous bool
 	// all the below are buffers
 	overLappedLevel []OverLappedLevel
 	buyBuf          []PriceLevel
@@ -25,15 +27,23 @@ type MatchEng struct {
 	maxExec         LevelIndex
 	leastSurplus    SurplusIndex
 	Trades          []Trade
-	LastTradePrice  int64
-	logger          tmlog.Logger
+	// SelfTradeCanceled are the ids of the orders canceled by the self-trade prevention in the last match,
+	// they are left in the order book for the caller to remove
+	SelfTradeCanceled []string
+	LastTradePrice    int64
+	logger            tmlog.Logger
 }
 
 // NewMatchEng constructs a new MatchEng.
 func NewMatchEng(pairSymbol string, basePrice, lotSize int64, priceLimit float64) *MatchEng {
+	return NewMatchEngOnBook(pairSymbol, basePrice, lotSize, priceLimit, NewOrderBookOnULList(10000, 16))
+}
+
+// NewMatchEngOnBook constructs a new MatchEng matching the orders in `book`.
+func NewMatchEngOnBook(pairSymbol string, basePrice, lotSize int64, priceLimit float64, book OrderBookInterface) *MatchEng {
 	return &MatchEng{
 		LastMatchHeight: 0,
-		Book:            NewOrderBookOnULList(10000, 16),
+		Book:            book,
 		LotSize:         lotSize,
 		PriceLimitPct:   priceLimit,
 		overLappedLevel: make([]OverLappedLevel, 0, 16),
@@ -47,6 +57,26 @@ func NewMatchEng(pairSymbol string, basePrice, lotSize int64, priceLimit float64
 	}
 }
 
+// MarketProtectionPrice returns the worst price a market order on `side` may be executed at.
+// The protection band is derived from LastTradePrice and PriceLimitPct, the same range
+// the call auction uses to choose the concluded price under market pressure.
+func (me *MatchEng) MarketProtectionPrice(side int8) int64 {
+	refPrice := float64(me.LastTradePrice)
+	var price float64
+	if side == BUYSIDE {
+		price = math.Ceil(refPrice * (1.0 + me.PriceLimitPct))
+	} else {
+		price = math.Floor(refPrice * (1.0 - me.PriceLimitPct))
+	}
+	if price >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	if price < PRECISION {
+		return PRECISION
+	}
+	return int64(price)
+}
+
 // fillOrders would fill the orders at BuyOrders[i] and SellOrders[j] against each other.
 // At least one side would be fully filled.
 func (me *MatchEng) fillOrders(i int, j int) {
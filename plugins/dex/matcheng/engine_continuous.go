@@ -0,0 +1,141 @@
+package matcheng
+
+import (
+	"fmt"
+
+	"github.com/bnb-chain/node/common/utils"
+)
+
+// In the continuous mode, the orders of a pair are matched on arrival in price-time priority, instead of by the call
+// auction run by Match once per block. The order book is never left crossed after an order is matched, so when a new
+// order is inserted, the only overlap is between the new order and the resting orders on the other side.
+
+// WouldTake returns true if an order on `side` at `price` would trade with the resting orders of the other side
+func (me *MatchEng) WouldTake(side int8, price int64) bool {
+	var best *PriceLevel
+	skip := func(p *PriceLevel, levelIndex int) {}
+	top := func(p *PriceLevel, levelIndex int) { best = p }
+	if side == BUYSIDE {
+		me.Book.ShowDepth(1, skip, top)
+	} else {
+		me.Book.ShowDepth(1, top, skip)
+	}
+	if best == nil {
+		return false
+	}
+	if side == BUYSIDE {
+		return compareBuy(price, best.Price) >= 0
+	}
+	return compareBuy(price, best.Price) <= 0
+}
+
+// MatchContinuous matches the order `id`, which has just been inserted into the book on `side` at `price`, against
+// the resting orders of the other side. The trades are done at the prices of the resting orders, and are appended to
+// Trades, which collects all the trades of the pair at `height`. As in Match, the filled orders are left in the book
+// for DropFilledOrder, and the orders canceled by the self-trade prevention are reported by SelfTradeCanceled.
+// It returns the trades of the order.
+func (me *MatchEng) MatchContinuous(id string, side int8, price int64, height int64) ([]Trade, error) {
+	if me.LastMatchHeight != height {
+		me.Trades = me.Trades[:0]
+		me.LastMatchHeight = height
+	}
+	me.SelfTradeCanceled = me.SelfTradeCanceled[:0]
+	start := len(me.Trades)
+	if me.Book.GetOverlappedRange(&me.overLappedLevel, &me.buyBuf, &me.sellBuf) <= 0 {
+		return nil, nil
+	}
+	prepareMatch(&me.overLappedLevel)
+
+	taker := me.findOverlappedOrder(id, side, price)
+	if taker == nil {
+		return nil, fmt.Errorf("order %s is not the only one crossing the order book", id)
+	}
+	// the taker is matched with the whole quantity, only the rest of an iceberg order is shown slice by slice
+	taker.nxtTrade = taker.LeavesQty()
+
+	n := len(me.overLappedLevel)
+	for k := 0; k < n && taker.nxtTrade > 0; k++ {
+		if side == BUYSIDE {
+			// the sell orders from the lowest price
+			l := &me.overLappedLevel[n-1-k]
+			if compareBuy(l.Price, price) > 0 {
+				break
+			}
+			me.fillMakers(taker, l.SellOrders, l.Price, side, height)
+		} else {
+			// the buy orders from the highest price
+			l := &me.overLappedLevel[k]
+			if compareBuy(l.Price, price) < 0 {
+				break
+			}
+			me.fillMakers(taker, l.BuyOrders, l.Price, side, height)
+		}
+	}
+	if taker.DisplayQty > 0 {
+		// the rest of an iceberg taker rests in the book with a new display slice
+		taker.SliceStart = taker.CumQty
+	}
+
+	trades := me.Trades[start:]
+	if len(trades) > 0 {
+		me.LastTradePrice = trades[len(trades)-1].LastPx
+	}
+	return trades, nil
+}
+
+func (me *MatchEng) findOverlappedOrder(id string, side int8, price int64) *OrderPart {
+	for i := range me.overLappedLevel {
+		l := &me.overLappedLevel[i]
+		if compareBuy(l.Price, price) != 0 {
+			continue
+		}
+		orders := l.SellOrders
+		if side == BUYSIDE {
+			orders = l.BuyOrders
+		}
+		for j := range orders {
+			if orders[j].Id == id {
+				return &orders[j]
+			}
+		}
+	}
+	return nil
+}
+
+// fillMakers fills the taker against the `makers` of one price level in time priority.
+// The replenished slices of the iceberg makers join the end of the queue, and can be filled by the rest of the taker.
+func (me *MatchEng) fillMakers(taker *OrderPart, makers []OrderPart, makerPrice int64, takerSide int8, height int64) {
+	for taker.nxtTrade > 0 {
+		for i := range makers {
+			maker := &makers[i]
+			if taker.nxtTrade == 0 {
+				return
+			}
+			if maker.nxtTrade == 0 || me.preventSelfTrade(maker, taker) {
+				continue
+			}
+			filledQty := utils.MinInt(maker.nxtTrade, taker.nxtTrade)
+			taker.nxtTrade -= filledQty
+			taker.CumQty += filledQty
+			maker.nxtTrade -= filledQty
+			maker.CumQty += filledQty
+			trade := Trade{
+				LastPx:  makerPrice,
+				LastQty: filledQty,
+			}
+			if takerSide == SELLSIDE {
+				trade.Sid, trade.Bid = taker.Id, maker.Id
+				trade.SellCumQty, trade.BuyCumQty = taker.CumQty, maker.CumQty
+				trade.TickType = SellTaker
+			} else {
+				trade.Sid, trade.Bid = maker.Id, taker.Id
+				trade.SellCumQty, trade.BuyCumQty = maker.CumQty, taker.CumQty
+				trade.TickType = BuyTaker
+			}
+			me.Trades = append(me.Trades, trade)
+		}
+		if !replenishIcebergOrders(makers, height) {
+			return
+		}
+	}
+}
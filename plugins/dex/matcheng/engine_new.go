@@ -25,6 +25,7 @@ func (me *MatchEng) runMatch(height int64) bool {
 	}
 	me.logger.Debug("match starts...", "height", height)
 	me.Trades = me.Trades[:0]
+	me.SelfTradeCanceled = me.SelfTradeCanceled[:0]
 	r := me.Book.GetOverlappedRange(&me.overLappedLevel, &me.buyBuf, &me.sellBuf)
 	if r <= 0 {
 		return true
@@ -47,11 +48,57 @@ func (me *MatchEng) runMatch(height int64) bool {
 	}
 	takerSideOrders := mergeTakerSideOrders(takerSide, tradePrice, me.overLappedLevel, index)
 	surplus := me.overLappedLevel[index].BuySellSurplus
-	me.fillOrdersNew(takerSide, takerSideOrders, index, tradePrice, surplus)
-	me.LastTradePrice = tradePrice
+	me.fillOrdersNew(height, takerSide, takerSideOrders, index, tradePrice, surplus)
+	// the price is not moved if all the trades are prevented as self-trades
+	if len(me.Trades) != 0 || len(me.SelfTradeCanceled) == 0 {
+		me.LastTradePrice = tradePrice
+	}
 	return true
 }
 
+// TakerOrderIds runs the price discovery of the next match on the current order book and returns
+// the ids of the orders which would be filled as the taker side. No trade is generated, and the order
+// book is left untouched since Match recalculates everything from the book.
+func (me *MatchEng) TakerOrderIds() ([]string, error) {
+	r := me.Book.GetOverlappedRange(&me.overLappedLevel, &me.buyBuf, &me.sellBuf)
+	if r <= 0 {
+		return nil, nil
+	}
+	prepareMatch(&me.overLappedLevel)
+	_, index := getTradePrice(&me.overLappedLevel, &me.maxExec, &me.leastSurplus, me.LastTradePrice, me.PriceLimitPct)
+	if index < 0 {
+		return nil, errors.New("failed to determine the trade price")
+	}
+	if err := me.dropRedundantQty(index); err != nil {
+		return nil, err
+	}
+	takerSide, err := me.determineTakerSide(index)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+	appendTakers := func(orders []OrderPart) {
+		for _, o := range orders {
+			if o.nxtTrade > 0 {
+				ids = append(ids, o.Id)
+			}
+		}
+	}
+	if takerSide == BUYSIDE {
+		for i := 0; i <= index; i++ {
+			l := &me.overLappedLevel[i]
+			appendTakers(l.BuyOrders[l.BuyTakerStartIdx:])
+		}
+	} else {
+		for i := len(me.overLappedLevel) - 1; i >= index; i-- {
+			l := &me.overLappedLevel[i]
+			appendTakers(l.SellOrders[l.SellTakerStartIdx:])
+		}
+	}
+	return ids, nil
+}
+
 func (me *MatchEng) dropRedundantQty(tradePriceLevelIdx int) error {
 	tradePriceLevel := me.overLappedLevel[tradePriceLevelIdx]
 	totalExec := tradePriceLevel.AccumulatedExecutions
@@ -209,7 +256,7 @@ func mergeOneTakerLevel(side int8, priceLevel *OverLappedLevel, merged *MergedPr
 	}
 }
 
-func (me *MatchEng) fillOrdersNew(takerSide int8, takerSideOrders TakerSideOrders, tradePriceIdx int, concludedPrice, surplus int64) {
+func (me *MatchEng) fillOrdersNew(height int64, takerSide int8, takerSideOrders TakerSideOrders, tradePriceIdx int, concludedPrice, surplus int64) {
 	takers := takerSideOrders.orders
 	totalTakerQty := takerSideOrders.totalQty
 	nTakers := len(takers)
@@ -232,6 +279,12 @@ func (me *MatchEng) fillOrdersNew(takerSide int8, takerSideOrders TakerSideOrder
 				tIndex++
 				continue
 			}
+			if me.preventSelfTrade(maker, taker) {
+				if taker.nxtTrade == 0 {
+					toFillQty[tIndex] = 0
+				}
+				continue
+			}
 			filledQty := utils.MinInt(maker.nxtTrade, toFillQty[tIndex])
 			toFillQty[tIndex] -= filledQty
 			taker.nxtTrade -= filledQty
@@ -306,6 +359,35 @@ func (me *MatchEng) fillOrdersNew(takerSide int8, takerSideOrders TakerSideOrder
 			genTrades(overlapped.SellOrders, concludedPrice, toFillQty)
 		}
 	}
+
+	for i := range me.overLappedLevel {
+		replenishIcebergOrders(me.overLappedLevel[i].BuyOrders, height)
+		replenishIcebergOrders(me.overLappedLevel[i].SellOrders, height)
+	}
+}
+
+// replenishIcebergOrders shows the next display slice of the iceberg orders whose current slice has been filled.
+// A replenished order loses its time priority, it's moved to the end of the price level as if placed at `height`,
+// while it still expires by the height it was placed at.
+// The orders are reordered in place, so that the order book sharing the slice is updated as well.
+// It returns true if any order is replenished.
+func replenishIcebergOrders(orders []OrderPart, height int64) bool {
+	n := len(orders)
+	var replenished []OrderPart
+	for i := 0; i < n; i++ {
+		if orders[i].needReplenish() {
+			ord := orders[i]
+			ord.SliceStart = ord.CumQty
+			ord.PlacedTime = ord.placedTime()
+			ord.Time = height
+			ord.nxtTrade = ord.VisibleQty()
+			replenished = append(replenished, ord)
+		} else if len(replenished) > 0 {
+			orders[i-len(replenished)] = orders[i]
+		}
+	}
+	copy(orders[n-len(replenished):], replenished)
+	return len(replenished) > 0
 }
 
 // the logic is similar to `allocateResidual`.
@@ -321,10 +403,48 @@ func calcFillQty(toFillQty []int64, makerQty int64, takers []*OrderPart, proport
 		toFillQty[i] = nxtTrade
 	}
 
-	for i := 0; residual > 0; i = (i + 1) % n {
+	// the takers canceled by the self-trade prevention may not be able to take all the residual
+	for i, added := 0, false; residual > 0; i = (i + 1) % n {
 		order := takers[i]
 		toAdd := utils.MinInt(order.nxtTrade-toFillQty[i], utils.MinInt(residual, lotSize))
 		residual -= toAdd
 		toFillQty[i] += toAdd
+		added = added || toAdd > 0
+		if i == n-1 {
+			if !added {
+				break
+			}
+			added = false
+		}
+	}
+}
+
+// preventSelfTrade cancels the rest of the orders chosen by the self-trade prevention mode of the newer order,
+// if the maker and the taker are from the same owner. It returns false if the two orders are allowed to trade.
+// The taker is the newer one if both are placed at the same height.
+func (me *MatchEng) preventSelfTrade(maker, taker *OrderPart) bool {
+	if maker.Owner == "" || maker.Owner != taker.Owner {
+		return false
+	}
+	newest, oldest := taker, maker
+	if maker.Time > taker.Time {
+		newest, oldest = maker, taker
 	}
+	switch newest.Stp {
+	case StpCancelNewest:
+		me.cancelSelfTrade(newest)
+	case StpCancelOldest:
+		me.cancelSelfTrade(oldest)
+	case StpCancelBoth:
+		me.cancelSelfTrade(newest)
+		me.cancelSelfTrade(oldest)
+	default:
+		return false
+	}
+	return true
+}
+
+func (me *MatchEng) cancelSelfTrade(ord *OrderPart) {
+	ord.nxtTrade = 0
+	me.SelfTradeCanceled = append(me.SelfTradeCanceled, ord.Id)
 }
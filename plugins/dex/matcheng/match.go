@@ -37,7 +37,7 @@ func sumOrdersTotalLeft(orders []OrderPart, reCalNxtTrade bool) int64 {
 	for i := 0; i < k; i++ {
 		o := &orders[i]
 		if reCalNxtTrade {
-			o.nxtTrade = o.Qty - o.CumQty
+			o.nxtTrade = o.VisibleQty()
 		}
 		s += o.nxtTrade
 	}
@@ -250,7 +250,7 @@ func allocateResidual(toAlloc *int64, orders []OrderPart, lotSize int64) bool {
 
 	for i := 0; residual > 0; i = (i + 1) % n {
 		order := &orders[i]
-		toAdd := utils.MinInt(order.LeavesQty()-order.nxtTrade, utils.MinInt(residual, lotSize))
+		toAdd := utils.MinInt(order.VisibleQty()-order.nxtTrade, utils.MinInt(residual, lotSize))
 		residual -= toAdd
 		order.nxtTrade += toAdd
 	}
@@ -15,7 +15,7 @@ func Test_dropRedundantQty(t *testing.T) {
 	assert.Error(dropRedundantQty([]OrderPart{}, 100, 5))
 
 	orders := []OrderPart{
-		{"1", 100, 1000, 100, 900},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 100, nxtTrade: 900},
 	}
 	err := dropRedundantQty(orders, 1000, 5)
 	assert.Error(err)
@@ -25,14 +25,14 @@ func Test_dropRedundantQty(t *testing.T) {
 	assert.Equal(int64(500), orders[0].nxtTrade)
 
 	orders = []OrderPart{
-		{"1", 100, 1000, 100, 900},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 100, nxtTrade: 900},
 	}
 	assert.NoError(dropRedundantQty(orders, 900, 5))
 	assert.Equal(int64(0), orders[0].nxtTrade)
 
 	orders = []OrderPart{
-		{"1", 100, 1000, 700, 300},
-		{"2", 100, 1000, 700, 300},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "2", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
 	}
 	assert.NoError(dropRedundantQty(orders, 400, 5))
 	assert.Equal(int64(100), orders[0].nxtTrade)
@@ -40,8 +40,8 @@ func Test_dropRedundantQty(t *testing.T) {
 	assert.Equal(int64(100), orders[1].nxtTrade)
 
 	orders = []OrderPart{
-		{"1", 100, 1000, 700, 300},
-		{"2", 100, 1000, 700, 300},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "2", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
 	}
 	assert.NoError(dropRedundantQty(orders, 600, 5))
 	assert.Equal(int64(0), orders[0].nxtTrade)
@@ -49,8 +49,8 @@ func Test_dropRedundantQty(t *testing.T) {
 	assert.Equal(int64(0), orders[1].nxtTrade)
 
 	orders = []OrderPart{
-		{"1", 100, 1000, 700, 300},
-		{"2", 100, 1000, 600, 400},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "2", Time: 100, Qty: 1000, CumQty: 600, nxtTrade: 400},
 	}
 	assert.NoError(dropRedundantQty(orders, 600, 5))
 	assert.Equal(int64(45), orders[0].nxtTrade)
@@ -58,8 +58,8 @@ func Test_dropRedundantQty(t *testing.T) {
 	assert.Equal(int64(55), orders[1].nxtTrade)
 
 	orders = []OrderPart{
-		{"1", 100, 1000, 700, 300},
-		{"2", 101, 1000, 700, 300},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "2", Time: 101, Qty: 1000, CumQty: 700, nxtTrade: 300},
 	}
 	assert.NoError(dropRedundantQty(orders, 200, 5))
 	assert.Equal(int64(300), orders[0].nxtTrade)
@@ -67,8 +67,8 @@ func Test_dropRedundantQty(t *testing.T) {
 	assert.Equal(int64(100), orders[1].nxtTrade)
 
 	orders = []OrderPart{
-		{"1", 100, 1000, 700, 300},
-		{"2", 101, 1000, 700, 300},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "2", Time: 101, Qty: 1000, CumQty: 700, nxtTrade: 300},
 	}
 	assert.NoError(dropRedundantQty(orders, 400, 5))
 	assert.Equal(int64(200), orders[0].nxtTrade)
@@ -76,8 +76,8 @@ func Test_dropRedundantQty(t *testing.T) {
 	assert.Equal(int64(0), orders[1].nxtTrade)
 
 	orders = []OrderPart{
-		{"1", 100, 1000, 700, 300},
-		{"2", 101, 1000, 700, 300},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "2", Time: 101, Qty: 1000, CumQty: 700, nxtTrade: 300},
 	}
 	assert.NoError(dropRedundantQty(orders, 600, 5))
 	assert.Equal(int64(0), orders[0].nxtTrade)
@@ -85,9 +85,9 @@ func Test_dropRedundantQty(t *testing.T) {
 	assert.Equal(int64(0), orders[1].nxtTrade)
 
 	orders = []OrderPart{
-		{"1", 100, 1000, 700, 300},
-		{"2", 101, 1000, 700, 300},
-		{"3", 101, 1000, 700, 300},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "2", Time: 101, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "3", Time: 101, Qty: 1000, CumQty: 700, nxtTrade: 300},
 	}
 	assert.NoError(dropRedundantQty(orders, 700, 5))
 	assert.Equal(int64(200), orders[0].nxtTrade)
@@ -97,11 +97,11 @@ func Test_dropRedundantQty(t *testing.T) {
 	assert.Equal(int64(0), orders[2].nxtTrade)
 
 	orders = []OrderPart{
-		{"1", 100, 1000, 800, 200},
-		{"2", 100, 1000, 700, 300},
-		{"3", 101, 1000, 600, 400},
-		{"4", 101, 1000, 500, 500},
-		{"5", 102, 1000, 400, 600},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 800, nxtTrade: 200},
+		{Id: "2", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "3", Time: 101, Qty: 1000, CumQty: 600, nxtTrade: 400},
+		{Id: "4", Time: 101, Qty: 1000, CumQty: 500, nxtTrade: 500},
+		{Id: "5", Time: 102, Qty: 1000, CumQty: 400, nxtTrade: 600},
 	}
 	assert.NoError(dropRedundantQty(orders, 700, 5))
 	assert.Equal(int64(200), orders[0].nxtTrade)
@@ -114,11 +114,11 @@ func Test_dropRedundantQty(t *testing.T) {
 	assert.Equal("4", orders[3].Id)
 
 	orders = []OrderPart{
-		{"1", 100, 100, 75, 25},
-		{"2", 100, 100, 65, 35},
-		{"3", 101, 100, 55, 45},
-		{"4", 101, 100, 45, 55},
-		{"5", 102, 100, 35, 65},
+		{Id: "1", Time: 100, Qty: 100, CumQty: 75, nxtTrade: 25},
+		{Id: "2", Time: 100, Qty: 100, CumQty: 65, nxtTrade: 35},
+		{Id: "3", Time: 101, Qty: 100, CumQty: 55, nxtTrade: 45},
+		{Id: "4", Time: 101, Qty: 100, CumQty: 45, nxtTrade: 55},
+		{Id: "5", Time: 102, Qty: 100, CumQty: 35, nxtTrade: 65},
 	}
 	assert.NoError(dropRedundantQty(orders, 70, 10))
 	assert.Equal(int64(25), orders[0].nxtTrade)
@@ -137,10 +137,10 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	me.overLappedLevel = []OverLappedLevel{{
 		Price: 1000,
 		BuyOrders: []OrderPart{
-			{"1", 100, 100, 0, 0},
+			{Id: "1", Time: 100, Qty: 100},
 		},
 		SellOrders: []OrderPart{
-			{"2", 100, 100, 0, 0},
+			{Id: "2", Time: 100, Qty: 100},
 		},
 	},
 	}
@@ -152,10 +152,10 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 		{
 			Price: 1000,
 			BuyOrders: []OrderPart{
-				{"1", 100, 100, 0, 100},
+				{Id: "1", Time: 100, Qty: 100, nxtTrade: 100},
 			},
 			SellOrders: []OrderPart{
-				{"2", 100, 100, 0, 100},
+				{Id: "2", Time: 100, Qty: 100, nxtTrade: 100},
 			},
 			SellTotal:             100,
 			AccumulatedSell:       100,
@@ -170,12 +170,12 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	me.overLappedLevel = []OverLappedLevel{{
 		Price: 1000,
 		BuyOrders: []OrderPart{
-			{"1", 100, 300, 0, 0},
-			{"3", 100, 400, 0, 0},
+			{Id: "1", Time: 100, Qty: 300},
+			{Id: "3", Time: 100, Qty: 400},
 		},
 		SellOrders: []OrderPart{
-			{"2", 100, 300, 0, 0},
-			{"4", 100, 200, 0, 0},
+			{Id: "2", Time: 100, Qty: 300},
+			{Id: "4", Time: 100, Qty: 200},
 		},
 	},
 	}
@@ -187,12 +187,12 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 		{
 			Price: 1000,
 			BuyOrders: []OrderPart{
-				{"1", 100, 300, 0, 215},
-				{"3", 100, 400, 0, 285},
+				{Id: "1", Time: 100, Qty: 300, nxtTrade: 215},
+				{Id: "3", Time: 100, Qty: 400, nxtTrade: 285},
 			},
 			SellOrders: []OrderPart{
-				{"2", 100, 300, 0, 300},
-				{"4", 100, 200, 0, 200},
+				{Id: "2", Time: 100, Qty: 300, nxtTrade: 300},
+				{Id: "4", Time: 100, Qty: 200, nxtTrade: 200},
 			},
 			SellTotal:             500,
 			AccumulatedSell:       500,
@@ -207,13 +207,13 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	me.overLappedLevel = []OverLappedLevel{{
 		Price: 1000,
 		BuyOrders: []OrderPart{
-			{"1", 100, 100, 0, 0},
-			{"3", 100, 200, 0, 0},
+			{Id: "1", Time: 100, Qty: 100},
+			{Id: "3", Time: 100, Qty: 200},
 		},
 		SellOrders: []OrderPart{
-			{"2", 100, 300, 0, 0},
-			{"4", 100, 400, 0, 0},
-			{"6", 101, 400, 0, 0},
+			{Id: "2", Time: 100, Qty: 300},
+			{Id: "4", Time: 100, Qty: 400},
+			{Id: "6", Time: 101, Qty: 400},
 		},
 	}}
 	prepareMatch(&me.overLappedLevel)
@@ -224,13 +224,13 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 		{
 			Price: 1000,
 			BuyOrders: []OrderPart{
-				{"1", 100, 100, 0, 100},
-				{"3", 100, 200, 0, 200},
+				{Id: "1", Time: 100, Qty: 100, nxtTrade: 100},
+				{Id: "3", Time: 100, Qty: 200, nxtTrade: 200},
 			},
 			SellOrders: []OrderPart{
-				{"2", 100, 300, 0, 130},
-				{"4", 100, 400, 0, 170},
-				{"6", 101, 400, 0, 0},
+				{Id: "2", Time: 100, Qty: 300, nxtTrade: 130},
+				{Id: "4", Time: 100, Qty: 400, nxtTrade: 170},
+				{Id: "6", Time: 101, Qty: 400},
 			},
 			SellTotal:             1100,
 			AccumulatedSell:       1100,
@@ -245,19 +245,19 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	me.overLappedLevel = []OverLappedLevel{{
 		Price: 1200,
 		BuyOrders: []OrderPart{
-			{"1", 100, 300, 0, 0},
+			{Id: "1", Time: 100, Qty: 300},
 		}}, {
 		Price: 1100,
 		BuyOrders: []OrderPart{
-			{"3", 100, 200, 0, 0},
+			{Id: "3", Time: 100, Qty: 200},
 		}}, {
 		Price: 1000,
 		BuyOrders: []OrderPart{
-			{"5", 101, 100, 0, 0},
+			{Id: "5", Time: 101, Qty: 100},
 		}}, {
 		Price: 900,
 		SellOrders: []OrderPart{
-			{"2", 100, 1000, 0, 0},
+			{Id: "2", Time: 100, Qty: 1000},
 		}},
 	}
 	prepareMatch(&me.overLappedLevel)
@@ -267,7 +267,7 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	assert.Equal([]OverLappedLevel{{
 		Price: 1200,
 		BuyOrders: []OrderPart{
-			{"1", 100, 300, 0, 300},
+			{Id: "1", Time: 100, Qty: 300, nxtTrade: 300},
 		},
 		SellTotal:             0,
 		AccumulatedSell:       1000,
@@ -278,7 +278,7 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	}, {
 		Price: 1100,
 		BuyOrders: []OrderPart{
-			{"3", 100, 200, 0, 200},
+			{Id: "3", Time: 100, Qty: 200, nxtTrade: 200},
 		},
 		SellTotal:             0,
 		AccumulatedSell:       1000,
@@ -289,7 +289,7 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	}, {
 		Price: 1000,
 		BuyOrders: []OrderPart{
-			{"5", 101, 100, 0, 100},
+			{Id: "5", Time: 101, Qty: 100, nxtTrade: 100},
 		},
 		SellTotal:             0,
 		AccumulatedSell:       1000,
@@ -300,7 +300,7 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	}, {
 		Price: 900,
 		SellOrders: []OrderPart{
-			{"2", 100, 1000, 0, 600},
+			{Id: "2", Time: 100, Qty: 1000, nxtTrade: 600},
 		},
 		SellTotal:             1000,
 		AccumulatedSell:       1000,
@@ -314,19 +314,19 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	me.overLappedLevel = []OverLappedLevel{{
 		Price: 1200,
 		BuyOrders: []OrderPart{
-			{"1", 100, 1000, 0, 0},
+			{Id: "1", Time: 100, Qty: 1000},
 		}}, {
 		Price: 1100,
 		SellOrders: []OrderPart{
-			{"2", 100, 100, 0, 0},
+			{Id: "2", Time: 100, Qty: 100},
 		}}, {
 		Price: 1000,
 		SellOrders: []OrderPart{
-			{"4", 101, 200, 0, 0},
+			{Id: "4", Time: 101, Qty: 200},
 		}}, {
 		Price: 900,
 		SellOrders: []OrderPart{
-			{"6", 101, 300, 0, 0},
+			{Id: "6", Time: 101, Qty: 300},
 		}},
 	}
 	prepareMatch(&me.overLappedLevel)
@@ -336,7 +336,7 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	assert.Equal([]OverLappedLevel{{
 		Price: 1200,
 		BuyOrders: []OrderPart{
-			{"1", 100, 1000, 0, 600},
+			{Id: "1", Time: 100, Qty: 1000, nxtTrade: 600},
 		},
 		SellTotal:             0,
 		AccumulatedSell:       600,
@@ -347,7 +347,7 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	}, {
 		Price: 1100,
 		SellOrders: []OrderPart{
-			{"2", 100, 100, 0, 100},
+			{Id: "2", Time: 100, Qty: 100, nxtTrade: 100},
 		},
 		SellTotal:             100,
 		AccumulatedSell:       600,
@@ -358,7 +358,7 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	}, {
 		Price: 1000,
 		SellOrders: []OrderPart{
-			{"4", 101, 200, 0, 200},
+			{Id: "4", Time: 101, Qty: 200, nxtTrade: 200},
 		},
 		SellTotal:             200,
 		AccumulatedSell:       500,
@@ -369,7 +369,7 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 	}, {
 		Price: 900,
 		SellOrders: []OrderPart{
-			{"6", 101, 300, 0, 300},
+			{Id: "6", Time: 101, Qty: 300, nxtTrade: 300},
 		},
 		SellTotal:             300,
 		AccumulatedSell:       300,
@@ -383,7 +383,7 @@ func TestMatchEng_DropRedundantOrders(t *testing.T) {
 func Test_calcFillQty(t *testing.T) {
 	assert := assert.New(t)
 	takers := []*OrderPart{
-		{"1", 100, 1800, 900, 900},
+		{Id: "1", Time: 100, Qty: 1800, CumQty: 900, nxtTrade: 900},
 	}
 	toFillQty := make([]int64, len(takers))
 	calcFillQty(toFillQty, 600, takers, []int64{900}, 900, 5)
@@ -392,9 +392,9 @@ func Test_calcFillQty(t *testing.T) {
 
 	// check takers not modified
 	takers = []*OrderPart{
-		{"1", 100, 900, 0, 900},
-		{"2", 100, 300, 0, 300},
-		{"3", 100, 600, 0, 600},
+		{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		{Id: "2", Time: 100, Qty: 300, nxtTrade: 300},
+		{Id: "3", Time: 100, Qty: 600, nxtTrade: 600},
 	}
 	toFillQty = make([]int64, len(takers))
 	calcFillQty(toFillQty, 600, takers, []int64{900, 300, 600}, 1800, 5)
@@ -415,25 +415,25 @@ func Test_calcFillQty(t *testing.T) {
 	assert.Equal([]int64{20, 5, 10}, toFillQty)
 
 	takers = []*OrderPart{
-		{"1", 100, 900, 0, 900},
-		{"2", 100, 900, 0, 900},
-		{"3", 100, 900, 0, 900},
+		{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		{Id: "2", Time: 100, Qty: 900, nxtTrade: 900},
+		{Id: "3", Time: 100, Qty: 900, nxtTrade: 900},
 	}
 	calcFillQty(toFillQty, 700, takers, []int64{900, 900, 900}, 2700, 5)
 	assert.Equal([]int64{235, 235, 230}, toFillQty)
 
 	takers = []*OrderPart{
-		{"1", 100, 1, 0, 1},
-		{"2", 100, 10, 0, 10},
-		{"3", 100, 6, 0, 6},
+		{Id: "1", Time: 100, Qty: 1, nxtTrade: 1},
+		{Id: "2", Time: 100, Qty: 10, nxtTrade: 10},
+		{Id: "3", Time: 100, Qty: 6, nxtTrade: 6},
 	}
 	calcFillQty(toFillQty, 15, takers, []int64{1, 10, 6}, 17, 5)
 	assert.Equal([]int64{1, 9, 5}, toFillQty)
 
 	takers = []*OrderPart{
-		{"1", 100, 10, 0, 10},
-		{"2", 100, 5, 0, 5},
-		{"3", 100, 50, 0, 50},
+		{Id: "1", Time: 100, Qty: 10, nxtTrade: 10},
+		{Id: "2", Time: 100, Qty: 5, nxtTrade: 5},
+		{Id: "3", Time: 100, Qty: 50, nxtTrade: 50},
 	}
 	calcFillQty(toFillQty, 35, takers, []int64{10, 5, 50}, 65, 5)
 	assert.Equal([]int64{10, 0, 25}, toFillQty)
@@ -445,36 +445,36 @@ func TestMatchEng_determineTakerSide(t *testing.T) {
 	me.overLappedLevel = []OverLappedLevel{{
 		Price: 1200,
 		BuyOrders: []OrderPart{
-			{"1", 100, 100, 0, 100},
-			{"3", 100, 100, 0, 100},
+			{Id: "1", Time: 100, Qty: 100, nxtTrade: 100},
+			{Id: "3", Time: 100, Qty: 100, nxtTrade: 100},
 		},
 		SellOrders: []OrderPart{
-			{"2", 99, 100, 0, 100},
-			{"4", 99, 100, 0, 100},
-			{"6", 100, 100, 0, 100},
+			{Id: "2", Time: 99, Qty: 100, nxtTrade: 100},
+			{Id: "4", Time: 99, Qty: 100, nxtTrade: 100},
+			{Id: "6", Time: 100, Qty: 100, nxtTrade: 100},
 		},
 	}, {
 		Price: 1100,
 		// BuyOrders is empty
 		BuyOrders: []OrderPart{},
 		SellOrders: []OrderPart{
-			{"8", 99, 100, 0, 100},
+			{Id: "8", Time: 99, Qty: 100, nxtTrade: 100},
 		},
 	}, {
 		Price: 1000,
 		BuyOrders: []OrderPart{
-			{"5", 99, 100, 0, 100},
-			{"7", 99, 100, 0, 100},
-			{"9", 100, 100, 0, 100},
+			{Id: "5", Time: 99, Qty: 100, nxtTrade: 100},
+			{Id: "7", Time: 99, Qty: 100, nxtTrade: 100},
+			{Id: "9", Time: 100, Qty: 100, nxtTrade: 100},
 		},
 		// SellOrders is nil
 	}, {
 		Price: 900,
 		BuyOrders: []OrderPart{
-			{"11", 99, 100, 0, 100},
+			{Id: "11", Time: 99, Qty: 100, nxtTrade: 100},
 		},
 		SellOrders: []OrderPart{
-			{"10", 100, 100, 0, 100},
+			{Id: "10", Time: 100, Qty: 100, nxtTrade: 100},
 		},
 	}}
 
@@ -521,10 +521,10 @@ func TestMatchEng_determineTakerSide(t *testing.T) {
 	me.overLappedLevel = []OverLappedLevel{{
 		Price: 1200,
 		BuyOrders: []OrderPart{
-			{"1", 100, 100, 0, 100},
+			{Id: "1", Time: 100, Qty: 100, nxtTrade: 100},
 		},
 		SellOrders: []OrderPart{
-			{"2", 100, 100, 0, 100},
+			{Id: "2", Time: 100, Qty: 100, nxtTrade: 100},
 		},
 	}}
 	takerSide, err = me.determineTakerSide(0)
@@ -535,10 +535,10 @@ func TestMatchEng_determineTakerSide(t *testing.T) {
 	me.overLappedLevel = []OverLappedLevel{{
 		Price: 1200,
 		BuyOrders: []OrderPart{
-			{"1", 99, 100, 0, 100},
+			{Id: "1", Time: 99, Qty: 100, nxtTrade: 100},
 		},
 		SellOrders: []OrderPart{
-			{"2", 99, 100, 0, 100},
+			{Id: "2", Time: 99, Qty: 100, nxtTrade: 100},
 		},
 	}}
 	takerSide, err = me.determineTakerSide(0)
@@ -569,7 +569,7 @@ func Test_mergeOneTakerLevel(t *testing.T) {
 	overlapped = OverLappedLevel{
 		Price: 110,
 		BuyOrders: []OrderPart{
-			{"1", 100, 1000, 0, 0},
+			{Id: "1", Time: 100, Qty: 1000},
 		},
 		BuyTakerStartIdx: 0,
 	}
@@ -581,16 +581,16 @@ func Test_mergeOneTakerLevel(t *testing.T) {
 	overlapped = OverLappedLevel{
 		Price: 110,
 		BuyOrders: []OrderPart{
-			{"1", 99, 200, 0, 200},
-			{"2", 100, 500, 0, 500},
-			{"3", 100, 1000, 0, 1000},
+			{Id: "1", Time: 99, Qty: 200, nxtTrade: 200},
+			{Id: "2", Time: 100, Qty: 500, nxtTrade: 500},
+			{Id: "3", Time: 100, Qty: 1000, nxtTrade: 1000},
 		},
 		BuyTakerStartIdx: 1,
 	}
 	mergeOneTakerLevel(BUYSIDE, &overlapped, merged)
 	assert.EqualValues([]*OrderPart{
-		{"3", 100, 1000, 0, 1000},
-		{"2", 100, 500, 0, 500},
+		{Id: "3", Time: 100, Qty: 1000, nxtTrade: 1000},
+		{Id: "2", Time: 100, Qty: 500, nxtTrade: 500},
 	}, merged.orders)
 	assert.Equal(int64(1500), merged.totalQty)
 
@@ -599,18 +599,18 @@ func Test_mergeOneTakerLevel(t *testing.T) {
 	overlapped = OverLappedLevel{
 		Price: 110,
 		BuyOrders: []OrderPart{
-			{"1", 99, 1000, 0, 200},
-			{"2", 99, 1000, 0, 0},
-			{"3", 100, 300, 0, 300},
-			{"4", 100, 100, 0, 0},
-			{"5", 100, 400, 0, 300},
+			{Id: "1", Time: 99, Qty: 1000, nxtTrade: 200},
+			{Id: "2", Time: 99, Qty: 1000},
+			{Id: "3", Time: 100, Qty: 300, nxtTrade: 300},
+			{Id: "4", Time: 100, Qty: 100},
+			{Id: "5", Time: 100, Qty: 400, nxtTrade: 300},
 		},
 		BuyTakerStartIdx: 2,
 	}
 	mergeOneTakerLevel(BUYSIDE, &overlapped, merged)
 	assert.Equal([]*OrderPart{
-		{"5", 100, 400, 0, 300},
-		{"3", 100, 300, 0, 300},
+		{Id: "5", Time: 100, Qty: 400, nxtTrade: 300},
+		{Id: "3", Time: 100, Qty: 300, nxtTrade: 300},
 	}, merged.orders)
 	assert.Equal(int64(600), merged.totalQty)
 }
@@ -634,27 +634,27 @@ func Test_mergeTakerSideOrders(t *testing.T) {
 			overlapped: []OverLappedLevel{{
 				Price: 110,
 				BuyOrders: []OrderPart{
-					{"1", 99, 200, 100, 100},
-					{"3", 100, 100, 0, 100},
-					{"5", 100, 500, 0, 500},
+					{Id: "1", Time: 99, Qty: 200, CumQty: 100, nxtTrade: 100},
+					{Id: "3", Time: 100, Qty: 100, nxtTrade: 100},
+					{Id: "5", Time: 100, Qty: 500, nxtTrade: 500},
 				},
 				BuyTakerStartIdx: 1,
 				SellOrders: []OrderPart{
-					{"2", 100, 1000, 0, 1000},
+					{Id: "2", Time: 100, Qty: 1000, nxtTrade: 1000},
 				},
 				SellTakerStartIdx: 0,
 			}, {
 				Price: 105,
 				BuyOrders: []OrderPart{
-					{"7", 99, 200, 100, 100},
+					{Id: "7", Time: 99, Qty: 200, CumQty: 100, nxtTrade: 100},
 				},
 				BuyTakerStartIdx:  1,
 				SellTakerStartIdx: 0,
 			}, {
 				Price: 100,
 				BuyOrders: []OrderPart{
-					{"9", 100, 200, 0, 200},
-					{"11", 100, 100, 0, 100},
+					{Id: "9", Time: 100, Qty: 200, nxtTrade: 200},
+					{Id: "11", Time: 100, Qty: 100, nxtTrade: 100},
 				},
 				BuyTakerStartIdx:  0,
 				SellTakerStartIdx: 0,
@@ -665,10 +665,10 @@ func Test_mergeTakerSideOrders(t *testing.T) {
 			&MergedPriceLevel{
 				price: 100,
 				orders: []*OrderPart{
-					{"5", 100, 500, 0, 500},
-					{"3", 100, 100, 0, 100},
-					{"9", 100, 200, 0, 200},
-					{"11", 100, 100, 0, 100},
+					{Id: "5", Time: 100, Qty: 500, nxtTrade: 500},
+					{Id: "3", Time: 100, Qty: 100, nxtTrade: 100},
+					{Id: "9", Time: 100, Qty: 200, nxtTrade: 200},
+					{Id: "11", Time: 100, Qty: 100, nxtTrade: 100},
 				},
 				totalQty: 900,
 			},
@@ -681,26 +681,26 @@ func Test_mergeTakerSideOrders(t *testing.T) {
 			overlapped: []OverLappedLevel{{
 				Price: 110,
 				BuyOrders: []OrderPart{
-					{"1", 99, 200, 100, 100},
+					{Id: "1", Time: 99, Qty: 200, CumQty: 100, nxtTrade: 100},
 				},
 				BuyTakerStartIdx: 1,
 				SellOrders: []OrderPart{
-					{"2", 99, 1000, 0, 1000},
+					{Id: "2", Time: 99, Qty: 1000, nxtTrade: 1000},
 				},
 				SellTakerStartIdx: 1,
 			}, {
 				Price:            105,
 				BuyTakerStartIdx: 0,
 				SellOrders: []OrderPart{
-					{"4", 99, 200, 0, 200},
-					{"6", 100, 1000, 0, 1000},
+					{Id: "4", Time: 99, Qty: 200, nxtTrade: 200},
+					{Id: "6", Time: 100, Qty: 1000, nxtTrade: 1000},
 				},
 				SellTakerStartIdx: 1,
 			}, {
 				Price: 100,
 				SellOrders: []OrderPart{
-					{"8", 100, 200, 0, 200},
-					{"10", 100, 300, 0, 300},
+					{Id: "8", Time: 100, Qty: 200, nxtTrade: 200},
+					{Id: "10", Time: 100, Qty: 300, nxtTrade: 300},
 				},
 				BuyTakerStartIdx:  0,
 				SellTakerStartIdx: 0,
@@ -711,9 +711,9 @@ func Test_mergeTakerSideOrders(t *testing.T) {
 			&MergedPriceLevel{
 				price: 110,
 				orders: []*OrderPart{
-					{"10", 100, 300, 0, 300},
-					{"8", 100, 200, 0, 200},
-					{"6", 100, 1000, 0, 1000},
+					{Id: "10", Time: 100, Qty: 300, nxtTrade: 300},
+					{Id: "8", Time: 100, Qty: 200, nxtTrade: 200},
+					{Id: "6", Time: 100, Qty: 1000, nxtTrade: 1000},
 				},
 				totalQty: 1500,
 			},
@@ -732,11 +732,11 @@ func TestMatchEng_fillOrdersNew(t *testing.T) {
 	// 1. buy side is maker side
 	me := NewMatchEng("AAA_BNB", 100, 5, 0.05)
 	makerSideOrders := []OrderPart{
-		{"1", 99, 1000, 700, 300},
-		{"3", 99, 1000, 900, 100},
-		{"5", 100, 1000, 700, 300},
-		{"7", 99, 1000, 800, 200},
-		{"9", 100, 1000, 200, 100},
+		{Id: "1", Time: 99, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "3", Time: 99, Qty: 1000, CumQty: 900, nxtTrade: 100},
+		{Id: "5", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "7", Time: 99, Qty: 1000, CumQty: 800, nxtTrade: 200},
+		{Id: "9", Time: 100, Qty: 1000, CumQty: 200, nxtTrade: 100},
 	}
 	me.overLappedLevel = []OverLappedLevel{{
 		Price:            110,
@@ -753,16 +753,16 @@ func TestMatchEng_fillOrdersNew(t *testing.T) {
 		&MergedPriceLevel{
 			price: 100,
 			orders: []*OrderPart{
-				{"8", 100, 1000, 800, 200},
-				{"6", 100, 800, 500, 300},
-				{"2", 100, 600, 200, 400},
-				{"4", 100, 400, 300, 100},
+				{Id: "8", Time: 100, Qty: 1000, CumQty: 800, nxtTrade: 200},
+				{Id: "6", Time: 100, Qty: 800, CumQty: 500, nxtTrade: 300},
+				{Id: "2", Time: 100, Qty: 600, CumQty: 200, nxtTrade: 400},
+				{Id: "4", Time: 100, Qty: 400, CumQty: 300, nxtTrade: 100},
 			},
 			totalQty: 1000,
 		},
 	}
 
-	me.fillOrdersNew(SELLSIDE, takerSideOrders, 1, 100, 10)
+	me.fillOrdersNew(100, SELLSIDE, takerSideOrders, 1, 100, 10)
 	assert.Equal([]Trade{
 		{"8", 110, 80, 780, 880, "1", SellTaker, nil, nil},
 		{"6", 110, 120, 900, 620, "1", SellTaker, nil, nil},
@@ -777,28 +777,28 @@ func TestMatchEng_fillOrdersNew(t *testing.T) {
 		{"4", 100, 60, 300, 400, "9", BuySurplus, nil, nil},
 	}, me.Trades)
 	assert.Equal([]OrderPart{
-		{"1", 99, 1000, 1000, 0},
-		{"3", 99, 1000, 1000, 0},
-		{"5", 100, 1000, 1000, 0},
-		{"7", 99, 1000, 1000, 0},
-		{"9", 100, 1000, 300, 0},
+		{Id: "1", Time: 99, Qty: 1000, CumQty: 1000},
+		{Id: "3", Time: 99, Qty: 1000, CumQty: 1000},
+		{Id: "5", Time: 100, Qty: 1000, CumQty: 1000},
+		{Id: "7", Time: 99, Qty: 1000, CumQty: 1000},
+		{Id: "9", Time: 100, Qty: 1000, CumQty: 300},
 	}, makerSideOrders)
 	assert.Equal([]*OrderPart{
-		{"8", 100, 1000, 1000, 0},
-		{"6", 100, 800, 800, 0},
-		{"2", 100, 600, 600, 0},
-		{"4", 100, 400, 400, 0},
+		{Id: "8", Time: 100, Qty: 1000, CumQty: 1000},
+		{Id: "6", Time: 100, Qty: 800, CumQty: 800},
+		{Id: "2", Time: 100, Qty: 600, CumQty: 600},
+		{Id: "4", Time: 100, Qty: 400, CumQty: 400},
 	}, takerSideOrders.orders)
 
 	// 2. sell side is maker side
 	me = NewMatchEng("AAA_BNB", 100, 5, 0.05)
 	makerSideOrders = []OrderPart{
-		{"2", 99, 1000, 700, 300},
-		{"4", 99, 1000, 800, 200},
-		{"6", 99, 1000, 900, 100},
-		{"8", 99, 1000, 900, 100},
-		{"10", 99, 1000, 900, 100},
-		{"12", 100, 1000, 800, 200},
+		{Id: "2", Time: 99, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "4", Time: 99, Qty: 1000, CumQty: 800, nxtTrade: 200},
+		{Id: "6", Time: 99, Qty: 1000, CumQty: 900, nxtTrade: 100},
+		{Id: "8", Time: 99, Qty: 1000, CumQty: 900, nxtTrade: 100},
+		{Id: "10", Time: 99, Qty: 1000, CumQty: 900, nxtTrade: 100},
+		{Id: "12", Time: 100, Qty: 1000, CumQty: 800, nxtTrade: 200},
 	}
 	me.overLappedLevel = []OverLappedLevel{{
 		Price:             100,
@@ -820,14 +820,14 @@ func TestMatchEng_fillOrdersNew(t *testing.T) {
 		&MergedPriceLevel{
 			price: 100,
 			orders: []*OrderPart{
-				{"1", 100, 600, 0, 600},
-				{"3", 100, 300, 0, 300},
-				{"5", 100, 100, 0, 100},
+				{Id: "1", Time: 100, Qty: 600, nxtTrade: 600},
+				{Id: "3", Time: 100, Qty: 300, nxtTrade: 300},
+				{Id: "5", Time: 100, Qty: 100, nxtTrade: 100},
 			},
 			totalQty: 1000,
 		},
 	}
-	me.fillOrdersNew(BUYSIDE, takerSideOrders, 0, 100, -100)
+	me.fillOrdersNew(100, BUYSIDE, takerSideOrders, 0, 100, -100)
 	assert.Equal([]Trade{
 		{"2", 90, 180, 180, 880, "1", BuyTaker, nil, nil},
 		{"2", 90, 90, 90, 970, "3", BuyTaker, nil, nil},
@@ -845,24 +845,24 @@ func TestMatchEng_fillOrdersNew(t *testing.T) {
 		{"12", 100, 40, 100, 1000, "5", SellSurplus, nil, nil},
 	}, me.Trades)
 	assert.Equal([]OrderPart{
-		{"2", 99, 1000, 1000, 0},
-		{"4", 99, 1000, 1000, 0},
-		{"6", 99, 1000, 1000, 0},
-		{"8", 99, 1000, 1000, 0},
-		{"10", 99, 1000, 1000, 0},
-		{"12", 100, 1000, 1000, 0},
+		{Id: "2", Time: 99, Qty: 1000, CumQty: 1000},
+		{Id: "4", Time: 99, Qty: 1000, CumQty: 1000},
+		{Id: "6", Time: 99, Qty: 1000, CumQty: 1000},
+		{Id: "8", Time: 99, Qty: 1000, CumQty: 1000},
+		{Id: "10", Time: 99, Qty: 1000, CumQty: 1000},
+		{Id: "12", Time: 100, Qty: 1000, CumQty: 1000},
 	}, makerSideOrders)
 	assert.Equal([]*OrderPart{
-		{"1", 100, 600, 600, 0},
-		{"3", 100, 300, 300, 0},
-		{"5", 100, 100, 100, 0},
+		{Id: "1", Time: 100, Qty: 600, CumQty: 600},
+		{Id: "3", Time: 100, Qty: 300, CumQty: 300},
+		{Id: "5", Time: 100, Qty: 100, CumQty: 100},
 	}, takerSideOrders.orders)
 
 	// 3. no maker orders
 	me = NewMatchEng("AAA_BNB", 100, 5, 0.05)
 	makerSideOrders = []OrderPart{
-		{"2", 100, 1000, 700, 300},
-		{"4", 100, 1000, 900, 100},
+		{Id: "2", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+		{Id: "4", Time: 100, Qty: 1000, CumQty: 900, nxtTrade: 100},
 	}
 	me.overLappedLevel = []OverLappedLevel{{
 		Price:             100,
@@ -879,25 +879,25 @@ func TestMatchEng_fillOrdersNew(t *testing.T) {
 		&MergedPriceLevel{
 			price: 100,
 			orders: []*OrderPart{
-				{"1", 100, 1000, 700, 300},
-				{"3", 100, 1000, 900, 100},
+				{Id: "1", Time: 100, Qty: 1000, CumQty: 700, nxtTrade: 300},
+				{Id: "3", Time: 100, Qty: 1000, CumQty: 900, nxtTrade: 100},
 			},
 			totalQty: 400,
 		},
 	}
-	me.fillOrdersNew(BUYSIDE, takerSideOrders, 0, 100, 0)
+	me.fillOrdersNew(100, BUYSIDE, takerSideOrders, 0, 100, 0)
 	assert.Equal([]Trade{
 		{"4", 100, 100, 800, 1000, "1", Neutral, nil, nil},
 		{"2", 100, 200, 1000, 900, "1", Neutral, nil, nil},
 		{"2", 100, 100, 1000, 1000, "3", Neutral, nil, nil},
 	}, me.Trades)
 	assert.Equal([]OrderPart{
-		{"2", 100, 1000, 1000, 0},
-		{"4", 100, 1000, 1000, 0},
+		{Id: "2", Time: 100, Qty: 1000, CumQty: 1000},
+		{Id: "4", Time: 100, Qty: 1000, CumQty: 1000},
 	}, makerSideOrders)
 	assert.Equal([]*OrderPart{
-		{"1", 100, 1000, 1000, 0},
-		{"3", 100, 1000, 1000, 0},
+		{Id: "1", Time: 100, Qty: 1000, CumQty: 1000},
+		{Id: "3", Time: 100, Qty: 1000, CumQty: 1000},
 	}, takerSideOrders.orders)
 }
 
@@ -943,24 +943,24 @@ func TestMatchEng_Match(t *testing.T) {
 	assert.Equal([]PriceLevel{{
 		Price: 90,
 		Orders: []OrderPart{
-			{"2", 92, 5, 0, 5},
+			{Id: "2", Time: 92, Qty: 5, nxtTrade: 5},
 		},
 	}, {
 		Price: 80,
 		Orders: []OrderPart{
-			{"4", 93, 30, 0, 30},
+			{Id: "4", Time: 93, Qty: 30, nxtTrade: 30},
 		},
 	}}, buys)
 	assert.Equal([]PriceLevel{{
 		Price: 100,
 		Orders: []OrderPart{
-			{"5", 91, 5, 0, 5},
-			{"7", 91, 50, 25, 25},
+			{Id: "5", Time: 91, Qty: 5, nxtTrade: 5},
+			{Id: "7", Time: 91, Qty: 50, CumQty: 25, nxtTrade: 25},
 		},
 	}, {
 		Price: 110,
 		Orders: []OrderPart{
-			{"9", 91, 50, 0, 50},
+			{Id: "9", Time: 91, Qty: 50, nxtTrade: 50},
 		},
 	}}, sells)
 
@@ -1003,15 +1003,227 @@ func TestMatchEng_Match(t *testing.T) {
 	assert.Equal([]PriceLevel{{
 		Price: 70,
 		Orders: []OrderPart{
-			{"2", 92, 5, 0, 0},
-			{"4", 93, 30, 0, 0},
+			{Id: "2", Time: 92, Qty: 5},
+			{Id: "4", Time: 93, Qty: 30},
 		},
 	}}, buys)
 	assert.Equal([]PriceLevel{{
 		Price: 100,
 		Orders: []OrderPart{
-			{"13", 100, 20, 10, 10},
-			{"15", 100, 30, 10, 20},
+			{Id: "13", Time: 100, Qty: 20, CumQty: 10, nxtTrade: 10},
+			{Id: "15", Time: 100, Qty: 30, CumQty: 10, nxtTrade: 20},
 		},
 	}}, sells)
 }
+
+func TestMatchEng_SelfTradePrevention(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+
+	assert := assert.New(t)
+	newEng := func(stp int8) *MatchEng {
+		me := NewMatchEng(DefaultPairSymbol, 98, 5, 0.05)
+		me.Book = NewOrderBookOnULList(4, 2)
+		me.Book.InsertOrderPart(SELLSIDE, 100, OrderPart{Id: "1", Time: 90, Qty: 10, Owner: "A"})
+		me.Book.InsertOrderPart(SELLSIDE, 100, OrderPart{Id: "2", Time: 90, Qty: 5, Owner: "B"})
+		me.Book.InsertOrderPart(BUYSIDE, 100, OrderPart{Id: "3", Time: 100, Qty: 15, Owner: "A", Stp: stp})
+		me.LastMatchHeight = 99
+		return me
+	}
+
+	// trade with themselves
+	me := newEng(StpNone)
+	assert.True(me.Match(100))
+	assert.Equal([]Trade{
+		{"1", 100, 10, 10, 10, "3", BuyTaker, nil, nil},
+		{"2", 100, 5, 15, 5, "3", BuyTaker, nil, nil},
+	}, me.Trades)
+	assert.Empty(me.SelfTradeCanceled)
+
+	me = newEng(StpCancelNewest)
+	assert.True(me.Match(100))
+	assert.Empty(me.Trades)
+	assert.Equal([]string{"3"}, me.SelfTradeCanceled)
+	assert.Equal(int64(98), me.LastTradePrice)
+	assert.Empty(me.DropFilledOrder())
+
+	me = newEng(StpCancelOldest)
+	assert.True(me.Match(100))
+	assert.Equal([]Trade{
+		{"2", 100, 5, 5, 5, "3", BuyTaker, nil, nil},
+	}, me.Trades)
+	assert.Equal([]string{"1"}, me.SelfTradeCanceled)
+	assert.Equal(int64(100), me.LastTradePrice)
+	assert.Equal([]string{"2"}, me.DropFilledOrder())
+
+	me = newEng(StpCancelBoth)
+	assert.True(me.Match(100))
+	assert.Empty(me.Trades)
+	assert.Equal([]string{"3", "1"}, me.SelfTradeCanceled)
+
+	// the mode of the older order does not apply
+	me = newEng(StpNone)
+	me.Book.RemoveOrder("1", SELLSIDE, 100)
+	me.Book.InsertOrderPart(SELLSIDE, 100, OrderPart{Id: "1", Time: 90, Qty: 10, Owner: "A", Stp: StpCancelBoth})
+	assert.True(me.Match(100))
+	assert.Len(me.Trades, 2)
+	assert.Empty(me.SelfTradeCanceled)
+
+	// the share of the canceled taker is left to the other takers, and the rest of the makers are not filled
+	me = NewMatchEng(DefaultPairSymbol, 110, 10, 0.05)
+	me.Book = NewOrderBookOnULList(4, 2)
+	me.Book.InsertOrder("5", SELLSIDE, 99, 90, 30)
+	me.Book.InsertOrderPart(SELLSIDE, 80, OrderPart{Id: "7", Time: 99, Qty: 10, Owner: "A"})
+	me.Book.InsertOrder("9", SELLSIDE, 99, 80, 40)
+	me.Book.InsertOrderPart(BUYSIDE, 110, OrderPart{Id: "6", Time: 100, Qty: 40, Owner: "B"})
+	me.Book.InsertOrderPart(BUYSIDE, 110, OrderPart{Id: "8", Time: 100, Qty: 100, Owner: "A", Stp: StpCancelNewest})
+	me.LastMatchHeight = 99
+	assert.True(me.Match(100))
+	assert.Equal([]string{"8"}, me.SelfTradeCanceled)
+	assert.Equal([]Trade{
+		{"7", 80, 10, 10, 10, "6", BuyTaker, nil, nil},
+		{"5", 90, 20, 30, 20, "6", BuyTaker, nil, nil},
+	}, me.Trades)
+}
+
+func TestMatchEng_TakerOrderIds(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+
+	assert := assert.New(t)
+	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+	me.Book = NewOrderBookOnULList(4, 2)
+	ids, err := me.TakerOrderIds()
+	assert.Nil(err)
+	assert.Empty(ids)
+
+	me.Book.InsertOrder("1", SELLSIDE, 90, 100, 10)
+	me.Book.InsertOrder("3", SELLSIDE, 90, 105, 10)
+	me.Book.InsertOrder("12", BUYSIDE, 100, 110, 10)
+	me.Book.InsertOrder("14", BUYSIDE, 100, 100, 5)
+	me.Book.InsertOrder("16", BUYSIDE, 100, 90, 5)
+	me.LastMatchHeight = 99
+	ids, err = me.TakerOrderIds()
+	assert.Nil(err)
+	assert.Equal([]string{"12"}, ids)
+
+	// the order book is left untouched by the preview
+	upgrade.Mgr.SetHeight(100)
+	assert.True(me.Match(100))
+	assert.Equal([]Trade{
+		{"1", 100, 10, 10, 10, "12", BuyTaker, nil, nil},
+	}, me.Trades)
+}
+
+func TestMatchEng_IcebergOrder(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+
+	assert := assert.New(t)
+	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+	me.Book = NewOrderBookOnULList(4, 2)
+	me.Book.InsertOrderPart(SELLSIDE, 100, OrderPart{Id: "1", Time: 90, Qty: 25, DisplayQty: 10})
+	me.Book.InsertOrder("2", SELLSIDE, 95, 100, 10)
+	me.Book.InsertOrder("3", BUYSIDE, 100, 100, 15)
+	me.LastMatchHeight = 99
+	pl := me.Book.GetPriceLevel(100, SELLSIDE)
+	assert.Equal(int64(20), pl.TotalVisibleQty())
+	assert.Equal(int64(35), pl.TotalLeavesQty())
+
+	// only the display slice is matched, then the hidden reserve is shown at the end of the queue
+	assert.True(me.Match(100))
+	assert.Equal([]Trade{
+		{"1", 100, 10, 10, 10, "3", BuyTaker, nil, nil},
+		{"2", 100, 5, 15, 5, "3", BuyTaker, nil, nil},
+	}, me.Trades)
+	assert.Equal([]string{"3"}, me.DropFilledOrder())
+	pl = me.Book.GetPriceLevel(100, SELLSIDE)
+	assert.Equal([]OrderPart{
+		{Id: "2", Time: 95, Qty: 10, CumQty: 5, nxtTrade: 5},
+		{Id: "1", Time: 100, Qty: 25, CumQty: 10, nxtTrade: 10, DisplayQty: 10, SliceStart: 10, PlacedTime: 90},
+	}, pl.Orders)
+	assert.Equal(int64(15), pl.TotalVisibleQty())
+	assert.Equal(int64(20), pl.TotalLeavesQty())
+
+	// the replenished order has lost its time priority
+	upgrade.Mgr.SetHeight(101)
+	me.Book.InsertOrder("4", BUYSIDE, 101, 100, 30)
+	assert.True(me.Match(101))
+	assert.Equal([]Trade{
+		{"2", 100, 5, 5, 10, "4", BuyTaker, nil, nil},
+		{"1", 100, 10, 15, 20, "4", BuyTaker, nil, nil},
+	}, me.Trades)
+	assert.Equal([]string{"2"}, me.DropFilledOrder())
+	ord, err := me.Book.GetOrder("1", SELLSIDE, 100)
+	assert.Nil(err)
+	assert.Equal(int64(101), ord.Time)
+	assert.Equal(int64(5), ord.VisibleQty())
+
+	// it still expires by the height it was placed at
+	var expired []string
+	me.Book.RemoveOrders(91, SELLSIDE, func(ord OrderPart) {
+		expired = append(expired, ord.Id)
+	})
+	assert.Equal([]string{"1"}, expired)
+}
+
+func TestMatchEng_MatchContinuous(t *testing.T) {
+	assert := assert.New(t)
+	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+	me.Continuous = true
+	me.Book = NewOrderBookOnULList(4, 2)
+	me.Book.InsertOrder("1", SELLSIDE, 90, 101, 10)
+	me.Book.InsertOrderPart(SELLSIDE, 100, OrderPart{Id: "2", Time: 90, Qty: 20, DisplayQty: 5})
+	me.Book.InsertOrder("3", SELLSIDE, 95, 100, 10)
+	me.Book.InsertOrder("4", BUYSIDE, 95, 99, 10)
+
+	// no trade if the order does not cross the book
+	assert.False(me.WouldTake(BUYSIDE, 99))
+	me.Book.InsertOrder("5", BUYSIDE, 100, 99, 5)
+	trades, err := me.MatchContinuous("5", BUYSIDE, 99, 100)
+	assert.Nil(err)
+	assert.Empty(trades)
+	assert.Empty(me.DropFilledOrder())
+
+	// the sell orders are taken from the lowest price in time priority, at their own prices,
+	// and the replenished slice of the iceberg order joins the end of the queue
+	assert.True(me.WouldTake(BUYSIDE, 101))
+	me.Book.InsertOrder("6", BUYSIDE, 100, 101, 25)
+	trades, err = me.MatchContinuous("6", BUYSIDE, 101, 100)
+	assert.Nil(err)
+	assert.Equal([]Trade{
+		{"2", 100, 5, 5, 5, "6", BuyTaker, nil, nil},
+		{"3", 100, 10, 15, 10, "6", BuyTaker, nil, nil},
+		{"2", 100, 5, 20, 10, "6", BuyTaker, nil, nil},
+		{"2", 100, 5, 25, 15, "6", BuyTaker, nil, nil},
+	}, trades)
+	assert.Equal(int64(100), me.LastTradePrice)
+	assert.Equal(int64(100), me.LastMatchHeight)
+	assert.ElementsMatch([]string{"3", "6"}, me.DropFilledOrder())
+	ord, err := me.Book.GetOrder("2", SELLSIDE, 100)
+	assert.Nil(err)
+	assert.Equal(int64(5), ord.VisibleQty())
+
+	// the rest of the order rests in the book, and the trades of the height are collected
+	me.Book.InsertOrder("7", SELLSIDE, 100, 99, 20)
+	trades, err = me.MatchContinuous("7", SELLSIDE, 99, 100)
+	assert.Nil(err)
+	assert.Equal([]Trade{
+		{"7", 99, 10, 10, 10, "4", SellTaker, nil, nil},
+		{"7", 99, 5, 5, 15, "5", SellTaker, nil, nil},
+	}, trades)
+	assert.Len(me.Trades, 6)
+	assert.Equal([]string{"4", "5"}, me.DropFilledOrder())
+	ord, err = me.Book.GetOrder("7", SELLSIDE, 99)
+	assert.Nil(err)
+	assert.Equal(int64(5), ord.LeavesQty())
+	assert.False(me.WouldTake(SELLSIDE, 99))
+
+	// the trades are reset at a new height
+	me.Book.InsertOrder("8", BUYSIDE, 101, 99, 5)
+	trades, err = me.MatchContinuous("8", BUYSIDE, 99, 101)
+	assert.Nil(err)
+	assert.Equal([]Trade{
+		{"7", 99, 5, 5, 20, "8", BuyTaker, nil, nil},
+	}, trades)
+	assert.Len(me.Trades, 1)
+}
@@ -12,14 +12,14 @@ const DefaultPairSymbol = "ABC_BNB"
 
 func Test_sumOrders(t *testing.T) {
 	assert := assert.New(t)
-	orders := []OrderPart{OrderPart{"1", 100, 260, 0, 0}, OrderPart{"1", 100, 250, 0, 0}, OrderPart{"1", 100, 501, 0, 0}}
+	orders := []OrderPart{OrderPart{Id: "1", Time: 100, Qty: 260}, OrderPart{Id: "1", Time: 100, Qty: 250}, OrderPart{Id: "1", Time: 100, Qty: 501}}
 	assert.Equal(int64(1011), sumOrdersTotalLeft(orders, true))
 	orders[0].Qty = 10
 	orders[1].CumQty = 250
 	assert.Equal(int64(1011), sumOrdersTotalLeft(orders, false))
 	orders = []OrderPart{}
 	assert.Equal(int64(0), sumOrdersTotalLeft(orders, true))
-	orders = []OrderPart{OrderPart{"1", 100, 260, 0, 0}}
+	orders = []OrderPart{OrderPart{Id: "1", Time: 100, Qty: 260}}
 	assert.Equal(int64(260), sumOrdersTotalLeft(orders, true))
 	assert.Equal(int64(0), sumOrdersTotalLeft(nil, true))
 }
@@ -27,14 +27,14 @@ func Test_sumOrders(t *testing.T) {
 func Test_prepareMatch(t *testing.T) {
 	assert := assert.New(t)
 	overlap := []OverLappedLevel{
-		OverLappedLevel{Price: 1021, BuyOrders: []OrderPart{OrderPart{"1.1", 100, 1500, 0, 0}, OrderPart{"1.2", 102, 1500, 0, 0}}},
-		OverLappedLevel{Price: 1001, BuyOrders: []OrderPart{OrderPart{"2.1", 100, 1000, 0, 0}}},
-		OverLappedLevel{Price: 991, BuyOrders: []OrderPart{OrderPart{"3.1", 100, 2000, 0, 0}}},
+		OverLappedLevel{Price: 1021, BuyOrders: []OrderPart{OrderPart{Id: "1.1", Time: 100, Qty: 1500}, OrderPart{Id: "1.2", Time: 102, Qty: 1500}}},
+		OverLappedLevel{Price: 1001, BuyOrders: []OrderPart{OrderPart{Id: "2.1", Time: 100, Qty: 1000}}},
+		OverLappedLevel{Price: 991, BuyOrders: []OrderPart{OrderPart{Id: "3.1", Time: 100, Qty: 2000}}},
 		OverLappedLevel{Price: 981,
-			SellOrders: []OrderPart{OrderPart{"4.1", 100, 1000, 0, 0}, OrderPart{"4.2", 101, 1000, 0, 0}, OrderPart{"4.3", 101, 500, 0, 0}},
-			BuyOrders:  []OrderPart{OrderPart{"4.4", 100, 3000, 0, 0}}},
-		OverLappedLevel{Price: 971, SellOrders: []OrderPart{OrderPart{"5.1", 100, 2500, 0, 0}}},
-		OverLappedLevel{Price: 961, SellOrders: []OrderPart{OrderPart{"6.1", 101, 10000, 0, 0}}},
+			SellOrders: []OrderPart{OrderPart{Id: "4.1", Time: 100, Qty: 1000}, OrderPart{Id: "4.2", Time: 101, Qty: 1000}, OrderPart{Id: "4.3", Time: 101, Qty: 500}},
+			BuyOrders:  []OrderPart{OrderPart{Id: "4.4", Time: 100, Qty: 3000}}},
+		OverLappedLevel{Price: 971, SellOrders: []OrderPart{OrderPart{Id: "5.1", Time: 100, Qty: 2500}}},
+		OverLappedLevel{Price: 961, SellOrders: []OrderPart{OrderPart{Id: "6.1", Time: 101, Qty: 10000}}},
 	}
 	execs := []int64{3000, 4000, 6000, 9000, 9000, 9000}
 	surpluses := []int64{-12000, -11000, -9000, -6000, -3500, -1000}
@@ -50,14 +50,14 @@ func Test_prepareMatch(t *testing.T) {
 func Test_prepareMatch_overflow(t *testing.T) {
 	assert := assert.New(t)
 	overlap := []OverLappedLevel{
-		{Price: 1021, BuyOrders: []OrderPart{{"1.1", 100, 100e16, 0, 0}, {"1.2", 102, 200e16, 0, 0}}},
-		{Price: 1001, BuyOrders: []OrderPart{{"2.1", 100, 100e16, 0, 0}}},
-		{Price: 991, BuyOrders: []OrderPart{{"3.1", 100, 200e16, 0, 0}}},
+		{Price: 1021, BuyOrders: []OrderPart{{Id: "1.1", Time: 100, Qty: 100e16}, {Id: "1.2", Time: 102, Qty: 200e16}}},
+		{Price: 1001, BuyOrders: []OrderPart{{Id: "2.1", Time: 100, Qty: 100e16}}},
+		{Price: 991, BuyOrders: []OrderPart{{Id: "3.1", Time: 100, Qty: 200e16}}},
 		{Price: 981,
-			SellOrders: []OrderPart{{"4.1", 100, 100e16, 0, 0}, {"4.2", 101, 200e16, 0, 0}, {"4.3", 101, 200e16, 0, 0}},
-			BuyOrders:  []OrderPart{{"4.4", 100, 400e16, 0, 0}}},
-		{Price: 971, SellOrders: []OrderPart{{"5.1", 100, 300e16, 0, 0}}},
-		{Price: 961, SellOrders: []OrderPart{{"6.1", 101, 400e16, 0, 0}}},
+			SellOrders: []OrderPart{{Id: "4.1", Time: 100, Qty: 100e16}, {Id: "4.2", Time: 101, Qty: 200e16}, {Id: "4.3", Time: 101, Qty: 200e16}},
+			BuyOrders:  []OrderPart{{Id: "4.4", Time: 100, Qty: 400e16}}},
+		{Price: 971, SellOrders: []OrderPart{{Id: "5.1", Time: 100, Qty: 300e16}}},
+		{Price: 961, SellOrders: []OrderPart{{Id: "6.1", Time: 101, Qty: 400e16}}},
 	}
 	execs := []int64{300e16, 400e16, 600e16, math.MaxInt64, 700e16, 400e16}
 	surpluses := []int64{300e16 - math.MaxInt64, 400e16 - math.MaxInt64, 600e16 - math.MaxInt64, 0, math.MaxInt64 - 700e16, math.MaxInt64 - 400e16}
@@ -73,14 +73,14 @@ func Test_prepareMatch_overflow(t *testing.T) {
 func Test_getPriceCloseToRef(t *testing.T) {
 	assert := assert.New(t)
 	overlap := []OverLappedLevel{
-		OverLappedLevel{Price: 1021, BuyOrders: []OrderPart{OrderPart{"1.1", 100, 1500, 0, 0}, OrderPart{"1.2", 102, 1500, 0, 0}}},
-		OverLappedLevel{Price: 1001, BuyOrders: []OrderPart{OrderPart{"2.1", 100, 1000, 0, 0}}},
-		OverLappedLevel{Price: 991, BuyOrders: []OrderPart{OrderPart{"3.1", 100, 2000, 0, 0}}},
+		OverLappedLevel{Price: 1021, BuyOrders: []OrderPart{OrderPart{Id: "1.1", Time: 100, Qty: 1500}, OrderPart{Id: "1.2", Time: 102, Qty: 1500}}},
+		OverLappedLevel{Price: 1001, BuyOrders: []OrderPart{OrderPart{Id: "2.1", Time: 100, Qty: 1000}}},
+		OverLappedLevel{Price: 991, BuyOrders: []OrderPart{OrderPart{Id: "3.1", Time: 100, Qty: 2000}}},
 		OverLappedLevel{Price: 981,
-			SellOrders: []OrderPart{OrderPart{"4.1", 100, 1000, 0, 0}, OrderPart{"4.2", 101, 1000, 0, 0}, OrderPart{"4.3", 101, 500, 0, 0}},
-			BuyOrders:  []OrderPart{OrderPart{"4.4", 100, 3000, 0, 0}}},
-		OverLappedLevel{Price: 971, SellOrders: []OrderPart{OrderPart{"5.1", 100, 2500, 0, 0}}},
-		OverLappedLevel{Price: 961, SellOrders: []OrderPart{OrderPart{"6.1", 101, 10000, 0, 0}}},
+			SellOrders: []OrderPart{OrderPart{Id: "4.1", Time: 100, Qty: 1000}, OrderPart{Id: "4.2", Time: 101, Qty: 1000}, OrderPart{Id: "4.3", Time: 101, Qty: 500}},
+			BuyOrders:  []OrderPart{OrderPart{Id: "4.4", Time: 100, Qty: 3000}}},
+		OverLappedLevel{Price: 971, SellOrders: []OrderPart{OrderPart{Id: "5.1", Time: 100, Qty: 2500}}},
+		OverLappedLevel{Price: 961, SellOrders: []OrderPart{OrderPart{Id: "6.1", Time: 101, Qty: 10000}}},
 	}
 
 	p, i := getPriceCloseToRef(overlap, []int{0, 1, 2}, 990)
@@ -475,16 +475,16 @@ func TestMatchEng_fillOrders(t *testing.T) {
 	me.LastTradePrice = 999
 	me.overLappedLevel = []OverLappedLevel{OverLappedLevel{Price: 1000,
 		BuyOrders: []OrderPart{
-			OrderPart{"1", 100, 70, 0, 0},
-			OrderPart{"2", 100, 80, 0, 0},
-			OrderPart{"3", 100, 100, 0, 0},
-			OrderPart{"4", 100, 50, 0, 0},
+			OrderPart{Id: "1", Time: 100, Qty: 70},
+			OrderPart{Id: "2", Time: 100, Qty: 80},
+			OrderPart{Id: "3", Time: 100, Qty: 100},
+			OrderPart{Id: "4", Time: 100, Qty: 50},
 		},
 		SellOrders: []OrderPart{
-			OrderPart{"6", 100, 100, 0, 0},
-			OrderPart{"7", 100, 50, 0, 0},
-			OrderPart{"8", 100, 70, 0, 0},
-			OrderPart{"9", 100, 60, 0, 0},
+			OrderPart{Id: "6", Time: 100, Qty: 100},
+			OrderPart{Id: "7", Time: 100, Qty: 50},
+			OrderPart{Id: "8", Time: 100, Qty: 70},
+			OrderPart{Id: "9", Time: 100, Qty: 60},
 		},
 	}}
 	prepareMatch(&me.overLappedLevel)
@@ -507,19 +507,19 @@ func TestMatchEng_fillOrders(t *testing.T) {
 	me.overLappedLevel = []OverLappedLevel{
 		OverLappedLevel{Price: 1000,
 			BuyOrders: []OrderPart{
-				OrderPart{"1", 100, 70, 0, 0},
-				OrderPart{"2", 100, 80, 0, 0},
-				OrderPart{"3", 100, 100, 0, 0},
-				OrderPart{"4", 100, 50, 0, 0},
+				OrderPart{Id: "1", Time: 100, Qty: 70},
+				OrderPart{Id: "2", Time: 100, Qty: 80},
+				OrderPart{Id: "3", Time: 100, Qty: 100},
+				OrderPart{Id: "4", Time: 100, Qty: 50},
 			},
 			SellOrders: []OrderPart{}},
 		OverLappedLevel{Price: 1000,
 			BuyOrders: []OrderPart{},
 			SellOrders: []OrderPart{
-				OrderPart{"6", 100, 100, 0, 0},
-				OrderPart{"7", 100, 50, 0, 0},
-				OrderPart{"8", 100, 70, 0, 0},
-				OrderPart{"9", 100, 60, 0, 0},
+				OrderPart{Id: "6", Time: 100, Qty: 100},
+				OrderPart{Id: "7", Time: 100, Qty: 50},
+				OrderPart{Id: "8", Time: 100, Qty: 70},
+				OrderPart{Id: "9", Time: 100, Qty: 60},
 			}},
 	}
 	prepareMatch(&me.overLappedLevel)
@@ -542,7 +542,7 @@ func TestMatchEng_fillOrders(t *testing.T) {
 func Test_allocateResidual(t *testing.T) {
 	assert := assert.New(t)
 	orders := []OrderPart{
-		OrderPart{"1", 100, 1800, 900, 900},
+		OrderPart{Id: "1", Time: 100, Qty: 1800, CumQty: 900, nxtTrade: 900},
 	}
 	var toAlloc int64 = 500
 	assert.True(allocateResidual(&toAlloc, orders, 5))
@@ -550,9 +550,9 @@ func Test_allocateResidual(t *testing.T) {
 	assert.Equal(int64(0), toAlloc)
 
 	orders = []OrderPart{
-		OrderPart{"1", 100, 900, 0, 900},
-		OrderPart{"2", 100, 300, 0, 300},
-		OrderPart{"3", 100, 600, 0, 600},
+		OrderPart{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "2", Time: 100, Qty: 300, nxtTrade: 300},
+		OrderPart{Id: "3", Time: 100, Qty: 600, nxtTrade: 600},
 	}
 	toAlloc = 600
 	assert.True(allocateResidual(&toAlloc, orders, 5))
@@ -564,9 +564,9 @@ func Test_allocateResidual(t *testing.T) {
 	assert.Equal(int64(0), toAlloc)
 
 	orders = []OrderPart{
-		OrderPart{"1", 100, 900, 0, 900},
-		OrderPart{"2", 100, 300, 0, 300},
-		OrderPart{"3", 100, 600, 0, 600},
+		OrderPart{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "2", Time: 100, Qty: 300, nxtTrade: 300},
+		OrderPart{Id: "3", Time: 100, Qty: 600, nxtTrade: 600},
 	}
 	toAlloc = 500
 	assert.True(allocateResidual(&toAlloc, orders, 5))
@@ -578,9 +578,9 @@ func Test_allocateResidual(t *testing.T) {
 	assert.Equal(int64(0), toAlloc)
 
 	orders = []OrderPart{
-		OrderPart{"1", 100, 900, 0, 900},
-		OrderPart{"2", 100, 300, 0, 300},
-		OrderPart{"3", 100, 600, 0, 600},
+		OrderPart{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "2", Time: 100, Qty: 300, nxtTrade: 300},
+		OrderPart{Id: "3", Time: 100, Qty: 600, nxtTrade: 600},
 	}
 	toAlloc = 25
 	assert.True(allocateResidual(&toAlloc, orders, 5))
@@ -592,9 +592,9 @@ func Test_allocateResidual(t *testing.T) {
 	assert.Equal(int64(0), toAlloc)
 
 	orders = []OrderPart{
-		OrderPart{"1", 100, 900, 0, 900},
-		OrderPart{"2", 100, 300, 0, 300},
-		OrderPart{"3", 100, 600, 0, 600},
+		OrderPart{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "2", Time: 100, Qty: 300, nxtTrade: 300},
+		OrderPart{Id: "3", Time: 100, Qty: 600, nxtTrade: 600},
 	}
 	toAlloc = 35
 	assert.True(allocateResidual(&toAlloc, orders, 5))
@@ -606,9 +606,9 @@ func Test_allocateResidual(t *testing.T) {
 	assert.Equal(int64(0), toAlloc)
 
 	orders = []OrderPart{
-		OrderPart{"1", 100, 900, 0, 900},
-		OrderPart{"2", 100, 900, 0, 900},
-		OrderPart{"3", 100, 900, 0, 900},
+		OrderPart{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "2", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "3", Time: 100, Qty: 900, nxtTrade: 900},
 	}
 	toAlloc = 700
 	assert.True(allocateResidual(&toAlloc, orders, 5))
@@ -620,9 +620,9 @@ func Test_allocateResidual(t *testing.T) {
 	assert.Equal(int64(0), toAlloc)
 
 	orders = []OrderPart{
-		OrderPart{"1", 100, 1, 0, 1},
-		OrderPart{"2", 100, 10, 0, 10},
-		OrderPart{"3", 100, 6, 0, 6},
+		OrderPart{Id: "1", Time: 100, Qty: 1, nxtTrade: 1},
+		OrderPart{Id: "2", Time: 100, Qty: 10, nxtTrade: 10},
+		OrderPart{Id: "3", Time: 100, Qty: 6, nxtTrade: 6},
 	}
 	toAlloc = 15
 	allocateResidual(&toAlloc, orders, 5)
@@ -634,9 +634,9 @@ func Test_allocateResidual(t *testing.T) {
 	assert.Equal(int64(0), toAlloc)
 
 	orders = []OrderPart{
-		OrderPart{"1", 100, 10, 0, 10},
-		OrderPart{"2", 100, 5, 0, 5},
-		OrderPart{"3", 100, 50, 0, 50},
+		OrderPart{Id: "1", Time: 100, Qty: 10, nxtTrade: 10},
+		OrderPart{Id: "2", Time: 100, Qty: 5, nxtTrade: 5},
+		OrderPart{Id: "3", Time: 100, Qty: 50, nxtTrade: 50},
 	}
 	toAlloc = 35
 	allocateResidual(&toAlloc, orders, 5)
@@ -653,9 +653,9 @@ func Test_allocateResidualOddLot(t *testing.T) {
 
 	var toAlloc int64 = 605
 	orders := []OrderPart{
-		OrderPart{"1", 100, 300, 0, 300},
-		OrderPart{"2", 100, 900, 0, 900},
-		OrderPart{"3", 100, 600, 0, 600},
+		OrderPart{Id: "1", Time: 100, Qty: 300, nxtTrade: 300},
+		OrderPart{Id: "2", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "3", Time: 100, Qty: 600, nxtTrade: 600},
 	}
 	assert.True(allocateResidual(&toAlloc, orders, 10))
 	assert.Equal(int64(105), orders[0].nxtTrade)
@@ -667,9 +667,9 @@ func Test_allocateResidualOddLot(t *testing.T) {
 
 	toAlloc = 5
 	orders = []OrderPart{
-		OrderPart{"1", 100, 300, 0, 300},
-		OrderPart{"2", 100, 900, 0, 900},
-		OrderPart{"3", 100, 600, 0, 600},
+		OrderPart{Id: "1", Time: 100, Qty: 300, nxtTrade: 300},
+		OrderPart{Id: "2", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "3", Time: 100, Qty: 600, nxtTrade: 600},
 	}
 	assert.True(allocateResidual(&toAlloc, orders, 10))
 	assert.Equal(int64(5), orders[0].nxtTrade)
@@ -678,9 +678,9 @@ func Test_allocateResidualOddLot(t *testing.T) {
 
 	toAlloc = 15
 	orders = []OrderPart{
-		OrderPart{"1", 100, 25, 0, 25},
-		OrderPart{"2", 100, 25, 0, 25},
-		OrderPart{"3", 100, 25, 0, 25},
+		OrderPart{Id: "1", Time: 100, Qty: 25, nxtTrade: 25},
+		OrderPart{Id: "2", Time: 100, Qty: 25, nxtTrade: 25},
+		OrderPart{Id: "3", Time: 100, Qty: 25, nxtTrade: 25},
 	}
 	assert.True(allocateResidual(&toAlloc, orders, 10))
 	assert.Equal(int64(10), orders[0].nxtTrade)
@@ -694,14 +694,14 @@ func TestMatchEng_reserveQty(t *testing.T) {
 	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
 	assert := assert.New(t)
 	orders := []OrderPart{
-		OrderPart{"1", 100, 900, 0, 900},
+		OrderPart{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
 	}
 	assert.True(me.reserveQty(700, orders))
 	assert.Equal(int64(700), orders[0].nxtTrade)
 	orders = []OrderPart{
-		OrderPart{"1", 100, 900, 0, 900},
-		OrderPart{"2", 100, 900, 0, 900},
-		OrderPart{"3", 100, 900, 0, 900},
+		OrderPart{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "2", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "3", Time: 100, Qty: 900, nxtTrade: 900},
 	}
 
 	assert.True(me.reserveQty(900, orders))
@@ -710,9 +710,9 @@ func TestMatchEng_reserveQty(t *testing.T) {
 	assert.Equal(int64(300), orders[0].nxtTrade)
 
 	orders = []OrderPart{
-		OrderPart{"1", 100, 900, 0, 900},
-		OrderPart{"2", 100, 900, 0, 900},
-		OrderPart{"3", 100, 900, 0, 900},
+		OrderPart{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "2", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "3", Time: 100, Qty: 900, nxtTrade: 900},
 	}
 
 	assert.True(me.reserveQty(700, orders))
@@ -721,9 +721,9 @@ func TestMatchEng_reserveQty(t *testing.T) {
 	assert.Equal(int64(230), orders[2].nxtTrade)
 
 	orders = []OrderPart{
-		OrderPart{"1", 100, 900, 0, 900},
-		OrderPart{"2", 100, 900, 0, 900},
-		OrderPart{"3", 101, 900, 0, 900},
+		OrderPart{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "2", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "3", Time: 101, Qty: 900, nxtTrade: 900},
 	}
 
 	assert.True(me.reserveQty(700, orders))
@@ -732,13 +732,13 @@ func TestMatchEng_reserveQty(t *testing.T) {
 	assert.Equal(int64(0), orders[2].nxtTrade)
 
 	orders = []OrderPart{
-		OrderPart{"1", 100, 900, 0, 900},
-		OrderPart{"2", 100, 900, 0, 900},
-		OrderPart{"3", 101, 900, 0, 900},
-		OrderPart{"6", 101, 900, 0, 900},
-		OrderPart{"4", 102, 900, 0, 900},
-		OrderPart{"5", 102, 900, 0, 900},
-		OrderPart{"7", 102, 900, 0, 900},
+		OrderPart{Id: "1", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "2", Time: 100, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "3", Time: 101, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "6", Time: 101, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "4", Time: 102, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "5", Time: 102, Qty: 900, nxtTrade: 900},
+		OrderPart{Id: "7", Time: 102, Qty: 900, nxtTrade: 900},
 	}
 
 	assert.True(me.reserveQty(4300, orders))
@@ -897,3 +897,17 @@ func TestMatchEng_DropFilledOrder(t *testing.T) {
 		assert.True(o.CumQty > 0)
 	}
 }
+
+func TestMatchEng_MarketProtectionPrice(t *testing.T) {
+	assert := assert.New(t)
+	me := NewMatchEng("XYZ-000_BNB", 1000, 5, 0.05)
+	assert.Equal(int64(1050), me.MarketProtectionPrice(BUYSIDE))
+	assert.Equal(int64(950), me.MarketProtectionPrice(SELLSIDE))
+
+	me.LastTradePrice = 10
+	assert.Equal(int64(11), me.MarketProtectionPrice(BUYSIDE))
+	assert.Equal(int64(9), me.MarketProtectionPrice(SELLSIDE))
+
+	me.LastTradePrice = 1
+	assert.Equal(int64(1), me.MarketProtectionPrice(SELLSIDE))
+}
@@ -9,16 +9,19 @@ import (
 )
 
 // OrderBookInterface is a generic sequenced order to quickly get the spread to match.
-// It can be implemented in different structures but here a fast unrolled-linked list,
-// or/and google/B-Tree are chosen, still need performance benchmark to justify this.
+// It is implemented on a fast unrolled-linked list, google/B-Tree and a skip list,
+// the backend can be chosen per pair type via NewOrderBook.
 type OrderBookInterface interface {
 	GetOverlappedRange(overlapped *[]OverLappedLevel, buyBuf *[]PriceLevel, sellBuf *[]PriceLevel) int
 	//TODO: especially for ULList, it might be faster by inserting multiple orders in one go then
 	//looping through InsertOrder() one after another.
 	InsertOrder(id string, side int8, time int64, price int64, qty int64) (*PriceLevel, error)
+	// InsertOrderPart is InsertOrder with the owner and the self-trade prevention mode of the order
+	InsertOrderPart(side int8, price int64, ord OrderPart) (*PriceLevel, error)
 	InsertPriceLevel(p *PriceLevel, side int8) error
 	GetOrder(id string, side int8, price int64) (OrderPart, error)
 	RemoveOrder(id string, side int8, price int64) (OrderPart, error)
+	UpdateOrderQty(id string, side int8, price int64, qty int64) (OrderPart, error)
 	RemoveOrders(beforeTime int64, side int8, cb func(OrderPart)) error
 	RemoveOrdersBasedOnPriceLevel(expireTime int64, forceExpireTime int64, priceLevelsToReserve int, side int8, removeCallback func(ord OrderPart)) error
 	UpdateForEachPriceLevel(side int8, updater LevelIter)
@@ -29,6 +32,36 @@ type OrderBookInterface interface {
 	Clear()
 }
 
+// the order book backends which can be chosen by NewOrderBook
+const (
+	OrderBookULList   = "ullist"
+	OrderBookBTree    = "btree"
+	OrderBookSkipList = "skiplist"
+)
+
+// IsValidOrderBookBackend returns true if `backend` is a known order book backend.
+// An empty backend is valid and falls back to OrderBookULList.
+func IsValidOrderBookBackend(backend string) bool {
+	switch backend {
+	case "", OrderBookULList, OrderBookBTree, OrderBookSkipList:
+		return true
+	}
+	return false
+}
+
+// NewOrderBook creates an empty order book on `backend`
+func NewOrderBook(backend string) (OrderBookInterface, error) {
+	switch backend {
+	case "", OrderBookULList:
+		return NewOrderBookOnULList(10000, 16), nil
+	case OrderBookBTree:
+		return NewOrderBookOnBTree(8), nil
+	case OrderBookSkipList:
+		return NewOrderBookOnSkipList(), nil
+	}
+	return nil, fmt.Errorf("unknown order book backend %s", backend)
+}
+
 type OrderBookOnBTree struct {
 	buyQueue  *bt.BTree
 	sellQueue *bt.BTree
@@ -39,7 +72,14 @@ type OrderBookOnULList struct {
 	sellQueue *ULList
 }
 
+type OrderBookOnSkipList struct {
+	buyQueue  *SkipList
+	sellQueue *SkipList
+}
+
 var _ OrderBookInterface = (*OrderBookOnULList)(nil)
+var _ OrderBookInterface = (*OrderBookOnBTree)(nil)
+var _ OrderBookInterface = (*OrderBookOnSkipList)(nil)
 
 func NewOrderBookOnULList(capacity int, bucketSize int) *OrderBookOnULList {
 	//TODO: find out the best degree
@@ -87,17 +127,21 @@ func (ob *OrderBookOnULList) GetOverlappedRange(overlapped *[]OverLappedLevel, b
 }
 
 func (ob *OrderBookOnULList) InsertOrder(id string, side int8, time int64, price int64, qty int64) (*PriceLevel, error) {
+	return ob.InsertOrderPart(side, price, OrderPart{Id: id, Time: time, Qty: qty})
+}
+
+func (ob *OrderBookOnULList) InsertOrderPart(side int8, price int64, ord OrderPart) (*PriceLevel, error) {
 	q := ob.getSideQueue(side)
 	var pl *PriceLevel
 	if pl = q.GetPriceLevel(price); pl == nil {
 		// price level not exist, insert a new one
-		pl = &PriceLevel{price, []OrderPart{{id, time, qty, 0, 0}}}
+		pl = &PriceLevel{price, []OrderPart{ord}}
 		if !q.AddPriceLevel(pl) {
-			return pl, fmt.Errorf("Failed to insert order %s at price %d", id, price)
+			return pl, fmt.Errorf("Failed to insert order %s at price %d", ord.Id, price)
 		}
 		return pl, nil
 	} else {
-		if _, err := pl.addOrder(id, time, qty); err != nil {
+		if _, err := pl.addOrderPart(ord); err != nil {
 			return pl, err
 		}
 		return pl, nil
@@ -130,6 +174,15 @@ func (ob *OrderBookOnULList) RemoveOrder(id string, side int8, price int64) (Ord
 	return op, ok
 }
 
+func (ob *OrderBookOnULList) UpdateOrderQty(id string, side int8, price int64, qty int64) (OrderPart, error) {
+	q := ob.getSideQueue(side)
+	var pl *PriceLevel
+	if pl = q.GetPriceLevel(price); pl == nil {
+		return OrderPart{}, fmt.Errorf("order price %d doesn't exist at side %d.", price, side)
+	}
+	return pl.updateOrderQty(id, qty)
+}
+
 func (ob *OrderBookOnULList) RemoveOrders(beforeTime int64, side int8, cb func(OrderPart)) error {
 	ob.UpdateForEachPriceLevel(side, func(pl *PriceLevel, levelIndex int) {
 		pl.removeOrders(beforeTime, cb)
@@ -202,6 +255,164 @@ func (ob *OrderBookOnULList) Clear() {
 	ob.sellQueue.Clear()
 }
 
+func NewOrderBookOnSkipList() *OrderBookOnSkipList {
+	return &OrderBookOnSkipList{NewSkipList(compareBuy), NewSkipList(compareSell)}
+}
+
+func (ob *OrderBookOnSkipList) String() string {
+	return fmt.Sprintf("buyQueue: [%v]\nsellQueue:[%v]", ob.buyQueue, ob.sellQueue)
+}
+
+func (ob *OrderBookOnSkipList) getSideQueue(side int8) *SkipList {
+	switch side {
+	case BUYSIDE:
+		return ob.buyQueue
+	case SELLSIDE:
+		return ob.sellQueue
+	}
+	return nil
+}
+
+func (ob *OrderBookOnSkipList) GetOverlappedRange(overlapped *[]OverLappedLevel, buyBuf *[]PriceLevel, sellBuf *[]PriceLevel) int {
+	*overlapped = (*overlapped)[:0]
+	*buyBuf = (*buyBuf)[:0]
+	*sellBuf = (*sellBuf)[:0]
+	buyTop := ob.buyQueue.GetTop()
+	if buyTop == nil { // one side market
+		return 0
+	}
+	sellTop := ob.sellQueue.GetTop()
+	if sellTop == nil { // on side market
+		return 0
+	}
+	var p2, p1 int64 = buyTop.Price, sellTop.Price
+	if compareBuy(p2, p1) < 0 { //p2 < p1
+		return 0 // not overlapped
+	}
+	buyLevels := ob.buyQueue.GetPriceRange(p2, p1, buyBuf)
+	sellLevels := ob.sellQueue.GetPriceRange(p1, p2, sellBuf)
+	mergeLevels(buyLevels, sellLevels, overlapped)
+	return len(*overlapped)
+}
+
+func (ob *OrderBookOnSkipList) InsertOrder(id string, side int8, time int64, price int64, qty int64) (*PriceLevel, error) {
+	return ob.InsertOrderPart(side, price, OrderPart{Id: id, Time: time, Qty: qty})
+}
+
+func (ob *OrderBookOnSkipList) InsertOrderPart(side int8, price int64, ord OrderPart) (*PriceLevel, error) {
+	q := ob.getSideQueue(side)
+	if pl := q.GetPriceLevel(price); pl != nil {
+		_, err := pl.addOrderPart(ord)
+		return pl, err
+	}
+	// price level not exist, insert a new one
+	pl := &PriceLevel{price, []OrderPart{ord}}
+	if !q.AddPriceLevel(pl) {
+		return pl, fmt.Errorf("Failed to insert order %s at price %d", ord.Id, price)
+	}
+	return pl, nil
+}
+
+func (ob *OrderBookOnSkipList) InsertPriceLevel(pl *PriceLevel, side int8) error {
+	q := ob.getSideQueue(side)
+	if !q.AddPriceLevel(pl) {
+		return fmt.Errorf("Failed to insert price level at price %d", pl.Price)
+	}
+	return nil
+}
+
+func (ob *OrderBookOnSkipList) GetOrder(id string, side int8, price int64) (OrderPart, error) {
+	q := ob.getSideQueue(side)
+	var pl *PriceLevel
+	if pl = q.GetPriceLevel(price); pl == nil {
+		return OrderPart{}, fmt.Errorf("order price %d doesn't exist at side %d.", price, side)
+	}
+	return pl.getOrder(id)
+}
+
+func (ob *OrderBookOnSkipList) RemoveOrder(id string, side int8, price int64) (OrderPart, error) {
+	q := ob.getSideQueue(side)
+	var pl *PriceLevel
+	if pl = q.GetPriceLevel(price); pl == nil {
+		return OrderPart{}, fmt.Errorf("order price %d doesn't exist at side %d.", price, side)
+	}
+	op, total, err := pl.removeOrder(id)
+	if err != nil {
+		return op, err
+	}
+	//price level is gone
+	if total == 0 {
+		q.DeletePriceLevel(pl.Price)
+	}
+	return op, nil
+}
+
+func (ob *OrderBookOnSkipList) UpdateOrderQty(id string, side int8, price int64, qty int64) (OrderPart, error) {
+	q := ob.getSideQueue(side)
+	var pl *PriceLevel
+	if pl = q.GetPriceLevel(price); pl == nil {
+		return OrderPart{}, fmt.Errorf("order price %d doesn't exist at side %d.", price, side)
+	}
+	return pl.updateOrderQty(id, qty)
+}
+
+func (ob *OrderBookOnSkipList) RemoveOrders(beforeTime int64, side int8, cb func(OrderPart)) error {
+	ob.UpdateForEachPriceLevel(side, func(pl *PriceLevel, levelIndex int) {
+		pl.removeOrders(beforeTime, cb)
+	})
+	return nil
+}
+
+func (ob *OrderBookOnSkipList) RemoveOrdersBasedOnPriceLevel(expireTime int64, forceExpireTime int64, priceLevelsToReserve int, side int8, removeCallback func(ord OrderPart)) error {
+	ob.UpdateForEachPriceLevel(side, func(pl *PriceLevel, levelIndex int) {
+		if levelIndex < priceLevelsToReserve {
+			pl.removeOrders(forceExpireTime, removeCallback)
+		} else {
+			pl.removeOrders(expireTime, removeCallback)
+		}
+	})
+	return nil
+}
+
+func (ob *OrderBookOnSkipList) UpdateForEachPriceLevel(side int8, updater LevelIter) {
+	ob.getSideQueue(side).UpdateForEach(updater)
+}
+
+func (ob *OrderBookOnSkipList) GetPriceLevel(price int64, side int8) *PriceLevel {
+	return ob.getSideQueue(side).GetPriceLevel(price)
+}
+
+func (ob *OrderBookOnSkipList) RemovePriceLevel(price int64, side int8) int {
+	if ob.getSideQueue(side).DeletePriceLevel(price) {
+		return 1
+	}
+	return 0
+}
+
+func (ob *OrderBookOnSkipList) ShowDepth(maxLevels int, iterBuy LevelIter, iterSell LevelIter) {
+	ob.buyQueue.Iterate(maxLevels, iterBuy)
+	ob.sellQueue.Iterate(maxLevels, iterSell)
+}
+
+func (ob *OrderBookOnSkipList) GetAllLevels() ([]PriceLevel, []PriceLevel) {
+	buys := make([]PriceLevel, 0, ob.buyQueue.Len())
+	sells := make([]PriceLevel, 0, ob.sellQueue.Len())
+	ob.buyQueue.Iterate(ob.buyQueue.Len(),
+		func(p *PriceLevel, levelIndex int) {
+			buys = append(buys, *p)
+		})
+	ob.sellQueue.Iterate(ob.sellQueue.Len(),
+		func(p *PriceLevel, levelIndex int) {
+			sells = append(sells, *p)
+		})
+	return buys, sells
+}
+
+func (ob *OrderBookOnSkipList) Clear() {
+	ob.buyQueue.Clear()
+	ob.sellQueue.Clear()
+}
+
 func (ob *OrderBookOnBTree) getSideQueue(side int8) *bt.BTree {
 	switch side {
 	case BUYSIDE:
@@ -260,20 +471,24 @@ func (ob *OrderBookOnBTree) GetOverlappedRange(overlapped *[]OverLappedLevel, bu
 }
 
 func (ob *OrderBookOnBTree) InsertOrder(id string, side int8, time int64, price int64, qty int64) (*PriceLevel, error) {
+	return ob.InsertOrderPart(side, price, OrderPart{Id: id, Time: time, Qty: qty})
+}
+
+func (ob *OrderBookOnBTree) InsertOrderPart(side int8, price int64, ord OrderPart) (*PriceLevel, error) {
 	q := ob.getSideQueue(side)
 
 	if pl := q.Get(newPriceLevelKey(price, side)); pl == nil {
 		// price level not exist, insert a new one
-		pl2 := newPriceLevelBySide(price, []OrderPart{{id, time, qty, 0, 0}}, side)
+		pl2 := newPriceLevelBySide(price, []OrderPart{ord}, side)
 		if q.ReplaceOrInsert(pl2) != nil {
-			return toPriceLevel(pl2, side), fmt.Errorf("Severe error: data consistence break when insert %v @ %v orderbook", id, price)
+			return toPriceLevel(pl2, side), fmt.Errorf("Severe error: data consistence break when insert %v @ %v orderbook", ord.Id, price)
 		}
 		return toPriceLevel(pl2, side), nil
 	} else {
 		if pl2, ok := pl.(PriceLevelInterface); !ok {
 			return nil, errors.New("Severe error: Wrong type item inserted into OrderBook")
 		} else {
-			_, e := pl2.addOrder(id, time, qty)
+			_, e := pl2.addOrderPart(ord)
 			return toPriceLevel(pl2, side), e
 		}
 	}
@@ -301,6 +516,129 @@ func (ob *OrderBookOnBTree) RemoveOrder(id string, side int8, price int64) (Orde
 	}
 }
 
+func (ob *OrderBookOnBTree) InsertPriceLevel(pl *PriceLevel, side int8) error {
+	q := ob.getSideQueue(side)
+	if q.Has(newPriceLevelKey(pl.Price, side)) {
+		return fmt.Errorf("Failed to insert price level at price %d", pl.Price)
+	}
+	q.ReplaceOrInsert(newPriceLevelBySide(pl.Price, pl.Orders, side))
+	return nil
+}
+
+func (ob *OrderBookOnBTree) getPriceLevel(price int64, side int8) (*PriceLevel, error) {
+	q := ob.getSideQueue(side)
+	var pl bt.Item
+	if pl = q.Get(newPriceLevelKey(price, side)); pl == nil {
+		return nil, fmt.Errorf("order price %d doesn't exist at side %d.", price, side)
+	}
+	if pl2, ok := pl.(PriceLevelInterface); !ok {
+		return nil, errors.New("Severe error: Wrong type item inserted into OrderBook")
+	} else {
+		return toPriceLevel(pl2, side), nil
+	}
+}
+
+func (ob *OrderBookOnBTree) GetOrder(id string, side int8, price int64) (OrderPart, error) {
+	pl, err := ob.getPriceLevel(price, side)
+	if err != nil {
+		return OrderPart{}, err
+	}
+	return pl.getOrder(id)
+}
+
+func (ob *OrderBookOnBTree) UpdateOrderQty(id string, side int8, price int64, qty int64) (OrderPart, error) {
+	pl, err := ob.getPriceLevel(price, side)
+	if err != nil {
+		return OrderPart{}, err
+	}
+	return pl.updateOrderQty(id, qty)
+}
+
+func (ob *OrderBookOnBTree) RemoveOrders(beforeTime int64, side int8, cb func(OrderPart)) error {
+	ob.UpdateForEachPriceLevel(side, func(pl *PriceLevel, levelIndex int) {
+		pl.removeOrders(beforeTime, cb)
+	})
+	return nil
+}
+
+func (ob *OrderBookOnBTree) RemoveOrdersBasedOnPriceLevel(expireTime int64, forceExpireTime int64, priceLevelsToReserve int, side int8, removeCallback func(ord OrderPart)) error {
+	ob.UpdateForEachPriceLevel(side, func(pl *PriceLevel, levelIndex int) {
+		if levelIndex < priceLevelsToReserve {
+			pl.removeOrders(forceExpireTime, removeCallback)
+		} else {
+			pl.removeOrders(expireTime, removeCallback)
+		}
+	})
+	return nil
+}
+
+// UpdateForEachPriceLevel calls `updater` from the best price level, the price levels left without orders are removed
+func (ob *OrderBookOnBTree) UpdateForEachPriceLevel(side int8, updater LevelIter) {
+	q := ob.getSideQueue(side)
+	emptyLevels := make([]bt.Item, 0)
+	levelIndex := 0
+	q.Ascend(func(i bt.Item) bool {
+		pl := toPriceLevel(i.(PriceLevelInterface), side)
+		updater(pl, levelIndex)
+		levelIndex++
+		if len(pl.Orders) == 0 {
+			emptyLevels = append(emptyLevels, i)
+		}
+		return true
+	})
+	// the tree can not be modified during iteration
+	for _, i := range emptyLevels {
+		q.Delete(i)
+	}
+}
+
+func (ob *OrderBookOnBTree) GetPriceLevel(price int64, side int8) *PriceLevel {
+	pl, _ := ob.getPriceLevel(price, side)
+	return pl
+}
+
+func (ob *OrderBookOnBTree) RemovePriceLevel(price int64, side int8) int {
+	q := ob.getSideQueue(side)
+	if q.Delete(newPriceLevelKey(price, side)) != nil {
+		return 1
+	}
+	return 0
+}
+
+func (ob *OrderBookOnBTree) iterate(side int8, maxLevels int, iter LevelIter) {
+	levelIndex := 0
+	ob.getSideQueue(side).Ascend(func(i bt.Item) bool {
+		if levelIndex >= maxLevels {
+			return false
+		}
+		iter(toPriceLevel(i.(PriceLevelInterface), side), levelIndex)
+		levelIndex++
+		return true
+	})
+}
+
+func (ob *OrderBookOnBTree) ShowDepth(maxLevels int, iterBuy LevelIter, iterSell LevelIter) {
+	ob.iterate(BUYSIDE, maxLevels, iterBuy)
+	ob.iterate(SELLSIDE, maxLevels, iterSell)
+}
+
+func (ob *OrderBookOnBTree) GetAllLevels() ([]PriceLevel, []PriceLevel) {
+	buys := make([]PriceLevel, 0, ob.buyQueue.Len())
+	sells := make([]PriceLevel, 0, ob.sellQueue.Len())
+	ob.iterate(BUYSIDE, ob.buyQueue.Len(), func(p *PriceLevel, levelIndex int) {
+		buys = append(buys, *p)
+	})
+	ob.iterate(SELLSIDE, ob.sellQueue.Len(), func(p *PriceLevel, levelIndex int) {
+		sells = append(sells, *p)
+	})
+	return buys, sells
+}
+
+func (ob *OrderBookOnBTree) Clear() {
+	ob.buyQueue.Clear(false)
+	ob.sellQueue.Clear(false)
+}
+
 func toPriceLevel(pi PriceLevelInterface, side int8) *PriceLevel {
 	switch side {
 	case BUYSIDE:
@@ -348,5 +686,5 @@ func newPriceLevelKey(price int64, side int8) PriceLevelInterface {
 func NewOrderBookOnBTree(d int) *OrderBookOnBTree {
 	//TODO: find out the best degree
 	// 16 is my magic number, hopefully the real overlapped levels are less
-	return &OrderBookOnBTree{bt.New(8), bt.New(8)}
+	return &OrderBookOnBTree{bt.New(d), bt.New(d)}
 }
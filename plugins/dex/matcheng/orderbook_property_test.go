@@ -0,0 +1,136 @@
+package matcheng
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/upgrade"
+)
+
+var orderBookBackends = []string{OrderBookULList, OrderBookBTree, OrderBookSkipList}
+
+type liveOrder struct {
+	side  int8
+	price int64
+}
+
+func newBackendEngines(t *testing.T, lotSize int64) []*MatchEng {
+	engs := make([]*MatchEng, len(orderBookBackends))
+	for i, backend := range orderBookBackends {
+		book, err := NewOrderBook(backend)
+		require.Nil(t, err)
+		engs[i] = NewMatchEngOnBook(DefaultPairSymbol, 1000, lotSize, 0.05, book)
+	}
+	return engs
+}
+
+// requireSameBooks checks that all the engines hold the same price levels and orders
+func requireSameBooks(t *testing.T, engs []*MatchEng, msg string) {
+	buys, sells := engs[0].Book.GetAllLevels()
+	for i := 1; i < len(engs); i++ {
+		b, s := engs[i].Book.GetAllLevels()
+		require.Equal(t, buys, b, "%s: buy levels of %s", msg, orderBookBackends[i])
+		require.Equal(t, sells, s, "%s: sell levels of %s", msg, orderBookBackends[i])
+	}
+}
+
+// TestOrderBook_CrossBackendMatch runs the same randomized orders, cancels, expires and matches
+// on every order book backend, and requires identical trades and books after every step.
+func TestOrderBook_CrossBackendMatch(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+	const lotSize = 10
+	var totalTrades int
+	for seed := int64(1); seed <= 30; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		engs := newBackendEngines(t, lotSize)
+		live := make(map[string]liveOrder)
+		liveIds := make([]string, 0)
+		var seq int
+		for height := int64(1); height <= 40; height++ {
+			step := fmt.Sprintf("seed %d height %d", seed, height)
+			for n := r.Intn(20); n > 0; n-- {
+				seq++
+				id := fmt.Sprintf("order-%d", seq)
+				side := int8(r.Intn(2) + 1)
+				price := int64(950 + r.Intn(100))
+				qty := int64(r.Intn(20)+1) * lotSize
+				for _, eng := range engs {
+					_, err := eng.Book.InsertOrder(id, side, height, price, qty)
+					require.Nil(t, err, step)
+				}
+				live[id] = liveOrder{side, price}
+				liveIds = append(liveIds, id)
+			}
+			for n := r.Intn(4); n > 0 && len(liveIds) > 0; n-- {
+				idx := r.Intn(len(liveIds))
+				id := liveIds[idx]
+				if ord, ok := live[id]; ok {
+					expected, err := engs[0].Book.RemoveOrder(id, ord.side, ord.price)
+					require.Nil(t, err, step)
+					for _, eng := range engs[1:] {
+						removed, err := eng.Book.RemoveOrder(id, ord.side, ord.price)
+						require.Nil(t, err, step)
+						require.Equal(t, expected, removed, step)
+					}
+					delete(live, id)
+				}
+				liveIds = append(liveIds[:idx], liveIds[idx+1:]...)
+			}
+			if height%10 == 0 {
+				expired := make([][]string, len(engs))
+				for i, eng := range engs {
+					for _, side := range []int8{BUYSIDE, SELLSIDE} {
+						_ = eng.Book.RemoveOrdersBasedOnPriceLevel(height-5, height-20, 3, side, func(ord OrderPart) {
+							expired[i] = append(expired[i], ord.Id)
+						})
+					}
+				}
+				for i := 1; i < len(engs); i++ {
+					require.Equal(t, expired[0], expired[i], step)
+				}
+				for _, id := range expired[0] {
+					delete(live, id)
+				}
+			}
+			requireSameBooks(t, engs, step)
+
+			success := engs[0].Match(height)
+			trades := append([]Trade{}, engs[0].Trades...)
+			totalTrades += len(trades)
+			dropped := engs[0].DropFilledOrder()
+			for i, eng := range engs[1:] {
+				require.Equal(t, success, eng.Match(height), "%s: %s", step, orderBookBackends[i+1])
+				require.Equal(t, trades, eng.Trades, "%s: %s", step, orderBookBackends[i+1])
+				require.Equal(t, engs[0].LastTradePrice, eng.LastTradePrice, step)
+				require.Equal(t, dropped, eng.DropFilledOrder(), step)
+			}
+			for _, id := range dropped {
+				delete(live, id)
+			}
+			requireSameBooks(t, engs, step)
+		}
+	}
+	require.True(t, totalTrades > 0)
+}
+
+func BenchmarkOrderBook_InsertRemove(b *testing.B) {
+	for _, backend := range orderBookBackends {
+		b.Run(backend, func(b *testing.B) {
+			book, _ := NewOrderBook(backend)
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < b.N; i++ {
+				id := fmt.Sprintf("%d", i)
+				price := int64(r.Intn(5000) + 1)
+				side := int8(i%2 + 1)
+				_, _ = book.InsertOrder(id, side, int64(i), price, 10)
+				if i%2 == 1 {
+					_, _ = book.RemoveOrder(id, side, price)
+				}
+			}
+		})
+	}
+}
@@ -62,7 +62,7 @@ func TestPriceLevel_addOrder(t *testing.T) {
 		wantErr bool
 	}{
 		{"AddedOrder", fields{1000, make([]OrderPart, 0, 1)}, args{"12345", 2354, 10005}, 1, false},
-		{"Duplicated", fields{1000, []OrderPart{{"12345", 0, 1555, 0, 0}}}, args{"12345", 2354, 10005}, 0, true},
+		{"Duplicated", fields{1000, []OrderPart{{Id: "12345", Qty: 1555}}}, args{"12345", 2354, 10005}, 0, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -105,14 +105,14 @@ func TestPriceLevel_removeOrder(t *testing.T) {
 		want1   int
 		wantErr bool
 	}{
-		{"NotExist1", fields{1000, []OrderPart{{"12345", 0, 1555, 0, 0}}}, args{"12346"}, OrderPart{}, 0, true},
+		{"NotExist1", fields{1000, []OrderPart{{Id: "12345", Qty: 1555}}}, args{"12346"}, OrderPart{}, 0, true},
 		{"NotExist2", fields{1000, []OrderPart{}}, args{"12346"}, OrderPart{}, 0, true},
-		{"Delete1", fields{1000, []OrderPart{{"12345", 0, 1555, 0, 0}, {"12346", 0, 1556, 0, 0},
-			{"12347", 0, 1557, 0, 0}}}, args{"12345"}, OrderPart{"12345", 0, 1555, 0, 0}, 2, false},
-		{"Delete2", fields{1000, []OrderPart{{"12345", 0, 1555, 0, 0}, {"12346", 0, 1556, 0, 0},
-			{"12347", 0, 1557, 0, 0}}}, args{"12347"}, OrderPart{"12347", 0, 1557, 0, 0}, 2, false},
-		{"Delete3", fields{1000, []OrderPart{{"12345", 0, 1555, 0, 0}, {"12346", 0, 1556, 0, 0},
-			{"12347", 0, 1557, 0, 0}}}, args{"12346"}, OrderPart{"12346", 0, 1556, 0, 0}, 2, false},
+		{"Delete1", fields{1000, []OrderPart{{Id: "12345", Qty: 1555}, {Id: "12346", Qty: 1556},
+			{Id: "12347", Qty: 1557}}}, args{"12345"}, OrderPart{Id: "12345", Qty: 1555}, 2, false},
+		{"Delete2", fields{1000, []OrderPart{{Id: "12345", Qty: 1555}, {Id: "12346", Qty: 1556},
+			{Id: "12347", Qty: 1557}}}, args{"12347"}, OrderPart{Id: "12347", Qty: 1557}, 2, false},
+		{"Delete3", fields{1000, []OrderPart{{Id: "12345", Qty: 1555}, {Id: "12346", Qty: 1556},
+			{Id: "12347", Qty: 1557}}}, args{"12346"}, OrderPart{Id: "12346", Qty: 1556}, 2, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -140,15 +140,31 @@ func TestPriceLevel_removeOrder(t *testing.T) {
 	}
 }
 
+func TestPriceLevel_updateOrderQty(t *testing.T) {
+	assert := assert.New(t)
+	l := &PriceLevel{1000, []OrderPart{{Id: "12345", Qty: 1555}, {Id: "12346", Qty: 1556, CumQty: 500},
+		{Id: "12347", Qty: 1557}}}
+	_, err := l.updateOrderQty("12348", 1000)
+	assert.Error(err)
+	_, err = l.updateOrderQty("12346", 500)
+	assert.Error(err)
+	got, err := l.updateOrderQty("12346", 1000)
+	assert.NoError(err)
+	assert.Equal(OrderPart{Id: "12346", Qty: 1000, CumQty: 500}, got)
+	assert.Equal(3, len(l.Orders))
+	assert.Equal(OrderPart{Id: "12346", Qty: 1000, CumQty: 500}, l.Orders[1])
+	assert.Equal(int64(1555+500+1557), l.TotalLeavesQty())
+}
+
 func TestPriceLevel_removeOrders(t *testing.T) {
 	l := PriceLevel{
 		Price: 1000,
 		Orders: []OrderPart{
-			{"1", 0, 1, 0, 0},
-			{"2", 1, 2, 0, 0},
-			{"3", 1, 4, 0, 0},
-			{"4", 2, 8, 0, 0},
-			{"5", 2, 16, 0, 0},
+			{Id: "1", Qty: 1},
+			{Id: "2", Time: 1, Qty: 2},
+			{Id: "3", Time: 1, Qty: 4},
+			{Id: "4", Time: 2, Qty: 8},
+			{Id: "5", Time: 2, Qty: 16},
 		},
 	}
 
@@ -161,6 +177,25 @@ func TestPriceLevel_removeOrders(t *testing.T) {
 	require.Equal(t, int64(0), l.TotalLeavesQty())
 }
 
+func TestPriceLevel_removeOrders_Replenished(t *testing.T) {
+	l := PriceLevel{
+		Price: 1000,
+		Orders: []OrderPart{
+			{Id: "1", Time: 2, Qty: 1},
+			{Id: "2", Time: 3, Qty: 2, DisplayQty: 1, PlacedTime: 1},
+			{Id: "3", Time: 3, Qty: 4},
+		},
+	}
+
+	// the replenished iceberg order expires by the height it was placed at
+	var removed []string
+	l.removeOrders(2, func(ord OrderPart) {
+		removed = append(removed, ord.Id)
+	})
+	require.Equal(t, []string{"2"}, removed)
+	require.Equal(t, []OrderPart{{Id: "1", Time: 2, Qty: 1}, {Id: "3", Time: 3, Qty: 4}}, l.Orders)
+}
+
 func Test_mergeLevels(t *testing.T) {
 	type args struct {
 		buyLevels  []PriceLevel
@@ -268,25 +303,25 @@ func TestOrderBookOnULList_InsertOrder(t *testing.T) {
 		wantErr bool
 	}{
 		{"Sanity", fields{NewULList(4096, 16, compareBuy), NewULList(4096, 16, compareSell)},
-			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"SamePrice", fields{samePrice().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{"123455", 10000, 10000, 0, 0},
-				{"123457", 10001, 10000, 0, 0}, {"123458", 10002, 10000, 0, 0}, {"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{Id: "123455", Time: 10000, Qty: 10000},
+				{Id: "123457", Time: 10001, Qty: 10000}, {Id: "123458", Time: 10002, Qty: 10000}, {Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPrice1", fields{newPrice().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1010, 10000}, &PriceLevel{1010, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1010, 10000}, &PriceLevel{1010, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPrice2", fields{newPrice().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 990, 10000}, &PriceLevel{990, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 990, 10000}, &PriceLevel{990, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPriceSplit1", fields{newPrice2().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1010, 10000}, &PriceLevel{1010, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1010, 10000}, &PriceLevel{1010, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPriceSplit2", fields{newPrice2().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 990, 10000}, &PriceLevel{990, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 990, 10000}, &PriceLevel{990, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPriceSplit3", fields{newPrice2().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{"123455", 10000, 10000, 0, 0},
-				{"123458", 10002, 10000, 0, 0}, {"123460", 10002, 10000, 0, 0}, {"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{Id: "123455", Time: 10000, Qty: 10000},
+				{Id: "123458", Time: 10002, Qty: 10000}, {Id: "123460", Time: 10002, Qty: 10000}, {Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPriceSplit4", fields{newPrice2().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1004, 10000}, &PriceLevel{1004, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1004, 10000}, &PriceLevel{1004, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPriceSplit5", fields{newPrice3().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1006, 10000}, &PriceLevel{1006, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1006, 10000}, &PriceLevel{1006, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -402,25 +437,25 @@ func TestOrderBookOnBTree_InsertOrder(t *testing.T) {
 		wantErr bool
 	}{
 		{"Sanity", fields{bt.New(8), bt.New(8)},
-			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"SamePrice", fields{samePrice().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{"123455", 10000, 10000, 0, 0},
-				{"123457", 10001, 10000, 0, 0}, {"123458", 10002, 10000, 0, 0}, {"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{Id: "123455", Time: 10000, Qty: 10000},
+				{Id: "123457", Time: 10001, Qty: 10000}, {Id: "123458", Time: 10002, Qty: 10000}, {Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPrice1", fields{newPrice().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1010, 10000}, &PriceLevel{1010, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1010, 10000}, &PriceLevel{1010, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPrice2", fields{newPrice().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 990, 10000}, &PriceLevel{990, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 990, 10000}, &PriceLevel{990, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPriceSplit1", fields{newPrice2().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1010, 10000}, &PriceLevel{1010, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1010, 10000}, &PriceLevel{1010, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPriceSplit2", fields{newPrice2().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 990, 10000}, &PriceLevel{990, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 990, 10000}, &PriceLevel{990, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPriceSplit3", fields{newPrice2().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{"123455", 10000, 10000, 0, 0},
-				{"123458", 10002, 10000, 0, 0}, {"123460", 10002, 10000, 0, 0}, {"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1000, 10000}, &PriceLevel{1000, []OrderPart{{Id: "123455", Time: 10000, Qty: 10000},
+				{Id: "123458", Time: 10002, Qty: 10000}, {Id: "123460", Time: 10002, Qty: 10000}, {Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPriceSplit4", fields{newPrice2().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1004, 10000}, &PriceLevel{1004, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1004, 10000}, &PriceLevel{1004, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 		{"NewPriceSplit5", fields{newPrice3().buyQueue, nil},
-			args{"123456", BUYSIDE, 10000, 1006, 10000}, &PriceLevel{1006, []OrderPart{{"123456", 10000, 10000, 0, 0}}}, false},
+			args{"123456", BUYSIDE, 10000, 1006, 10000}, &PriceLevel{1006, []OrderPart{{Id: "123456", Time: 10000, Qty: 10000}}}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -449,13 +484,13 @@ func TestOrderBookOnULList_RemoveOrder(t *testing.T) {
 	samePrice.InsertOrder("123457", BUYSIDE, 10001, 1000, 10000)
 	samePrice.InsertOrder("123458", BUYSIDE, 10002, 1000, 10000)
 	ord, err := samePrice.RemoveOrder("123457", BUYSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123457", 10001, 10000, 0, 0}, "Failed to remove middle order from multiple orders at the same price")
+	assert.Equal(ord, OrderPart{Id: "123457", Time: 10001, Qty: 10000}, "Failed to remove middle order from multiple orders at the same price")
 	assert.Nil(err)
 	ord, err = samePrice.RemoveOrder("123456", BUYSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123456", 10000, 10000, 0, 0}, "Failed to remove head order from multiple orders at the same price")
+	assert.Equal(ord, OrderPart{Id: "123456", Time: 10000, Qty: 10000}, "Failed to remove head order from multiple orders at the same price")
 	assert.Nil(err)
 	ord, err = samePrice.RemoveOrder("123458", BUYSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123458", 10002, 10000, 0, 0}, "Failed to remove last order at the same price")
+	assert.Equal(ord, OrderPart{Id: "123458", Time: 10002, Qty: 10000}, "Failed to remove last order at the same price")
 	assert.Nil(err)
 
 	l := NewOrderBookOnULList(7, 2)
@@ -466,27 +501,27 @@ func TestOrderBookOnULList_RemoveOrder(t *testing.T) {
 	l.InsertOrder("123458", SELLSIDE, 10002, 1000, 10000)
 	l.InsertOrder("123460", SELLSIDE, 10002, 1000, 10000)
 	ord, err = l.RemoveOrder("123457", SELLSIDE, 1007)
-	assert.Equal(ord, OrderPart{"123457", 10001, 10000, 0, 0}, "Failed to remove last order level")
+	assert.Equal(ord, OrderPart{Id: "123457", Time: 10001, Qty: 10000}, "Failed to remove last order level")
 	assert.Equal("Bucket 0{995->[123459 10002 10000,]},Bucket 1{1000->[123455 10000 10000,123458 10002 10000,123460 10002 10000,]1005->[123459 10002 10000,]},",
 		l.sellQueue.String(), "Level at 1007 should be removed.")
 	ord, err = l.RemoveOrder("123459", SELLSIDE, 995)
-	assert.Equal(ord, OrderPart{"123459", 10002, 10000, 0, 0}, "Failed to remove 1st order level")
+	assert.Equal(ord, OrderPart{Id: "123459", Time: 10002, Qty: 10000}, "Failed to remove 1st order level")
 	assert.Equal("Bucket 0{1000->[123455 10000 10000,123458 10002 10000,123460 10002 10000,]1005->[123459 10002 10000,]},",
 		l.sellQueue.String(), "Level at 995 should be removed.")
 	ord, err = l.RemoveOrder("123459", SELLSIDE, 1005)
-	assert.Equal(ord, OrderPart{"123459", 10002, 10000, 0, 0}, "Failed to remove last price")
+	assert.Equal(ord, OrderPart{Id: "123459", Time: 10002, Qty: 10000}, "Failed to remove last price")
 	assert.Equal("Bucket 0{1000->[123455 10000 10000,123458 10002 10000,123460 10002 10000,]},",
 		l.sellQueue.String(), "Level at 1005 should be removed.")
 	ord, err = l.RemoveOrder("123455", SELLSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123455", 10000, 10000, 0, 0}, "Failed to remove 1st order at the same price")
+	assert.Equal(ord, OrderPart{Id: "123455", Time: 10000, Qty: 10000}, "Failed to remove 1st order at the same price")
 	assert.Equal("Bucket 0{1000->[123458 10002 10000,123460 10002 10000,]},",
 		l.sellQueue.String(), "Level at 1000 should remain.")
 	ord, err = l.RemoveOrder("123460", SELLSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123460", 10002, 10000, 0, 0}, "Failed to remove last order")
+	assert.Equal(ord, OrderPart{Id: "123460", Time: 10002, Qty: 10000}, "Failed to remove last order")
 	assert.Equal("Bucket 0{1000->[123458 10002 10000,]},",
 		l.sellQueue.String(), "Level at 1000 should remain.")
 	ord, err = l.RemoveOrder("123458", SELLSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123458", 10002, 10000, 0, 0}, "Failed to remove last order")
+	assert.Equal(ord, OrderPart{Id: "123458", Time: 10002, Qty: 10000}, "Failed to remove last order")
 	assert.Equal("Bucket 0{},",
 		l.sellQueue.String(), "Level at 1000 should be removed.")
 }
@@ -498,13 +533,13 @@ func TestOrderBookOnBTree_RemoveOrder(t *testing.T) {
 	samePrice.InsertOrder("123457", BUYSIDE, 10001, 1000, 1000)
 	samePrice.InsertOrder("123458", BUYSIDE, 10002, 1000, 1000)
 	ord, err := samePrice.RemoveOrder("123457", BUYSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123457", 10001, 1000, 0, 0}, "Failed to remove middle order from multiple orders at the same price")
+	assert.Equal(ord, OrderPart{Id: "123457", Time: 10001, Qty: 1000}, "Failed to remove middle order from multiple orders at the same price")
 	assert.Nil(err)
 	ord, err = samePrice.RemoveOrder("123456", BUYSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123456", 10000, 1000, 0, 0}, "Failed to remove head order from multiple orders at the same price")
+	assert.Equal(ord, OrderPart{Id: "123456", Time: 10000, Qty: 1000}, "Failed to remove head order from multiple orders at the same price")
 	assert.Nil(err)
 	ord, err = samePrice.RemoveOrder("123458", BUYSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123458", 10002, 1000, 0, 0}, "Failed to remove last order at the same price")
+	assert.Equal(ord, OrderPart{Id: "123458", Time: 10002, Qty: 1000}, "Failed to remove last order at the same price")
 	assert.Nil(err)
 
 	l := NewOrderBookOnBTree(8)
@@ -515,27 +550,27 @@ func TestOrderBookOnBTree_RemoveOrder(t *testing.T) {
 	l.InsertOrder("123458", SELLSIDE, 10002, 1000, 1000)
 	l.InsertOrder("123460", SELLSIDE, 10002, 1000, 1000)
 	ord, err = l.RemoveOrder("123457", SELLSIDE, 1007)
-	assert.Equal(ord, OrderPart{"123457", 10001, 1000, 0, 0}, "Failed to remove last order level")
-	assert.Equal("995->[[{123459 10002 1000 0 0}]], 1000->[[{123455 10000 1000 0 0} {123458 10002 1000 0 0} {123460 10002 1000 0 0}]], 1005->[[{123459 10002 1000 0 0}]], ",
+	assert.Equal(ord, OrderPart{Id: "123457", Time: 10001, Qty: 1000}, "Failed to remove last order level")
+	assert.Equal("995->[[{123459 10002 1000 0 0  0 0 0 0}]], 1000->[[{123455 10000 1000 0 0  0 0 0 0} {123458 10002 1000 0 0  0 0 0 0} {123460 10002 1000 0 0  0 0 0 0}]], 1005->[[{123459 10002 1000 0 0  0 0 0 0}]], ",
 		printOrderQueueString(l.sellQueue, SELLSIDE), "Level at 1007 should be removed.")
 	ord, err = l.RemoveOrder("123459", SELLSIDE, 995)
-	assert.Equal(ord, OrderPart{"123459", 10002, 1000, 0, 0}, "Failed to remove 1st order level")
-	assert.Equal("1000->[[{123455 10000 1000 0 0} {123458 10002 1000 0 0} {123460 10002 1000 0 0}]], 1005->[[{123459 10002 1000 0 0}]], ",
+	assert.Equal(ord, OrderPart{Id: "123459", Time: 10002, Qty: 1000}, "Failed to remove 1st order level")
+	assert.Equal("1000->[[{123455 10000 1000 0 0  0 0 0 0} {123458 10002 1000 0 0  0 0 0 0} {123460 10002 1000 0 0  0 0 0 0}]], 1005->[[{123459 10002 1000 0 0  0 0 0 0}]], ",
 		printOrderQueueString(l.sellQueue, SELLSIDE), "Level at 995 should be removed.")
 	ord, err = l.RemoveOrder("123459", SELLSIDE, 1005)
-	assert.Equal(ord, OrderPart{"123459", 10002, 1000, 0, 0}, "Failed to remove last price")
-	assert.Equal("1000->[[{123455 10000 1000 0 0} {123458 10002 1000 0 0} {123460 10002 1000 0 0}]], ",
+	assert.Equal(ord, OrderPart{Id: "123459", Time: 10002, Qty: 1000}, "Failed to remove last price")
+	assert.Equal("1000->[[{123455 10000 1000 0 0  0 0 0 0} {123458 10002 1000 0 0  0 0 0 0} {123460 10002 1000 0 0  0 0 0 0}]], ",
 		printOrderQueueString(l.sellQueue, SELLSIDE), "Level at 1005 should be removed.")
 	ord, err = l.RemoveOrder("123455", SELLSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123455", 10000, 1000, 0, 0}, "Failed to remove 1st order at the same price")
-	assert.Equal("1000->[[{123458 10002 1000 0 0} {123460 10002 1000 0 0}]], ",
+	assert.Equal(ord, OrderPart{Id: "123455", Time: 10000, Qty: 1000}, "Failed to remove 1st order at the same price")
+	assert.Equal("1000->[[{123458 10002 1000 0 0  0 0 0 0} {123460 10002 1000 0 0  0 0 0 0}]], ",
 		printOrderQueueString(l.sellQueue, SELLSIDE), "Level at 1000 should remain.")
 	ord, err = l.RemoveOrder("123460", SELLSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123460", 10002, 1000, 0, 0}, "Failed to remove last order")
-	assert.Equal("1000->[[{123458 10002 1000 0 0}]], ",
+	assert.Equal(ord, OrderPart{Id: "123460", Time: 10002, Qty: 1000}, "Failed to remove last order")
+	assert.Equal("1000->[[{123458 10002 1000 0 0  0 0 0 0}]], ",
 		printOrderQueueString(l.sellQueue, SELLSIDE), "Level at 1000 remain.")
 	ord, err = l.RemoveOrder("123458", SELLSIDE, 1000)
-	assert.Equal(ord, OrderPart{"123458", 10002, 1000, 0, 0}, "Failed to remove last order")
+	assert.Equal(ord, OrderPart{Id: "123458", Time: 10002, Qty: 1000}, "Failed to remove last order")
 	assert.Equal("",
 		printOrderQueueString(l.sellQueue, SELLSIDE), "Level at 1000 be removed.")
 }
@@ -0,0 +1,186 @@
+package matcheng
+
+import (
+	"bytes"
+	"fmt"
+)
+
+/* SkipList is implemented here as an alternative of ULList for one side of the order book.
+ULList is optimized for deep books whose activity concentrates on the top levels, while SkipList
+keeps O(log n) insert, delete and lookup at any depth of the book, which suits the sparse books
+with prices spreading far from the top (e.g. mini-token books).
+The level of a new node is drawn from a xorshift generator with a fixed seed, so that the structure
+only depends on the sequence of operations.
+*/
+
+const (
+	skipListMaxLevel = 16
+	skipListSeed     = 0x9E3779B97F4A7C15
+)
+
+type skipListNode struct {
+	level PriceLevel
+	next  []*skipListNode
+}
+
+type SkipList struct {
+	head    *skipListNode
+	height  int // the number of levels in use
+	length  int
+	compare Comparator
+	seed    uint64
+}
+
+func NewSkipList(comp Comparator) *SkipList {
+	return &SkipList{
+		head:    &skipListNode{next: make([]*skipListNode, skipListMaxLevel)},
+		height:  1,
+		compare: comp,
+		seed:    skipListSeed,
+	}
+}
+
+func (sl *SkipList) String() string {
+	var buffer bytes.Buffer
+	for n := sl.head.next[0]; n != nil; n = n.next[0] {
+		buffer.WriteString(fmt.Sprintf("%d->[", n.level.Price))
+		for _, o := range n.level.Orders {
+			buffer.WriteString(fmt.Sprintf("%s %d %d,", o.Id, o.Time, o.Qty))
+		}
+		buffer.WriteString("],")
+	}
+	return buffer.String()
+}
+
+// randomHeight returns the height of a new node, each extra level is taken with a probability of 1/4
+func (sl *SkipList) randomHeight() int {
+	sl.seed ^= sl.seed << 13
+	sl.seed ^= sl.seed >> 7
+	sl.seed ^= sl.seed << 17
+	h, r := 1, sl.seed
+	for h < skipListMaxLevel && r&3 == 0 {
+		h++
+		r >>= 2
+	}
+	return h
+}
+
+// findPrev fills `prev` with the last node on each level which is before the price,
+// i.e. larger for buy (smaller for sell) than p
+func (sl *SkipList) findPrev(p int64, prev []*skipListNode) *skipListNode {
+	x := sl.head
+	for i := sl.height - 1; i >= 0; i-- {
+		for x.next[i] != nil && sl.compare(x.next[i].level.Price, p) > 0 {
+			x = x.next[i]
+		}
+		if prev != nil {
+			prev[i] = x
+		}
+	}
+	return x
+}
+
+func (sl *SkipList) Len() int {
+	return sl.length
+}
+
+// AddPriceLevel would only add price that doesn't exist in the list yet, otherwise return false.
+func (sl *SkipList) AddPriceLevel(p *PriceLevel) bool {
+	prev := make([]*skipListNode, skipListMaxLevel)
+	x := sl.findPrev(p.Price, prev).next[0]
+	if x != nil && sl.compare(x.level.Price, p.Price) == 0 {
+		return false
+	}
+	h := sl.randomHeight()
+	for i := sl.height; i < h; i++ {
+		prev[i] = sl.head
+	}
+	if h > sl.height {
+		sl.height = h
+	}
+	n := &skipListNode{level: *p, next: make([]*skipListNode, h)}
+	for i := 0; i < h; i++ {
+		n.next[i] = prev[i].next[i]
+		prev[i].next[i] = n
+	}
+	sl.length++
+	return true
+}
+
+func (sl *SkipList) DeletePriceLevel(price int64) bool {
+	prev := make([]*skipListNode, skipListMaxLevel)
+	x := sl.findPrev(price, prev).next[0]
+	if x == nil || sl.compare(x.level.Price, price) != 0 {
+		return false
+	}
+	for i := 0; i < len(x.next); i++ {
+		prev[i].next[i] = x.next[i]
+	}
+	for sl.height > 1 && sl.head.next[sl.height-1] == nil {
+		sl.height--
+	}
+	sl.length--
+	return true
+}
+
+func (sl *SkipList) GetTop() *PriceLevel {
+	if n := sl.head.next[0]; n != nil {
+		return &n.level
+	}
+	return nil
+}
+
+func (sl *SkipList) Iterate(levelNum int, iter LevelIter) {
+	var curLevel int
+	for n := sl.head.next[0]; n != nil && curLevel < levelNum; n = n.next[0] {
+		iter(&n.level, curLevel)
+		curLevel++
+	}
+}
+
+// GetPriceRange returns all the price levels between p1 and p2, where p1 is closer to the top
+func (sl *SkipList) GetPriceRange(p1 int64, p2 int64, buffer *[]PriceLevel) []PriceLevel {
+	*buffer = (*buffer)[:0]
+	if sl.compare(p1, p2) < 0 {
+		return *buffer
+	}
+	for n := sl.findPrev(p1, nil).next[0]; n != nil && sl.compare(n.level.Price, p2) >= 0; n = n.next[0] {
+		*buffer = append(*buffer, n.level)
+	}
+	return *buffer
+}
+
+// GetPriceLevel returns the PriceLevel point that has the same price as p.
+// It will return nil if no such price.
+func (sl *SkipList) GetPriceLevel(p int64) *PriceLevel {
+	x := sl.findPrev(p, nil).next[0]
+	if x != nil && sl.compare(x.level.Price, p) == 0 {
+		return &x.level
+	}
+	return nil
+}
+
+// UpdateForEach calls `updater` from the top, the price levels left without orders are removed
+func (sl *SkipList) UpdateForEach(updater LevelIter) {
+	emptyLevels := make([]int64, 0)
+	levelIndex := 0
+	for n := sl.head.next[0]; n != nil; n = n.next[0] {
+		updater(&n.level, levelIndex)
+		levelIndex++
+		if len(n.level.Orders) == 0 {
+			emptyLevels = append(emptyLevels, n.level.Price)
+		}
+	}
+	for _, p := range emptyLevels {
+		sl.DeletePriceLevel(p)
+	}
+}
+
+func (sl *SkipList) Clear() {
+	for i := range sl.head.next {
+		sl.head.next[i] = nil
+	}
+	sl.height = 1
+	sl.length = 0
+	sl.seed = skipListSeed
+}
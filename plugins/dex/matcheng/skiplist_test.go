@@ -0,0 +1,91 @@
+package matcheng
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipList_AddPriceLevel(t *testing.T) {
+	assert := assert.New(t)
+	buys := NewSkipList(compareBuy)
+	assert.Nil(buys.GetTop())
+	for _, p := range []int64{100, 98, 103, 99, 101} {
+		assert.True(buys.AddPriceLevel(&PriceLevel{Price: p}))
+	}
+	assert.False(buys.AddPriceLevel(&PriceLevel{Price: 99}))
+	assert.Equal(5, buys.Len())
+	assert.Equal(int64(103), buys.GetTop().Price)
+	prices := make([]int64, 0)
+	buys.Iterate(10, func(pl *PriceLevel, levelIndex int) {
+		assert.Equal(len(prices), levelIndex)
+		prices = append(prices, pl.Price)
+	})
+	assert.Equal([]int64{103, 101, 100, 99, 98}, prices)
+
+	sells := NewSkipList(compareSell)
+	for _, p := range []int64{100, 98, 103, 99, 101} {
+		assert.True(sells.AddPriceLevel(&PriceLevel{Price: p}))
+	}
+	prices = prices[:0]
+	sells.Iterate(3, func(pl *PriceLevel, levelIndex int) {
+		prices = append(prices, pl.Price)
+	})
+	assert.Equal([]int64{98, 99, 100}, prices)
+}
+
+func TestSkipList_DeletePriceLevel(t *testing.T) {
+	assert := assert.New(t)
+	l := NewSkipList(compareBuy)
+	for p := int64(1); p <= 1000; p++ {
+		assert.True(l.AddPriceLevel(&PriceLevel{Price: p}))
+	}
+	for p := int64(2); p <= 1000; p += 2 {
+		assert.True(l.DeletePriceLevel(p))
+	}
+	assert.False(l.DeletePriceLevel(2))
+	assert.False(l.DeletePriceLevel(1001))
+	assert.Equal(500, l.Len())
+	assert.Equal(int64(999), l.GetTop().Price)
+	assert.Nil(l.GetPriceLevel(500))
+	assert.Equal(int64(501), l.GetPriceLevel(501).Price)
+	l.Clear()
+	assert.Equal(0, l.Len())
+	assert.Nil(l.GetTop())
+	assert.True(l.AddPriceLevel(&PriceLevel{Price: 5}))
+	assert.Equal(int64(5), l.GetTop().Price)
+}
+
+func TestSkipList_GetPriceRange(t *testing.T) {
+	assert := assert.New(t)
+	buf := make([]PriceLevel, 16)
+	l := NewSkipList(compareSell)
+	for _, p := range []int64{100, 98, 103, 99, 101} {
+		l.AddPriceLevel(&PriceLevel{Price: p})
+	}
+	levels := l.GetPriceRange(99, 101, &buf)
+	assert.Equal(3, len(levels))
+	assert.Equal(int64(99), levels[0].Price)
+	assert.Equal(int64(101), levels[2].Price)
+	assert.Equal(0, len(l.GetPriceRange(101, 99, &buf)))
+	assert.Equal(0, len(l.GetPriceRange(104, 110, &buf)))
+}
+
+func TestSkipList_UpdateForEach(t *testing.T) {
+	assert := assert.New(t)
+	l := NewSkipList(compareBuy)
+	for _, p := range []int64{100, 98, 103} {
+		l.AddPriceLevel(&PriceLevel{p, []OrderPart{{Id: "1", Time: 1, Qty: 10}, {Id: "2", Time: 2, Qty: 10}}})
+	}
+	l.UpdateForEach(func(pl *PriceLevel, levelIndex int) {
+		if levelIndex == 1 {
+			pl.removeOrders(3, nil)
+		} else {
+			pl.removeOrders(2, nil)
+		}
+	})
+	assert.Equal(2, l.Len())
+	assert.Nil(l.GetPriceLevel(100))
+	assert.Equal(1, len(l.GetPriceLevel(103).Orders))
+	assert.Equal(1, len(l.GetPriceLevel(98).Orders))
+}
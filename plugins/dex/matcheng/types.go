@@ -2,7 +2,6 @@ package matcheng
 
 import (
 	"fmt"
-	"sort"
 
 	bt "github.com/google/btree"
 
@@ -45,12 +44,32 @@ type Trade struct {
 	BuyerFee   *sdk.Fee // buyer's fee
 }
 
+// Self-trade prevention modes, chosen by the newer of two orders from the same owner which would trade
+// with each other. The canceled orders are reported by MatchEng.SelfTradeCanceled instead of trading.
+const (
+	StpNone int8 = iota
+	StpCancelNewest
+	StpCancelOldest
+	StpCancelBoth
+)
+
 type OrderPart struct {
 	Id       string
 	Time     int64
 	Qty      int64
 	CumQty   int64
 	nxtTrade int64
+	// Owner and Stp are only used by the self-trade prevention. They are not saved in the order book snapshot,
+	// and are restored from the orders when the snapshot is loaded.
+	Owner string `json:"-"`
+	Stp   int8   `json:"-"`
+	// DisplayQty is the size of each display slice of an iceberg order, 0 for an order showing all its quantity.
+	// SliceStart is the CumQty when the current slice was shown, the rest of the order is the hidden reserve.
+	DisplayQty int64
+	SliceStart int64
+	// PlacedTime keeps the height the order was placed at once a replenished iceberg order has moved its Time
+	// for the priority, so that it still expires by the original height. 0 means the order was placed at Time.
+	PlacedTime int64
 }
 
 func (o *OrderPart) LeavesQty() int64 {
@@ -61,8 +80,38 @@ func (o *OrderPart) LeavesQty() int64 {
 	}
 }
 
+// VisibleQty returns the quantity shown in the order book, i.e. the unfilled part of the current display slice
+// for an iceberg order, and the LeavesQty for the others. Only the visible quantity can be matched.
+func (o *OrderPart) VisibleQty() int64 {
+	if o.DisplayQty <= 0 {
+		return o.LeavesQty()
+	}
+	sliceEnd := o.SliceStart + o.DisplayQty
+	if sliceEnd > o.Qty {
+		sliceEnd = o.Qty
+	}
+	if o.CumQty >= sliceEnd {
+		return 0
+	}
+	return sliceEnd - o.CumQty
+}
+
+// placedTime returns the height the order was placed at, which decides the expiry of the order
+func (o *OrderPart) placedTime() int64 {
+	if o.PlacedTime == 0 {
+		return o.Time
+	}
+	return o.PlacedTime
+}
+
+// needReplenish returns true if the display slice of an iceberg order is filled while there is hidden reserve left
+func (o *OrderPart) needReplenish() bool {
+	return o.DisplayQty > 0 && o.VisibleQty() == 0 && o.LeavesQty() > 0
+}
+
 type PriceLevelInterface interface {
 	addOrder(id string, time int64, qty int64) (int, error)
+	addOrderPart(ord OrderPart) (int, error)
 	removeOrder(id string) (OrderPart, int, error)
 	removeOrders(beforeTime int64, callback func(OrderPart))
 	getOrder(id string) (OrderPart, error)
@@ -97,13 +146,17 @@ func (l *PriceLevel) String() string {
 
 //addOrder would implicitly called with sequence of 'time' parameter
 func (l *PriceLevel) addOrder(id string, time int64, qty int64) (int, error) {
+	return l.addOrderPart(OrderPart{Id: id, Time: time, Qty: qty})
+}
+
+func (l *PriceLevel) addOrderPart(ord OrderPart) (int, error) {
 	// TODO: need benchmark - queue is not expected to be very long (less than hundreds)
 	for _, o := range l.Orders {
-		if o.Id == id {
-			return 0, fmt.Errorf("Order %s has existed in the price level.", id)
+		if o.Id == ord.Id {
+			return 0, fmt.Errorf("Order %s has existed in the price level.", ord.Id)
 		}
 	}
-	l.Orders = append(l.Orders, OrderPart{id, time, qty, 0, 0})
+	l.Orders = append(l.Orders, ord)
 	return len(l.Orders), nil
 }
 
@@ -125,18 +178,33 @@ func (l *PriceLevel) removeOrder(id string) (OrderPart, int, error) {
 	return OrderPart{}, 0, fmt.Errorf("order %s doesn't exist.", id)
 }
 
-// since the orders in one PriceLevel are sorted by time(height), the orders to be removed are all in the front of the slice.
+// the orders in one PriceLevel are sorted by time(height), but a replenished iceberg order is moved to the end of
+// the level and keeps its placed time, so the orders to be removed are not always in the front of the slice.
 func (l *PriceLevel) removeOrders(beforeTime int64, callback func(OrderPart)) {
-	i := sort.Search(len(l.Orders), func(i int) bool {
-		return l.Orders[i].Time >= beforeTime
-	})
-
-	if callback != nil {
-		for _, ord := range l.Orders[:i] {
+	kept := l.Orders[:0]
+	for _, ord := range l.Orders {
+		if ord.placedTime() >= beforeTime {
+			kept = append(kept, ord)
+		} else if callback != nil {
 			callback(ord)
 		}
 	}
-	l.Orders = l.Orders[i:]
+	l.Orders = kept
+}
+
+// updateOrderQty changes the qty of the order in place, so that the order keeps its position in the queue
+func (l *PriceLevel) updateOrderQty(id string, qty int64) (OrderPart, error) {
+	for i, o := range l.Orders {
+		if o.Id == id {
+			if qty <= o.CumQty {
+				return o, fmt.Errorf("order %s has been filled by %d, cannot be amended to %d", id, o.CumQty, qty)
+			}
+			l.Orders[i].Qty = qty
+			return l.Orders[i], nil
+		}
+	}
+	// not found
+	return OrderPart{}, fmt.Errorf("order %s doesn't exist.", id)
 }
 
 func (l *PriceLevel) getOrder(id string) (OrderPart, error) {
@@ -157,6 +225,15 @@ func (l *PriceLevel) TotalLeavesQty() int64 {
 	return total
 }
 
+// TotalVisibleQty is TotalLeavesQty without the hidden reserve of the iceberg orders
+func (l *PriceLevel) TotalVisibleQty() int64 {
+	var total int64 = 0
+	for _, o := range l.Orders {
+		total += o.VisibleQty()
+	}
+	return total
+}
+
 type OverLappedLevel struct {
 	Price                 int64
 	BuyOrders             []OrderPart
@@ -0,0 +1,51 @@
+package order
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+)
+
+// RegisterBatchFeeCalculators makes the batch msgs charged the fee of a NewOrderMsg/CancelOrderMsg per order.
+// The calculators of the batch msgs are generated along with the ones of the single order msgs whenever the fee
+// params are loaded or changed, so they need no fee params of their own.
+// It must be called before the fee params are loaded.
+func RegisterBatchFeeCalculators() {
+	fees.CalculatorsGen[RouteNewOrder] = batchFeeCalculatorGen(RouteBatchNewOrder)
+	fees.CalculatorsGen[RouteCancelOrder] = batchFeeCalculatorGen(RouteBatchCancelOrder)
+}
+
+func batchFeeCalculatorGen(batchMsgType string) fees.FeeCalculatorGenerator {
+	return func(params param.FeeParam) fees.FeeCalculator {
+		calculator := fees.FixedFeeCalculatorGen(params)
+		fees.RegisterCalculator(batchMsgType, batchFeeCalculator(calculator))
+		return calculator
+	}
+}
+
+func batchFeeCalculator(calculator fees.FeeCalculator) fees.FeeCalculator {
+	return func(msg sdk.Msg) sdk.Fee {
+		var msgs []sdk.Msg
+		switch msg := msg.(type) {
+		case BatchNewOrderMsg:
+			for _, orderMsg := range msg.NewOrderMsgs() {
+				msgs = append(msgs, orderMsg)
+			}
+		case BatchCancelOrderMsg:
+			for _, cancelMsg := range msg.CancelOrderMsgs() {
+				msgs = append(msgs, cancelMsg)
+			}
+		default:
+			panic("unexpected msg for BatchFeeCalculator")
+		}
+
+		var totalFee sdk.Fee
+		for _, m := range msgs {
+			totalFee.AddFee(calculator(m))
+		}
+		if totalFee.IsEmpty() {
+			return fees.FreeFeeCalculator()(msg)
+		}
+		return totalFee
+	}
+}
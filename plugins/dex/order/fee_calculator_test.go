@@ -0,0 +1,30 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+)
+
+func TestBatchFeeCalculator(t *testing.T) {
+	RegisterBatchFeeCalculators()
+	defer fees.UnsetAllCalculators()
+	addr := sdk.AccAddress("testaddr")
+
+	generator := fees.GetCalculatorGenerator(RouteCancelOrder)
+	generator(&param.FixedFeeParams{MsgType: RouteCancelOrder, Fee: 1e4, FeeFor: sdk.FeeForProposer})
+	cancels := []BatchCancel{{"XYZ_BNB", "addr-1"}, {"XYZ_BNB", "addr-2"}, {"XYZ_BNB", "addr-3"}}
+	fee := fees.GetCalculator(RouteBatchCancelOrder)(NewBatchCancelOrderMsg(addr, cancels))
+	require.Equal(t, sdk.NewFee(sdk.Coins{sdk.NewCoin(sdk.NativeTokenSymbol, 3e4)}, sdk.FeeForProposer), fee)
+
+	generator = fees.GetCalculatorGenerator(RouteNewOrder)
+	generator(&param.FixedFeeParams{MsgType: RouteNewOrder, Fee: 0, FeeFor: sdk.FeeFree})
+	orders := []BatchOrder{{Id: "addr-1", Symbol: "XYZ_BNB"}, {Id: "addr-2", Symbol: "XYZ_BNB"}}
+	fee = fees.GetCalculator(RouteBatchNewOrder)(NewBatchNewOrderMsg(addr, orders))
+	require.Equal(t, sdk.FeeFree, fee.Type)
+	require.True(t, fee.Tokens.IsZero())
+}
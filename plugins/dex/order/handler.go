@@ -22,6 +22,10 @@ type NewOrderResponse struct {
 	OrderID string `json:"order_id"`
 }
 
+type BatchNewOrderResponse struct {
+	OrderIDs []string `json:"order_ids"`
+}
+
 // NewHandler - returns a handler for dex type messages.
 func NewHandler(dexKeeper *DexKeeper) sdk.Handler {
 	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
@@ -33,6 +37,27 @@ func NewHandler(dexKeeper *DexKeeper) sdk.Handler {
 			return handleNewOrder(ctx, dexKeeper, msg)
 		case CancelOrderMsg:
 			return handleCancelOrder(ctx, dexKeeper, msg)
+		case ReplaceOrderMsg:
+			if !sdk.IsUpgrade(upgrade.ReplaceOrderUpgrade) {
+				return sdk.ErrMsgNotSupported("").Result()
+			}
+			if sdk.IsUpgrade(upgrade.BEP151) {
+				return sdk.ErrMsgNotSupported("ReplaceOrderMsg disabled in BEP-151").Result()
+			}
+			return handleReplaceOrder(ctx, dexKeeper, msg)
+		case BatchNewOrderMsg:
+			if !sdk.IsUpgrade(upgrade.BatchOrderUpgrade) {
+				return sdk.ErrMsgNotSupported("").Result()
+			}
+			if sdk.IsUpgrade(upgrade.BEP151) {
+				return sdk.ErrMsgNotSupported("BatchNewOrderMsg disabled in BEP-151").Result()
+			}
+			return handleBatchNewOrder(ctx, dexKeeper, msg)
+		case BatchCancelOrderMsg:
+			if !sdk.IsUpgrade(upgrade.BatchOrderUpgrade) {
+				return sdk.ErrMsgNotSupported("").Result()
+			}
+			return handleBatchCancelOrder(ctx, dexKeeper, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized dex msg type: %v", reflect.TypeOf(msg).Name())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -105,11 +130,19 @@ func handleNewOrder(
 	ctx sdk.Context, dexKeeper *DexKeeper, msg NewOrderMsg,
 ) sdk.Result {
 
-	if _, ok := dexKeeper.OrderExists(msg.Symbol, msg.Id); ok {
+	_, inBook := dexKeeper.OrderExists(msg.Symbol, msg.Id)
+	_, inConditionalBook := dexKeeper.ConditionalOrderExists(msg.Symbol, msg.Id)
+	if inBook || inConditionalBook {
 		errString := fmt.Sprintf("Duplicated order [%v] on symbol [%v]", msg.Id, msg.Symbol)
 		return sdk.NewError(types.DefaultCodespace, types.CodeDuplicatedOrder, errString).Result()
 	}
 
+	// market orders are converted to IOC orders at the protection price before any validation,
+	// so that the worst-case quote amount is locked for buy orders.
+	if err := dexKeeper.priceMarketOrder(&msg); err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+	}
+
 	acc := dexKeeper.am.GetAccount(ctx, msg.Sender).(common.NamedAccount)
 	if !ctx.IsReCheckTx() {
 		//for recheck:
@@ -123,6 +156,10 @@ func handleNewOrder(
 		if err != nil {
 			return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
 		}
+
+		if sdkError := dexKeeper.checkRiskLimits(msg, 0); sdkError != nil {
+			return sdkError.Result()
+		}
 	}
 
 	// the following is done in the app's checkstate / deliverstate, so it's safe to ignore isCheckTx
@@ -133,53 +170,69 @@ func handleNewOrder(
 
 	// this is done in memory! we must not run this block in checktx or simulate!
 	if ctx.IsDeliverTx() { // only subtract coins & insert into OB during DeliverTx
-		if txHash, ok := ctx.Value(baseapp.TxHashKey).(string); ok {
-			blockHeader := ctx.BlockHeader()
-			height := blockHeader.Height
-			timestamp := blockHeader.Time.UnixNano()
-			var txSource int64
-			upgrade.UpgradeBEP10(func() {
-				txSource = 0
-			}, func() {
-				if txSrc, ok := ctx.Value(baseapp.TxSourceKey).(int64); ok {
-					txSource = txSrc
-				} else {
-					dexKeeper.logger.Error("cannot get txSource from ctx")
-				}
-			})
-			msg := OrderInfo{
-				msg,
-				height, timestamp,
-				height, timestamp,
-				0, txHash, txSource}
-
-			err := dexKeeper.AddOrder(msg, false)
-
-			if err != nil {
-				return sdk.NewError(types.DefaultCodespace, types.CodeFailInsertOrder, err.Error()).Result()
-			}
-		} else {
-			panic("cannot get txHash from ctx")
+		if sdkError := insertOrder(ctx, dexKeeper, msg); sdkError != nil {
+			return sdkError.Result()
 		}
 	}
 
-	response := NewOrderResponse{
-		OrderID: msg.Id,
-	}
-	serialized, err := json.Marshal(&response)
-	if err != nil {
-		return sdk.ErrInternal(err.Error()).Result()
-	}
+	return newOrderResult(msg.Id)
+}
 
-	return sdk.Result{
-		Data: serialized,
+// insertOrder puts the order, whose balance has been locked, into the order book, or into the conditional orders
+// waiting for their trigger. The orders of a continuous pair are matched right after the insertion, and the fee of
+// the match is added to the fee of the tx. It's done in memory and must only be called during DeliverTx.
+func insertOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg NewOrderMsg) sdk.Error {
+	txHash, ok := ctx.Value(baseapp.TxHashKey).(string)
+	if !ok {
+		panic("cannot get txHash from ctx")
+	}
+	blockHeader := ctx.BlockHeader()
+	height := blockHeader.Height
+	timestamp := blockHeader.Time.UnixNano()
+	var txSource int64
+	upgrade.UpgradeBEP10(func() {
+		txSource = 0
+	}, func() {
+		if txSrc, ok := ctx.Value(baseapp.TxSourceKey).(int64); ok {
+			txSource = txSrc
+		} else {
+			dexKeeper.logger.Error("cannot get txSource from ctx")
+		}
+	})
+	info := OrderInfo{
+		msg,
+		height, timestamp,
+		height, timestamp,
+		0, txHash, txSource}
+
+	// conditional orders wait outside of the order book for their trigger, the balance is locked already
+	if IsConditionalOrderType(info.OrderType) {
+		dexKeeper.addConditionalOrder(info, false)
+	} else if err := dexKeeper.AddOrder(info, false); err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeFailInsertOrder, err.Error())
+	} else if dexKeeper.IsContinuous(info.Symbol) {
+		var postAlloTransHandler TransferHandler
+		if dexKeeper.CollectOrderInfoForPublish {
+			postAlloTransHandler = dexKeeper.publishIocExpire
+		}
+		var txFee sdk.Fee
+		if fee := fees.Pool.GetFee(txHash); fee != nil {
+			txFee = *fee
+		}
+		txFee.AddFee(dexKeeper.MatchContinuousOrder(ctx, info.Symbol, info.Id, postAlloTransHandler))
+		fees.Pool.AddFee(txHash, txFee)
 	}
+	return nil
 }
 
 // Handle CancelOffer -
 func handleCancelOrder(
 	ctx sdk.Context, dexKeeper *DexKeeper, msg CancelOrderMsg,
 ) sdk.Result {
+	if _, ok := dexKeeper.ConditionalOrderExists(msg.Symbol, msg.RefId); ok {
+		return handleCancelConditionalOrder(ctx, dexKeeper, msg)
+	}
+
 	origOrd, ok := dexKeeper.OrderExists(msg.Symbol, msg.RefId)
 
 	//only check whether there exists order to cancel
@@ -198,18 +251,361 @@ func handleCancelOrder(
 	if err != nil {
 		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, err.Error()).Result()
 	}
+	fee, sdkError := unlockCanceledOrder(ctx, dexKeeper, ord, origOrd)
+	if sdkError != nil {
+		return sdkError.Result()
+	}
+
+	// this is done in memory! we must not run this block in checktx or simulate!
+	if ctx.IsDeliverTx() {
+		if txHash, ok := ctx.Value(baseapp.TxHashKey).(string); !ok {
+			panic("cannot get txHash from ctx")
+		} else {
+			// add fee to pool, even it's free
+			fees.Pool.AddFee(txHash, fee)
+		}
+		//remove order from cache and order book
+		if err := removeCanceledOrder(dexKeeper, origOrd, fee, false); err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeFailCancelOrder, err.Error()).Result()
+		}
+	}
+
+	return sdk.Result{}
+}
+
+// handleCancelConditionalOrder cancels an untriggered conditional order. The order is not in the order book,
+// so only the locked balance is released and the cancel fee is charged.
+func handleCancelConditionalOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg CancelOrderMsg) sdk.Result {
+	origOrd, _ := dexKeeper.ConditionalOrderExists(msg.Symbol, msg.RefId)
+	if !reflect.DeepEqual(msg.Sender, origOrd.Sender) {
+		errString := fmt.Sprintf("Order [%v] does not belong to transaction sender", msg.RefId)
+		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, errString).Result()
+	}
+
+	fee, sdkError := unlockCanceledOrder(ctx, dexKeeper, conditionalOrderPart(&origOrd), origOrd)
+	if sdkError != nil {
+		return sdkError.Result()
+	}
+
+	// this is done in memory! we must not run this block in checktx or simulate!
+	if ctx.IsDeliverTx() {
+		if txHash, ok := ctx.Value(baseapp.TxHashKey).(string); !ok {
+			panic("cannot get txHash from ctx")
+		} else {
+			fees.Pool.AddFee(txHash, fee)
+		}
+		if err := removeCanceledOrder(dexKeeper, origOrd, fee, true); err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeFailCancelOrder, err.Error()).Result()
+		}
+	}
+
+	return sdk.Result{}
+}
+
+// unlockCanceledOrder releases the locked balance of the canceled order and charges the cancel fee
+// unless the order has been partially filled.
+func unlockCanceledOrder(ctx sdk.Context, dexKeeper *DexKeeper, ord me.OrderPart, origOrd OrderInfo) (sdk.Fee, sdk.Error) {
 	transfer := TransferFromCanceled(ord, origOrd, false)
 	sdkError := dexKeeper.doTransfer(ctx, &transfer)
 	if sdkError != nil {
-		return sdkError.Result()
+		return sdk.Fee{}, sdkError
 	}
 	fee := sdk.Fee{}
 	if !transfer.FeeFree() {
-		acc := dexKeeper.am.GetAccount(ctx, msg.Sender)
+		acc := dexKeeper.am.GetAccount(ctx, origOrd.Sender)
 		fee = dexKeeper.FeeManager.CalcFixedFee(acc.GetCoins(), transfer.eventType, transfer.inAsset, dexKeeper.GetEngines())
 		_ = acc.SetCoins(acc.GetCoins().Minus(fee.Tokens))
 		dexKeeper.am.SetAccount(ctx, acc)
 	}
+	return fee, nil
+}
+
+// locateOrderToCancel finds the order `refId` of the sender, either in the order book or in the untriggered conditional orders
+func locateOrderToCancel(ctx sdk.Context, dexKeeper *DexKeeper, sender sdk.AccAddress, symbol, refId string) (
+	origOrd OrderInfo, ord me.OrderPart, isConditional bool, sdkError sdk.Error) {
+	origOrd, ok := dexKeeper.OrderExists(symbol, refId)
+	if !ok {
+		if origOrd, isConditional = dexKeeper.ConditionalOrderExists(symbol, refId); !isConditional {
+			errString := fmt.Sprintf("Failed to find order [%v]", refId)
+			return origOrd, ord, false, sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, errString)
+		}
+	}
+
+	// only can cancel their own order
+	if !reflect.DeepEqual(sender, origOrd.Sender) {
+		errString := fmt.Sprintf("Order [%v] does not belong to transaction sender", refId)
+		return origOrd, ord, isConditional, sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, errString)
+	}
+
+	if isConditional {
+		return origOrd, conditionalOrderPart(&origOrd), true, nil
+	}
+	ord, err := dexKeeper.GetOrder(origOrd.Id, origOrd.Symbol, origOrd.Side, origOrd.Price)
+	if err != nil {
+		return origOrd, ord, false, sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, err.Error())
+	}
+	return origOrd, ord, false, nil
+}
+
+// removeCanceledOrder drops the canceled order from the order book, or from the untriggered conditional orders.
+func removeCanceledOrder(dexKeeper *DexKeeper, origOrd OrderInfo, fee sdk.Fee, isConditional bool) error {
+	publishCanceled := func() {
+		if dexKeeper.ShouldPublishOrder() {
+			change := OrderChange{origOrd.Id, Canceled, fee.String(), nil}
+			dexKeeper.UpdateOrderChangeSync(change, origOrd.Symbol)
+			dexKeeper.updateRoundOrderFee(string(origOrd.Sender), fee)
+		}
+	}
+	if isConditional {
+		if _, err := dexKeeper.removeConditionalOrder(origOrd.Id, origOrd.Symbol); err != nil {
+			return err
+		}
+		publishCanceled()
+		return nil
+	}
+	return dexKeeper.RemoveOrder(origOrd.Id, origOrd.Symbol, func(ord me.OrderPart) {
+		publishCanceled()
+	})
+}
+
+// handleReplaceOrder cancels the order `RefId` and places a new order in one tx.
+// If the price is unchanged and the qty is reduced, the order is amended in place and keeps its priority
+// in the price level. Otherwise the replaced order is canceled with the cancel fee, and the new order locks
+// the balance released by it.
+func handleReplaceOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg ReplaceOrderMsg) sdk.Result {
+	origOrd, ord, isConditional, sdkError := locateOrderToCancel(ctx, dexKeeper, msg.Sender, msg.Symbol, msg.RefId)
+	if sdkError != nil {
+		return sdkError.Result()
+	}
+	inBook := !isConditional
+
+	// market orders never rest on the book and their price is not chosen by the sender
+	if origOrd.OrderType == OrderType.MARKET {
+		errString := fmt.Sprintf("Market order [%v] can not be replaced", msg.RefId)
+		return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, errString).Result()
+	}
+
+	if inBook && isAmendInPlace(origOrd, msg) {
+		return handleAmendOrder(ctx, dexKeeper, msg, ord, origOrd)
+	}
+
+	newMsg := msg.newOrderMsg(origOrd)
+	_, dupInBook := dexKeeper.OrderExists(newMsg.Symbol, newMsg.Id)
+	_, dupInConditionalBook := dexKeeper.ConditionalOrderExists(newMsg.Symbol, newMsg.Id)
+	if dupInBook || dupInConditionalBook {
+		errString := fmt.Sprintf("Duplicated order [%v] on symbol [%v]", newMsg.Id, newMsg.Symbol)
+		return sdk.NewError(types.DefaultCodespace, types.CodeDuplicatedOrder, errString).Result()
+	}
+
+	if !ctx.IsReCheckTx() {
+		acc := dexKeeper.am.GetAccount(ctx, msg.Sender)
+		if err := validateOrder(ctx, dexKeeper, acc, newMsg); err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+		}
+		// the replaced order leaves the order book
+		if sdkError := dexKeeper.checkRiskLimits(newMsg, -1); sdkError != nil {
+			return sdkError.Result()
+		}
+	}
+
+	// the replaced order is unlocked first, so that the new order can lock the released balance.
+	// Nothing is changed in memory until both the cancel and the new order pass, so that a tx failure
+	// would never leave the order half-replaced.
+	fee, sdkError := unlockCanceledOrder(ctx, dexKeeper, ord, origOrd)
+	if sdkError != nil {
+		return sdkError.Result()
+	}
+
+	acc := dexKeeper.am.GetAccount(ctx, msg.Sender).(common.NamedAccount)
+	if err := validateQtyAndLockBalance(ctx, dexKeeper, acc, newMsg); err != nil {
+		return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+	}
+
+	// this is done in memory! we must not run this block in checktx or simulate!
+	if ctx.IsDeliverTx() {
+		txHash, ok := ctx.Value(baseapp.TxHashKey).(string)
+		if !ok {
+			panic("cannot get txHash from ctx")
+		}
+		// add fee to pool, even it's free
+		fees.Pool.AddFee(txHash, fee)
+		if err := removeCanceledOrder(dexKeeper, origOrd, fee, isConditional); err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeFailCancelOrder, err.Error()).Result()
+		}
+
+		if sdkError := insertOrder(ctx, dexKeeper, newMsg); sdkError != nil {
+			return sdkError.Result()
+		}
+	}
+
+	return newOrderResult(newMsg.Id)
+}
+
+// isAmendInPlace returns true if the replace only reduces the qty of the order at the same price,
+// in which case the order keeps its priority in the price level.
+func isAmendInPlace(origOrd OrderInfo, msg ReplaceOrderMsg) bool {
+	return !IsConditionalOrderType(origOrd.OrderType) &&
+		msg.Price == origOrd.Price &&
+		msg.Quantity < origOrd.Quantity
+}
+
+// handleAmendOrder reduces the qty of the order in place. The reduced part is unlocked free of charge,
+// the same as the leaves qty of a partially filled order being canceled.
+func handleAmendOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg ReplaceOrderMsg, ord me.OrderPart, origOrd OrderInfo) sdk.Result {
+	if !ctx.IsReCheckTx() {
+		if err := validateAmendQty(ctx, dexKeeper, ord, origOrd, msg.Quantity); err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+		}
+	}
+
+	transfer := TransferFromAmended(origOrd, msg.Quantity)
+	if sdkError := dexKeeper.doTransfer(ctx, &transfer); sdkError != nil {
+		return sdkError.Result()
+	}
+
+	// this is done in memory! we must not run this block in checktx or simulate!
+	if ctx.IsDeliverTx() {
+		blockHeader := ctx.BlockHeader()
+		err := dexKeeper.amendOrder(origOrd.Id, origOrd.Symbol, msg.Quantity, blockHeader.Height, blockHeader.Time.UnixNano(), false)
+		if err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeFailCancelOrder, err.Error()).Result()
+		}
+	}
+
+	return newOrderResult(origOrd.Id)
+}
+
+func validateAmendQty(ctx sdk.Context, dexKeeper *DexKeeper, ord me.OrderPart, origOrd OrderInfo, qty int64) error {
+	if qty <= ord.CumQty {
+		return fmt.Errorf("quantity(%v) should be larger than the filled quantity(%v)", qty, ord.CumQty)
+	}
+
+	baseAsset, quoteAsset, err := utils.TradingPair2Assets(origOrd.Symbol)
+	if err != nil {
+		return err
+	}
+	pair, err := dexKeeper.PairMapper.GetTradingPair(ctx, baseAsset, quoteAsset)
+	if err != nil {
+		return err
+	}
+	if qty%pair.LotSize.ToInt64() != 0 {
+		return fmt.Errorf("quantity(%v) is not rounded to lotSize(%v)", qty, pair.LotSize.ToInt64())
+	}
+
+	if sdk.IsUpgrade(upgrade.LotSizeOptimization) {
+		if utils.IsUnderMinNotional(origOrd.Price, qty) {
+			return errors.New("notional value of the order is too small")
+		}
+	}
+	return nil
+}
+
+func newOrderResult(orderId string) sdk.Result {
+	response := NewOrderResponse{
+		OrderID: orderId,
+	}
+	serialized, err := json.Marshal(&response)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+
+	return sdk.Result{
+		Data: serialized,
+	}
+}
+
+// handleBatchNewOrder places all the orders in the batch, or none of them. The balance of each order is locked
+// one after another, so that every order is validated against the amount locked by the orders before it.
+// The whole batch is validated and locked before any order is inserted, as the orders inserted into the
+// in-memory book would not be rolled back by the failure of the tx.
+func handleBatchNewOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg BatchNewOrderMsg) sdk.Result {
+	acc := dexKeeper.am.GetAccount(ctx, msg.Sender).(common.NamedAccount)
+	orderMsgs := msg.NewOrderMsgs()
+	pendingOrders := make(map[string]int) // symbol -> orders of the batch before the current one
+	for i := range orderMsgs {
+		orderMsg := &orderMsgs[i]
+		_, inBook := dexKeeper.OrderExists(orderMsg.Symbol, orderMsg.Id)
+		_, inConditionalBook := dexKeeper.ConditionalOrderExists(orderMsg.Symbol, orderMsg.Id)
+		if inBook || inConditionalBook {
+			errString := fmt.Sprintf("Duplicated order [%v] on symbol [%v]", orderMsg.Id, orderMsg.Symbol)
+			return sdk.NewError(types.DefaultCodespace, types.CodeDuplicatedOrder, errString).Result()
+		}
+
+		if err := dexKeeper.priceMarketOrder(orderMsg); err != nil {
+			return batchOrderError(orderMsg.Id, err).Result()
+		}
+
+		if !ctx.IsReCheckTx() {
+			expectedID := GenerateBatchOrderID(acc.GetSequence(), i, msg.Sender)
+			if err := validateOrderWithID(ctx, dexKeeper, *orderMsg, expectedID); err != nil {
+				return batchOrderError(orderMsg.Id, err).Result()
+			}
+			symbol := strings.ToUpper(orderMsg.Symbol)
+			if sdkError := dexKeeper.checkRiskLimits(*orderMsg, pendingOrders[symbol]); sdkError != nil {
+				return sdkError.Result()
+			}
+			pendingOrders[symbol]++
+		}
+
+		if err := validateQtyAndLockBalance(ctx, dexKeeper, acc, *orderMsg); err != nil {
+			return batchOrderError(orderMsg.Id, err).Result()
+		}
+
+		if err := dexKeeper.checkInsertOrder(*orderMsg); err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeFailInsertOrder, err.Error()).Result()
+		}
+	}
+
+	// this is done in memory! we must not run this block in checktx or simulate!
+	if ctx.IsDeliverTx() {
+		for _, orderMsg := range orderMsgs {
+			if sdkError := insertOrder(ctx, dexKeeper, orderMsg); sdkError != nil {
+				return sdkError.Result()
+			}
+		}
+	}
+
+	response := BatchNewOrderResponse{
+		OrderIDs: make([]string, len(orderMsgs)),
+	}
+	for i, orderMsg := range orderMsgs {
+		response.OrderIDs[i] = orderMsg.Id
+	}
+	serialized, err := json.Marshal(&response)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+
+	return sdk.Result{
+		Data: serialized,
+	}
+}
+
+func batchOrderError(id string, err error) sdk.Error {
+	errString := fmt.Sprintf("Order [%v]: %v", id, err.Error())
+	return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, errString)
+}
+
+// handleBatchCancelOrder cancels all the orders in the batch, or none of them.
+// The cancel fee of each order is charged the same as a CancelOrderMsg.
+func handleBatchCancelOrder(ctx sdk.Context, dexKeeper *DexKeeper, msg BatchCancelOrderMsg) sdk.Result {
+	cancelMsgs := msg.CancelOrderMsgs()
+	origOrds := make([]OrderInfo, len(cancelMsgs))
+	isConditionals := make([]bool, len(cancelMsgs))
+	orderFees := make([]sdk.Fee, len(cancelMsgs))
+	totalFee := sdk.Fee{}
+	for i, cancelMsg := range cancelMsgs {
+		origOrd, ord, isConditional, sdkError := locateOrderToCancel(ctx, dexKeeper, msg.Sender, cancelMsg.Symbol, cancelMsg.RefId)
+		if sdkError != nil {
+			return sdkError.Result()
+		}
+		fee, sdkError := unlockCanceledOrder(ctx, dexKeeper, ord, origOrd)
+		if sdkError != nil {
+			return sdkError.Result()
+		}
+		origOrds[i], isConditionals[i], orderFees[i] = origOrd, isConditional, fee
+		totalFee.AddFee(fee)
+	}
 
 	// this is done in memory! we must not run this block in checktx or simulate!
 	if ctx.IsDeliverTx() {
@@ -217,18 +613,12 @@ func handleCancelOrder(
 			panic("cannot get txHash from ctx")
 		} else {
 			// add fee to pool, even it's free
-			fees.Pool.AddFee(txHash, fee)
+			fees.Pool.AddFee(txHash, totalFee)
 		}
-		//remove order from cache and order book
-		err := dexKeeper.RemoveOrder(origOrd.Id, origOrd.Symbol, func(ord me.OrderPart) {
-			if dexKeeper.ShouldPublishOrder() {
-				change := OrderChange{msg.RefId, Canceled, fee.String(), nil}
-				dexKeeper.UpdateOrderChangeSync(change, msg.Symbol)
-				dexKeeper.updateRoundOrderFee(string(msg.Sender), fee)
+		for i, origOrd := range origOrds {
+			if err := removeCanceledOrder(dexKeeper, origOrd, orderFees[i], isConditionals[i]); err != nil {
+				return sdk.NewError(types.DefaultCodespace, types.CodeFailCancelOrder, err.Error()).Result()
 			}
-		})
-		if err != nil {
-			return sdk.NewError(types.DefaultCodespace, types.CodeFailCancelOrder, err.Error()).Result()
 		}
 	}
 
@@ -236,13 +626,19 @@ func handleCancelOrder(
 }
 
 func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg NewOrderMsg) error {
+	if _, _, err := utils.TradingPair2Assets(msg.Symbol); err != nil {
+		return err
+	}
+	return validateOrderWithID(ctx, dexKeeper, msg, GenerateOrderID(acc.GetSequence(), msg.Sender))
+}
+
+// validateOrderWithID validates the order the same as validateOrder, against the given expected order ID
+func validateOrderWithID(ctx sdk.Context, dexKeeper *DexKeeper, msg NewOrderMsg, expectedID string) error {
 	baseAsset, quoteAsset, err := utils.TradingPair2Assets(msg.Symbol)
 	if err != nil {
 		return err
 	}
 
-	seq := acc.GetSequence()
-	expectedID := GenerateOrderID(seq, msg.Sender)
 	if expectedID != msg.Id {
 		return fmt.Errorf("the order ID(%s) given did not match the expected one: `%s`", msg.Id, expectedID)
 	}
@@ -256,10 +652,19 @@ func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg N
 		return fmt.Errorf("quantity(%v) is not rounded to lotSize(%v)", msg.Quantity, pair.LotSize.ToInt64())
 	}
 
-	if msg.Price <= 0 || msg.Price%pair.TickSize.ToInt64() != 0 {
+	if msg.IsIceberg() && msg.DisplayQuantity%pair.LotSize.ToInt64() != 0 {
+		return fmt.Errorf("display quantity(%v) is not rounded to lotSize(%v)", msg.DisplayQuantity, pair.LotSize.ToInt64())
+	}
+
+	// the price of a market order is the protection price which is not necessarily rounded to tickSize
+	if msg.Price <= 0 || (msg.OrderType != OrderType.MARKET && msg.Price%pair.TickSize.ToInt64() != 0) {
 		return fmt.Errorf("price(%v) is not rounded to tickSize(%v)", msg.Price, pair.TickSize.ToInt64())
 	}
 
+	if IsConditionalOrderType(msg.OrderType) && msg.StopPrice%pair.TickSize.ToInt64() != 0 {
+		return fmt.Errorf("stop price(%v) is not rounded to tickSize(%v)", msg.StopPrice, pair.TickSize.ToInt64())
+	}
+
 	if sdk.IsUpgrade(upgrade.LotSizeOptimization) {
 		if utils.IsUnderMinNotional(msg.Price, msg.Quantity) {
 			return errors.New("notional value of the order is too small")
@@ -270,5 +675,14 @@ func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg N
 		return errors.New("notional value of the order is too large(cannot fit in int64)")
 	}
 
+	// the good-till orders are checked after the match of each block, they should live for at least one match
+	blockHeader := ctx.BlockHeader()
+	if msg.ExpireHeight > 0 && msg.ExpireHeight <= blockHeader.Height {
+		return fmt.Errorf("expire height(%v) should be later than the current height(%v)", msg.ExpireHeight, blockHeader.Height)
+	}
+	if msg.ExpireTime > 0 && msg.ExpireTime <= blockHeader.Time.Unix() {
+		return fmt.Errorf("expire time(%v) should be later than the current block time(%v)", msg.ExpireTime, blockHeader.Time.Unix())
+	}
+
 	return nil
 }
@@ -192,3 +192,66 @@ func TestHandler_ValidateOrder_MaxNotional(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, "notional value of the order is too large(cannot fit in int64)", err.Error())
 }
+
+func TestHandler_ValidateOrder_Market(t *testing.T) {
+	pairMapper, accMapper, ctx, keeper := setupMappers()
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	err := pairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+	keeper.AddEngine(pair)
+
+	acc, _ := setupAccount(ctx, accMapper)
+
+	msg := NewOrderMsg{
+		Symbol:      "AAA-000_BNB",
+		Sender:      acc.GetAddress(),
+		OrderType:   OrderType.MARKET,
+		Side:        Side.BUY,
+		TimeInForce: TimeInForce.IOC,
+		Quantity:    1e5,
+		Id:          fmt.Sprintf("%X-0", acc.GetAddress()),
+	}
+
+	err = keeper.priceMarketOrder(&msg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1.05e8), msg.Price)
+	err = validateOrder(ctx, keeper, acc, msg)
+	require.NoError(t, err)
+
+	msg.Side = Side.SELL
+	msg.Price = 0
+	err = keeper.priceMarketOrder(&msg)
+	require.NoError(t, err)
+	require.Equal(t, int64(0.95e8), msg.Price)
+
+	msg.Symbol = "BBB-000_BNB"
+	err = keeper.priceMarketOrder(&msg)
+	require.Error(t, err)
+}
+
+func TestHandler_ValidateOrder_StopPrice(t *testing.T) {
+	pairMapper, accMapper, ctx, keeper := setupMappers()
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	err := pairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+
+	acc, _ := setupAccount(ctx, accMapper)
+
+	msg := NewOrderMsg{
+		Symbol:    "AAA-000_BNB",
+		Sender:    acc.GetAddress(),
+		OrderType: OrderType.STOPLIMIT,
+		Price:     1e3,
+		StopPrice: 1e3 + 1,
+		Quantity:  1e5,
+		Id:        fmt.Sprintf("%X-0", acc.GetAddress()),
+	}
+
+	err = validateOrder(ctx, keeper, acc, msg)
+	require.Error(t, err)
+	require.Equal(t, fmt.Sprintf("stop price(%v) is not rounded to tickSize(%v)", msg.StopPrice, pair.TickSize.ToInt64()), err.Error())
+
+	msg.StopPrice = 2e3
+	err = validateOrder(ctx, keeper, acc, msg)
+	require.NoError(t, err)
+}
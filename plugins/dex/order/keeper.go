@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -56,10 +57,16 @@ type DexKeeper struct {
 	recentPrices               map[string]*utils.FixedSizeRing // symbol -> latest "numPricesStored" prices per "pricesStoreEvery" blocks
 	am                         auth.AccountKeeper
 	FeeManager                 *FeeManager
-	RoundOrderFees             FeeHolder // order (and trade) related fee of this round, str of addr bytes -> fee
-	CollectOrderInfoForPublish bool      //TODO separate for each order keeper
+	riskConfig                 RiskConfig       // pre-trade risk limits, updated along with the fee config
+	openOrders                 openOrderCounter // number of open orders of each sender on each pair, for the risk limits
+	RoundOrderFees             FeeHolder        // order (and trade) related fee of this round, str of addr bytes -> fee
+	CollectOrderInfoForPublish bool             //TODO separate for each order keeper
 	engines                    map[string]*me.MatchEng
+	conditionalOrders          map[string]map[string]*OrderInfo // symbol -> order ID -> untriggered conditional order
+	goodTillOrders             map[string]map[string]goodTill   // symbol -> order ID -> expiry of good-till orders
 	pairsType                  map[string]SymbolPairType
+	orderBookBackends          map[SymbolPairType]string               // pair type -> order book backend of the match engines
+	replayRecorder             func(symbol, id string, tpe ChangeType) // only set by ReplayMatch
 	logger                     tmlog.Logger
 	poolSize                   uint // number of concurrent channels, counted in the pow of 2
 	cdc                        *wire.Codec
@@ -82,9 +89,14 @@ func NewDexKeeper(key sdk.StoreKey, am auth.AccountKeeper, tradingPairMapper sto
 		am:                         am,
 		RoundOrderFees:             make(map[string]*sdk.Fee, 256),
 		FeeManager:                 NewFeeManager(cdc, logger),
+		riskConfig:                 NewRiskConfig(),
+		openOrders:                 make(openOrderCounter),
 		CollectOrderInfoForPublish: collectOrderInfoForPublish,
 		engines:                    make(map[string]*me.MatchEng),
+		conditionalOrders:          make(map[string]map[string]*OrderInfo),
+		goodTillOrders:             make(map[string]map[string]goodTill),
 		pairsType:                  make(map[string]SymbolPairType),
+		orderBookBackends:          make(map[SymbolPairType]string),
 		poolSize:                   concurrency,
 		cdc:                        cdc,
 		logger:                     logger,
@@ -105,6 +117,15 @@ func (kp *DexKeeper) SetBUSDSymbol(symbol string) {
 	BUSDSymbol = symbol
 }
 
+// SetOrderBookBackend chooses the order book backend of the match engines created for `pairType` from now on
+func (kp *DexKeeper) SetOrderBookBackend(pairType SymbolPairType, backend string) error {
+	if !me.IsValidOrderBookBackend(backend) {
+		return fmt.Errorf("unknown order book backend %s", backend)
+	}
+	kp.orderBookBackends[pairType] = backend
+	return nil
+}
+
 func (kp *DexKeeper) EnablePublish() {
 	kp.CollectOrderInfoForPublish = true
 	for i := range kp.OrderKeepers {
@@ -260,13 +281,19 @@ func (kp *DexKeeper) UpdateLotSize(symbol string, lotSize int64) {
 
 func (kp *DexKeeper) AddEngine(pair dexTypes.TradingPair) *me.MatchEng {
 	symbol := strings.ToUpper(pair.GetSymbol())
-	eng := CreateMatchEng(symbol, pair.ListPrice.ToInt64(), pair.LotSize.ToInt64())
-	kp.engines[symbol] = eng
 	pairType := PairType.BEP2
 	if dexUtils.IsMiniTokenTradingPair(symbol) {
 		pairType = PairType.MINI
 	}
+	book, err := me.NewOrderBook(kp.orderBookBackends[pairType])
+	if err != nil {
+		panic(err)
+	}
+	eng := CreateMatchEng(symbol, pair.ListPrice.ToInt64(), pair.LotSize.ToInt64(), book)
+	eng.Continuous = pair.IsContinuous()
+	kp.engines[symbol] = eng
 	kp.pairsType[symbol] = pairType
+	kp.openOrders.initPair(symbol)
 	for i := range kp.OrderKeepers {
 		if kp.OrderKeepers[i].supportPairType(pairType) {
 			kp.OrderKeepers[i].initOrders(symbol)
@@ -278,21 +305,59 @@ func (kp *DexKeeper) AddEngine(pair dexTypes.TradingPair) *me.MatchEng {
 
 func (kp *DexKeeper) AddOrder(info OrderInfo, isRecovery bool) (err error) {
 	//try update order book first
+	if err = kp.insertOrderToBook(info); err != nil {
+		return err
+	}
+
+	kp.trackOrder(info, isRecovery)
+	return nil
+}
+
+// insertOrderToBook puts the order into the book of the match engine only, see trackOrder for the rest of AddOrder
+func (kp *DexKeeper) insertOrderToBook(info OrderInfo) error {
 	symbol := strings.ToUpper(info.Symbol)
 	eng, ok := kp.engines[symbol]
 	if !ok {
-		err = fmt.Errorf("match engine of symbol %s doesn't exist", symbol)
-		return
+		return fmt.Errorf("match engine of symbol %s doesn't exist", symbol)
 	}
 
-	_, err = eng.Book.InsertOrder(info.Id, info.Side, info.CreatedHeight, info.Price, info.Quantity)
-	if err != nil {
-		return err
+	_, err := eng.Book.InsertOrderPart(info.Side, info.Price, newOrderPart(&info))
+	return err
+}
+
+// checkInsertOrder returns the error which insertOrderToBook would fail with, without changing the book
+func (kp *DexKeeper) checkInsertOrder(msg NewOrderMsg) error {
+	symbol := strings.ToUpper(msg.Symbol)
+	eng, ok := kp.engines[symbol]
+	if !ok {
+		return fmt.Errorf("match engine of symbol %s doesn't exist", symbol)
+	}
+	if IsConditionalOrderType(msg.OrderType) {
+		return nil
+	}
+	if _, err := eng.Book.GetOrder(msg.Id, msg.Side, msg.Price); err == nil {
+		return fmt.Errorf("Order %s has existed in the price level.", msg.Id)
 	}
+	return nil
+}
 
+// trackOrder records the order inserted into the book by the order keeper
+func (kp *DexKeeper) trackOrder(info OrderInfo, isRecovery bool) {
+	symbol := strings.ToUpper(info.Symbol)
 	kp.mustGetOrderKeeper(symbol).addOrder(symbol, info, isRecovery)
+	kp.openOrders.inc(symbol, info.Sender)
+	kp.indexGoodTillOrder(symbol, &info)
 	kp.logger.Debug("Added orders", "symbol", symbol, "id", info.Id)
-	return nil
+}
+
+// newOrderPart builds the order book representation of an order, the sender is the owner for the self-trade prevention
+func newOrderPart(info *OrderInfo) me.OrderPart {
+	ord := me.OrderPart{Id: info.Id, Time: info.CreatedHeight, Qty: info.Quantity, Owner: string(info.Sender),
+		Stp: info.SelfTradePrevention}
+	if info.IsIceberg() {
+		ord.DisplayQty = info.DisplayQuantity
+	}
+	return ord
 }
 
 func orderNotFound(symbol, id string) error {
@@ -314,6 +379,22 @@ func (kp *DexKeeper) RemoveOrder(id string, symbol string, postCancelHandler fun
 	return orderNotFound(symbol, id)
 }
 
+// amendOrder changes the qty of the order in the order book without changing its priority
+func (kp *DexKeeper) amendOrder(id string, symbol string, qty, height, timestamp int64, isRecovery bool) error {
+	symbol = strings.ToUpper(symbol)
+	dexOrderKeeper, err := kp.getOrderKeeper(symbol)
+	if err != nil {
+		return orderNotFound(symbol, id)
+	}
+	if _, err := dexOrderKeeper.amendOrder(kp, id, symbol, qty, height, timestamp); err != nil {
+		return err
+	}
+	if kp.ShouldPublishOrder() && !isRecovery {
+		dexOrderKeeper.appendOrderChangeSync(OrderChange{id, Amended, "", nil})
+	}
+	return nil
+}
+
 func (kp *DexKeeper) GetOrder(id string, symbol string, side int8, price int64) (ord me.OrderPart, err error) {
 	symbol = strings.ToUpper(symbol)
 	_, ok := kp.OrderExists(symbol, id)
@@ -356,6 +437,9 @@ func (kp *DexKeeper) SubscribeParamChange(hub *paramhub.Keeper) {
 				if feeConfig != nil {
 					kp.FeeManager.UpdateConfig(*feeConfig)
 				}
+				if riskConfig := ParamToRiskConfig(change); riskConfig != nil {
+					kp.SetRiskConfig(*riskConfig)
+				}
 			default:
 				kp.logger.Debug("Receive param changes that not interested.")
 			}
@@ -370,6 +454,11 @@ func (kp *DexKeeper) SubscribeParamChange(hub *paramhub.Keeper) {
 				} else {
 					panic("Genesis with no dex fee config ")
 				}
+				if riskConfig := ParamToRiskConfig(state.FeeGenesis); riskConfig != nil {
+					kp.SetRiskConfig(*riskConfig)
+				} else {
+					kp.SetRiskConfig(NewRiskConfig())
+				}
 			default:
 				kp.logger.Debug("Receive param genesis state that not interested.")
 			}
@@ -383,6 +472,11 @@ func (kp *DexKeeper) SubscribeParamChange(hub *paramhub.Keeper) {
 				} else {
 					panic("Load with no dex fee config ")
 				}
+				if riskConfig := ParamToRiskConfig(load); riskConfig != nil {
+					kp.SetRiskConfig(*riskConfig)
+				} else {
+					kp.SetRiskConfig(NewRiskConfig())
+				}
 			default:
 				kp.logger.Debug("Receive param load that not interested.")
 			}
@@ -397,11 +491,11 @@ func (kp *DexKeeper) GetOrderBookLevels(pair string, maxLevels int) (orderbook [
 		// TODO: check considered bucket splitting?
 		eng.Book.ShowDepth(maxLevels, func(p *me.PriceLevel, levelIndex int) {
 			orderbook[i].BuyPrice = utils.Fixed8(p.Price)
-			orderbook[i].BuyQty = utils.Fixed8(p.TotalLeavesQty())
+			orderbook[i].BuyQty = utils.Fixed8(p.TotalVisibleQty())
 			i++
 		}, func(p *me.PriceLevel, levelIndex int) {
 			orderbook[j].SellPrice = utils.Fixed8(p.Price)
-			orderbook[j].SellQty = utils.Fixed8(p.TotalLeavesQty())
+			orderbook[j].SellQty = utils.Fixed8(p.TotalVisibleQty())
 			j++
 		})
 		roundOrders := kp.mustGetOrderKeeper(pair).getRoundOrdersForPair(pair)
@@ -426,9 +520,9 @@ func (kp *DexKeeper) GetOrderBooks(maxLevels int) ChangedPriceLevelsMap {
 
 		// TODO: check considered bucket splitting?
 		eng.Book.ShowDepth(maxLevels, func(p *me.PriceLevel, levelIndex int) {
-			buys[p.Price] = p.TotalLeavesQty()
+			buys[p.Price] = p.TotalVisibleQty()
 		}, func(p *me.PriceLevel, levelIndex int) {
-			sells[p.Price] = p.TotalLeavesQty()
+			sells[p.Price] = p.TotalVisibleQty()
 		})
 	}
 
@@ -443,6 +537,29 @@ func (kp *DexKeeper) GetPriceLevel(pair string, side int8, price int64) *me.Pric
 	}
 }
 
+// GetMarketOrderPrice returns the protection price a market order would be placed at on the order book.
+func (kp *DexKeeper) GetMarketOrderPrice(pair string, side int8) (int64, error) {
+	if eng, ok := kp.engines[strings.ToUpper(pair)]; ok {
+		return eng.MarketProtectionPrice(side), nil
+	}
+	return 0, fmt.Errorf("match engine of symbol %s doesn't exist", pair)
+}
+
+// priceMarketOrder sets the price of a market order to the protection price, so that the
+// order can be locked, matched and settled like an IOC limit order at its worst acceptable price.
+// It must be called in the same sequence during DeliverTx and block replay to stay deterministic.
+func (kp *DexKeeper) priceMarketOrder(msg *NewOrderMsg) error {
+	if msg.OrderType != OrderType.MARKET {
+		return nil
+	}
+	price, err := kp.GetMarketOrderPrice(msg.Symbol, msg.Side)
+	if err != nil {
+		return err
+	}
+	msg.Price = price
+	return nil
+}
+
 func (kp *DexKeeper) GetLastTrades(height int64, pair string) ([]me.Trade, int64) {
 	if eng, ok := kp.engines[pair]; ok {
 		if eng.LastMatchHeight == height {
@@ -744,14 +861,14 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 		transferChs[i] = make(chan Transfer, channelSize*2)
 	}
 
-	expire := func(orders map[string]*OrderInfo, engine *me.MatchEng, side int8) {
+	expire := func(symbol string, orders map[string]*OrderInfo, engine *me.MatchEng, side int8) {
 		removeCallback := func(ord me.OrderPart) {
 			// gen transfer
 			if ordMsg, ok := orders[ord.Id]; ok && ordMsg != nil {
 				h := channelHash(ordMsg.Sender, concurrency)
 				transferChs[h] <- TransferFromExpired(ord, *ordMsg)
 				// delete from allOrders
-				delete(orders, ord.Id)
+				kp.dropOpenOrder(symbol, orders, ord.Id)
 			} else {
 				kp.logger.Error("failed to locate order to remove in order book", "oid", ord.Id)
 			}
@@ -763,6 +880,25 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 		}
 	}
 
+	// untriggered conditional orders have no price level, they expire the same as the orders placed before expireHeight
+	expireConditional := func(symbol string) {
+		orders := kp.conditionalOrders[symbol]
+		ids := make([]string, 0, len(orders))
+		for id := range orders {
+			ids = append(ids, id)
+		}
+		// the transfers of the expired orders are allocated in sequence, so they must not follow the map order
+		sort.Strings(ids)
+		for _, id := range ids {
+			ordMsg := orders[id]
+			if ordMsg.CreatedHeight < expireHeight {
+				h := channelHash(ordMsg.Sender, concurrency)
+				transferChs[h] <- TransferFromExpired(conditionalOrderPart(ordMsg), *ordMsg)
+				kp.dropOpenOrder(symbol, orders, id)
+			}
+		}
+	}
+
 	symbolCh := make(chan string, concurrency)
 	utils.ConcurrentExecuteAsync(concurrency,
 		func() {
@@ -774,8 +910,9 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 			for symbol := range symbolCh {
 				engine := kp.engines[symbol]
 				orders := allOrders[symbol]
-				expire(orders, engine, me.BUYSIDE)
-				expire(orders, engine, me.SELLSIDE)
+				expire(symbol, orders, engine, me.BUYSIDE)
+				expire(symbol, orders, engine, me.SELLSIDE)
+				expireConditional(symbol)
 			}
 		}, func() {
 			for _, transferCh := range transferChs {
@@ -945,6 +1082,9 @@ func (kp *DexKeeper) DelistTradingPair(ctx sdk.Context, symbol string, postAlloc
 	}
 
 	delete(kp.engines, symbol)
+	delete(kp.conditionalOrders, symbol)
+	delete(kp.goodTillOrders, symbol)
+	delete(kp.openOrders, symbol)
 	kp.deleteRecentPrices(ctx, symbol)
 	kp.mustGetOrderKeeper(symbol).deleteOrdersForPair(symbol)
 
@@ -966,7 +1106,8 @@ func (kp *DexKeeper) expireAllOrders(ctx sdk.Context, symbol string) []chan Tran
 		ordersOfSymbol = dexOrderKeeper.getAllOrdersForPair(symbol)
 	}
 
-	orderNum := len(ordersOfSymbol)
+	conditionalOrders := kp.conditionalOrders[symbol]
+	orderNum := len(ordersOfSymbol) + len(conditionalOrders)
 	if orderNum == 0 {
 		kp.logger.Info("no orders to expire", "symbol", symbol)
 		return nil
@@ -997,6 +1138,10 @@ func (kp *DexKeeper) expireAllOrders(ctx sdk.Context, symbol string) []chan Tran
 		orders := ordersOfSymbol
 		expire(orders, engine, me.BUYSIDE)
 		expire(orders, engine, me.SELLSIDE)
+		for _, ordMsg := range conditionalOrders {
+			h := channelHash(ordMsg.Sender, concurrency)
+			transferChs[h] <- TransferFromExpired(conditionalOrderPart(ordMsg), *ordMsg)
+		}
 
 		for _, transferCh := range transferChs {
 			close(transferCh)
@@ -1060,6 +1205,8 @@ func (kp *DexKeeper) GetAllOrdersForPair(symbol string) map[string]*OrderInfo {
 
 func (kp *DexKeeper) ReloadOrder(symbol string, orderInfo *OrderInfo, height int64) {
 	kp.mustGetOrderKeeper(symbol).reloadOrder(symbol, orderInfo, height)
+	kp.openOrders.inc(symbol, orderInfo.Sender)
+	kp.indexGoodTillOrder(symbol, orderInfo)
 }
 
 func (kp *DexKeeper) GetOrderChanges(pairType SymbolPairType) OrderChanges {
@@ -1145,8 +1292,8 @@ func appendOrderInfoForPub(ms ...OrderInfoForPublish) OrderInfoForPublish {
 	return res
 }
 
-func CreateMatchEng(pairSymbol string, basePrice, lotSize int64) *me.MatchEng {
-	return me.NewMatchEng(pairSymbol, basePrice, lotSize, 0.05)
+func CreateMatchEng(pairSymbol string, basePrice, lotSize int64, book me.OrderBookInterface) *me.MatchEng {
+	return me.NewMatchEngOnBook(pairSymbol, basePrice, lotSize, 0.05, book)
 }
 
 func isMiniSymbolPair(baseAsset, quoteAsset string) bool {
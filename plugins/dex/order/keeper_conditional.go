@@ -0,0 +1,131 @@
+package order
+
+import (
+	"sort"
+	"strings"
+
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+)
+
+// conditional orders (stop-limit and take-profit) are held by DexKeeper outside of the match engine.
+// Their balance is locked when they are placed, and they are injected into the order book via AddOrder
+// once the last trade price of the pair reaches their StopPrice.
+
+// isTriggeredBy returns true if the conditional order should be placed into the order book at `lastTradePrice`.
+// A buy stop-limit and a sell take-profit are triggered when the price rises to the StopPrice,
+// a sell stop-limit and a buy take-profit are triggered when the price falls to the StopPrice.
+func isTriggeredBy(info *OrderInfo, lastTradePrice int64) bool {
+	rising := (info.OrderType == OrderType.STOPLIMIT) == (info.Side == Side.BUY)
+	if rising {
+		return lastTradePrice >= info.StopPrice
+	}
+	return lastTradePrice <= info.StopPrice
+}
+
+func (kp *DexKeeper) addConditionalOrder(info OrderInfo, isRecovery bool) {
+	symbol := strings.ToUpper(info.Symbol)
+	if _, ok := kp.conditionalOrders[symbol]; !ok {
+		kp.conditionalOrders[symbol] = make(map[string]*OrderInfo)
+	}
+	kp.conditionalOrders[symbol][info.Id] = &info
+	kp.mustGetOrderKeeper(symbol).ackOrder(&info, isRecovery)
+	kp.openOrders.inc(symbol, info.Sender)
+	kp.indexGoodTillOrder(symbol, &info)
+	kp.logger.Debug("Added conditional order", "symbol", symbol, "id", info.Id)
+}
+
+// ConditionalOrderExists returns the conditional order with `id` which is still waiting for its trigger
+func (kp *DexKeeper) ConditionalOrderExists(symbol, id string) (OrderInfo, bool) {
+	if orders, ok := kp.conditionalOrders[strings.ToUpper(symbol)]; ok {
+		if info, ok := orders[id]; ok {
+			return *info, true
+		}
+	}
+	return OrderInfo{}, false
+}
+
+func (kp *DexKeeper) removeConditionalOrder(id string, symbol string) (me.OrderPart, error) {
+	symbol = strings.ToUpper(symbol)
+	if orders, ok := kp.conditionalOrders[symbol]; ok {
+		if info, ok := orders[id]; ok {
+			kp.dropOpenOrder(symbol, orders, id)
+			return conditionalOrderPart(info), nil
+		}
+	}
+	return me.OrderPart{}, orderNotFound(symbol, id)
+}
+
+// GetConditionalOrders returns all the untriggered conditional orders, symbol -> order ID -> order
+func (kp *DexKeeper) GetConditionalOrders() map[string]map[string]*OrderInfo {
+	return kp.conditionalOrders
+}
+
+// conditionalOrderPart builds the order book representation of an untriggered order,
+// so that the transfers for cancel/expire can be calculated the same as for the orders in the book.
+func conditionalOrderPart(info *OrderInfo) me.OrderPart {
+	return me.OrderPart{Id: info.Id, Time: info.CreatedHeight, Qty: info.Quantity}
+}
+
+// triggerConditionalOrders moves the conditional orders, whose StopPrice has been reached by the last trade price,
+// into the order book. The triggered orders join the next round of matching as new orders, so they are placed
+// at `height`+1 to be treated as takers. On the continuous pairs, they are placed at `height` instead, and are
+// returned in the sequence of triggering to be matched right away.
+func (kp *DexKeeper) triggerConditionalOrders(height, timestamp int64, isRecovery bool) []OrderInfo {
+	toMatch := make([]OrderInfo, 0)
+	symbols := make([]string, 0, len(kp.conditionalOrders))
+	for symbol := range kp.conditionalOrders {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		eng, ok := kp.engines[symbol]
+		if !ok {
+			continue
+		}
+		orders := kp.conditionalOrders[symbol]
+		triggered := make([]*OrderInfo, 0)
+		for _, info := range orders {
+			if isTriggeredBy(info, eng.LastTradePrice) {
+				triggered = append(triggered, info)
+			}
+		}
+		// the orders are inserted into the book in the sequence they were placed
+		sort.Slice(triggered, func(i, j int) bool {
+			if triggered[i].CreatedHeight != triggered[j].CreatedHeight {
+				return triggered[i].CreatedHeight < triggered[j].CreatedHeight
+			}
+			return triggered[i].Id < triggered[j].Id
+		})
+
+		for _, info := range triggered {
+			orderInfo := *info
+			placedHeight := height + 1
+			if eng.Continuous {
+				placedHeight = height
+			}
+			orderInfo.CreatedHeight = placedHeight
+			orderInfo.CreatedTimestamp = timestamp
+			orderInfo.LastUpdatedHeight = placedHeight
+			orderInfo.LastUpdatedTimestamp = timestamp
+			// the order keeps waiting for the trigger if it can't be put into the book,
+			// so that its balance is still refunded by the cancel or the expiry of the order
+			if err := kp.insertOrderToBook(orderInfo); err != nil {
+				kp.logger.Error("Failed to add triggered order", "symbol", symbol, "id", orderInfo.Id, "err", err)
+				continue
+			}
+			kp.dropOpenOrder(symbol, orders, info.Id)
+			if kp.CollectOrderInfoForPublish && !isRecovery {
+				kp.mustGetOrderKeeper(symbol).appendOrderChangeSync(OrderChange{orderInfo.Id, Triggered, "", nil})
+			}
+			kp.recordReplay(symbol, orderInfo.Id, Triggered)
+			kp.trackOrder(orderInfo, isRecovery)
+			kp.logger.Debug("Triggered conditional order", "symbol", symbol, "id", orderInfo.Id,
+				"lastTradePrice", eng.LastTradePrice)
+			if eng.Continuous {
+				toMatch = append(toMatch, orderInfo)
+			}
+		}
+	}
+	return toMatch
+}
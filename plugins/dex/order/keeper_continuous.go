@@ -0,0 +1,151 @@
+package order
+
+import (
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The pairs listed in the continuous matching mode are not matched by the auction at the end of the block. Instead,
+// each order is matched on arrival in DeliverTx against the resting orders of the other side, and the transfers of the
+// trades are allocated right away. The fees of the match are added to the fee of the tx, so they are committed only
+// if the tx succeeds, the same as the fee of a cancel. CheckTx doesn't match, the same as for the auction pairs, so the
+// balances of the counterparties in the check state are only updated on Commit. The trades are still collected by
+// the match engine of the pair for the block, so they are published with the trades of the auction. Conditional
+// orders are triggered at the end of the block as usual, and are matched on being triggered.
+
+// IsContinuous returns true if the pair is matched in the continuous mode
+func (kp *DexKeeper) IsContinuous(symbol string) bool {
+	eng, ok := kp.engines[strings.ToUpper(symbol)]
+	return ok && eng.Continuous
+}
+
+// MatchContinuousOrder matches the order `id` just inserted into the book of a continuous pair, and allocates the
+// transfers of the trades and of the orders removed by the match. It returns the fee of the match to be committed.
+func (kp *DexKeeper) MatchContinuousOrder(ctx sdk.Context, symbol, id string, postAlloTransHandler TransferHandler) sdk.Fee {
+	blockHeader := ctx.BlockHeader()
+	transfers := kp.matchContinuously(symbol, id, blockHeader.Height, blockHeader.Time.UnixNano(), true)
+	if len(transfers) == 0 {
+		return sdk.Fee{}
+	}
+
+	concurrency := 1 << kp.poolSize
+	tradeOuts := make([]chan Transfer, concurrency)
+	for i := range tradeOuts {
+		tradeOuts[i] = make(chan Transfer, len(transfers))
+	}
+	for _, tran := range transfers {
+		c := channelHash(tran.accAddress, concurrency)
+		tradeOuts[c] <- tran
+	}
+	for _, tradeOut := range tradeOuts {
+		close(tradeOut)
+	}
+	return kp.allocateAndCalcFee(ctx, tradeOuts, postAlloTransHandler)
+}
+
+// publishIocExpire reports the IOC orders expired by the continuous match to the order change stream
+func (kp *DexKeeper) publishIocExpire(tran Transfer) {
+	if !tran.IsExpire() {
+		return
+	}
+	reason := IocExpire
+	if tran.IsExpiredWithFee() {
+		reason = IocNoFill
+	}
+	kp.UpdateOrderChangeSync(OrderChange{Id: tran.Oid, Tpe: reason, SingleFee: tran.Fee.String()}, tran.Symbol)
+}
+
+// matchContinuously does the match of MatchContinuousOrder in memory. It returns the transfers to allocate
+// if distributeTrade, otherwise nil as in the recovery.
+func (kp *DexKeeper) matchContinuously(symbol, id string, height, timestamp int64, distributeTrade bool) []Transfer {
+	symbol = strings.ToUpper(symbol)
+	engine := kp.engines[symbol]
+	orderKeeper := kp.mustGetOrderKeeper(symbol)
+	orders := orderKeeper.getAllOrdersForPair(symbol)
+	msg, ok := orders[id]
+	if !ok {
+		return nil
+	}
+
+	var transfers []Transfer
+	if msg.TimeInForce == TimeInForce.POSTONLY && engine.WouldTake(msg.Side, msg.Price) {
+		kp.dropOpenOrder(symbol, orders, id)
+		if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
+			kp.logger.Debug("Rejected post-only order", "ordID", id)
+			if distributeTrade {
+				transfers = append(transfers, TransferFromPostOnlyRejected(ord, *msg))
+			}
+		} else {
+			kp.logger.Error("Failed to remove post-only order, may be fatal!", "orderID", id)
+		}
+		if kp.CollectOrderInfoForPublish {
+			orderKeeper.appendOrderChangeSync(OrderChange{id, PostOnlyRejected, "", nil})
+		}
+		kp.recordReplay(symbol, id, PostOnlyRejected)
+		return transfers
+	}
+
+	trades, err := engine.MatchContinuous(id, msg.Side, msg.Price, height)
+	if err != nil {
+		// the order rests in the book, it's matched by nothing else until canceled
+		kp.logger.Error("Failed to match order continuously", "symbol", symbol, "id", id, "err", err)
+		return nil
+	}
+	for i := range trades {
+		t := &trades[i]
+		updateOrderMsg(orders[t.Bid], t.BuyCumQty, height, timestamp)
+		updateOrderMsg(orders[t.Sid], t.SellCumQty, height, timestamp)
+		if distributeTrade {
+			t1, t2 := TransferFromTrade(t, symbol, orders)
+			transfers = append(transfers, t1, t2)
+		}
+	}
+	if len(trades) > 0 || len(engine.SelfTradeCanceled) > 0 {
+		droppedIds := engine.DropFilledOrder()
+		for _, droppedId := range droppedIds {
+			kp.dropOpenOrder(symbol, orders, droppedId)
+		}
+		kp.logger.Debug("Drop filled orders", "total", droppedIds)
+	}
+
+	// the removal of the self-trade canceled orders is shared with the auction, through a single channel here
+	canceledOut := make(chan Transfer, len(engine.SelfTradeCanceled))
+	kp.removeSelfTradeCanceled(symbol, engine, orderKeeper, orders, distributeTrade, []chan Transfer{canceledOut})
+	close(canceledOut)
+	for tran := range canceledOut {
+		transfers = append(transfers, tran)
+	}
+
+	// the rest of an IOC order is expired right away
+	if msg.TimeInForce == TimeInForce.IOC {
+		if _, ok := orders[id]; ok {
+			kp.dropOpenOrder(symbol, orders, id)
+			if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
+				kp.logger.Debug("Removed unclosed IOC order", "ordID", id)
+				if distributeTrade {
+					transfers = append(transfers, TransferFromExpired(ord, *msg))
+				}
+				if ord.CumQty == 0 {
+					kp.recordReplay(symbol, id, IocNoFill)
+				} else {
+					kp.recordReplay(symbol, id, IocExpire)
+				}
+			} else {
+				kp.logger.Error("Failed to remove IOC order, may be fatal!", "orderID", id)
+			}
+		}
+	}
+	return transfers
+}
+
+// matchTriggeredOrders matches the conditional orders triggered on the continuous pairs in the sequence of triggering.
+// Each of them is allocated before the next one is matched, as the transfers refer to the trades collected by the
+// match engine. It returns the total fee of the matches.
+func (kp *DexKeeper) matchTriggeredOrders(ctx sdk.Context, triggered []OrderInfo, postAlloTransHandler TransferHandler) sdk.Fee {
+	var totalFee sdk.Fee
+	for i := range triggered {
+		totalFee.AddFee(kp.MatchContinuousOrder(ctx, triggered[i].Symbol, triggered[i].Id, postAlloTransHandler))
+	}
+	return totalFee
+}
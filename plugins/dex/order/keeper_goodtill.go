@@ -0,0 +1,131 @@
+package order
+
+import (
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
+
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+)
+
+// good-till orders carry an ExpireHeight (good-till-block) and/or an ExpireTime (good-till-time). Besides the daily
+// expiry in the breathe block, they are indexed by DexKeeper and checked after the match of every block. An order is
+// expired once the block height reaches its ExpireHeight or the block time reaches its ExpireTime, whichever comes first.
+// The index only grows on placement, the orders filled or canceled in the meantime are dropped from it when checked.
+
+type goodTill struct {
+	height int64 // 0 for no limit
+	time   int64 // unix seconds, 0 for no limit
+}
+
+func (g goodTill) isReachedAt(height, blockTime int64) bool {
+	return (g.height > 0 && height >= g.height) || (g.time > 0 && blockTime >= g.time)
+}
+
+// indexGoodTillOrder adds the order to the expiry index if it is a good-till order
+func (kp *DexKeeper) indexGoodTillOrder(symbol string, info *OrderInfo) {
+	if !info.IsGoodTill() {
+		return
+	}
+	if _, ok := kp.goodTillOrders[symbol]; !ok {
+		kp.goodTillOrders[symbol] = make(map[string]goodTill)
+	}
+	kp.goodTillOrders[symbol][info.Id] = goodTill{height: info.ExpireHeight, time: info.ExpireTime}
+}
+
+// goodTillOrdersToExpire returns the IDs of the orders to expire at the block in a deterministic sequence,
+// symbol -> order IDs, and drops the orders which are no longer open from the index
+func (kp *DexKeeper) goodTillOrdersToExpire(height, blockTime int64) ([]string, map[string][]string) {
+	symbols := make([]string, 0)
+	toExpire := make(map[string][]string)
+	for symbol, orders := range kp.goodTillOrders {
+		ids := make([]string, 0)
+		for id, g := range orders {
+			_, inBook := kp.OrderExists(symbol, id)
+			_, inConditionalBook := kp.ConditionalOrderExists(symbol, id)
+			if !inBook && !inConditionalBook {
+				delete(orders, id)
+			} else if g.isReachedAt(height, blockTime) {
+				ids = append(ids, id)
+			}
+		}
+		if len(orders) == 0 {
+			delete(kp.goodTillOrders, symbol)
+		}
+		if len(ids) > 0 {
+			sort.Strings(ids)
+			symbols = append(symbols, symbol)
+			toExpire[symbol] = ids
+		}
+	}
+	sort.Strings(symbols)
+	return symbols, toExpire
+}
+
+// expireGoodTillOrders removes the good-till orders reaching their expiry at the block from the order books and
+// the conditional orders. The transfers of the expired orders are returned unless `isRecovery`.
+func (kp *DexKeeper) expireGoodTillOrders(height int64, blockTime time.Time, isRecovery bool) []chan Transfer {
+	symbols, toExpire := kp.goodTillOrdersToExpire(height, blockTime.Unix())
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	concurrency := 1 << kp.poolSize
+	var transferChs []chan Transfer
+	if !isRecovery {
+		size := 0
+		for _, ids := range toExpire {
+			size += len(ids)
+		}
+		transferChs = make([]chan Transfer, concurrency)
+		for i := range transferChs {
+			transferChs[i] = make(chan Transfer, size)
+		}
+	}
+
+	for _, symbol := range symbols {
+		for _, id := range toExpire[symbol] {
+			delete(kp.goodTillOrders[symbol], id)
+			var ord me.OrderPart
+			info, inBook := kp.OrderExists(symbol, id)
+			if inBook {
+				if err := kp.RemoveOrder(id, symbol, func(o me.OrderPart) { ord = o }); err != nil {
+					kp.logger.Error("failed to remove good-till order from order book", "oid", id, "err", err)
+					continue
+				}
+			} else {
+				info, _ = kp.ConditionalOrderExists(symbol, id)
+				ord, _ = kp.removeConditionalOrder(id, symbol)
+			}
+			kp.recordReplay(symbol, id, Expired)
+			kp.logger.Debug("Expired good-till order", "symbol", symbol, "id", id,
+				"expireHeight", info.ExpireHeight, "expireTime", info.ExpireTime)
+			if transferChs != nil {
+				transferChs[channelHash(info.Sender, concurrency)] <- TransferFromExpired(ord, info)
+			}
+		}
+		if len(kp.goodTillOrders[symbol]) == 0 {
+			delete(kp.goodTillOrders, symbol)
+		}
+	}
+
+	for _, transferCh := range transferChs {
+		close(transferCh)
+	}
+	return transferChs
+}
+
+// ExpireGoodTillOrders expires the good-till orders reaching their expiry at the block, and charges the expire fees
+// the same as the orders expired in the breathe block
+func (kp *DexKeeper) ExpireGoodTillOrders(ctx sdk.Context, postAlloTransHandler TransferHandler) {
+	blockHeader := ctx.BlockHeader()
+	transferChs := kp.expireGoodTillOrders(blockHeader.Height, blockHeader.Time, false)
+	if transferChs == nil {
+		return
+	}
+
+	totalFee := kp.allocateAndCalcFee(ctx, transferChs, postAlloTransHandler)
+	fees.Pool.AddAndCommitFee("EXPIRE_GOODTILL", totalFee)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
 )
 
 func (kp *DexKeeper) SelectSymbolsToMatch(height int64, matchAllSymbols bool) []string {
@@ -23,7 +24,14 @@ func (kp *DexKeeper) SelectSymbolsToMatch(height int64, matchAllSymbols bool) []
 			}
 		}
 	}
-	return symbolsToMatch
+	// the continuous pairs are matched on arrival of the orders
+	auctionSymbols := symbolsToMatch[:0]
+	for _, symbol := range symbolsToMatch {
+		if eng, ok := kp.engines[symbol]; !ok || !eng.Continuous {
+			auctionSymbols = append(auctionSymbols, symbol)
+		}
+	}
+	return auctionSymbols
 }
 
 func (kp *DexKeeper) MatchAndAllocateSymbols(ctx sdk.Context, postAlloTransHandler TransferHandler, matchAllSymbols bool) {
@@ -42,8 +50,10 @@ func (kp *DexKeeper) MatchAndAllocateSymbols(ctx sdk.Context, postAlloTransHandl
 	}
 
 	totalFee := kp.allocateAndCalcFee(ctx, tradeOuts, postAlloTransHandler)
-	fees.Pool.AddAndCommitFee("MATCH", totalFee)
 	kp.ClearAfterMatch()
+	triggered := kp.triggerConditionalOrders(blockHeader.Height, timestamp, false)
+	totalFee.AddFee(kp.matchTriggeredOrders(ctx, triggered, postAlloTransHandler))
+	fees.Pool.AddAndCommitFee("MATCH", totalFee)
 }
 
 // please note if distributeTrade this method will work in async mode, otherwise in sync mode.
@@ -101,6 +111,9 @@ func (kp *DexKeeper) MatchSymbols(height, timestamp int64, matchAllSymbols bool)
 	}
 
 	kp.ClearAfterMatch()
+	for _, info := range kp.triggerConditionalOrders(height, timestamp, true) {
+		kp.matchContinuously(info.Symbol, info.Id, height, timestamp, false)
+	}
 }
 
 func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, timestamp int64, distributeTrade bool,
@@ -109,6 +122,9 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 	concurrency := len(tradeOuts)
 	orderKeeper := kp.mustGetOrderKeeper(symbol)
 	orders := orderKeeper.getAllOrdersForPair(symbol)
+	if sdk.IsUpgrade(upgrade.PostOnlyUpgrade) {
+		kp.rejectPostOnlyTakers(symbol, engine, orderKeeper, orders, distributeTrade, tradeOuts)
+	}
 	// please note there is no logging in matching, expecting to see the order book details
 	// from the exchange's order book stream.
 	if engine.Match(height) {
@@ -127,9 +143,10 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 		}
 		droppedIds := engine.DropFilledOrder() //delete from order books
 		for _, id := range droppedIds {
-			delete(orders, id) //delete from order cache
+			kp.dropOpenOrder(symbol, orders, id) //delete from order cache
 		}
 		kp.logger.Debug("Drop filled orders", "total", droppedIds)
+		kp.removeSelfTradeCanceled(symbol, engine, orderKeeper, orders, distributeTrade, tradeOuts)
 	} else {
 		// FUTURE-TODO:
 		// when Match() failed, have to unsolicited cancel all the new orders
@@ -141,7 +158,7 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 		thisRoundIds := orderKeeper.getRoundOrdersForPair(symbol)
 		for _, id := range thisRoundIds {
 			msg := orders[id]
-			delete(orders, id)
+			kp.dropOpenOrder(symbol, orders, id)
 			if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
 				kp.logger.Info("Removed due to match failure", "ordID", msg.Id)
 				if distributeTrade {
@@ -157,13 +174,14 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 			if kp.CollectOrderInfoForPublish {
 				orderKeeper.appendOrderChangeSync(OrderChange{id, FailedMatching, "", nil})
 			}
+			kp.recordReplay(symbol, id, FailedMatching)
 		}
 		return // no need to handle IOC
 	}
 	iocIDs := orderKeeper.getRoundIOCOrdersForPair(symbol)
 	for _, id := range iocIDs {
 		if msg, ok := orders[id]; ok {
-			delete(orders, id)
+			kp.dropOpenOrder(symbol, orders, id)
 			if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
 				kp.logger.Debug("Removed unclosed IOC order", "ordID", msg.Id)
 				if distributeTrade {
@@ -177,6 +195,84 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 	}
 }
 
+// rejectPostOnlyTakers removes the post-only orders of this round which would be filled as the taker side.
+// Removing an order may move the concluded price, so the check repeats until no post-only order would cross.
+func (kp *DexKeeper) rejectPostOnlyTakers(symbol string, engine *me.MatchEng, orderKeeper DexOrderKeeper,
+	orders map[string]*OrderInfo, distributeTrade bool, tradeOuts []chan Transfer) {
+	hasPostOnly := false
+	for _, id := range orderKeeper.getRoundOrdersForPair(symbol) {
+		if msg, ok := orders[id]; ok && msg.TimeInForce == TimeInForce.POSTONLY {
+			hasPostOnly = true
+			break
+		}
+	}
+	if !hasPostOnly {
+		return
+	}
+
+	concurrency := len(tradeOuts)
+	for {
+		takerIds, err := engine.TakerOrderIds()
+		if err != nil {
+			// leave it to the match, which would cancel all the new orders of this round
+			kp.logger.Error("Failed to determine taker orders", "symbol", symbol, "error", err)
+			return
+		}
+		rejected := false
+		for _, id := range takerIds {
+			msg, ok := orders[id]
+			if !ok || msg.TimeInForce != TimeInForce.POSTONLY {
+				continue
+			}
+			rejected = true
+			kp.dropOpenOrder(symbol, orders, id)
+			if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
+				kp.logger.Debug("Rejected post-only order", "ordID", msg.Id)
+				if distributeTrade {
+					c := channelHash(msg.Sender, concurrency)
+					tradeOuts[c] <- TransferFromPostOnlyRejected(ord, *msg)
+				}
+			} else {
+				kp.logger.Error("Failed to remove post-only order, may be fatal!", "orderID", id)
+			}
+			if kp.CollectOrderInfoForPublish {
+				orderKeeper.appendOrderChangeSync(OrderChange{id, PostOnlyRejected, "", nil})
+			}
+			kp.recordReplay(symbol, id, PostOnlyRejected)
+		}
+		if !rejected {
+			return
+		}
+	}
+}
+
+// removeSelfTradeCanceled removes the orders canceled by the self-trade prevention in the match,
+// which are left in the order book by the match engine
+func (kp *DexKeeper) removeSelfTradeCanceled(symbol string, engine *me.MatchEng, orderKeeper DexOrderKeeper,
+	orders map[string]*OrderInfo, distributeTrade bool, tradeOuts []chan Transfer) {
+	concurrency := len(tradeOuts)
+	for _, id := range engine.SelfTradeCanceled {
+		msg, ok := orders[id]
+		if !ok {
+			continue
+		}
+		kp.dropOpenOrder(symbol, orders, id)
+		if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
+			kp.logger.Debug("Removed order to prevent self-trade", "ordID", msg.Id)
+			if distributeTrade {
+				c := channelHash(msg.Sender, concurrency)
+				tradeOuts[c] <- TransferFromSelfTradePrevented(ord, *msg)
+			}
+		} else {
+			kp.logger.Error("Failed to remove self-trade order, may be fatal!", "orderID", id)
+		}
+		if kp.CollectOrderInfoForPublish {
+			orderKeeper.appendOrderChangeSync(OrderChange{id, SelfTradePrevented, "", nil})
+		}
+		kp.recordReplay(symbol, id, SelfTradePrevented)
+	}
+}
+
 // Run as postConsume procedure of async, no concurrent updates of orders map
 func updateOrderMsg(order *OrderInfo, cumQty, height, timestamp int64) {
 	order.CumQty = cumQty
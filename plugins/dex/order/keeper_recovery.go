@@ -48,6 +48,10 @@ func genActiveOrdersSnapshotKey(height int64) string {
 	return fmt.Sprintf("activeorders_%v", height)
 }
 
+func genConditionalOrdersSnapshotKey(height int64) string {
+	return fmt.Sprintf("conditionalorders_%v", height)
+}
+
 func compressAndSave(snapshot interface{}, cdc *wire.Codec, key string, kv sdk.KVStore) error {
 	bytes, err := cdc.MarshalBinaryLengthPrefixed(snapshot)
 	if err != nil {
@@ -99,12 +103,49 @@ func (kp *DexKeeper) SnapShotOrderBook(ctx sdk.Context, height int64) (effectedS
 	key := genActiveOrdersSnapshotKey(height)
 	effectedStoreKeys = append(effectedStoreKeys, key)
 	ctx.Logger().Info("Saving active orders", "height", height)
-	return effectedStoreKeys, compressAndSave(snapshot, kp.cdc, key, kvstore)
+	if err := compressAndSave(snapshot, kp.cdc, key, kvstore); err != nil {
+		return nil, err
+	}
+
+	conditionalMsgs := kp.sortedConditionalOrders()
+	if len(conditionalMsgs) == 0 {
+		return effectedStoreKeys, nil
+	}
+	key = genConditionalOrdersSnapshotKey(height)
+	effectedStoreKeys = append(effectedStoreKeys, key)
+	ctx.Logger().Info("Saving conditional orders", "height", height)
+	return effectedStoreKeys, compressAndSave(ActiveOrders{Orders: conditionalMsgs}, kp.cdc, key, kvstore)
+}
+
+func (kp *DexKeeper) sortedConditionalOrders() []OrderInfo {
+	msgs := make([]OrderInfo, 0)
+	for _, orders := range kp.conditionalOrders {
+		for _, info := range orders {
+			msgs = append(msgs, *info)
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].Id < msgs[j].Id })
+	return msgs
+}
+
+func loadCompressedSnapshot(cdc *wire.Codec, bz []byte, ptr interface{}) error {
+	r, err := zlib.NewReader(bytes.NewBuffer(bz))
+	if err != nil {
+		return err
+	}
+	var bw bytes.Buffer
+	_, _ = io.Copy(&bw, r)
+	return cdc.UnmarshalBinaryLengthPrefixed(bw.Bytes(), ptr)
 }
 
 func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight int64, timeOfLatestBlock time.Time, blockInterval, daysBack int) (int64, error) {
 	height := kp.GetLastBreatheBlockHeight(ctx, latestBlockHeight, timeOfLatestBlock, blockInterval, daysBack)
 	ctx.Logger().Info("Loading order book snapshot from last breathe block", "blockHeight", height)
+	return height, kp.LoadOrderBookSnapshotAt(ctx, height)
+}
+
+// LoadOrderBookSnapshotAt loads the order books and active orders saved at the breathe block `height`
+func (kp *DexKeeper) LoadOrderBookSnapshotAt(ctx sdk.Context, height int64) error {
 	allPairs := kp.PairMapper.ListAllTradingPairs(ctx)
 	if height == 0 {
 		// just initialize engines for all pairs
@@ -115,7 +156,7 @@ func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight in
 			}
 		}
 		ctx.Logger().Info("No breathe block is ever saved. just created match engines for all the pairs.")
-		return height, nil
+		return nil
 	}
 
 	upgrade.Mgr.SetHeight(height)
@@ -170,7 +211,7 @@ func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight in
 	bz := kvStore.Get([]byte(key))
 	if bz == nil {
 		ctx.Logger().Info("Pair is newly listed, no active order snapshot was saved", "pair", key)
-		return height, nil
+		return nil
 	}
 	b := bytes.NewBuffer(bz)
 	var bw bytes.Buffer
@@ -188,9 +229,43 @@ func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight in
 		orderHolder := m
 		symbol := strings.ToUpper(m.Symbol)
 		kp.ReloadOrder(symbol, &orderHolder, height)
+		kp.restoreOrderOwner(symbol, &orderHolder)
+	}
+	ctx.Logger().Info("Recovered active orders")
+
+	key = genConditionalOrdersSnapshotKey(height)
+	if bz = kvStore.Get([]byte(key)); bz != nil {
+		var co ActiveOrders
+		if err := loadCompressedSnapshot(kp.cdc, bz, &co); err != nil {
+			panic(fmt.Sprintf("failed to unmarshal snapshort for conditional orders [%s], err: %v", key, err))
+		}
+		for _, m := range co.Orders {
+			kp.addConditionalOrder(m, true)
+		}
+		ctx.Logger().Info("Recovered conditional orders")
+	}
+	ctx.Logger().Info("Snapshot is fully loaded")
+	return nil
+}
+
+// restoreOrderOwner sets the owner and the self-trade prevention mode of the order in the order book,
+// which are not saved in the order book snapshot
+func (kp *DexKeeper) restoreOrderOwner(symbol string, info *OrderInfo) {
+	eng, ok := kp.engines[symbol]
+	if !ok {
+		return
+	}
+	pl := eng.Book.GetPriceLevel(info.Price, info.Side)
+	if pl == nil {
+		return
+	}
+	for i := range pl.Orders {
+		if pl.Orders[i].Id == info.Id {
+			ord := newOrderPart(info)
+			pl.Orders[i].Owner, pl.Orders[i].Stp = ord.Owner, ord.Stp
+			return
+		}
 	}
-	ctx.Logger().Info("Recovered active orders. Snapshot is fully loaded")
-	return height, nil
 }
 
 func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, stateDB dbm.DB, txDecoder sdk.TxDecoder,
@@ -199,6 +274,16 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 		logger.Error("No block is loaded. Ignore replay for orderbook")
 		return
 	}
+	abciRes := mustLoadABCIResponses(stateDB, block, height)
+	// the time we replay should be consistent with ctx.BlockHeader().Time
+	t := timestamp.UnixNano()
+	kp.replayTxs(logger, block, abciRes, txDecoder, height, t)
+	logger.Info("replayed all tx. Starting match", "height", height)
+	kp.MatchSymbols(height, t, false) //no need to check result
+	kp.expireGoodTillOrders(height, timestamp, true)
+}
+
+func mustLoadABCIResponses(stateDB dbm.DB, block *tmtypes.Block, height int64) *state.ABCIResponses {
 	abciRes, err := state.LoadABCIResponses(stateDB, height)
 	if err != nil {
 		panic(fmt.Errorf("failed to load abci response when replay block at height %d, err %v", height, err))
@@ -206,8 +291,12 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 	if abciRes != nil && len(abciRes.DeliverTx) != len(block.Txs) {
 		panic(fmt.Errorf("length of delivertx %d and lenght of tx %d mismatch ", len(abciRes.DeliverTx), len(block.Txs)))
 	}
-	// the time we replay should be consistent with ctx.BlockHeader().Time
-	t := timestamp.UnixNano()
+	return abciRes
+}
+
+// replayTxs applies the order messages of the successful txs in the block onto the order books
+func (kp *DexKeeper) replayTxs(logger log.Logger, block *tmtypes.Block, abciRes *state.ABCIResponses, txDecoder sdk.TxDecoder,
+	height, t int64) {
 	for idx, txBytes := range block.Txs {
 		if abciRes.DeliverTx[idx].IsErr() {
 			logger.Info("Skip tx when replay", "height", height, "idx", idx)
@@ -222,35 +311,53 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 		for _, m := range msgs {
 			switch msg := m.(type) {
 			case NewOrderMsg:
-				var txSource int64
-				upgrade.UpgradeBEP10(nil, func() {
-					if stdTx, ok := tx.(auth.StdTx); ok {
-						txSource = stdTx.GetSource()
-					} else {
-						logger.Error("tx is not an auth.StdTx", "txhash", txHash.String())
+				kp.replayNewOrder(logger, msg, height, t, txHash.String(), replayTxSource(logger, tx, txHash))
+			case CancelOrderMsg:
+				kp.replayCancelOrder(logger, msg)
+			case BatchNewOrderMsg:
+				txSource := replayTxSource(logger, tx, txHash)
+				for _, orderMsg := range msg.NewOrderMsgs() {
+					kp.replayNewOrder(logger, orderMsg, height, t, txHash.String(), txSource)
+				}
+			case BatchCancelOrderMsg:
+				for _, cancelMsg := range msg.CancelOrderMsgs() {
+					kp.replayCancelOrder(logger, cancelMsg)
+				}
+			case ReplaceOrderMsg:
+				origOrd, inBook := kp.OrderExists(msg.Symbol, msg.RefId)
+				if inBook && isAmendInPlace(origOrd, msg) {
+					if err := kp.amendOrder(msg.RefId, msg.Symbol, msg.Quantity, height, t, true); err != nil {
+						logger.Error("Failed to replay amend msg", "err", err)
 					}
-				})
-				orderInfo := OrderInfo{
-					msg,
-					height, t,
-					height, t,
-					0, txHash.String(), txSource}
-				err := kp.AddOrder(orderInfo, true)
-				if err != nil {
-					logger.Error("Failed to replay NreOrderMsg", "err", err)
+					kp.recordReplay(msg.Symbol, msg.RefId, Amended)
+					logger.Info("Amended Order", "order", msg)
+					continue
 				}
-				logger.Info("Added Order", "order", msg)
-			case CancelOrderMsg:
-				err := kp.RemoveOrder(msg.RefId, msg.Symbol, func(ord me.OrderPart) {
+				if !inBook {
+					origOrd, _ = kp.ConditionalOrderExists(msg.Symbol, msg.RefId)
+					_, _ = kp.removeConditionalOrder(msg.RefId, msg.Symbol)
+				} else if err := kp.RemoveOrder(msg.RefId, msg.Symbol, func(ord me.OrderPart) {
 					if kp.CollectOrderInfoForPublish {
-						bnclog.Debug("deleted order from order changes map", "orderId", msg.RefId, "isRecovery", true)
 						kp.RemoveOrderInfosForPub(msg.Symbol, msg.RefId)
 					}
-				})
-				if err != nil {
-					logger.Error("Failed to replay cancel msg", "err", err)
+				}); err != nil {
+					logger.Error("Failed to replay replace msg", "err", err)
 				}
-				logger.Info("Canceled Order", "order", msg)
+				kp.recordReplay(msg.Symbol, msg.RefId, Canceled)
+				orderInfo := OrderInfo{
+					msg.newOrderMsg(origOrd),
+					height, t,
+					height, t,
+					0, txHash.String(), replayTxSource(logger, tx, txHash)}
+				kp.recordReplay(msg.Symbol, msg.Id, Ack)
+				if IsConditionalOrderType(orderInfo.OrderType) {
+					kp.addConditionalOrder(orderInfo, true)
+				} else if err := kp.AddOrder(orderInfo, true); err != nil {
+					logger.Error("Failed to replay replace msg", "err", err)
+				} else if kp.IsContinuous(msg.Symbol) {
+					kp.matchContinuously(msg.Symbol, msg.Id, height, t, false)
+				}
+				logger.Info("Replaced Order", "order", msg)
 			case dextypes.ListMiniMsg:
 				kp.engines[dexutils.Assets2TradingPair(msg.BaseAssetSymbol, msg.QuoteAssetSymbol)].LastMatchHeight = 0
 			case dextypes.ListMsg:
@@ -258,8 +365,62 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 			}
 		}
 	}
-	logger.Info("replayed all tx. Starting match", "height", height)
-	kp.MatchSymbols(height, t, false) //no need to check result
+}
+
+func replayTxSource(logger log.Logger, tx sdk.Tx, txHash cmn.HexBytes) int64 {
+	var txSource int64
+	upgrade.UpgradeBEP10(nil, func() {
+		if stdTx, ok := tx.(auth.StdTx); ok {
+			txSource = stdTx.GetSource()
+		} else {
+			logger.Error("tx is not an auth.StdTx", "txhash", txHash.String())
+		}
+	})
+	return txSource
+}
+
+func (kp *DexKeeper) replayNewOrder(logger log.Logger, msg NewOrderMsg, height, t int64, txHash string, txSource int64) {
+	if err := kp.priceMarketOrder(&msg); err != nil {
+		logger.Error("Failed to replay market order", "err", err)
+		return
+	}
+	orderInfo := OrderInfo{
+		msg,
+		height, t,
+		height, t,
+		0, txHash, txSource}
+	kp.recordReplay(msg.Symbol, msg.Id, Ack)
+	if IsConditionalOrderType(msg.OrderType) {
+		kp.addConditionalOrder(orderInfo, true)
+		logger.Info("Added conditional Order", "order", msg)
+		return
+	}
+	err := kp.AddOrder(orderInfo, true)
+	if err != nil {
+		logger.Error("Failed to replay NreOrderMsg", "err", err)
+	} else if kp.IsContinuous(msg.Symbol) {
+		kp.matchContinuously(msg.Symbol, msg.Id, height, t, false)
+	}
+	logger.Info("Added Order", "order", msg)
+}
+
+func (kp *DexKeeper) replayCancelOrder(logger log.Logger, msg CancelOrderMsg) {
+	kp.recordReplay(msg.Symbol, msg.RefId, Canceled)
+	if _, ok := kp.ConditionalOrderExists(msg.Symbol, msg.RefId); ok {
+		_, _ = kp.removeConditionalOrder(msg.RefId, msg.Symbol)
+		logger.Info("Canceled conditional Order", "order", msg)
+		return
+	}
+	err := kp.RemoveOrder(msg.RefId, msg.Symbol, func(ord me.OrderPart) {
+		if kp.CollectOrderInfoForPublish {
+			bnclog.Debug("deleted order from order changes map", "orderId", msg.RefId, "isRecovery", true)
+			kp.RemoveOrderInfosForPub(msg.Symbol, msg.RefId)
+		}
+	})
+	if err != nil {
+		logger.Error("Failed to replay cancel msg", "err", err)
+	}
+	logger.Info("Canceled Order", "order", msg)
 }
 
 func (kp *DexKeeper) ReplayOrdersFromBlock(ctx sdk.Context, bc *tmstore.BlockStore, stateDb dbm.DB, lastHeight, breatheHeight int64,
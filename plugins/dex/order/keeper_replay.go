@@ -0,0 +1,236 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/state"
+	tmstore "github.com/tendermint/tendermint/store"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// ReplayedTrade is a trade generated by replaying the order messages of a block
+type ReplayedTrade struct {
+	Symbol     string `json:"symbol"`
+	Price      int64  `json:"price"`
+	Qty        int64  `json:"qty"`
+	Sid        string `json:"sid"`
+	Bid        string `json:"bid"`
+	BuyCumQty  int64  `json:"buyCumQty"`
+	SellCumQty int64  `json:"sellCumQty"`
+	TickType   int8   `json:"tickType"`
+}
+
+// ReplayedOrderChange is a status change of an order while replaying a block.
+// CumQty is only filled for the changes caused by the match.
+type ReplayedOrderChange struct {
+	Symbol  string     `json:"symbol"`
+	OrderId string     `json:"orderId"`
+	Status  ChangeType `json:"status"`
+	CumQty  int64      `json:"cumQty,omitempty"`
+}
+
+// ReplayedBlock is everything that happened to the order books at a height, the books are only
+// included for the pairs with trades or order changes
+type ReplayedBlock struct {
+	Height       int64                             `json:"height"`
+	Timestamp    int64                             `json:"timestamp"`
+	Trades       []ReplayedTrade                   `json:"trades"`
+	OrderChanges []ReplayedOrderChange             `json:"orderChanges"`
+	Books        map[string][]store.OrderBookLevel `json:"books"`
+}
+
+// recordReplay reports the order change to the recorder installed by ReplayMatch, it is a no-op otherwise
+func (kp *DexKeeper) recordReplay(symbol, id string, tpe ChangeType) {
+	if kp.replayRecorder != nil {
+		kp.replayRecorder(strings.ToUpper(symbol), id, tpe)
+	}
+}
+
+// ReplayMatch replays the blocks in (fromHeight, toHeight] onto the order books, which must have been loaded
+// by LoadOrderBookSnapshotAt(fromHeight), and calls `onBlock` with what happened at each height.
+// The orders are not expired by the breathe block during the replay, so the replay can not go across a breathe block.
+func (kp *DexKeeper) ReplayMatch(ctx sdk.Context, bc *tmstore.BlockStore, stateDB dbm.DB, txDecoder sdk.TxDecoder,
+	fromHeight, toHeight int64, depth int, onBlock func(ReplayedBlock) error) error {
+	kvStore := ctx.KVStore(kp.storeKey)
+	for height := fromHeight + 1; height <= toHeight; height++ {
+		if kvStore.Has([]byte(genActiveOrdersSnapshotKey(height))) {
+			return fmt.Errorf("height %d is a breathe block, replay from there instead", height)
+		}
+		block := bc.LoadBlock(height)
+		if block == nil {
+			return fmt.Errorf("block %d is not in the block store", height)
+		}
+		upgrade.Mgr.SetHeight(height)
+		replayed := kp.replayAndRecordBlock(ctx, block, mustLoadABCIResponses(stateDB, block, height), txDecoder, height, depth)
+		if err := onBlock(replayed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kp *DexKeeper) replayAndRecordBlock(ctx sdk.Context, block *tmtypes.Block, abciRes *state.ABCIResponses,
+	txDecoder sdk.TxDecoder, height int64, depth int) ReplayedBlock {
+	t := block.Time.UnixNano()
+	replayed := ReplayedBlock{
+		Height:       height,
+		Timestamp:    t,
+		Trades:       make([]ReplayedTrade, 0),
+		OrderChanges: make([]ReplayedOrderChange, 0),
+		Books:        make(map[string][]store.OrderBookLevel),
+	}
+	// the match runs the pairs concurrently
+	var mtx sync.Mutex
+	kp.replayRecorder = func(symbol, id string, tpe ChangeType) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		replayed.OrderChanges = append(replayed.OrderChanges, ReplayedOrderChange{Symbol: symbol, OrderId: id, Status: tpe})
+	}
+	defer func() { kp.replayRecorder = nil }()
+
+	kp.replayTxs(ctx.Logger(), block, abciRes, txDecoder, height, t)
+
+	// the IOC orders are removed by the match, keep them to tell whether they are filled. The IOC orders of
+	// the continuous pairs have been matched on arrival, and are reported by the continuous match instead.
+	iocOrders := make(map[string]OrderInfo)
+	for _, orderKeeper := range kp.OrderKeepers {
+		orderKeeper.iterateRoundSelectedPairs(func(symbol string) {
+			if kp.IsContinuous(symbol) {
+				return
+			}
+			orders := orderKeeper.getAllOrdersForPair(symbol)
+			for _, id := range orderKeeper.getRoundIOCOrdersForPair(symbol) {
+				if info, ok := orders[id]; ok {
+					iocOrders[id] = *info
+				}
+			}
+		})
+	}
+
+	txChanges := len(replayed.OrderChanges)
+	kp.MatchSymbols(height, t, false)
+	changes := replayed.OrderChanges
+	replayed.OrderChanges = make([]ReplayedOrderChange, 0, len(changes))
+	// the changes made by the concurrent match are sorted to be deterministic, the triggered orders come in sequence
+	triggered := make([]ReplayedOrderChange, 0)
+	matched := make([]ReplayedOrderChange, 0)
+	// the partially filled IOC orders expired by the continuous match, on arrival or on being triggered,
+	// are reported with the fills
+	expiredIoc := make(map[string]bool)
+	for i, change := range changes {
+		if change.Status == IocExpire {
+			expiredIoc[change.OrderId] = true
+		} else if i < txChanges {
+			replayed.OrderChanges = append(replayed.OrderChanges, change)
+		} else if change.Status == Triggered {
+			triggered = append(triggered, change)
+		} else {
+			matched = append(matched, change)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Symbol < matched[j].Symbol })
+
+	symbols := make([]string, 0, len(kp.engines))
+	for symbol, eng := range kp.engines {
+		if eng.LastMatchHeight == height && len(eng.Trades) > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	fills := make([]ReplayedOrderChange, 0)
+	for _, symbol := range symbols {
+		fills = append(fills, kp.replayTrades(symbol, &replayed, iocOrders, expiredIoc)...)
+	}
+	// the orders canceled by the self-trade prevention after being filled are reported along with their fills
+	filledStp := make(map[string]bool)
+	for _, change := range fills {
+		if change.Status == SelfTradePrevented {
+			filledStp[change.OrderId] = true
+		}
+	}
+	for _, change := range matched {
+		if change.Status != SelfTradePrevented || !filledStp[change.OrderId] {
+			replayed.OrderChanges = append(replayed.OrderChanges, change)
+		}
+	}
+	replayed.OrderChanges = append(replayed.OrderChanges, fills...)
+	filled := make(map[string]bool)
+	for _, t := range replayed.Trades {
+		filled[t.Bid], filled[t.Sid] = true, true
+	}
+	noFills := make([]ReplayedOrderChange, 0)
+	for id, info := range iocOrders {
+		if !filled[id] {
+			noFills = append(noFills, ReplayedOrderChange{strings.ToUpper(info.Symbol), id, IocNoFill, 0})
+		}
+	}
+	sort.Slice(noFills, func(i, j int) bool { return noFills[i].OrderId < noFills[j].OrderId })
+	replayed.OrderChanges = append(replayed.OrderChanges, noFills...)
+	replayed.OrderChanges = append(replayed.OrderChanges, triggered...)
+	// the good-till orders are expired after the match, in sequence
+	kp.expireGoodTillOrders(height, block.Time, true)
+
+	for _, change := range replayed.OrderChanges {
+		if _, ok := replayed.Books[change.Symbol]; !ok {
+			replayed.Books[change.Symbol] = kp.replayedDepth(change.Symbol, depth)
+		}
+	}
+	return replayed
+}
+
+// replayTrades appends the trades of the pair to `replayed` and returns the fills of the orders
+func (kp *DexKeeper) replayTrades(symbol string, replayed *ReplayedBlock, iocOrders map[string]OrderInfo,
+	expiredIoc map[string]bool) []ReplayedOrderChange {
+	eng := kp.engines[symbol]
+	cumQty := make(map[string]int64)
+	ids := make([]string, 0)
+	fill := func(id string, qty int64) {
+		if _, ok := cumQty[id]; !ok {
+			ids = append(ids, id)
+		}
+		cumQty[id] = qty
+	}
+	for _, t := range eng.Trades {
+		replayed.Trades = append(replayed.Trades, ReplayedTrade{
+			symbol, t.LastPx, t.LastQty, t.Sid, t.Bid, t.BuyCumQty, t.SellCumQty, t.TickType})
+		fill(t.Bid, t.BuyCumQty)
+		fill(t.Sid, t.SellCumQty)
+	}
+	sort.Strings(ids)
+	stpCanceled := make(map[string]bool, len(eng.SelfTradeCanceled))
+	for _, id := range eng.SelfTradeCanceled {
+		stpCanceled[id] = true
+	}
+
+	changes := make([]ReplayedOrderChange, 0, len(ids))
+	for _, id := range ids {
+		status := FullyFill
+		if _, open := kp.OrderExists(symbol, id); open {
+			status = PartialFill
+		} else if stpCanceled[id] {
+			status = SelfTradePrevented
+		} else if info, ok := iocOrders[id]; (ok && cumQty[id] < info.Quantity) || expiredIoc[id] {
+			status = IocExpire
+		}
+		changes = append(changes, ReplayedOrderChange{symbol, id, status, cumQty[id]})
+	}
+	return changes
+}
+
+func (kp *DexKeeper) replayedDepth(symbol string, depth int) []store.OrderBookLevel {
+	levels, _ := kp.GetOrderBookLevels(symbol, depth)
+	n := len(levels)
+	for n > 0 && levels[n-1] == (store.OrderBookLevel{}) {
+		n--
+	}
+	return levels[:n]
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/cosmos/cosmos-sdk/baseapp"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdkstore "github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -46,6 +47,9 @@ func MakeCodec() *wire.Codec {
 	types.RegisterWire(cdc)
 	cdc.RegisterConcrete(NewOrderMsg{}, "dex/NewOrder", nil)
 	cdc.RegisterConcrete(CancelOrderMsg{}, "dex/CancelOrder", nil)
+	cdc.RegisterConcrete(ReplaceOrderMsg{}, "dex/ReplaceOrder", nil)
+	cdc.RegisterConcrete(BatchNewOrderMsg{}, "dex/BatchNewOrder", nil)
+	cdc.RegisterConcrete(BatchCancelOrderMsg{}, "dex/BatchCancelOrder", nil)
 
 	cdc.RegisterConcrete(OrderBookSnapshot{}, "dex/OrderBookSnapshot", nil)
 	cdc.RegisterConcrete(ActiveOrders{}, "dex/ActiveOrders", nil)
@@ -75,6 +79,22 @@ func MakeCMS(memDB *db.MemDB) sdk.CacheMultiStore {
 	return cms
 }
 
+func TestKeeper_SetOrderBookBackend(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	assert.NotNil(keeper.SetOrderBookBackend(PairType.BEP2, "unknown"))
+	assert.Nil(keeper.SetOrderBookBackend(PairType.BEP2, me.OrderBookBTree))
+	assert.Nil(keeper.SetOrderBookBackend(PairType.MINI, me.OrderBookSkipList))
+
+	eng := keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	assert.IsType(&me.OrderBookOnBTree{}, eng.Book)
+	eng = keeper.AddEngine(dextypes.NewTradingPair("XYZ-000M", "BNB", 1e8))
+	assert.IsType(&me.OrderBookOnSkipList{}, eng.Book)
+	eng = keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BTC-000", 1e8))
+	assert.IsType(&me.OrderBookOnBTree{}, eng.Book)
+}
+
 func TestKeeper_MatchFailure(t *testing.T) {
 	assert := assert.New(t)
 	cdc := MakeCodec()
@@ -335,6 +355,37 @@ func TestKeeper_SnapShotOrderBookEmpty(t *testing.T) {
 	assert.Equal(0, len(sells))
 }
 
+func TestKeeper_SnapShotConditionalOrders(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, logger)
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	msg := NewNewOrderMsg(accAdd, "123456", Side.BUY, "XYZ-000_BNB", 1.2e8, 1000000)
+	msg.OrderType = OrderType.STOPLIMIT
+	msg.StopPrice = 1.1e8
+	info := OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}
+	keeper.addConditionalOrder(info, false)
+
+	_, err := keeper.SnapShotOrderBook(ctx, 43)
+	assert.Nil(err)
+	keeper.MarkBreatheBlock(ctx, 43, time.Now())
+	keeper2 := MakeKeeper(cdc)
+	h, err := keeper2.LoadOrderBookSnapshot(ctx, 43, utils.Now(), 0, 10)
+	assert.Nil(err)
+	assert.Equal(int64(43), h)
+	assert.Equal(0, len(keeper2.GetAllOrdersForPair("XYZ-000_BNB")))
+	loaded, ok := keeper2.ConditionalOrderExists("XYZ-000_BNB", "123456")
+	assert.True(ok)
+	assert.Equal(info, loaded)
+}
+
 func TestKeeper_LoadOrderBookSnapshot(t *testing.T) {
 	assert := assert.New(t)
 	cdc := MakeCodec()
@@ -509,6 +560,58 @@ func TestKeeper_ReplayOrdersFromBlockWithInvalidTx(t *testing.T) {
 	assert.Equal(int64(0), buys[0].Orders[0].CumQty)
 }
 
+func TestKeeper_ReplayMatch(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	memDB := db.NewMemDB()
+	blockStore, stateDB := GenerateBlocksAndSave(memDB, false, cdc)
+	logger := log.NewTMLogger(os.Stdout)
+	cms := MakeCMS(nil)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, logger)
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	blocks := make([]ReplayedBlock, 0)
+	err := keeper.ReplayMatch(ctx, blockStore, stateDB, auth.DefaultTxDecoder(cdc), 1, 3, 10, func(block ReplayedBlock) error {
+		blocks = append(blocks, block)
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(2, len(blocks))
+	assert.Equal(int64(2), blocks[0].Height)
+	assert.Equal(4, len(blocks[0].Trades))
+	assert.Equal(ReplayedTrade{"XYZ-000_BNB", 97000, 4000000, "123460", "123458", 5000000, 4000000, 0}, blocks[0].Trades[3])
+	assert.Equal(12, len(blocks[0].OrderChanges))
+	assert.Equal(ReplayedOrderChange{"XYZ-000_BNB", "123456", Ack, 0}, blocks[0].OrderChanges[0])
+	assert.Equal(ReplayedOrderChange{"XYZ-000_BNB", "123460", PartialFill, 4000000}, blocks[0].OrderChanges[10])
+	assert.Equal(ReplayedOrderChange{"XYZ-000_BNB", "123461", FullyFill, 5000000}, blocks[0].OrderChanges[11])
+	assert.Equal(2, len(blocks[0].Books["XYZ-000_BNB"]))
+
+	assert.Equal(int64(3), blocks[1].Height)
+	assert.Equal(1, len(blocks[1].Trades))
+	assert.Equal([]ReplayedOrderChange{
+		{"XYZ-000_BNB", "123463", Ack, 0},
+		{"XYZ-000_BNB", "123464", Ack, 0},
+		{"XYZ-000_BNB", "123465", Ack, 0},
+		{"XYZ-000_BNB", "123462", Canceled, 0},
+		{"XYZ-000_BNB", "123465", Canceled, 0},
+		{"XYZ-000_BNB", "123460", FullyFill, 5000000},
+		{"XYZ-000_BNB", "123464", PartialFill, 1000000},
+	}, blocks[1].OrderChanges)
+	assert.Equal(int64(97000), blocks[1].Books["XYZ-000_BNB"][0].BuyPrice.ToInt64())
+	assert.Equal(int64(98000), blocks[1].Books["XYZ-000_BNB"][0].SellPrice.ToInt64())
+
+	// can not replay across a breathe block
+	keeper2 := MakeKeeper(cdc)
+	keeper2.AddEngine(tradingPair)
+	_, err = keeper2.SnapShotOrderBook(ctx, 2)
+	assert.Nil(err)
+	err = keeper2.ReplayMatch(ctx, blockStore, stateDB, auth.DefaultTxDecoder(cdc), 1, 3, 10, func(ReplayedBlock) error { return nil })
+	assert.NotNil(err)
+}
+
 func TestKeeper_InitOrderBookDay1(t *testing.T) {
 	assert := assert.New(t)
 	cdc := MakeCodec()
@@ -610,6 +713,33 @@ func TestKeeper_ExpireOrders(t *testing.T) {
 	fees.Pool.Clear()
 }
 
+func TestKeeper_ExpireConditionalOrders(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	addr := acc.GetAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e6))
+	for _, id := range []string{"3", "1", "4", "2"} {
+		msg := NewNewOrderMsg(addr, id, Side.BUY, "ABC-000_BNB", 1e6, 1e6)
+		msg.OrderType, msg.StopPrice = OrderType.STOPLIMIT, 0.9e6
+		keeper.addConditionalOrder(OrderInfo{msg, 10000, 0, 10000, 0, 0, "", 0}, false)
+	}
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 4e4)})
+	am.SetAccount(ctx, acc)
+
+	breathTime, _ := time.Parse(time.RFC3339, "2018-01-02T00:00:01Z")
+	keeper.MarkBreatheBlock(ctx, 15000, breathTime)
+
+	// the transfers of the same sender are allocated in the order of the ids
+	var expired []string
+	keeper.ExpireOrders(ctx, breathTime.AddDate(0, 0, 3), func(tran Transfer) {
+		expired = append(expired, tran.Oid)
+	})
+	require.Equal(t, []string{"1", "2", "3", "4"}, expired)
+	require.Len(t, keeper.GetConditionalOrders()["ABC-000_BNB"], 0)
+	fees.Pool.Clear()
+}
+
 func TestKeeper_ExpireOrdersBasedOnPrice(t *testing.T) {
 	setChainVersion()
 	defer resetChainVersion()
@@ -658,6 +788,165 @@ func TestKeeper_ExpireOrdersBasedOnPrice(t *testing.T) {
 	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP67, 0)
 }
 
+func TestKeeper_ExpireGoodTillOrders(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 1e8)
+	addr := acc.GetAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e6))
+	pair := "ABC-000_BNB"
+	blockTime, _ := time.Parse(time.RFC3339, "2018-01-02T00:00:01Z")
+
+	goodTill := func(id string, side int8, price, qty, expireHeight, expireTime int64) OrderInfo {
+		msg := NewNewOrderMsg(addr, id, side, pair, price, qty)
+		msg.ExpireHeight, msg.ExpireTime = expireHeight, expireTime
+		return OrderInfo{msg, 90, 0, 90, 0, 0, "", 0}
+	}
+	keeper.AddOrder(goodTill("1", Side.BUY, 1e6, 1e6, 100, 0), false)
+	keeper.AddOrder(goodTill("2", Side.BUY, 2e6, 2e6, 0, blockTime.Unix()+60), false)
+	keeper.AddOrder(goodTill("3", Side.SELL, 1e7, 1e8, 0, 0), false)
+	keeper.AddOrder(goodTill("4", Side.SELL, 1e7, 1e8, 100, 0), false)
+	require.NoError(t, keeper.RemoveOrder("4", pair, nil))
+	conditional := goodTill("5", Side.SELL, 1e6, 1e8, 100, 0)
+	conditional.OrderType, conditional.StopPrice = OrderType.STOPLIMIT, 0.9e6
+	keeper.addConditionalOrder(conditional, false)
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{
+		sdk.NewCoin("ABC-000", 2e8),
+		sdk.NewCoin("BNB", 5e4),
+	}.Sort())
+	am.SetAccount(ctx, acc)
+	require.Len(t, keeper.goodTillOrders[pair], 4)
+
+	var expired []string
+	collect := func(tran Transfer) {
+		if tran.IsExpire() {
+			expired = append(expired, tran.Oid)
+		}
+	}
+	keeper.ExpireGoodTillOrders(ctx.WithBlockHeader(abci.Header{Height: 99, Time: blockTime}), collect)
+	require.Len(t, expired, 0)
+	require.Len(t, keeper.goodTillOrders[pair], 3)
+
+	keeper.ExpireGoodTillOrders(ctx.WithBlockHeader(abci.Header{Height: 100, Time: blockTime.Add(30 * time.Second)}), collect)
+	require.ElementsMatch(t, []string{"1", "5"}, expired)
+	require.Len(t, keeper.GetAllOrdersForPair(pair), 2)
+	_, ok := keeper.ConditionalOrderExists(pair, "5")
+	require.False(t, ok)
+	require.Equal(t, sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 4e4)}, sdk.FeeForProposer), fees.Pool.BlockFees())
+	fees.Pool.Clear()
+
+	expired = nil
+	keeper.ExpireGoodTillOrders(ctx.WithBlockHeader(abci.Header{Height: 101, Time: blockTime.Add(60 * time.Second)}), collect)
+	require.Equal(t, []string{"2"}, expired)
+	orders := keeper.GetAllOrdersForPair(pair)
+	require.Len(t, orders, 1)
+	require.Contains(t, orders, "3")
+	require.Len(t, keeper.goodTillOrders, 0)
+
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("ABC-000", 1e8)}, acc.(types.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.Coins{
+		sdk.NewCoin("ABC-000", 1e8),
+		sdk.NewCoin("BNB", 1e8+5e4-6e4),
+	}.Sort(), acc.GetCoins())
+	fees.Pool.Clear()
+}
+
+func TestKeeper_IcebergOrderBookLevels(t *testing.T) {
+	_, _, keeper := setup()
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e6))
+	pair := "ABC-000_BNB"
+	_, addr := testutils.PrivAndAddr()
+
+	iceberg := NewNewOrderMsg(addr, "1", Side.SELL, pair, 1e6, 5e8)
+	iceberg.DisplayQuantity = 1e8
+	keeper.AddOrder(OrderInfo{iceberg, 42, 0, 42, 0, 0, "", 0}, false)
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(addr, "2", Side.SELL, pair, 1e6, 2e8), 42, 0, 42, 0, 0, "", 0}, false)
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(addr, "3", Side.BUY, pair, 0.9e6, 3e8), 42, 0, 42, 0, 0, "", 0}, false)
+
+	// the hidden reserve is neither in the depth nor in the published order books
+	levels, _ := keeper.GetOrderBookLevels(pair, 1)
+	require.Equal(t, utils.Fixed8(3e8), levels[0].SellQty)
+	require.Equal(t, utils.Fixed8(3e8), levels[0].BuyQty)
+	books := keeper.GetOrderBooks(1)
+	require.Equal(t, map[int64]int64{1e6: 3e8}, books[pair].Sells)
+
+	pl := keeper.GetPriceLevel(pair, Side.SELL, 1e6)
+	require.Equal(t, int64(7e8), pl.TotalLeavesQty())
+	require.Equal(t, int64(1e8), pl.Orders[0].DisplayQty)
+}
+
+func TestKeeper_MatchContinuousOrder(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	fees.Pool.Clear()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, seller := testutils.NewAccount(ctx, am, 0)
+	_, buyer := testutils.NewAccount(ctx, am, 0)
+	tradingPair := dextypes.NewTradingPair("ABC-000", "BNB", 1e8)
+	tradingPair.MatchingMode = dextypes.MatchingModeContinuous
+	keeper.AddEngine(tradingPair)
+	pair := "ABC-000_BNB"
+	require.True(t, keeper.IsContinuous(pair))
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 42, Time: time.Unix(84, 0)})
+
+	seller.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("ABC-000", 1e8)})
+	am.SetAccount(ctx, seller)
+	buyer.(types.NamedAccount).SetLockedCoins(sdk.Coins{sdk.NewCoin("BNB", 5e8)})
+	am.SetAccount(ctx, buyer)
+
+	// the orders are matched on arrival in DeliverTx, the sell order rests as the maker
+	deliver := func(msg NewOrderMsg) {
+		ctx := ctx.WithValue(baseapp.TxHashKey, "tx"+msg.Id).WithRunTxMode(sdk.RunTxModeDeliver)
+		require.NoError(t, insertOrder(ctx, keeper, msg))
+	}
+	deliver(NewNewOrderMsg(seller.GetAddress(), "1", Side.SELL, pair, 1e8, 1e8))
+	require.Len(t, keeper.engines[pair].Trades, 0)
+
+	// a post-only buy order crossing the maker is rejected
+	msg := NewNewOrderMsg(buyer.GetAddress(), "2", Side.BUY, pair, 1e8, 1e8)
+	msg.TimeInForce = TimeInForce.POSTONLY
+	deliver(msg)
+	_, ok := keeper.OrderExists(pair, "2")
+	require.False(t, ok)
+	require.Len(t, keeper.engines[pair].Trades, 0)
+
+	// an IOC buy order takes the maker at its price, the rest is expired right away
+	msg = NewNewOrderMsg(buyer.GetAddress(), "3", Side.BUY, pair, 2e8, 2e8)
+	msg.TimeInForce = TimeInForce.IOC
+	deliver(msg)
+	trades, lastPx := keeper.GetLastTrades(42, pair)
+	require.Len(t, trades, 1)
+	require.Equal(t, int64(1e8), lastPx)
+	require.Equal(t, "3", trades[0].Bid)
+	require.Equal(t, "1", trades[0].Sid)
+	require.Equal(t, int8(me.BuyTaker), trades[0].TickType)
+	require.Len(t, keeper.GetAllOrdersForPair(pair), 0)
+	require.Empty(t, keeper.SelectSymbolsToMatch(42, true))
+
+	seller = am.GetAccount(ctx, seller.GetAddress())
+	require.Empty(t, seller.(types.NamedAccount).GetLockedCoins())
+	require.Equal(t, 1e8-trades[0].SellerFee.Tokens.AmountOf("BNB"), seller.GetCoins().AmountOf("BNB"))
+	buyer = am.GetAccount(ctx, buyer.GetAddress())
+	require.Empty(t, buyer.(types.NamedAccount).GetLockedCoins())
+	require.Equal(t, int64(1e8), buyer.GetCoins().AmountOf("ABC-000"))
+	require.Equal(t, int64(5e8-1e8), buyer.GetCoins().AmountOf("BNB")+trades[0].BuyerFee.Tokens.AmountOf("BNB"))
+
+	// the fee of the match is committed along with the tx, and is not committed again by the end of the block
+	require.True(t, fees.Pool.BlockFees().IsEmpty())
+	matchFee := fees.Pool.GetFee("tx3")
+	require.NotNil(t, matchFee)
+	require.Equal(t, matchFee.Tokens.AmountOf("BNB"),
+		trades[0].SellerFee.Tokens.AmountOf("BNB")+trades[0].BuyerFee.Tokens.AmountOf("BNB"))
+	fees.Pool.CommitFee("tx3")
+	keeper.MatchAndAllocateSymbols(ctx, nil, false)
+	require.Equal(t, matchFee.Tokens, fees.Pool.BlockFees().Tokens)
+	trades, _ = keeper.GetLastTrades(42, pair)
+	require.Len(t, trades, 1)
+	fees.Pool.Clear()
+}
+
 func TestKeeper_DetermineLotSize(t *testing.T) {
 	assert := assert.New(t)
 	ctx, _, keeper := setup()
@@ -865,6 +1154,187 @@ func addOrderAfterMatch(t *testing.T, symbol string) {
 	assert.Equal(0, len(res))
 }
 
+func TestKeeper_RejectPostOnlyTakers(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.PostOnlyUpgrade, -1)
+	defer resetChainVersion()
+	assert := assert.New(t)
+	keeper := initKeeper()
+	keeper.AddEngine(dextypes.NewTradingPair("NNB-123", "BNB", 100000000))
+	pair := "NNB-123_BNB"
+	keeper.engines[pair].LastMatchHeight = 42
+
+	// a resting sell order as the maker
+	msg := NewNewOrderMsg(zz, ZzAddr+"-0", Side.SELL, pair, 1000000000, 300000000)
+	keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false)
+	// a post-only buy order crossing the maker, which would be the taker
+	msg = NewNewOrderMsg(zc, ZcAddr+"-0", Side.BUY, pair, 1000000000, 100000000)
+	msg.TimeInForce = TimeInForce.POSTONLY
+	keeper.AddOrder(OrderInfo{msg, 43, 86, 43, 86, 0, "", 0}, false)
+	// a post-only buy order not crossing the book
+	msg = NewNewOrderMsg(zc, ZcAddr+"-1", Side.BUY, pair, 900000000, 100000000)
+	msg.TimeInForce = TimeInForce.POSTONLY
+	keeper.AddOrder(OrderInfo{msg, 43, 86, 43, 86, 0, "", 0}, false)
+
+	keeper.MatchSymbols(43, 86, false)
+	assert.Equal(0, len(keeper.engines[pair].Trades))
+	res := keeper.GetOpenOrders(pair, zc)
+	assert.Equal(1, len(res))
+	assert.Equal(ZcAddr+"-1", res[0].Id)
+	res = keeper.GetOpenOrders(pair, zz)
+	assert.Equal(1, len(res))
+	assert.Equal(utils.Fixed8(0), res[0].CumQty)
+
+	changes := keeper.GetAllOrderChanges()
+	assert.Equal(4, len(changes))
+	assert.Equal(OrderChange{ZcAddr + "-0", PostOnlyRejected, "", nil}, changes[3])
+}
+
+func TestKeeper_SelfTradePrevention(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer resetChainVersion()
+	assert := assert.New(t)
+	keeper := initKeeper()
+	keeper.AddEngine(dextypes.NewTradingPair("NNB-123", "BNB", 100000000))
+	pair := "NNB-123_BNB"
+	keeper.engines[pair].LastMatchHeight = 42
+
+	// resting sell orders of two senders
+	msg := NewNewOrderMsg(zc, ZcAddr+"-0", Side.SELL, pair, 1000000000, 100000000)
+	keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false)
+	msg = NewNewOrderMsg(zz, ZzAddr+"-0", Side.SELL, pair, 1000000000, 100000000)
+	keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false)
+	// a buy order which would trade with the older order of the same sender first
+	msg = NewNewOrderMsg(zc, ZcAddr+"-1", Side.BUY, pair, 1000000000, 200000000)
+	msg.SelfTradePrevention = SelfTradePrevention.CANCELOLDEST
+	keeper.AddOrder(OrderInfo{msg, 43, 86, 43, 86, 0, "", 0}, false)
+
+	keeper.MatchSymbols(43, 86, false)
+	trades := keeper.engines[pair].Trades
+	assert.Equal(1, len(trades))
+	assert.Equal(ZcAddr+"-1", trades[0].Bid)
+	assert.Equal(ZzAddr+"-0", trades[0].Sid)
+	res := keeper.GetOpenOrders(pair, zc)
+	assert.Equal(1, len(res))
+	assert.Equal(ZcAddr+"-1", res[0].Id)
+	assert.Equal(utils.Fixed8(100000000), res[0].CumQty)
+	assert.Equal(0, len(keeper.GetOpenOrders(pair, zz)))
+
+	changes := keeper.GetAllOrderChanges()
+	assert.Equal(4, len(changes))
+	assert.Equal(OrderChange{ZcAddr + "-0", SelfTradePrevented, "", nil}, changes[3])
+}
+
+func TestKeeper_SnapShotOrderOwners(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, logger)
+	accAdd, _ := MakeAddress()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	keeper.AddEngine(tradingPair)
+
+	msg := NewNewOrderMsg(accAdd, "123456", Side.BUY, "XYZ-000_BNB", 1e8, 1000000)
+	msg.SelfTradePrevention = SelfTradePrevention.CANCELNEWEST
+	keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false)
+
+	_, err := keeper.SnapShotOrderBook(ctx, 43)
+	assert.Nil(err)
+	keeper.MarkBreatheBlock(ctx, 43, time.Now())
+	keeper2 := MakeKeeper(cdc)
+	_, err = keeper2.LoadOrderBookSnapshot(ctx, 43, utils.Now(), 0, 10)
+	assert.Nil(err)
+	ord, err := keeper2.GetOrder("123456", "XYZ-000_BNB", Side.BUY, 1e8)
+	assert.Nil(err)
+	assert.Equal(string(accAdd), ord.Owner)
+	assert.Equal(SelfTradePrevention.CANCELNEWEST, ord.Stp)
+}
+
+func TestKeeper_TriggerConditionalOrders(t *testing.T) {
+	assert := assert.New(t)
+	keeper := initKeeper()
+	keeper.AddEngine(dextypes.NewTradingPair("NNB-123", "BNB", 1e8))
+	pair := "NNB-123_BNB"
+
+	addConditional := func(id string, orderType, side int8, price, stopPrice int64) {
+		msg := NewNewOrderMsg(zc, id, side, pair, price, 1e8)
+		msg.OrderType = orderType
+		msg.StopPrice = stopPrice
+		keeper.addConditionalOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false)
+	}
+	addConditional(ZcAddr+"-0", OrderType.STOPLIMIT, Side.BUY, 1.2e8, 1.1e8)
+	addConditional(ZcAddr+"-1", OrderType.STOPLIMIT, Side.SELL, 0.8e8, 0.9e8)
+	addConditional(ZcAddr+"-2", OrderType.TAKEPROFIT, Side.SELL, 1.05e8, 1.05e8)
+	addConditional(ZcAddr+"-3", OrderType.TAKEPROFIT, Side.BUY, 0.95e8, 0.95e8)
+	assert.Equal(4, len(keeper.GetAllOrderChanges()))
+	assert.Equal(4, len(keeper.GetAllOrderInfosForPub()))
+	keeper.ClearOrderChanges()
+
+	keeper.triggerConditionalOrders(43, 86, false)
+	assert.Equal(0, len(keeper.GetAllOrdersForPair(pair)))
+
+	keeper.engines[pair].LastTradePrice = 1.1e8
+	keeper.triggerConditionalOrders(44, 88, false)
+	orders := keeper.GetAllOrdersForPair(pair)
+	assert.Equal(2, len(orders))
+	assert.Equal(int64(45), orders[ZcAddr+"-0"].CreatedHeight)
+	assert.Equal(int64(88), orders[ZcAddr+"-0"].CreatedTimestamp)
+	assert.Equal(int64(45), orders[ZcAddr+"-2"].CreatedHeight)
+	_, ok := keeper.ConditionalOrderExists(pair, ZcAddr+"-0")
+	assert.False(ok)
+	_, ok = keeper.ConditionalOrderExists(pair, ZcAddr+"-1")
+	assert.True(ok)
+	_, ok = keeper.ConditionalOrderExists(pair, ZcAddr+"-3")
+	assert.True(ok)
+	// the triggered orders have been acked when they were placed
+	assert.Equal(OrderChanges{
+		{ZcAddr + "-0", Triggered, "", nil},
+		{ZcAddr + "-2", Triggered, "", nil},
+	}, keeper.GetAllOrderChanges())
+	assert.Equal(int64(45), keeper.GetAllOrderInfosForPub()[ZcAddr+"-0"].CreatedHeight)
+
+	keeper.engines[pair].LastTradePrice = 0.9e8
+	keeper.triggerConditionalOrders(45, 90, true)
+	assert.Equal(4, len(keeper.GetAllOrdersForPair(pair)))
+	assert.Equal(0, len(keeper.GetConditionalOrders()[pair]))
+
+	// an order failed to be put into the book keeps waiting for the trigger
+	addConditional(ZcAddr+"-1", OrderType.STOPLIMIT, Side.SELL, 0.8e8, 0.9e8)
+	keeper.triggerConditionalOrders(46, 92, false)
+	_, ok = keeper.ConditionalOrderExists(pair, ZcAddr+"-1")
+	assert.True(ok)
+	assert.Equal(4, len(keeper.GetAllOrdersForPair(pair)))
+	assert.Equal(2, len(keeper.GetAllOrderChanges()))
+}
+
+func TestKeeper_CancelConditionalOrder(t *testing.T) {
+	keeper := initKeeper()
+	keeper.AddEngine(dextypes.NewTradingPair("NNB-123", "BNB", 1e8))
+	pair := "NNB-123_BNB"
+	msg := NewNewOrderMsg(zc, ZcAddr+"-0", Side.BUY, pair, 1.2e8, 1e8)
+	msg.OrderType, msg.StopPrice = OrderType.STOPLIMIT, 1.1e8
+	info := OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}
+	keeper.addConditionalOrder(info, false)
+	require.Equal(t, OrderChanges{{ZcAddr + "-0", Ack, "", nil}}, keeper.GetAllOrderChanges())
+	require.Contains(t, keeper.GetAllOrderInfosForPub(), ZcAddr+"-0")
+
+	fee := sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 2e4)}, sdk.FeeForProposer)
+	require.NoError(t, removeCanceledOrder(keeper, info, fee, true))
+	_, ok := keeper.ConditionalOrderExists(pair, ZcAddr+"-0")
+	require.False(t, ok)
+	require.Equal(t, OrderChanges{
+		{ZcAddr + "-0", Ack, "", nil},
+		{ZcAddr + "-0", Canceled, fee.String(), nil},
+	}, keeper.GetAllOrderChanges())
+	require.Equal(t, fee, *keeper.RoundOrderFees[string(zc)])
+
+	require.Error(t, removeCanceledOrder(keeper, info, fee, true))
+	require.Len(t, keeper.GetAllOrderChanges(), 2)
+}
+
 func TestKeeper_DelistTradingPair(t *testing.T) {
 	assert := assert.New(t)
 	ctx, am, keeper := setup()
@@ -9,13 +9,20 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	txbuilder "github.com/cosmos/cosmos-sdk/x/auth/client/txbuilder"
 
+	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/plugins/dex/matcheng"
 	"github.com/bnb-chain/node/plugins/dex/types"
 )
 
 const (
-	RouteNewOrder    = "orderNew"
-	RouteCancelOrder = "orderCancel"
+	RouteNewOrder         = "orderNew"
+	RouteCancelOrder      = "orderCancel"
+	RouteReplaceOrder     = "orderReplace"
+	RouteBatchNewOrder    = "orderBatchNew"
+	RouteBatchCancelOrder = "orderBatchCancel"
+
+	// MaxOrdersInBatch is the max number of orders a BatchNewOrderMsg or BatchCancelOrderMsg can carry
+	MaxOrdersInBatch = 100
 )
 
 // Side/TimeInForce/OrderType are const, following FIX protocol convention
@@ -36,6 +43,12 @@ func GenerateOrderID(sequence int64, addr sdk.AccAddress) string {
 	return id
 }
 
+// GenerateBatchOrderID generates the ID of the order at `index` of a BatchNewOrderMsg,
+// so that all the orders in the batch share one sequence
+func GenerateBatchOrderID(sequence int64, index int, addr sdk.AccAddress) string {
+	return fmt.Sprintf("%X-%d-%d", addr, sequence, index)
+}
+
 // IsValidSide validates that a side is valid and supported by the matching engine
 func IsValidSide(side int8) bool {
 	switch side {
@@ -56,43 +69,78 @@ func SideStringToSideCode(side string) (int8, error) {
 }
 
 const (
-	_           int8 = iota
-	orderMarket int8 = iota
-	orderLimit  int8 = iota
+	_               int8 = iota
+	orderMarket     int8 = iota
+	orderLimit      int8 = iota
+	orderStopLimit  int8 = iota
+	orderTakeProfit int8 = iota
 )
 
-// OrderType is an enum of order type options supported by the matching engine
+// OrderType is an enum of order type options supported by the matching engine.
+// STOPLIMIT and TAKEPROFIT are conditional limit orders, which are held outside of the order book
+// until the last trade price of the pair reaches their StopPrice.
 var OrderType = struct {
-	LIMIT  int8
-	MARKET int8
-}{orderLimit, orderMarket}
+	LIMIT      int8
+	MARKET     int8
+	STOPLIMIT  int8
+	TAKEPROFIT int8
+}{orderLimit, orderMarket, orderStopLimit, orderTakeProfit}
+
+var orderTypeNames = map[string]int8{
+	"LIMIT":      orderLimit,
+	"MARKET":     orderMarket,
+	"STOPLIMIT":  orderStopLimit,
+	"TAKEPROFIT": orderTakeProfit,
+}
 
 // IsValidOrderType validates that an order type is valid and supported by the matching engine
 func IsValidOrderType(ot int8) bool {
 	switch ot {
-	case OrderType.LIMIT: // only allow LIMIT for now.
+	case OrderType.LIMIT:
 		return true
+	case OrderType.MARKET:
+		return sdk.IsUpgrade(upgrade.MarketOrderUpgrade)
+	case OrderType.STOPLIMIT, OrderType.TAKEPROFIT:
+		return sdk.IsUpgrade(upgrade.ConditionalOrderUpgrade)
 	default:
 		return false
 	}
 }
 
+// IsConditionalOrderType returns true if orders of this type wait for a trigger before entering the order book
+func IsConditionalOrderType(ot int8) bool {
+	return ot == OrderType.STOPLIMIT || ot == OrderType.TAKEPROFIT
+}
+
+// OrderTypeStringToOrderTypeCode converts a string like "LIMIT" to its internal order type code
+func OrderTypeStringToOrderTypeCode(ot string) (int8, error) {
+	upperOt := strings.ToUpper(ot)
+	if val, ok := orderTypeNames[upperOt]; ok {
+		return val, nil
+	}
+	return -1, errors.New("order type `" + upperOt + "` not found or supported")
+}
+
 const (
-	_      int8 = iota
-	tifGTE int8 = iota
-	_      int8 = iota
-	tifIOC int8 = iota
+	_           int8 = iota
+	tifGTE      int8 = iota
+	_           int8 = iota
+	tifIOC      int8 = iota
+	tifPostOnly int8 = iota
 )
 
-// TimeInForce is an enum of TIF (Time in Force) options supported by the matching engine
+// TimeInForce is an enum of TIF (Time in Force) options supported by the matching engine.
+// POSTONLY orders behave like GTE orders, but are rejected rather than being filled as the taker side.
 var TimeInForce = struct {
-	GTE int8
-	IOC int8
-}{tifGTE, tifIOC}
+	GTE      int8
+	IOC      int8
+	POSTONLY int8
+}{tifGTE, tifIOC, tifPostOnly}
 
 var timeInForceNames = map[string]int8{
-	"GTE": tifGTE,
-	"IOC": tifIOC,
+	"GTE":      tifGTE,
+	"IOC":      tifIOC,
+	"POSTONLY": tifPostOnly,
 }
 
 // IsValidTimeInForce validates that a tif code is correct
@@ -100,6 +148,8 @@ func IsValidTimeInForce(tif int8) bool {
 	switch tif {
 	case TimeInForce.GTE, TimeInForce.IOC:
 		return true
+	case TimeInForce.POSTONLY:
+		return sdk.IsUpgrade(upgrade.PostOnlyUpgrade)
 	default:
 		return false
 	}
@@ -114,6 +164,43 @@ func TifStringToTifCode(tif string) (int8, error) {
 	return -1, errors.New("tif `" + upperTif + "` not found or supported")
 }
 
+// SelfTradePrevention is an enum of the self-trade prevention modes. When an order would trade with another order
+// of the same sender, the mode of the newer one decides the orders to cancel, and NONE lets them trade.
+var SelfTradePrevention = struct {
+	NONE         int8
+	CANCELNEWEST int8
+	CANCELOLDEST int8
+	CANCELBOTH   int8
+}{matcheng.StpNone, matcheng.StpCancelNewest, matcheng.StpCancelOldest, matcheng.StpCancelBoth}
+
+var selfTradePreventionNames = map[string]int8{
+	"NONE":         matcheng.StpNone,
+	"CANCELNEWEST": matcheng.StpCancelNewest,
+	"CANCELOLDEST": matcheng.StpCancelOldest,
+	"CANCELBOTH":   matcheng.StpCancelBoth,
+}
+
+// IsValidSelfTradePrevention validates that a self-trade prevention mode is supported by the matching engine
+func IsValidSelfTradePrevention(stp int8) bool {
+	switch stp {
+	case SelfTradePrevention.NONE:
+		return true
+	case SelfTradePrevention.CANCELNEWEST, SelfTradePrevention.CANCELOLDEST, SelfTradePrevention.CANCELBOTH:
+		return sdk.IsUpgrade(upgrade.SelfTradePreventionUpgrade)
+	default:
+		return false
+	}
+}
+
+// StpStringToStpCode converts a string like "CANCELNEWEST" to its internal self-trade prevention code
+func StpStringToStpCode(stp string) (int8, error) {
+	upperStp := strings.ToUpper(stp)
+	if val, ok := selfTradePreventionNames[upperStp]; ok {
+		return val, nil
+	}
+	return -1, errors.New("self-trade prevention `" + upperStp + "` not found or supported")
+}
+
 var _ sdk.Msg = NewOrderMsg{}
 
 type NewOrderMsg struct {
@@ -125,6 +212,14 @@ type NewOrderMsg struct {
 	Price       int64          `json:"price"`
 	Quantity    int64          `json:"quantity"`
 	TimeInForce int8           `json:"timeinforce"`
+	StopPrice   int64          `json:"stopprice,omitempty"` // trigger price of conditional orders
+
+	SelfTradePrevention int8 `json:"stp,omitempty"` // self-trade prevention mode, default to NONE
+
+	ExpireHeight int64 `json:"expireheight,omitempty"` // good-till-block, the order expires after the match of this height
+	ExpireTime   int64 `json:"expiretime,omitempty"`   // good-till-time in unix seconds, the order expires after the match of the first block at or after it
+
+	DisplayQuantity int64 `json:"displayqty,omitempty"` // iceberg, the quantity shown in the order book at a time, 0 to show all
 }
 
 // NewNewOrderMsg constructs a new NewOrderMsg
@@ -242,22 +337,71 @@ func (msg NewOrderMsg) ValidateBasic() sdk.Error {
 	if msg.Quantity <= 0 {
 		return types.ErrInvalidOrderParam("Quantity", fmt.Sprintf("Zero/Negative Number:%d", msg.Quantity))
 	}
-	if msg.Price <= 0 {
-		return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Zero/Negative Number:%d", msg.Price))
-	}
 	if !IsValidOrderType(msg.OrderType) {
 		return types.ErrInvalidOrderParam("OrderType", fmt.Sprintf("Invalid order type:%d", msg.OrderType))
 	}
+	if msg.OrderType == OrderType.MARKET {
+		// market orders are priced by the protection band of the match engine and never rest on the book
+		if msg.Price != 0 {
+			return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Market order should not have a price:%d", msg.Price))
+		}
+		if msg.TimeInForce != TimeInForce.IOC {
+			return types.ErrInvalidOrderParam("TimeInForce", fmt.Sprintf("Market order only supports IOC:%d", msg.TimeInForce))
+		}
+	} else if msg.Price <= 0 {
+		return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Zero/Negative Number:%d", msg.Price))
+	}
+	if IsConditionalOrderType(msg.OrderType) {
+		if msg.StopPrice <= 0 {
+			return types.ErrInvalidOrderParam("StopPrice", fmt.Sprintf("Zero/Negative Number:%d", msg.StopPrice))
+		}
+	} else if msg.StopPrice != 0 {
+		return types.ErrInvalidOrderParam("StopPrice", fmt.Sprintf("Only conditional orders can have a stop price:%d", msg.StopPrice))
+	}
 	if !IsValidSide(msg.Side) {
 		return types.ErrInvalidOrderParam("Side", fmt.Sprintf("Invalid side:%d", msg.Side))
 	}
 	if !IsValidTimeInForce(msg.TimeInForce) {
 		return types.ErrInvalidOrderParam("TimeInForce", fmt.Sprintf("Invalid TimeInForce:%d", msg.TimeInForce))
 	}
+	if !IsValidSelfTradePrevention(msg.SelfTradePrevention) {
+		return types.ErrInvalidOrderParam("SelfTradePrevention", fmt.Sprintf("Invalid SelfTradePrevention:%d", msg.SelfTradePrevention))
+	}
+	if msg.ExpireHeight < 0 {
+		return types.ErrInvalidOrderParam("ExpireHeight", fmt.Sprintf("Negative Number:%d", msg.ExpireHeight))
+	}
+	if msg.ExpireTime < 0 {
+		return types.ErrInvalidOrderParam("ExpireTime", fmt.Sprintf("Negative Number:%d", msg.ExpireTime))
+	}
+	if msg.IsGoodTill() && !sdk.IsUpgrade(upgrade.GoodTillOrderUpgrade) {
+		return types.ErrInvalidOrderParam("ExpireHeight", "Expire height or time is not supported yet")
+	}
+	if msg.IsGoodTill() && msg.TimeInForce == TimeInForce.IOC {
+		return types.ErrInvalidOrderParam("TimeInForce", "IOC order can not have an expire height or time")
+	}
+	if msg.DisplayQuantity < 0 || msg.DisplayQuantity > msg.Quantity {
+		return types.ErrInvalidOrderParam("DisplayQuantity", fmt.Sprintf("Should be within [0, Quantity]:%d", msg.DisplayQuantity))
+	}
+	if msg.DisplayQuantity != 0 && !sdk.IsUpgrade(upgrade.IcebergOrderUpgrade) {
+		return types.ErrInvalidOrderParam("DisplayQuantity", "Display quantity is not supported yet")
+	}
+	if msg.IsIceberg() && msg.TimeInForce == TimeInForce.IOC {
+		return types.ErrInvalidOrderParam("TimeInForce", "IOC order can not have a display quantity")
+	}
 
 	return nil
 }
 
+// IsGoodTill returns true if the order expires at a given height or time besides the daily expiry of GTE orders
+func (msg NewOrderMsg) IsGoodTill() bool {
+	return msg.ExpireHeight > 0 || msg.ExpireTime > 0
+}
+
+// IsIceberg returns true if only a slice of the order is shown in the order book at a time
+func (msg NewOrderMsg) IsIceberg() bool {
+	return msg.DisplayQuantity > 0 && msg.DisplayQuantity < msg.Quantity
+}
+
 // ValidateBasic is used to quickly disqualify obviously invalid messages quickly
 func (msg CancelOrderMsg) ValidateBasic() sdk.Error {
 	if len(msg.Sender) == 0 {
@@ -268,3 +412,276 @@ func (msg CancelOrderMsg) ValidateBasic() sdk.Error {
 	}
 	return nil
 }
+
+var _ sdk.Msg = ReplaceOrderMsg{}
+
+// ReplaceOrderMsg represents a message to cancel an open order and place a new one in the same tx.
+// The new order inherits the side, type and time in force of the replaced order.
+type ReplaceOrderMsg struct {
+	Sender   sdk.AccAddress `json:"sender"`
+	Id       string         `json:"id"`
+	Symbol   string         `json:"symbol"`
+	RefId    string         `json:"refid"`
+	Price    int64          `json:"price"`
+	Quantity int64          `json:"quantity"`
+}
+
+// NewReplaceOrderMsg constructs a new ReplaceOrderMsg
+func NewReplaceOrderMsg(sender sdk.AccAddress, id, symbol, refId string, price, qty int64) ReplaceOrderMsg {
+	return ReplaceOrderMsg{
+		Sender:   sender,
+		Id:       id,
+		Symbol:   symbol,
+		RefId:    refId,
+		Price:    price,
+		Quantity: qty,
+	}
+}
+
+// nolint
+func (msg ReplaceOrderMsg) Route() string { return RouteReplaceOrder }
+
+// Type is the same as NewOrderMsg, so the replace is charged like a new order before handling,
+// and the cancel fee of the replaced order is charged by the handler.
+func (msg ReplaceOrderMsg) Type() string                 { return RouteNewOrder }
+func (msg ReplaceOrderMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.Sender} }
+func (msg ReplaceOrderMsg) String() string {
+	return fmt.Sprintf("ReplaceOrderMsg{Sender: %v, Id: %v, Symbol: %v, RefId: %s}", msg.Sender, msg.Id, msg.Symbol, msg.RefId)
+}
+
+// GetSignBytes - Get the bytes for the message signer to sign on
+func (msg ReplaceOrderMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg ReplaceOrderMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
+
+// ValidateBasic is used to quickly disqualify obviously invalid messages quickly
+func (msg ReplaceOrderMsg) ValidateBasic() sdk.Error {
+	if len(msg.Id) == 0 || !strings.Contains(msg.Id, "-") {
+		return types.ErrInvalidOrderParam("Id", fmt.Sprintf("Invalid order ID:%s", msg.Id))
+	}
+	if len(msg.Sender) == 0 {
+		return sdk.ErrUnknownAddress(msg.Sender.String()).TraceSDK("")
+	}
+	if len(msg.RefId) == 0 || !strings.Contains(msg.RefId, "-") {
+		return types.ErrInvalidOrderParam("RefId", fmt.Sprintf("Invalid ref ID:%s", msg.RefId))
+	}
+	if msg.Price <= 0 {
+		return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Zero/Negative Number:%d", msg.Price))
+	}
+	if msg.Quantity <= 0 {
+		return types.ErrInvalidOrderParam("Quantity", fmt.Sprintf("Zero/Negative Number:%d", msg.Quantity))
+	}
+	return nil
+}
+
+// newOrderMsg builds the order placed by the replace from the order being replaced
+func (msg ReplaceOrderMsg) newOrderMsg(orig OrderInfo) NewOrderMsg {
+	return NewOrderMsg{
+		Sender:      msg.Sender,
+		Id:          msg.Id,
+		Symbol:      orig.Symbol,
+		OrderType:   orig.OrderType,
+		Side:        orig.Side,
+		Price:       msg.Price,
+		Quantity:    msg.Quantity,
+		TimeInForce: orig.TimeInForce,
+		StopPrice:   orig.StopPrice,
+
+		SelfTradePrevention: orig.SelfTradePrevention,
+
+		ExpireHeight: orig.ExpireHeight,
+		ExpireTime:   orig.ExpireTime,
+
+		DisplayQuantity: orig.DisplayQuantity,
+	}
+}
+
+var _ sdk.Msg = BatchNewOrderMsg{}
+
+// BatchOrder is an order carried by BatchNewOrderMsg, which is placed the same as a NewOrderMsg from the batch sender
+type BatchOrder struct {
+	Id          string `json:"id"`
+	Symbol      string `json:"symbol"`
+	OrderType   int8   `json:"ordertype"`
+	Side        int8   `json:"side"`
+	Price       int64  `json:"price"`
+	Quantity    int64  `json:"quantity"`
+	TimeInForce int8   `json:"timeinforce"`
+	StopPrice   int64  `json:"stopprice,omitempty"`
+
+	SelfTradePrevention int8 `json:"stp,omitempty"`
+
+	ExpireHeight int64 `json:"expireheight,omitempty"`
+	ExpireTime   int64 `json:"expiretime,omitempty"`
+
+	DisplayQuantity int64 `json:"displayqty,omitempty"`
+}
+
+// BatchNewOrderMsg represents a message to place up to MaxOrdersInBatch orders across symbols in one tx.
+// The orders are placed in sequence, and the tx fails as a whole if any of them can not be placed.
+type BatchNewOrderMsg struct {
+	Sender sdk.AccAddress `json:"sender"`
+	Orders []BatchOrder   `json:"orders"`
+}
+
+// NewBatchNewOrderMsg constructs a new BatchNewOrderMsg
+func NewBatchNewOrderMsg(sender sdk.AccAddress, orders []BatchOrder) BatchNewOrderMsg {
+	return BatchNewOrderMsg{
+		Sender: sender,
+		Orders: orders,
+	}
+}
+
+// nolint
+func (msg BatchNewOrderMsg) Route() string { return RouteBatchNewOrder }
+
+// Type is the route of the batch, the batch is charged the fee of a NewOrderMsg per order before handling
+func (msg BatchNewOrderMsg) Type() string                 { return RouteBatchNewOrder }
+func (msg BatchNewOrderMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.Sender} }
+func (msg BatchNewOrderMsg) String() string {
+	return fmt.Sprintf("BatchNewOrderMsg{Sender: %v, Orders: %d}", msg.Sender, len(msg.Orders))
+}
+
+// GetSignBytes - Get the bytes for the message signer to sign on
+func (msg BatchNewOrderMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg BatchNewOrderMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
+
+// ValidateBasic is used to quickly disqualify obviously invalid messages quickly
+func (msg BatchNewOrderMsg) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrUnknownAddress(msg.Sender.String()).TraceSDK("")
+	}
+	if len(msg.Orders) == 0 || len(msg.Orders) > MaxOrdersInBatch {
+		return types.ErrInvalidOrderParam("Orders", fmt.Sprintf("Number of orders should be between 1 and %d:%d", MaxOrdersInBatch, len(msg.Orders)))
+	}
+	ids := make(map[string]struct{}, len(msg.Orders))
+	for _, orderMsg := range msg.NewOrderMsgs() {
+		if err := orderMsg.ValidateBasic(); err != nil {
+			return err
+		}
+		if _, ok := ids[orderMsg.Id]; ok {
+			return types.ErrInvalidOrderParam("Id", fmt.Sprintf("Duplicated order ID:%s", orderMsg.Id))
+		}
+		ids[orderMsg.Id] = struct{}{}
+	}
+	return nil
+}
+
+// NewOrderMsgs converts the orders in the batch to NewOrderMsgs of the batch sender
+func (msg BatchNewOrderMsg) NewOrderMsgs() []NewOrderMsg {
+	msgs := make([]NewOrderMsg, len(msg.Orders))
+	for i, o := range msg.Orders {
+		msgs[i] = NewOrderMsg{
+			Sender:      msg.Sender,
+			Id:          o.Id,
+			Symbol:      o.Symbol,
+			OrderType:   o.OrderType,
+			Side:        o.Side,
+			Price:       o.Price,
+			Quantity:    o.Quantity,
+			TimeInForce: o.TimeInForce,
+			StopPrice:   o.StopPrice,
+
+			SelfTradePrevention: o.SelfTradePrevention,
+
+			ExpireHeight: o.ExpireHeight,
+			ExpireTime:   o.ExpireTime,
+
+			DisplayQuantity: o.DisplayQuantity,
+		}
+	}
+	return msgs
+}
+
+var _ sdk.Msg = BatchCancelOrderMsg{}
+
+// BatchCancel refers to an order to be canceled by BatchCancelOrderMsg
+type BatchCancel struct {
+	Symbol string `json:"symbol"`
+	RefId  string `json:"refid"`
+}
+
+// BatchCancelOrderMsg represents a message to cancel up to MaxOrdersInBatch open orders across symbols in one tx.
+// The tx fails as a whole if any of the orders can not be canceled.
+type BatchCancelOrderMsg struct {
+	Sender  sdk.AccAddress `json:"sender"`
+	Cancels []BatchCancel  `json:"cancels"`
+}
+
+// NewBatchCancelOrderMsg constructs a new BatchCancelOrderMsg
+func NewBatchCancelOrderMsg(sender sdk.AccAddress, cancels []BatchCancel) BatchCancelOrderMsg {
+	return BatchCancelOrderMsg{
+		Sender:  sender,
+		Cancels: cancels,
+	}
+}
+
+// nolint
+func (msg BatchCancelOrderMsg) Route() string { return RouteBatchCancelOrder }
+
+// Type is the route of the batch, the batch is charged the fee of a CancelOrderMsg per order before handling
+func (msg BatchCancelOrderMsg) Type() string                 { return RouteBatchCancelOrder }
+func (msg BatchCancelOrderMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.Sender} }
+func (msg BatchCancelOrderMsg) String() string {
+	return fmt.Sprintf("BatchCancelOrderMsg{Sender: %v, Cancels: %d}", msg.Sender, len(msg.Cancels))
+}
+
+// GetSignBytes - Get the bytes for the message signer to sign on
+func (msg BatchCancelOrderMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg BatchCancelOrderMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
+
+// ValidateBasic is used to quickly disqualify obviously invalid messages quickly
+func (msg BatchCancelOrderMsg) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrUnknownAddress(msg.Sender.String()).TraceSDK("")
+	}
+	if len(msg.Cancels) == 0 || len(msg.Cancels) > MaxOrdersInBatch {
+		return types.ErrInvalidOrderParam("Cancels", fmt.Sprintf("Number of orders should be between 1 and %d:%d", MaxOrdersInBatch, len(msg.Cancels)))
+	}
+	refIds := make(map[string]struct{}, len(msg.Cancels))
+	for _, cancelMsg := range msg.CancelOrderMsgs() {
+		if err := cancelMsg.ValidateBasic(); err != nil {
+			return err
+		}
+		if _, ok := refIds[cancelMsg.RefId]; ok {
+			return types.ErrInvalidOrderParam("RefId", fmt.Sprintf("Duplicated ref ID:%s", cancelMsg.RefId))
+		}
+		refIds[cancelMsg.RefId] = struct{}{}
+	}
+	return nil
+}
+
+// CancelOrderMsgs converts the cancels in the batch to CancelOrderMsgs of the batch sender
+func (msg BatchCancelOrderMsg) CancelOrderMsgs() []CancelOrderMsg {
+	msgs := make([]CancelOrderMsg, len(msg.Cancels))
+	for i, c := range msg.Cancels {
+		msgs[i] = NewCancelOrderMsg(msg.Sender, c.Symbol, c.RefId)
+	}
+	return msgs
+}
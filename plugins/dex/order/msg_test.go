@@ -2,6 +2,7 @@ package order
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"testing"
 
@@ -17,6 +18,7 @@ import (
 
 	cmn "github.com/bnb-chain/node/common"
 	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func newCLIContext() context.CLIContext {
@@ -40,10 +42,19 @@ func TestIsValidSide(t *testing.T) {
 
 func TestIsValidOrderType(t *testing.T) {
 	assert := assert.New(t)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.MarketOrderUpgrade, math.MaxInt64)
 	assert.False(IsValidOrderType(1))
+	upgrade.Mgr.AddUpgradeHeight(upgrade.MarketOrderUpgrade, -1)
+	assert.True(IsValidOrderType(1))
 	assert.True(IsValidOrderType(2))
-	assert.False(IsValidOrderType(0))
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ConditionalOrderUpgrade, math.MaxInt64)
 	assert.False(IsValidOrderType(3))
+	assert.False(IsValidOrderType(4))
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ConditionalOrderUpgrade, -1)
+	assert.True(IsValidOrderType(3))
+	assert.True(IsValidOrderType(4))
+	assert.False(IsValidOrderType(0))
+	assert.False(IsValidOrderType(5))
 }
 
 func TestIsValidTimeInForce(t *testing.T) {
@@ -52,10 +63,17 @@ func TestIsValidTimeInForce(t *testing.T) {
 	assert.False(IsValidTimeInForce(2))
 	assert.False(IsValidTimeInForce(0))
 	assert.True(IsValidTimeInForce(3))
+	upgrade.Mgr.AddUpgradeHeight(upgrade.PostOnlyUpgrade, math.MaxInt64)
+	assert.False(IsValidTimeInForce(4))
+	upgrade.Mgr.AddUpgradeHeight(upgrade.PostOnlyUpgrade, -1)
+	assert.True(IsValidTimeInForce(4))
+	assert.False(IsValidTimeInForce(5))
 }
 
 func TestNewOrderMsg_ValidateBasic(t *testing.T) {
 	assert := assert.New(t)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.MarketOrderUpgrade, -1)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ConditionalOrderUpgrade, -1)
 	_, acct := testutils.PrivAndAddr()
 	msg := NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
 	assert.Nil(msg.ValidateBasic())
@@ -68,6 +86,82 @@ func TestNewOrderMsg_ValidateBasic(t *testing.T) {
 	msg = NewNewOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", 355, 10)
 	msg.TimeInForce = 5
 	assert.Regexp(regexp.MustCompile(".*Invalid TimeInForce.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 0, 100)
+	msg.OrderType = OrderType.MARKET
+	msg.TimeInForce = TimeInForce.IOC
+	assert.Nil(msg.ValidateBasic())
+	msg.Price = 355
+	assert.Regexp(regexp.MustCompile(".*Market order should not have a price.*"), msg.ValidateBasic().Error())
+	msg.Price = 0
+	msg.TimeInForce = TimeInForce.GTE
+	assert.Regexp(regexp.MustCompile(".*Market order only supports IOC.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.StopPrice = 300
+	assert.Regexp(regexp.MustCompile(".*Only conditional orders can have a stop price.*"), msg.ValidateBasic().Error())
+	msg.OrderType = OrderType.STOPLIMIT
+	assert.Nil(msg.ValidateBasic())
+	msg.StopPrice = 0
+	assert.Regexp(regexp.MustCompile(".*StopPrice.*Zero/Negative Number.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.ExpireHeight = 100
+	msg.ExpireTime = 1600000000
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GoodTillOrderUpgrade, math.MaxInt64)
+	assert.Regexp(regexp.MustCompile(".*Expire height or time is not supported yet.*"), msg.ValidateBasic().Error())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.GoodTillOrderUpgrade, -1)
+	assert.Nil(msg.ValidateBasic())
+	assert.True(msg.IsGoodTill())
+	msg.ExpireHeight = -1
+	assert.Regexp(regexp.MustCompile(".*ExpireHeight.*Negative Number.*"), msg.ValidateBasic().Error())
+	msg.ExpireHeight = 0
+	msg.TimeInForce = TimeInForce.IOC
+	assert.Regexp(regexp.MustCompile(".*IOC order can not have an expire height or time.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.DisplayQuantity = 10
+	upgrade.Mgr.AddUpgradeHeight(upgrade.IcebergOrderUpgrade, math.MaxInt64)
+	assert.Regexp(regexp.MustCompile(".*Display quantity is not supported yet.*"), msg.ValidateBasic().Error())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.IcebergOrderUpgrade, -1)
+	assert.Nil(msg.ValidateBasic())
+	assert.True(msg.IsIceberg())
+	msg.DisplayQuantity = 100
+	assert.Nil(msg.ValidateBasic())
+	assert.False(msg.IsIceberg())
+	msg.DisplayQuantity = 101
+	assert.Regexp(regexp.MustCompile(".*DisplayQuantity.*"), msg.ValidateBasic().Error())
+	msg.DisplayQuantity = 10
+	msg.TimeInForce = TimeInForce.IOC
+	assert.Regexp(regexp.MustCompile(".*IOC order can not have a display quantity.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.SelfTradePrevention = SelfTradePrevention.CANCELBOTH
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SelfTradePreventionUpgrade, math.MaxInt64)
+	assert.Regexp(regexp.MustCompile(".*Invalid SelfTradePrevention.*"), msg.ValidateBasic().Error())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SelfTradePreventionUpgrade, -1)
+	assert.Nil(msg.ValidateBasic())
+	msg.SelfTradePrevention = 4
+	assert.Regexp(regexp.MustCompile(".*Invalid SelfTradePrevention.*"), msg.ValidateBasic().Error())
+}
+
+func TestStpStringToStpCode(t *testing.T) {
+	assert := assert.New(t)
+	stp, err := StpStringToStpCode("cancelNewest")
+	assert.Nil(err)
+	assert.Equal(SelfTradePrevention.CANCELNEWEST, stp)
+	stp, err = StpStringToStpCode("NONE")
+	assert.Nil(err)
+	assert.Equal(SelfTradePrevention.NONE, stp)
+	_, err = StpStringToStpCode("cancel")
+	assert.NotNil(err)
+}
+
+func TestOrderTypeStringToOrderTypeCode(t *testing.T) {
+	assert := assert.New(t)
+	ot, err := OrderTypeStringToOrderTypeCode("limit")
+	assert.Nil(err)
+	assert.Equal(OrderType.LIMIT, ot)
+	ot, err = OrderTypeStringToOrderTypeCode("MARKET")
+	assert.Nil(err)
+	assert.Equal(OrderType.MARKET, ot)
+	_, err = OrderTypeStringToOrderTypeCode("stop")
+	assert.NotNil(err)
 }
 
 func TestCancelOrderMsg_ValidateBasic(t *testing.T) {
@@ -76,6 +170,65 @@ func TestCancelOrderMsg_ValidateBasic(t *testing.T) {
 	assert.NotNil(msg.ValidateBasic())
 }
 
+func TestReplaceOrderMsg_ValidateBasic(t *testing.T) {
+	assert := assert.New(t)
+	addr := sdk.AccAddress("testaddr")
+	msg := NewReplaceOrderMsg(addr, "addr-2", "XYZ_BNB", "addr-1", 1e8, 1e8)
+	assert.Nil(msg.ValidateBasic())
+	msg = NewReplaceOrderMsg(sdk.AccAddress{}, "addr-2", "XYZ_BNB", "addr-1", 1e8, 1e8)
+	assert.NotNil(msg.ValidateBasic())
+	msg = NewReplaceOrderMsg(addr, "addr-2", "XYZ_BNB", "order1", 1e8, 1e8)
+	assert.NotNil(msg.ValidateBasic())
+	msg = NewReplaceOrderMsg(addr, "addr-2", "XYZ_BNB", "addr-1", 0, 1e8)
+	assert.NotNil(msg.ValidateBasic())
+	msg = NewReplaceOrderMsg(addr, "addr-2", "XYZ_BNB", "addr-1", 1e8, -1)
+	assert.NotNil(msg.ValidateBasic())
+}
+
+func TestBatchNewOrderMsg_ValidateBasic(t *testing.T) {
+	assert := assert.New(t)
+	addr := sdk.AccAddress("testaddr")
+	order := func(id string) BatchOrder {
+		return BatchOrder{id, "XYZ_BNB", OrderType.LIMIT, Side.BUY, 1e8, 1e8, TimeInForce.GTE, 0, 0, 0, 0, 0}
+	}
+	msg := NewBatchNewOrderMsg(addr, []BatchOrder{order("addr-1-0"), order("addr-1-1")})
+	assert.Nil(msg.ValidateBasic())
+	assert.Equal(2, len(msg.NewOrderMsgs()))
+	assert.Equal(addr, msg.NewOrderMsgs()[1].Sender)
+	msg = NewBatchNewOrderMsg(sdk.AccAddress{}, []BatchOrder{order("addr-1-0")})
+	assert.NotNil(msg.ValidateBasic())
+	msg = NewBatchNewOrderMsg(addr, []BatchOrder{})
+	assert.NotNil(msg.ValidateBasic())
+	msg = NewBatchNewOrderMsg(addr, []BatchOrder{order("addr-1-0"), order("addr-1-0")})
+	assert.NotNil(msg.ValidateBasic())
+	invalid := order("addr-1-1")
+	invalid.Quantity = 0
+	msg = NewBatchNewOrderMsg(addr, []BatchOrder{order("addr-1-0"), invalid})
+	assert.NotNil(msg.ValidateBasic())
+	orders := make([]BatchOrder, MaxOrdersInBatch+1)
+	for i := range orders {
+		orders[i] = order(GenerateBatchOrderID(1, i, addr))
+	}
+	msg = NewBatchNewOrderMsg(addr, orders)
+	assert.NotNil(msg.ValidateBasic())
+}
+
+func TestBatchCancelOrderMsg_ValidateBasic(t *testing.T) {
+	assert := assert.New(t)
+	addr := sdk.AccAddress("testaddr")
+	msg := NewBatchCancelOrderMsg(addr, []BatchCancel{{"XYZ_BNB", "addr-1"}, {"ABC_BNB", "addr-2"}})
+	assert.Nil(msg.ValidateBasic())
+	assert.Equal(2, len(msg.CancelOrderMsgs()))
+	msg = NewBatchCancelOrderMsg(sdk.AccAddress{}, []BatchCancel{{"XYZ_BNB", "addr-1"}})
+	assert.NotNil(msg.ValidateBasic())
+	msg = NewBatchCancelOrderMsg(addr, nil)
+	assert.NotNil(msg.ValidateBasic())
+	msg = NewBatchCancelOrderMsg(addr, []BatchCancel{{"XYZ_BNB", "addr-1"}, {"XYZ_BNB", "addr-1"}})
+	assert.NotNil(msg.ValidateBasic())
+	msg = NewBatchCancelOrderMsg(addr, []BatchCancel{{"XYZ_BNB", "order1"}})
+	assert.NotNil(msg.ValidateBasic())
+}
+
 func TestGenerateOrderId(t *testing.T) {
 	viper.SetDefault(client.FlagSequence, "5")
 	viper.SetDefault(client.FlagChainID, "mychaindid")
@@ -22,8 +22,10 @@ const (
 type DexOrderKeeper interface {
 	initOrders(symbol string)
 	addOrder(symbol string, info OrderInfo, isRecovery bool)
+	ackOrder(info *OrderInfo, isRecovery bool)
 	reloadOrder(symbol string, orderInfo *OrderInfo, height int64)
 	removeOrder(dexKeeper *DexKeeper, id string, symbol string) (ord me.OrderPart, err error)
+	amendOrder(dexKeeper *DexKeeper, id string, symbol string, qty, height, timestamp int64) (ord me.OrderPart, err error)
 	orderExists(symbol, id string) (OrderInfo, bool)
 	getOpenOrders(pair string, addr sdk.AccAddress) []store.OpenOrder
 	getAllOrders() map[string]map[string]*OrderInfo
@@ -83,18 +85,23 @@ func NewBaseOrderKeeper(moduleName string) BaseOrderKeeper {
 }
 
 func (kp *BaseOrderKeeper) addOrder(symbol string, info OrderInfo, isRecovery bool) {
+	kp.ackOrder(&info, isRecovery)
+	kp.allOrders[symbol][info.Id] = &info
+	kp.addRoundOrders(symbol, info)
+}
+
+// ackOrder records the order for publication. A triggered conditional order has been acked when it was placed,
+// so only its order info is refreshed.
+func (kp *BaseOrderKeeper) ackOrder(info *OrderInfo, isRecovery bool) {
 	if kp.collectOrderInfoForPublish {
-		change := OrderChange{info.Id, Ack, "", nil}
+		_, acked := kp.orderInfosForPub[info.Id]
 		// deliberately not add this message to orderChanges
-		if !isRecovery {
-			kp.orderChanges = append(kp.orderChanges, change)
+		if !isRecovery && !acked {
+			kp.orderChanges = append(kp.orderChanges, OrderChange{info.Id, Ack, "", nil})
 		}
 		kp.logger.Debug("add order to order changes map", "orderId", info.Id, "isRecovery", isRecovery)
-		kp.orderInfosForPub[info.Id] = &info
+		kp.orderInfosForPub[info.Id] = info
 	}
-
-	kp.allOrders[symbol][info.Id] = &info
-	kp.addRoundOrders(symbol, info)
 }
 
 func (kp *BaseOrderKeeper) addRoundOrders(symbol string, info OrderInfo) {
@@ -127,10 +134,31 @@ func (kp *BaseOrderKeeper) removeOrder(dexKeeper *DexKeeper, id string, symbol s
 	if !ok {
 		return me.OrderPart{}, orderNotFound(symbol, id)
 	}
-	delete(kp.allOrders[symbol], id)
+	dexKeeper.dropOpenOrder(symbol, kp.allOrders[symbol], id)
 	return eng.Book.RemoveOrder(id, ordMsg.Side, ordMsg.Price)
 }
 
+// amendOrder reduces the qty of the order without moving it in the price level queue
+func (kp *BaseOrderKeeper) amendOrder(dexKeeper *DexKeeper, id string, symbol string, qty, height, timestamp int64) (ord me.OrderPart, err error) {
+	ordMsg, ok := kp.allOrders[symbol][id]
+	if !ok {
+		return me.OrderPart{}, orderNotFound(symbol, id)
+	}
+	eng, ok := dexKeeper.engines[symbol]
+	if !ok {
+		return me.OrderPart{}, orderNotFound(symbol, id)
+	}
+	ord, err = eng.Book.UpdateOrderQty(id, ordMsg.Side, ordMsg.Price, qty)
+	if err != nil {
+		return ord, err
+	}
+	// the order info is shared with orderInfosForPub, so the publication sees the new qty as well
+	ordMsg.Quantity = qty
+	ordMsg.LastUpdatedHeight = height
+	ordMsg.LastUpdatedTimestamp = timestamp
+	return ord, nil
+}
+
 func (kp *BaseOrderKeeper) deleteOrdersForPair(pair string) {
 	delete(kp.allOrders, pair)
 }
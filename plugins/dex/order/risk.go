@@ -0,0 +1,223 @@
+package order
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+
+	"github.com/bnb-chain/node/common/types"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
+	"github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+// RiskLimitsMsgType is the msg type of RiskLimitParam in the fee params. The param is set in the genesis and changed
+// by the fee change proposals, the same as the dex fees. No fee calculator is registered for the msg type.
+const RiskLimitsMsgType = "dexRiskLimits"
+
+var _ param.MsgFeeParams = (*RiskLimitParam)(nil)
+
+// RiskLimits are the pre-trade risk limits of a pair. A limit of 0 means no limit.
+type RiskLimits struct {
+	MaxOpenOrders     int64 `json:"max_open_orders"`     // max number of open orders per account on a pair
+	MaxOrderNotional  int64 `json:"max_order_notional"`  // max notional of an order, in native token
+	MaxPriceDeviation int64 `json:"max_price_deviation"` // max deviation of the price from the last trade price, in 1e-6
+}
+
+func (l RiskLimits) check() error {
+	if l.MaxOpenOrders < 0 || l.MaxOrderNotional < 0 || l.MaxPriceDeviation < 0 {
+		return fmt.Errorf("risk limits %+v should not be negative", l)
+	}
+	return nil
+}
+
+type PairRiskLimits struct {
+	Symbol string     `json:"symbol"`
+	Limits RiskLimits `json:"limits"`
+}
+
+// RiskLimitParam carries the risk limits in the fee params. The limits of a pair in Pairs override all the default ones.
+type RiskLimitParam struct {
+	Default RiskLimits       `json:"default"`
+	Pairs   []PairRiskLimits `json:"pairs"`
+}
+
+func (p *RiskLimitParam) GetParamType() string {
+	return param.OperateFeeType
+}
+
+func (p *RiskLimitParam) GetMsgType() string {
+	return RiskLimitsMsgType
+}
+
+func (p *RiskLimitParam) Check() error {
+	if err := p.Default.check(); err != nil {
+		return err
+	}
+	symbols := make(map[string]struct{}, len(p.Pairs))
+	for _, pair := range p.Pairs {
+		if _, _, err := utils.TradingPair2Assets(pair.Symbol); err != nil {
+			return err
+		}
+		symbol := strings.ToUpper(pair.Symbol)
+		if _, ok := symbols[symbol]; ok {
+			return fmt.Errorf("duplicated risk limits of pair %s", symbol)
+		}
+		symbols[symbol] = struct{}{}
+		if err := pair.Limits.check(); err != nil {
+			return fmt.Errorf("invalid risk limits of pair %s: %v", symbol, err)
+		}
+	}
+	return nil
+}
+
+type RiskConfig struct {
+	Default RiskLimits
+	Pairs   map[string]RiskLimits // symbol -> limits overriding the default ones
+}
+
+func NewRiskConfig() RiskConfig {
+	return RiskConfig{Pairs: make(map[string]RiskLimits)}
+}
+
+func (c RiskConfig) LimitsOf(symbol string) RiskLimits {
+	if limits, ok := c.Pairs[strings.ToUpper(symbol)]; ok {
+		return limits
+	}
+	return c.Default
+}
+
+// ParamToRiskConfig returns nil if there is no RiskLimitParam in the params
+func ParamToRiskConfig(feeParams []param.FeeParam) *RiskConfig {
+	for _, p := range feeParams {
+		if u, ok := p.(*RiskLimitParam); ok {
+			config := NewRiskConfig()
+			config.Default = u.Default
+			for _, pair := range u.Pairs {
+				config.Pairs[strings.ToUpper(pair.Symbol)] = pair.Limits
+			}
+			return &config
+		}
+	}
+	return nil
+}
+
+// SetRiskConfig should only happen when Init or in BreatheBlock, the same as the fee config
+func (kp *DexKeeper) SetRiskConfig(config RiskConfig) {
+	kp.riskConfig = config
+}
+
+func (kp *DexKeeper) GetRiskConfig() RiskConfig {
+	return kp.riskConfig
+}
+
+// checkRiskLimits checks the new order against the risk limits of its pair. `pendingOrders` is the number of
+// open orders of the sender on the pair which are not in the order books yet (or are leaving them) in the same tx.
+func (kp *DexKeeper) checkRiskLimits(msg NewOrderMsg, pendingOrders int) sdk.Error {
+	symbol := strings.ToUpper(msg.Symbol)
+	limits := kp.riskConfig.LimitsOf(symbol)
+
+	if limits.MaxOpenOrders > 0 {
+		if n := kp.countOpenOrders(symbol, msg.Sender) + pendingOrders + 1; int64(n) > limits.MaxOpenOrders {
+			return dextypes.ErrExceedRiskLimit(msg.Id, fmt.Sprintf("the sender would have %d open orders on %s, more than %d",
+				n, symbol, limits.MaxOpenOrders))
+		}
+	}
+
+	if limits.MaxOrderNotional > 0 {
+		if notional, ok := kp.orderNotional(symbol, msg.Price, msg.Quantity); ok && notional.Cmp(big.NewInt(limits.MaxOrderNotional)) > 0 {
+			return dextypes.ErrExceedRiskLimit(msg.Id, fmt.Sprintf("the notional %s is larger than %d", notional, limits.MaxOrderNotional))
+		}
+	}
+
+	// the price of a market order is the protection price which is not chosen by the sender
+	if limits.MaxPriceDeviation > 0 && msg.OrderType != OrderType.MARKET {
+		eng, ok := kp.engines[symbol]
+		if !ok || eng.LastTradePrice <= 0 {
+			return nil
+		}
+		// a conditional order is placed into the order book when the last trade price reaches the stop price
+		refPrice := eng.LastTradePrice
+		if IsConditionalOrderType(msg.OrderType) {
+			refPrice = msg.StopPrice
+		}
+		var diff, deviation big.Int
+		diff.Abs(diff.Sub(big.NewInt(msg.Price), big.NewInt(refPrice)))
+		deviation.Div(deviation.Mul(&diff, FeeRateMultiplier), big.NewInt(refPrice))
+		if deviation.Cmp(big.NewInt(limits.MaxPriceDeviation)) > 0 {
+			return dextypes.ErrExceedRiskLimit(msg.Id, fmt.Sprintf("the price %d deviates from %d by more than %d/%d",
+				msg.Price, refPrice, limits.MaxPriceDeviation, FeeRateMultiplier.Int64()))
+		}
+	}
+	return nil
+}
+
+func (kp *DexKeeper) countOpenOrders(symbol string, addr sdk.AccAddress) int {
+	return kp.openOrders.count(symbol, addr)
+}
+
+// openOrderCounter is the number of open orders of each sender on each pair, including the untriggered conditional
+// orders, symbol -> address bytes -> number. The counter of a pair is created along with its match engine, so that
+// the pairs matched concurrently only change their own counters.
+type openOrderCounter map[string]map[string]int
+
+func (c openOrderCounter) initPair(symbol string) {
+	c[symbol] = make(map[string]int)
+}
+
+func (c openOrderCounter) inc(symbol string, addr sdk.AccAddress) {
+	symbol = strings.ToUpper(symbol)
+	counts, ok := c[symbol]
+	if !ok {
+		counts = make(map[string]int)
+		c[symbol] = counts
+	}
+	counts[string(addr)]++
+}
+
+func (c openOrderCounter) dec(symbol string, addr sdk.AccAddress) {
+	counts := c[strings.ToUpper(symbol)]
+	if counts[string(addr)] > 1 {
+		counts[string(addr)]--
+	} else {
+		delete(counts, string(addr))
+	}
+}
+
+func (c openOrderCounter) count(symbol string, addr sdk.AccAddress) int {
+	return c[strings.ToUpper(symbol)][string(addr)]
+}
+
+// dropOpenOrder deletes the order `id` from `orders`, the open orders of the pair in the order keeper or the
+// conditional orders, and uncounts it from the open orders of its sender
+func (kp *DexKeeper) dropOpenOrder(symbol string, orders map[string]*OrderInfo, id string) {
+	if info, ok := orders[id]; ok {
+		kp.openOrders.dec(symbol, info.Sender)
+		delete(orders, id)
+	}
+}
+
+// orderNotional returns the notional of the order in native token. The base asset is priced against the native
+// token first, then the quote asset, e.g. for ABC_BUSD. It returns false if neither has a pair with the native token.
+func (kp *DexKeeper) orderNotional(symbol string, price, qty int64) (*big.Int, bool) {
+	baseAsset, quoteAsset, err := utils.TradingPair2Assets(symbol)
+	if err != nil {
+		return nil, false
+	}
+	if baseAsset == types.NativeTokenSymbol {
+		return big.NewInt(qty), true
+	}
+	if notional, ok := kp.FeeManager.calcNotional(baseAsset, qty, types.NativeTokenSymbol, kp.engines); ok {
+		return notional, true
+	}
+	quoteQty := utils.CalBigNotional(price, qty)
+	if quoteAsset == types.NativeTokenSymbol {
+		return quoteQty, true
+	}
+	if !quoteQty.IsInt64() {
+		return nil, false
+	}
+	return kp.FeeManager.calcNotional(quoteAsset, quoteQty.Int64(), types.NativeTokenSymbol, kp.engines)
+}
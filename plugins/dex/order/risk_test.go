@@ -0,0 +1,149 @@
+package order
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/plugins/dex/types"
+)
+
+func TestParamToRiskConfig(t *testing.T) {
+	require.Nil(t, ParamToRiskConfig([]param.FeeParam{&param.FixedFeeParams{MsgType: "send", Fee: 1},
+		&param.DexFeeParam{DexFeeFields: []param.DexFeeField{{FeeName: ExpireFeeField, FeeValue: 1000}}}}))
+
+	config := ParamToRiskConfig([]param.FeeParam{&RiskLimitParam{
+		Default: RiskLimits{MaxOpenOrders: 100, MaxOrderNotional: 1e12, MaxPriceDeviation: 1e5},
+		Pairs: []PairRiskLimits{
+			{Symbol: "xyz-000_BNB", Limits: RiskLimits{MaxOpenOrders: 5}},
+			{Symbol: "ABC-000_BNB", Limits: RiskLimits{MaxOpenOrders: 100, MaxOrderNotional: 1e12}},
+		},
+	}})
+	require.NotNil(t, config)
+	require.Equal(t, RiskLimits{100, 1e12, 1e5}, config.Default)
+	require.Equal(t, RiskLimits{5, 0, 0}, config.LimitsOf("XYZ-000_BNB"))
+	require.Equal(t, RiskLimits{100, 1e12, 0}, config.LimitsOf("ABC-000_BNB"))
+	require.Equal(t, config.Default, config.LimitsOf("BBB-000_BNB"))
+
+	// no limit by default
+	config = ParamToRiskConfig([]param.FeeParam{&RiskLimitParam{}})
+	require.Equal(t, RiskLimits{}, config.LimitsOf("XYZ-000_BNB"))
+}
+
+func TestRiskLimitParam_Check(t *testing.T) {
+	p := RiskLimitParam{
+		Default: RiskLimits{MaxOpenOrders: 100},
+		Pairs:   []PairRiskLimits{{Symbol: "XYZ-000_BNB", Limits: RiskLimits{MaxOpenOrders: 5}}},
+	}
+	require.NoError(t, p.Check())
+	require.Equal(t, RiskLimitsMsgType, p.GetMsgType())
+
+	p.Default.MaxOrderNotional = -1
+	require.Error(t, p.Check())
+	p.Default.MaxOrderNotional = 0
+
+	p.Pairs = append(p.Pairs, PairRiskLimits{Symbol: "XYZ000BNB"})
+	require.Error(t, p.Check())
+	p.Pairs[1].Symbol = "xyz-000_BNB"
+	require.Error(t, p.Check())
+	p.Pairs[1].Symbol = "ABC-000_BNB"
+	p.Pairs[1].Limits.MaxPriceDeviation = -1
+	require.Error(t, p.Check())
+	p.Pairs[1].Limits.MaxPriceDeviation = 1e5
+	require.NoError(t, p.Check())
+
+	// the param is checked by the fee change proposals
+	require.Error(t, (&param.FeeChangeParams{FeeParams: []param.FeeParam{&RiskLimitParam{Default: RiskLimits{MaxOpenOrders: -1}}}}).Check())
+}
+
+func TestKeeper_CountOpenOrders(t *testing.T) {
+	pairMapper, _, ctx, keeper := setupMappers()
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, pairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	addr, _ := MakeAddress()
+	addr2, _ := MakeAddress()
+
+	newInfo := func(sender sdk.AccAddress, id string, side int8, price int64) OrderInfo {
+		msg := NewOrderMsg{Sender: sender, Id: id, Symbol: "AAA-000_BNB", OrderType: OrderType.LIMIT,
+			Side: side, Price: price, Quantity: 1e8, TimeInForce: TimeInForce.GTE}
+		return OrderInfo{msg, 1, 0, 1, 0, 0, "", 0}
+	}
+	require.NoError(t, keeper.AddOrder(newInfo(addr, "1", Side.BUY, 1e8), false))
+	require.NoError(t, keeper.AddOrder(newInfo(addr, "2", Side.BUY, 1e8), false))
+	require.NoError(t, keeper.AddOrder(newInfo(addr2, "3", Side.SELL, 2e8), false))
+	stop := newInfo(addr, "4", Side.BUY, 3e8)
+	stop.OrderType = OrderType.STOPLIMIT
+	stop.StopPrice = 3e8
+	keeper.addConditionalOrder(stop, false)
+	require.Equal(t, 3, keeper.countOpenOrders("AAA-000_BNB", addr))
+	require.Equal(t, 1, keeper.countOpenOrders("AAA-000_BNB", addr2))
+	require.Equal(t, 0, keeper.countOpenOrders("XYZ-000_BNB", addr))
+
+	require.NoError(t, keeper.RemoveOrder("1", "AAA-000_BNB", nil))
+	_, err := keeper.removeConditionalOrder("4", "AAA-000_BNB")
+	require.NoError(t, err)
+	require.Equal(t, 1, keeper.countOpenOrders("AAA-000_BNB", addr))
+
+	// the filled orders are uncounted in the match
+	require.NoError(t, keeper.AddOrder(newInfo(addr, "5", Side.SELL, 1e8), false))
+	keeper.MatchSymbols(2, 0, false)
+	require.Equal(t, 0, keeper.countOpenOrders("AAA-000_BNB", addr))
+	require.Equal(t, 1, keeper.countOpenOrders("AAA-000_BNB", addr2))
+}
+
+func TestKeeper_CheckRiskLimits(t *testing.T) {
+	pairMapper, accMapper, ctx, keeper := setupMappers()
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	require.NoError(t, pairMapper.AddTradingPair(ctx, pair))
+	keeper.AddEngine(pair)
+	acc, addr := setupAccount(ctx, accMapper)
+
+	newMsg := func(id string, price, qty int64) NewOrderMsg {
+		return NewOrderMsg{Sender: addr, Id: id, Symbol: "AAA-000_BNB", OrderType: OrderType.LIMIT,
+			Side: Side.BUY, Price: price, Quantity: qty, TimeInForce: TimeInForce.GTE}
+	}
+	msg := newMsg(GenerateOrderID(acc.GetSequence(), addr), 1e8, 1e10)
+	require.Nil(t, keeper.checkRiskLimits(msg, 0))
+
+	keeper.SetRiskConfig(RiskConfig{
+		Default: RiskLimits{MaxOpenOrders: 2, MaxOrderNotional: 50e8, MaxPriceDeviation: 1e5},
+		Pairs:   map[string]RiskLimits{},
+	})
+	require.Nil(t, keeper.checkRiskLimits(newMsg("1", 1.1e8, 1e9), 0))
+
+	// open orders
+	require.NoError(t, keeper.AddOrder(OrderInfo{newMsg("1", 1e8, 1e8), 1, 0, 1, 0, 0, "", 0}, false))
+	require.Nil(t, keeper.checkRiskLimits(newMsg("2", 1e8, 1e8), 0))
+	sdkErr := keeper.checkRiskLimits(newMsg("2", 1e8, 1e8), 1)
+	require.NotNil(t, sdkErr)
+	require.Equal(t, types.CodeExceedRiskLimit, sdkErr.Code())
+	require.Nil(t, keeper.checkRiskLimits(newMsg("2", 1e8, 1e8), -1))
+	require.NoError(t, keeper.AddOrder(OrderInfo{newMsg("2", 1e8, 1e8), 1, 0, 1, 0, 0, "", 0}, false))
+	require.NotNil(t, keeper.checkRiskLimits(newMsg("3", 1e8, 1e8), 0))
+
+	// the limits of other pairs do not apply
+	keeper.SetRiskConfig(RiskConfig{
+		Default: RiskLimits{MaxOrderNotional: 50e8, MaxPriceDeviation: 1e5},
+		Pairs:   map[string]RiskLimits{"XYZ-000_BNB": {MaxOpenOrders: 1}},
+	})
+	require.Nil(t, keeper.checkRiskLimits(newMsg("3", 1e8, 1e8), 0))
+
+	// notional, priced at the last trade price of 1 BNB
+	require.Nil(t, keeper.checkRiskLimits(newMsg("3", 1e8, 50e8), 0))
+	require.NotNil(t, keeper.checkRiskLimits(newMsg("3", 1e8, 51e8), 0))
+
+	// price deviation from the last trade price
+	require.Nil(t, keeper.checkRiskLimits(newMsg("3", 0.9e8, 1e8), 0))
+	require.NotNil(t, keeper.checkRiskLimits(newMsg("3", 0.89e8, 1e8), 0))
+	require.NotNil(t, keeper.checkRiskLimits(newMsg("3", 1.11e8, 1e8), 0))
+	market := newMsg("3", 2e8, 1e8)
+	market.OrderType = OrderType.MARKET
+	require.Nil(t, keeper.checkRiskLimits(market, 0))
+	stop := newMsg("3", 2e8, 1e8)
+	stop.OrderType = OrderType.STOPLIMIT
+	stop.StopPrice = 1.9e8
+	require.Nil(t, keeper.checkRiskLimits(stop, 0))
+}
@@ -22,6 +22,8 @@ const (
 	eventFullyCancel
 	eventPartiallyCancel
 	eventCancelForMatchFailure
+	eventCancelForPostOnly
+	eventCancelForSelfTrade
 )
 
 // Transfer represents a transfer between trade currencies
@@ -43,7 +45,9 @@ func (tran Transfer) FeeFree() bool {
 	return tran.eventType == eventPartiallyExpire ||
 		tran.eventType == eventIOCPartiallyExpire ||
 		tran.eventType == eventPartiallyCancel ||
-		tran.eventType == eventCancelForMatchFailure
+		tran.eventType == eventCancelForMatchFailure ||
+		tran.eventType == eventCancelForPostOnly ||
+		tran.eventType == eventCancelForSelfTrade
 }
 
 func (tran Transfer) IsExpire() bool {
@@ -144,6 +148,21 @@ func TransferFromCanceled(ord me.OrderPart, ordMsg OrderInfo, isMatchFailure boo
 	return transferFromOrderRemoved(ord, ordMsg, tranEventType)
 }
 
+func TransferFromPostOnlyRejected(ord me.OrderPart, ordMsg OrderInfo) Transfer {
+	return transferFromOrderRemoved(ord, ordMsg, eventCancelForPostOnly)
+}
+
+func TransferFromSelfTradePrevented(ord me.OrderPart, ordMsg OrderInfo) Transfer {
+	return transferFromOrderRemoved(ord, ordMsg, eventCancelForSelfTrade)
+}
+
+// TransferFromAmended unlocks the qty reduced by amending the order in place. It's free of charge,
+// as the order stays in the order book.
+func TransferFromAmended(ordMsg OrderInfo, qty int64) Transfer {
+	reduced := me.OrderPart{Id: ordMsg.Id, Qty: ordMsg.Quantity - qty}
+	return transferFromOrderRemoved(reduced, ordMsg, eventPartiallyCancel)
+}
+
 func transferFromOrderRemoved(ord me.OrderPart, ordMsg OrderInfo, tranEventType transferEventType) Transfer {
 	//here is a trick to use the same currency as in and out ccy to simulate cancel
 	qty := ord.LeavesQty()
@@ -11,15 +11,19 @@ import (
 type ChangeType uint8
 
 const (
-	Ack            ChangeType = iota // new order tx
-	Canceled                         // cancel order tx
-	Expired                          // expired for gte order
-	IocNoFill                        // ioc order is not filled expire
-	IocExpire                        // ioc order is partial filled expire
-	PartialFill                      // order is partial filled, derived from trade
-	FullyFill                        // order is fully filled, derived from trade
-	FailedBlocking                   // order tx is failed blocking, we only publish essential message
-	FailedMatching                   // order failed matching
+	Ack                ChangeType = iota // new order tx
+	Canceled                             // cancel order tx
+	Expired                              // expired for gte order
+	IocNoFill                            // ioc order is not filled expire
+	IocExpire                            // ioc order is partial filled expire
+	PartialFill                          // order is partial filled, derived from trade
+	FullyFill                            // order is fully filled, derived from trade
+	FailedBlocking                       // order tx is failed blocking, we only publish essential message
+	FailedMatching                       // order failed matching
+	PostOnlyRejected                     // post-only order would be the taker side in matching
+	Triggered                            // conditional order is triggered and placed into the order book
+	Amended                              // order qty is reduced by a replace order tx, keeping its queue priority
+	SelfTradePrevented                   // order is canceled in matching to prevent trading with an order of the same sender
 )
 
 // True for should not remove order in these status from OrderInfoForPub
@@ -28,7 +32,9 @@ func (tpe ChangeType) IsOpen() bool {
 	// FailedBlocking tx doesn't effect OrderInfoForPub, should not be put into closedToPublish
 	return tpe == Ack ||
 		tpe == PartialFill ||
-		tpe == FailedBlocking
+		tpe == FailedBlocking ||
+		tpe == Triggered ||
+		tpe == Amended
 }
 
 func (tpe ChangeType) String() string {
@@ -51,6 +57,14 @@ func (tpe ChangeType) String() string {
 		return "FailedBlocking"
 	case FailedMatching:
 		return "FailedMatching"
+	case PostOnlyRejected:
+		return "PostOnlyRejected"
+	case Triggered:
+		return "Triggered"
+	case Amended:
+		return "Amended"
+	case SelfTradePrevented:
+		return "SelfTradePrevented"
 	default:
 		return "Unknown"
 	}
@@ -75,7 +89,7 @@ type OrderChange struct {
 	Id             string
 	Tpe            ChangeType
 	SingleFee      string
-	MsgForFailedTx interface{} // pointer to NewOrderMsg or CancelOrderMsg
+	MsgForFailedTx interface{} // pointer to NewOrderMsg, CancelOrderMsg or ReplaceOrderMsg
 }
 
 func (oc OrderChange) String() string {
@@ -92,6 +106,10 @@ func (oc OrderChange) failedBlockingMsg() *OrderInfo {
 		return &OrderInfo{
 			NewOrderMsg: NewOrderMsg{Sender: msg.Sender, Id: msg.RefId, Symbol: msg.Symbol},
 		}
+	case ReplaceOrderMsg:
+		return &OrderInfo{
+			NewOrderMsg: NewOrderMsg{Sender: msg.Sender, Id: msg.RefId, Symbol: msg.Symbol},
+		}
 	default:
 		return nil
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/bnb-chain/node/app/pub"
 	bnclog "github.com/bnb-chain/node/common/log"
 	app "github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/dex/order"
 	"github.com/bnb-chain/node/plugins/dex/utils"
 	"github.com/bnb-chain/node/plugins/tokens"
 )
@@ -24,6 +25,9 @@ func InitPlugin(
 	appp app.ChainApp, dexKeeper *DexKeeper, tokenMapper tokens.Mapper, govKeeper gov.Keeper,
 ) {
 
+	// the fee calculators of the batch msgs are generated with the ones of the single order msgs
+	order.RegisterBatchFeeCalculators()
+
 	// add msg handlers
 	for route, handler := range Routes(dexKeeper, tokenMapper, govKeeper) {
 		appp.GetRouter().AddRoute(route, handler)
@@ -41,6 +45,15 @@ func createQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQuery
 	return createAbciQueryHandler(keeper, abciQueryPrefix)
 }
 
+// EndBlocker expires the good-till orders reaching their expiry at this block, after the match.
+func EndBlocker(ctx sdk.Context, dexKeeper *DexKeeper) {
+	if dexKeeper.ShouldPublishOrder() {
+		pub.ExpireGoodTillOrdersForPublish(dexKeeper, ctx)
+	} else {
+		dexKeeper.ExpireGoodTillOrders(ctx, nil)
+	}
+}
+
 // EndBreatheBlock processes the breathe block lifecycle event.
 func EndBreatheBlock(ctx sdk.Context, dexKeeper *DexKeeper, govKeeper gov.Keeper, height int64, blockTime time.Time) {
 	logger := bnclog.With("module", "dex")
@@ -16,6 +16,9 @@ func Routes(dexKeeper *DexKeeper, tokenMapper tokens.Mapper, govKeeper gov.Keepe
 	orderHandler := order.NewHandler(dexKeeper)
 	routes[order.RouteNewOrder] = orderHandler
 	routes[order.RouteCancelOrder] = orderHandler
+	routes[order.RouteReplaceOrder] = orderHandler
+	routes[order.RouteBatchNewOrder] = orderHandler
+	routes[order.RouteBatchCancelOrder] = orderHandler
 	routes[types.ListRoute] = list.NewHandler(dexKeeper, tokenMapper, govKeeper)
 	return routes
 }
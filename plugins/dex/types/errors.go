@@ -18,6 +18,7 @@ const (
 	CodeFailLocateOrderToCancel sdk.CodeType = 405
 	CodeDuplicatedOrder         sdk.CodeType = 406
 	CodeInvalidProposal         sdk.CodeType = 407
+	CodeExceedRiskLimit         sdk.CodeType = 408
 )
 
 // ErrIncorrectDexOperation - Error returned upon an incorrect guess
@@ -36,3 +37,7 @@ func ErrInvalidTradeSymbol(err string) sdk.Error {
 func ErrInvalidProposal(err string) sdk.Error {
 	return sdk.NewError(DefaultCodespace, CodeInvalidProposal, fmt.Sprintf("Invalid proposal: %s", err))
 }
+
+func ErrExceedRiskLimit(orderId string, err string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeExceedRiskLimit, fmt.Sprintf("Order [%s] exceeds the risk limit: %s", orderId, err))
+}
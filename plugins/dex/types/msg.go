@@ -7,6 +7,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 const ListRoute = "dexList"
@@ -19,6 +20,7 @@ type ListMsg struct {
 	BaseAssetSymbol  string         `json:"base_asset_symbol"`
 	QuoteAssetSymbol string         `json:"quote_asset_symbol"`
 	InitPrice        int64          `json:"init_price"`
+	MatchingMode     string         `json:"matching_mode,omitempty"` // the auction mode if empty
 }
 
 func NewListMsg(from sdk.AccAddress, proposalId int64, baseAssetSymbol string, quoteAssetSymbol string, initPrice int64) ListMsg {
@@ -54,6 +56,13 @@ func (msg ListMsg) ValidateBasic() sdk.Error {
 	if msg.InitPrice <= 0 {
 		return sdk.ErrInvalidCoins("price should be positive")
 	}
+	mode, err := MatchingModeStringToCode(msg.MatchingMode)
+	if err != nil {
+		return sdk.ErrUnknownRequest(err.Error())
+	}
+	if mode != MatchingModeAuction && !sdk.IsUpgrade(upgrade.ContinuousMatchingUpgrade) {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("matching mode `%s` is not supported yet", msg.MatchingMode))
+	}
 	return nil
 }
 
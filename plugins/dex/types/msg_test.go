@@ -1,11 +1,14 @@
 package types
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func TestIdenticalBaseAssetAndQuoteAsset(t *testing.T) {
@@ -48,3 +51,23 @@ func TestRightMsg(t *testing.T) {
 	err := msg.ValidateBasic()
 	require.Nil(t, err, "msg should not be error")
 }
+
+func TestMatchingMode(t *testing.T) {
+	msg := NewListMsg(sdk.AccAddress{}, 1, "BTC-000", "BNB", 1000)
+	msg.MatchingMode = "random"
+	err := msg.ValidateBasic()
+	require.NotNil(t, err, "msg should be error")
+	require.Contains(t, err.Error(), "matching mode `random` not found or supported")
+
+	msg.MatchingMode = "auction"
+	require.Nil(t, msg.ValidateBasic(), "msg should not be error")
+
+	msg.MatchingMode = "continuous"
+	err = msg.ValidateBasic()
+	require.NotNil(t, err, "msg should be error")
+	require.Contains(t, err.Error(), "is not supported yet")
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.ContinuousMatchingUpgrade, -1)
+	defer upgrade.Mgr.AddUpgradeHeight(upgrade.ContinuousMatchingUpgrade, math.MaxInt64)
+	require.Nil(t, msg.ValidateBasic(), "msg should not be error")
+}
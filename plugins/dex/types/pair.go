@@ -1,6 +1,9 @@
 package types
 
 import (
+	"fmt"
+	"strings"
+
 	ctuils "github.com/bnb-chain/node/common/utils"
 	"github.com/bnb-chain/node/plugins/dex/utils"
 )
@@ -11,6 +14,30 @@ type TradingPair struct {
 	ListPrice        ctuils.Fixed8 `json:"list_price"`
 	TickSize         ctuils.Fixed8 `json:"tick_size"`
 	LotSize          ctuils.Fixed8 `json:"lot_size"`
+	MatchingMode     int8          `json:"matching_mode,omitempty"`
+}
+
+// The matching modes of a trading pair. The orders of an auction pair are matched by the call auction once per block,
+// while the orders of a continuous pair are matched one by one in the sequence of arrival in price-time priority.
+const (
+	MatchingModeAuction int8 = iota
+	MatchingModeContinuous
+)
+
+var matchingModeNames = map[string]int8{
+	"AUCTION":    MatchingModeAuction,
+	"CONTINUOUS": MatchingModeContinuous,
+}
+
+// MatchingModeStringToCode converts the name of a matching mode to its code, the empty name is the auction mode
+func MatchingModeStringToCode(mode string) (int8, error) {
+	if mode == "" {
+		return MatchingModeAuction, nil
+	}
+	if code, ok := matchingModeNames[strings.ToUpper(mode)]; ok {
+		return code, nil
+	}
+	return -1, fmt.Errorf("matching mode `%s` not found or supported", mode)
 }
 
 // NOTE: only for test use
@@ -30,6 +57,10 @@ func NewTradingPairWithLotSize(baseAsset, quoteAsset string, listPrice, lotSize
 	}
 }
 
+func (pair *TradingPair) IsContinuous() bool {
+	return pair.MatchingMode == MatchingModeContinuous
+}
+
 func (pair *TradingPair) GetSymbol() string {
 	return utils.Assets2TradingPair(pair.BaseAssetSymbol, pair.QuoteAssetSymbol)
 }
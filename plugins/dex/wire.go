@@ -13,6 +13,9 @@ func RegisterWire(cdc *wire.Codec) {
 
 	cdc.RegisterConcrete(order.NewOrderMsg{}, "dex/NewOrder", nil)
 	cdc.RegisterConcrete(order.CancelOrderMsg{}, "dex/CancelOrder", nil)
+	cdc.RegisterConcrete(order.ReplaceOrderMsg{}, "dex/ReplaceOrder", nil)
+	cdc.RegisterConcrete(order.BatchNewOrderMsg{}, "dex/BatchNewOrder", nil)
+	cdc.RegisterConcrete(order.BatchCancelOrderMsg{}, "dex/BatchCancelOrder", nil)
 
 	cdc.RegisterConcrete(types.ListMsg{}, "dex/ListMsg", nil)
 	cdc.RegisterConcrete(types.TradingPair{}, "dex/TradingPair", nil)
@@ -20,6 +23,7 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(types.ListMiniMsg{}, "dex/ListMiniMsg", nil)
 
 	cdc.RegisterConcrete(order.FeeConfig{}, "dex/FeeConfig", nil)
+	cdc.RegisterConcrete(&order.RiskLimitParam{}, "dex/RiskLimitParam", nil)
 	cdc.RegisterConcrete(order.OrderBookSnapshot{}, "dex/OrderBookSnapshot", nil)
 	cdc.RegisterConcrete(order.ActiveOrders{}, "dex/ActiveOrders", nil)
 	cdc.RegisterConcrete(store.RecentPrice{}, "dex/RecentPrice", nil)
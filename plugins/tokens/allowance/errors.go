@@ -0,0 +1,37 @@
+package allowance
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	DefaultCodespace sdk.CodespaceType = 9
+
+	CodeInvalidExpireTime     sdk.CodeType = 1
+	CodeAllowanceNotFound     sdk.CodeType = 2
+	CodeAllowanceExpired      sdk.CodeType = 3
+	CodeInsufficientAllowance sdk.CodeType = 4
+)
+
+//----------------------------------------
+// Error constructors
+
+func ErrInvalidExpireTime(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidExpireTime, fmt.Sprintf("Invalid expire time: %s", msg))
+}
+
+func ErrAllowanceNotFound(codespace sdk.CodespaceType, owner, spender sdk.AccAddress, symbol string) sdk.Error {
+	return sdk.NewError(codespace, CodeAllowanceNotFound,
+		fmt.Sprintf("Allowance does not exist, owner=%s, spender=%s, symbol=%s", owner.String(), spender.String(), symbol))
+}
+
+func ErrAllowanceExpired(codespace sdk.CodespaceType, owner, spender sdk.AccAddress, symbol string) sdk.Error {
+	return sdk.NewError(codespace, CodeAllowanceExpired,
+		fmt.Sprintf("Allowance is expired, owner=%s, spender=%s, symbol=%s", owner.String(), spender.String(), symbol))
+}
+
+func ErrInsufficientAllowance(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInsufficientAllowance, fmt.Sprintf("Insufficient allowance: %s", msg))
+}
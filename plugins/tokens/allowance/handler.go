@@ -0,0 +1,52 @@
+package allowance
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
+)
+
+func NewHandler(keeper Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		if !sdk.IsUpgrade(upgrade.TokenAllowanceUpgrade) || sdk.IsUpgrade(sdk.FirstSunsetFork) {
+			return sdk.ErrMsgNotSupported("").Result()
+		}
+		switch msg := msg.(type) {
+		case ApproveMsg:
+			return handleApprove(ctx, keeper, msg)
+		case TransferFromMsg:
+			return handleTransferFrom(ctx, keeper, msg)
+		case RevokeAllowanceMsg:
+			return handleRevokeAllowance(ctx, keeper, msg)
+		default:
+			errMsg := fmt.Sprintf("unrecognized allowance message type: %T", msg)
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleApprove(ctx sdk.Context, keeper Keeper, msg ApproveMsg) sdk.Result {
+	err := keeper.Approve(ctx, msg.From, msg.Spender, msg.Amount, msg.ExpireTime)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{}
+}
+
+func handleTransferFrom(ctx sdk.Context, keeper Keeper, msg TransferFromMsg) sdk.Result {
+	tags, err := keeper.TransferFrom(ctx, msg.From, msg.Owner, msg.To, msg.Amount)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{Tags: tags}
+}
+
+func handleRevokeAllowance(ctx sdk.Context, keeper Keeper, msg RevokeAllowanceMsg) sdk.Result {
+	err := keeper.Revoke(ctx, msg.From, msg.Spender, msg.Symbol)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{}
+}
@@ -0,0 +1,182 @@
+package allowance
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	bnclog "github.com/bnb-chain/node/common/log"
+)
+
+type Keeper struct {
+	ck        bank.Keeper
+	storeKey  sdk.StoreKey // The key used to access the store from the Context.
+	codespace sdk.CodespaceType
+	cdc       *codec.Codec
+	logger    tmlog.Logger
+}
+
+func NewKeeper(cdc *codec.Codec, key sdk.StoreKey, ck bank.Keeper, codespace sdk.CodespaceType) Keeper {
+	logger := bnclog.With("module", "allowance")
+	return Keeper{
+		ck:        ck,
+		storeKey:  key,
+		codespace: codespace,
+		cdc:       cdc,
+		logger:    logger,
+	}
+}
+
+func (keeper Keeper) setAllowance(ctx sdk.Context, allowance Allowance) {
+	store := ctx.KVStore(keeper.storeKey)
+	bz := keeper.cdc.MustMarshalBinaryLengthPrefixed(allowance)
+	store.Set(KeyAllowance(allowance.Owner, allowance.Spender, allowance.Amount.Denom), bz)
+	store.Set(KeySpenderAllowance(allowance.Spender, allowance.Owner, allowance.Amount.Denom), []byte{1})
+}
+
+func (keeper Keeper) deleteAllowance(ctx sdk.Context, owner, spender sdk.AccAddress, symbol string) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(KeyAllowance(owner, spender, symbol))
+	store.Delete(KeySpenderAllowance(spender, owner, symbol))
+}
+
+func (keeper Keeper) GetAllowance(ctx sdk.Context, owner, spender sdk.AccAddress, symbol string) (Allowance, bool) {
+	store := ctx.KVStore(keeper.storeKey)
+	bz := store.Get(KeyAllowance(owner, spender, symbol))
+	if bz == nil {
+		return Allowance{}, false
+	}
+
+	var allowance Allowance
+	keeper.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &allowance)
+	return allowance, true
+}
+
+// GetAllowances returns the allowances granted by the owner, ordered by their spenders and symbols
+func (keeper Keeper) GetAllowances(ctx sdk.Context, owner sdk.AccAddress) []Allowance {
+	store := ctx.KVStore(keeper.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, KeyAllowanceSubSpace(owner))
+	defer iterator.Close()
+
+	allowances := make([]Allowance, 0)
+	for ; iterator.Valid(); iterator.Next() {
+		var allowance Allowance
+		keeper.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &allowance)
+		allowances = append(allowances, allowance)
+	}
+	return allowances
+}
+
+// GetSpenderAllowances returns the allowances granted to the spender, ordered by their owners and symbols
+func (keeper Keeper) GetSpenderAllowances(ctx sdk.Context, spender sdk.AccAddress) []Allowance {
+	store := ctx.KVStore(keeper.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, KeySpenderAllowanceSubSpace(spender))
+	defer iterator.Close()
+
+	allowances := make([]Allowance, 0)
+	for ; iterator.Valid(); iterator.Next() {
+		owner, symbol, err := ParseKeySpenderAllowance(iterator.Key())
+		if err != nil {
+			keeper.logger.Error("failed to parse the spender allowance key", "error", err)
+			continue
+		}
+		bz := store.Get(KeyAllowance(owner, spender, symbol))
+		if bz == nil {
+			keeper.logger.Error("allowance of the spender index does not exist", "key", string(iterator.Key()))
+			continue
+		}
+		var allowance Allowance
+		keeper.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &allowance)
+		allowances = append(allowances, allowance)
+	}
+	return allowances
+}
+
+// Approve sets the allowance of the spender to transfer the amount from the owner, the previous allowance of the
+// same token is replaced.
+func (keeper Keeper) Approve(ctx sdk.Context, owner, spender sdk.AccAddress, amount sdk.Coin, expireTime int64) sdk.Error {
+	if expireTime != 0 && expireTime <= ctx.BlockHeader().Time.Unix() {
+		return ErrInvalidExpireTime(keeper.codespace,
+			fmt.Sprintf("expire time(%d) should be later than the block time(%d)", expireTime, ctx.BlockHeader().Time.Unix()))
+	}
+
+	keeper.setAllowance(ctx, Allowance{
+		Owner:      owner,
+		Spender:    spender,
+		Amount:     amount,
+		ExpireTime: expireTime,
+	})
+	return nil
+}
+
+func (keeper Keeper) Revoke(ctx sdk.Context, owner, spender sdk.AccAddress, symbol string) sdk.Error {
+	if _, found := keeper.GetAllowance(ctx, owner, spender, symbol); !found {
+		return ErrAllowanceNotFound(keeper.codespace, owner, spender, symbol)
+	}
+
+	keeper.deleteAllowance(ctx, owner, spender, symbol)
+	return nil
+}
+
+// TransferFrom transfers the amount from the owner to `to` within the allowances of the spender, which are decreased
+// by the amount and are deleted once used up.
+func (keeper Keeper) TransferFrom(ctx sdk.Context, spender, owner, to sdk.AccAddress, amount sdk.Coins) (sdk.Tags, sdk.Error) {
+	allowances := make([]Allowance, 0, len(amount))
+	for _, coin := range amount {
+		allowance, found := keeper.GetAllowance(ctx, owner, spender, coin.Denom)
+		if !found {
+			return nil, ErrAllowanceNotFound(keeper.codespace, owner, spender, coin.Denom)
+		}
+		if allowance.IsExpired(ctx.BlockHeader().Time) {
+			return nil, ErrAllowanceExpired(keeper.codespace, owner, spender, coin.Denom)
+		}
+		if allowance.Amount.Amount < coin.Amount {
+			return nil, ErrInsufficientAllowance(keeper.codespace,
+				fmt.Sprintf("allowance is %s, but %s is requested", allowance.Amount, coin))
+		}
+		allowance.Amount.Amount -= coin.Amount
+		allowances = append(allowances, allowance)
+	}
+
+	if err := keeper.checkTransfer(ctx, owner, to, amount); err != nil {
+		return nil, err
+	}
+
+	tags, err := keeper.ck.SendCoins(ctx, owner, to, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, allowance := range allowances {
+		if allowance.Amount.Amount == 0 {
+			keeper.deleteAllowance(ctx, allowance.Owner, allowance.Spender, allowance.Amount.Denom)
+		} else {
+			keeper.setAllowance(ctx, allowance)
+		}
+	}
+	return tags, nil
+}
+
+// checkTransfer applies the checks of a MsgSend from the owner to the transfer, i.e. the scripts registered for the
+// send msgs like the memo check of the receiver, and the min amount of the mini tokens
+func (keeper Keeper) checkTransfer(ctx sdk.Context, owner, to sdk.AccAddress, amount sdk.Coins) sdk.Error {
+	sendMsg := bank.NewMsgSend([]bank.Input{bank.NewInput(owner, amount)}, []bank.Output{bank.NewOutput(to, amount)})
+	for _, script := range sdk.GetRegisteredScripts(sendMsg.Type()) {
+		if script == nil {
+			keeper.logger.Error(fmt.Sprintf("Empty script is specified for msg %s", sendMsg.Type()))
+			continue
+		}
+		if err := script(ctx, sendMsg); err != nil {
+			return err
+		}
+	}
+
+	if sdk.IsUpgrade(sdk.BEP8) {
+		return bank.CheckAndValidateMiniTokenCoins(ctx, keeper.ck.GetAccountKeeper(), owner, amount)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package allowance
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdkstore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/account/scripts"
+	"github.com/bnb-chain/node/wire"
+)
+
+func getAccountCache(cdc *codec.Codec, ms sdk.MultiStore) sdk.AccountCache {
+	accountStore := ms.GetKVStore(common.AccountStoreKey)
+	accountStoreCache := auth.NewAccountStoreCache(cdc, accountStore, 10)
+	return auth.NewAccountCache(accountStoreCache)
+}
+
+func MakeCodec() *wire.Codec {
+	var cdc = wire.NewCodec()
+
+	wire.RegisterCrypto(cdc) // Register crypto.
+	bank.RegisterCodec(cdc)
+	sdk.RegisterCodec(cdc) // Register Msgs
+	types.RegisterWire(cdc)
+
+	return cdc
+}
+
+func MakeCMS(memDB *db.MemDB) sdk.CacheMultiStore {
+	if memDB == nil {
+		memDB = db.NewMemDB()
+	}
+	ms := sdkstore.NewCommitMultiStore(memDB)
+	ms.MountStoreWithDB(common.AccountStoreKey, sdk.StoreTypeIAVL, nil)
+	ms.MountStoreWithDB(common.AllowanceStoreKey, sdk.StoreTypeIAVL, nil)
+	ms.LoadLatestVersion()
+	cms := ms.CacheMultiStore()
+	return cms
+}
+
+func MakeKeeper(cdc *wire.Codec) (auth.AccountKeeper, Keeper) {
+	accKeeper := auth.NewAccountKeeper(cdc, common.AccountStoreKey, types.ProtoAppAccount)
+	ck := bank.NewBaseKeeper(accKeeper)
+	codespacer := sdk.NewCodespacer()
+	keeper := NewKeeper(cdc, common.AllowanceStoreKey, ck, codespacer.RegisterNext(DefaultCodespace))
+	return accKeeper, keeper
+}
+
+func makeContext(cdc *wire.Codec) sdk.Context {
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accountCache := getAccountCache(cdc, cms)
+	return sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeDeliver, logger).
+		WithAccountCache(accountCache).WithBlockTime(time.Unix(1000, 0))
+}
+
+func TestKeeper_Approve(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	ctx := makeContext(cdc)
+
+	_, owner := testutils.NewAccount(ctx, accKeeper, 100e8)
+	_, spender1 := testutils.NewAccount(ctx, accKeeper, 0)
+	_, spender2 := testutils.NewAccount(ctx, accKeeper, 0)
+
+	err := keeper.Approve(ctx, owner.GetAddress(), spender1.GetAddress(), sdk.NewCoin("BNB", 10e8), 0)
+	require.Nil(t, err)
+	err = keeper.Approve(ctx, owner.GetAddress(), spender2.GetAddress(), sdk.NewCoin("BNB", 20e8), 2000)
+	require.Nil(t, err)
+
+	// expired already
+	err = keeper.Approve(ctx, owner.GetAddress(), spender2.GetAddress(), sdk.NewCoin("BNB", 20e8), 1000)
+	require.NotNil(t, err)
+	require.Equal(t, CodeInvalidExpireTime, err.Code())
+
+	// replace the previous allowance
+	err = keeper.Approve(ctx, owner.GetAddress(), spender1.GetAddress(), sdk.NewCoin("BNB", 5e8), 0)
+	require.Nil(t, err)
+	allowance, found := keeper.GetAllowance(ctx, owner.GetAddress(), spender1.GetAddress(), "BNB")
+	require.True(t, found)
+	require.Equal(t, int64(5e8), allowance.Amount.Amount)
+
+	require.Len(t, keeper.GetAllowances(ctx, owner.GetAddress()), 2)
+	spenderAllowances := keeper.GetSpenderAllowances(ctx, spender2.GetAddress())
+	require.Len(t, spenderAllowances, 1)
+	require.Equal(t, owner.GetAddress(), spenderAllowances[0].Owner)
+	require.Equal(t, int64(2000), spenderAllowances[0].ExpireTime)
+
+	err = keeper.Revoke(ctx, owner.GetAddress(), spender2.GetAddress(), "BNB")
+	require.Nil(t, err)
+	err = keeper.Revoke(ctx, owner.GetAddress(), spender2.GetAddress(), "BNB")
+	require.NotNil(t, err)
+	require.Equal(t, CodeAllowanceNotFound, err.Code())
+	require.Len(t, keeper.GetAllowances(ctx, owner.GetAddress()), 1)
+	require.Len(t, keeper.GetSpenderAllowances(ctx, spender2.GetAddress()), 0)
+}
+
+func TestKeeper_TransferFrom(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	ctx := makeContext(cdc)
+
+	_, owner := testutils.NewAccount(ctx, accKeeper, 0)
+	_ = owner.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 100e8), sdk.NewCoin("XYZ-000", 100e8)}.Sort())
+	accKeeper.SetAccount(ctx, owner)
+	_, spender := testutils.NewAccount(ctx, accKeeper, 0)
+	_, to := testutils.NewAccount(ctx, accKeeper, 0)
+
+	err := keeper.Approve(ctx, owner.GetAddress(), spender.GetAddress(), sdk.NewCoin("BNB", 10e8), 0)
+	require.Nil(t, err)
+	err = keeper.Approve(ctx, owner.GetAddress(), spender.GetAddress(), sdk.NewCoin("XYZ-000", 10e8), 2000)
+	require.Nil(t, err)
+
+	_, err = keeper.TransferFrom(ctx, spender.GetAddress(), owner.GetAddress(), to.GetAddress(),
+		sdk.Coins{sdk.NewCoin("BNB", 11e8)})
+	require.NotNil(t, err)
+	require.Equal(t, CodeInsufficientAllowance, err.Code())
+
+	// the spender can't transfer from the owner without an allowance
+	_, err = keeper.TransferFrom(ctx, to.GetAddress(), owner.GetAddress(), to.GetAddress(),
+		sdk.Coins{sdk.NewCoin("BNB", 1e8)})
+	require.NotNil(t, err)
+	require.Equal(t, CodeAllowanceNotFound, err.Code())
+
+	_, err = keeper.TransferFrom(ctx, spender.GetAddress(), owner.GetAddress(), to.GetAddress(),
+		sdk.Coins{sdk.NewCoin("BNB", 4e8), sdk.NewCoin("XYZ-000", 10e8)}.Sort())
+	require.Nil(t, err)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 4e8), sdk.NewCoin("XYZ-000", 10e8)}.Sort(),
+		accKeeper.GetAccount(ctx, to.GetAddress()).GetCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 96e8), sdk.NewCoin("XYZ-000", 90e8)}.Sort(),
+		accKeeper.GetAccount(ctx, owner.GetAddress()).GetCoins())
+
+	// the used up allowance is deleted
+	allowance, found := keeper.GetAllowance(ctx, owner.GetAddress(), spender.GetAddress(), "BNB")
+	require.True(t, found)
+	require.Equal(t, int64(6e8), allowance.Amount.Amount)
+	_, found = keeper.GetAllowance(ctx, owner.GetAddress(), spender.GetAddress(), "XYZ-000")
+	require.False(t, found)
+
+	err = keeper.Approve(ctx, owner.GetAddress(), spender.GetAddress(), sdk.NewCoin("XYZ-000", 10e8), 2000)
+	require.Nil(t, err)
+	ctx = ctx.WithBlockTime(time.Unix(2000, 0))
+	_, err = keeper.TransferFrom(ctx, spender.GetAddress(), owner.GetAddress(), to.GetAddress(),
+		sdk.Coins{sdk.NewCoin("XYZ-000", 1e8)})
+	require.NotNil(t, err)
+	require.Equal(t, CodeAllowanceExpired, err.Code())
+
+	// the allowance can be larger than the balance of the owner
+	err = keeper.Approve(ctx, owner.GetAddress(), spender.GetAddress(), sdk.NewCoin("BNB", 1000e8), 0)
+	require.Nil(t, err)
+	_, err = keeper.TransferFrom(ctx, spender.GetAddress(), owner.GetAddress(), to.GetAddress(),
+		sdk.Coins{sdk.NewCoin("BNB", 100e8)})
+	require.NotNil(t, err)
+	require.Equal(t, sdk.CodeInsufficientCoins, err.Code())
+}
+
+func TestKeeper_TransferFrom_SendChecks(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	ctx := makeContext(cdc)
+	scripts.RegisterTransferMemoCheckScript(accKeeper)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP8, 1)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP12, 1)
+	upgrade.Mgr.SetHeight(1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
+	_, owner := testutils.NewAccount(ctx, accKeeper, 0)
+	_ = owner.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 100e8), sdk.NewCoin("XYZ-000M", 2e8)}.Sort())
+	accKeeper.SetAccount(ctx, owner)
+	_, spender := testutils.NewAccount(ctx, accKeeper, 0)
+	_, to := testutils.NewNamedAccount(ctx, accKeeper, 0)
+	to.SetFlags(scripts.TransferMemoCheckerFlag)
+	accKeeper.SetAccount(ctx, to)
+
+	err := keeper.Approve(ctx, owner.GetAddress(), spender.GetAddress(), sdk.NewCoin("BNB", 10e8), 0)
+	require.Nil(t, err)
+	err = keeper.Approve(ctx, owner.GetAddress(), spender.GetAddress(), sdk.NewCoin("XYZ-000M", 2e8), 0)
+	require.Nil(t, err)
+
+	// the receiver requires a memo of digits
+	msg := NewTransferFromMsg(spender.GetAddress(), owner.GetAddress(), to.GetAddress(), sdk.Coins{sdk.NewCoin("BNB", 1e8)})
+	ctx = ctx.WithTx(auth.StdTx{Msgs: []sdk.Msg{msg}, Memo: ""})
+	_, err = keeper.TransferFrom(ctx, spender.GetAddress(), owner.GetAddress(), to.GetAddress(), msg.Amount)
+	require.NotNil(t, err)
+	require.Equal(t, sdk.CodeInvalidTxMemo, err.Code())
+	ctx = ctx.WithTx(auth.StdTx{Msgs: []sdk.Msg{msg}, Memo: "12345"})
+	_, err = keeper.TransferFrom(ctx, spender.GetAddress(), owner.GetAddress(), to.GetAddress(), msg.Amount)
+	require.Nil(t, err)
+
+	// the mini tokens less than the min amount can only be transferred with the whole balance
+	_, err = keeper.TransferFrom(ctx, spender.GetAddress(), owner.GetAddress(), to.GetAddress(),
+		sdk.Coins{sdk.NewCoin("XYZ-000M", 1e7)})
+	require.NotNil(t, err)
+	require.Equal(t, sdk.CodeInvalidCoins, err.Code())
+	_, err = keeper.TransferFrom(ctx, spender.GetAddress(), owner.GetAddress(), to.GetAddress(),
+		sdk.Coins{sdk.NewCoin("XYZ-000M", 2e8)})
+	require.Nil(t, err)
+	require.Equal(t, int64(2e8), accKeeper.GetAccount(ctx, to.GetAddress()).GetCoins().AmountOf("XYZ-000M"))
+}
+
+func TestHandler(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	ctx := makeContext(cdc)
+	handler := NewHandler(keeper)
+
+	_, owner := testutils.NewAccount(ctx, accKeeper, 100e8)
+	_, spender := testutils.NewAccount(ctx, accKeeper, 0)
+
+	approveMsg := NewApproveMsg(owner.GetAddress(), spender.GetAddress(), sdk.NewCoin("BNB", 10e8), 0)
+	result := handler(ctx, approveMsg)
+	require.Equal(t, sdk.ErrMsgNotSupported("").ABCICode(), result.Code)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TokenAllowanceUpgrade, 1)
+	upgrade.Mgr.SetHeight(1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
+	result = handler(ctx, approveMsg)
+	require.True(t, result.IsOK())
+
+	result = handler(ctx, NewTransferFromMsg(spender.GetAddress(), owner.GetAddress(), spender.GetAddress(),
+		sdk.Coins{sdk.NewCoin("BNB", 3e8)}))
+	require.True(t, result.IsOK())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 3e8)}, accKeeper.GetAccount(ctx, spender.GetAddress()).GetCoins())
+
+	querier := NewQuerier(keeper)
+	bz, err := cdc.MarshalJSON(QueryAllowanceParams{Owner: owner.GetAddress(), Spender: spender.GetAddress(), Symbol: "BNB"})
+	require.NoError(t, err)
+	res, sdkErr := querier(ctx, []string{QueryAllowance}, abci.RequestQuery{Data: bz})
+	require.Nil(t, sdkErr)
+	var allowance Allowance
+	require.NoError(t, cdc.UnmarshalJSON(res, &allowance))
+	require.Equal(t, sdk.NewCoin("BNB", 7e8), allowance.Amount)
+
+	result = handler(ctx, NewRevokeAllowanceMsg(owner.GetAddress(), spender.GetAddress(), "BNB"))
+	require.True(t, result.IsOK())
+
+	bz, err = cdc.MarshalJSON(QueryAllowancesParams{Account: spender.GetAddress()})
+	require.NoError(t, err)
+	res, sdkErr = querier(ctx, []string{QuerySpenderAllowances}, abci.RequestQuery{Data: bz})
+	require.Nil(t, sdkErr)
+	var allowances []Allowance
+	require.NoError(t, cdc.UnmarshalJSON(res, &allowances))
+	require.Len(t, allowances, 0)
+}
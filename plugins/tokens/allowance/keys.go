@@ -0,0 +1,45 @@
+package allowance
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The allowances are kept under their owners as "allowance:<owner>:<spender>:<symbol>", and are indexed by their
+// spenders as "spender:<spender>:<owner>:<symbol>".
+var (
+	AllowancePrefix = []byte("allowance:")
+	SpenderPrefix   = []byte("spender:")
+)
+
+func KeyAllowance(owner, spender sdk.AccAddress, symbol string) []byte {
+	return []byte(fmt.Sprintf("allowance:%d:%d:%s", owner, spender, symbol))
+}
+
+func KeyAllowanceSubSpace(owner sdk.AccAddress) []byte {
+	return []byte(fmt.Sprintf("allowance:%d:", owner))
+}
+
+func KeySpenderAllowance(spender, owner sdk.AccAddress, symbol string) []byte {
+	return []byte(fmt.Sprintf("spender:%d:%d:%s", spender, owner, symbol))
+}
+
+func KeySpenderAllowanceSubSpace(spender sdk.AccAddress) []byte {
+	return []byte(fmt.Sprintf("spender:%d:", spender))
+}
+
+// ParseKeySpenderAllowance returns the owner and the symbol of the allowance indexed by the key
+func ParseKeySpenderAllowance(key []byte) (sdk.AccAddress, string, error) {
+	key = bytes.TrimPrefix(key, SpenderPrefix)
+	if len(key) <= sdk.AddrLen*4+2 {
+		return []byte{}, "", fmt.Errorf("invalid spender allowance key: %s", key)
+	}
+	owner, err := hex.DecodeString(string(key[sdk.AddrLen*2+1 : sdk.AddrLen*4+1]))
+	if err != nil {
+		return []byte{}, "", err
+	}
+	return sdk.AccAddress(owner), string(key[sdk.AddrLen*4+2:]), nil
+}
@@ -0,0 +1,21 @@
+package allowance
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeySpenderAllowance(t *testing.T) {
+	owner := sdk.AccAddress([]byte("12345678901234567890"))
+	spender := sdk.AccAddress([]byte("abcdefghijklmnopqrst"))
+
+	parsedOwner, symbol, err := ParseKeySpenderAllowance(KeySpenderAllowance(spender, owner, "XYZ-000M"))
+	require.Nil(t, err)
+	require.Equal(t, owner, parsedOwner)
+	require.Equal(t, "XYZ-000M", symbol)
+
+	_, _, err = ParseKeySpenderAllowance(KeySpenderAllowanceSubSpace(spender))
+	require.NotNil(t, err)
+}
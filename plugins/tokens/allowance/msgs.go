@@ -0,0 +1,211 @@
+package allowance
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/plugins/account"
+	"github.com/bnb-chain/node/plugins/tokens/ownership"
+)
+
+const (
+	MsgRoute = "allowance"
+
+	MaxTransferFromCoins = 10
+)
+
+var _ sdk.Msg = ApproveMsg{}
+
+// ApproveMsg allows Spender to transfer up to Amount of the token from From by TransferFromMsg, till ExpireTime if
+// it's not 0. It replaces the allowance of Spender for the same token. As a change of the permissions of the account,
+// it's charged the same as SetAccountFlagsMsg.
+type ApproveMsg struct {
+	From       sdk.AccAddress `json:"from"`
+	Spender    sdk.AccAddress `json:"spender"`
+	Amount     sdk.Coin       `json:"amount"`
+	ExpireTime int64          `json:"expire_time"`
+}
+
+func NewApproveMsg(from, spender sdk.AccAddress, amount sdk.Coin, expireTime int64) ApproveMsg {
+	return ApproveMsg{
+		From:       from,
+		Spender:    spender,
+		Amount:     amount,
+		ExpireTime: expireTime,
+	}
+}
+
+func (msg ApproveMsg) Route() string { return MsgRoute }
+func (msg ApproveMsg) Type() string  { return account.SetAccountFlagsMsgType }
+func (msg ApproveMsg) String() string {
+	return fmt.Sprintf("Approve{%s#%s#%v#%v}", msg.From, msg.Spender, msg.Amount, msg.ExpireTime)
+}
+func (msg ApproveMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From, msg.Spender}
+}
+func (msg ApproveMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg ApproveMsg) ValidateBasic() sdk.Error {
+	if err := validateSpender(msg.From, msg.Spender); err != nil {
+		return err
+	}
+
+	if !msg.Amount.IsPositive() {
+		return sdk.ErrInvalidCoins(msg.Amount.String())
+	}
+
+	if err := validateSymbol(msg.Amount.Denom); err != nil {
+		return err
+	}
+
+	if msg.ExpireTime < 0 {
+		return ErrInvalidExpireTime(DefaultCodespace, fmt.Sprintf("expire time(%d) should not be less than 0", msg.ExpireTime))
+	}
+
+	return nil
+}
+
+func (msg ApproveMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var _ sdk.Msg = TransferFromMsg{}
+
+// TransferFromMsg transfers Amount from Owner to To, signed by the spender From within its allowances granted by
+// Owner. There is no fee param of it yet, it's charged the same as TransferOwnershipMsg.
+type TransferFromMsg struct {
+	From   sdk.AccAddress `json:"from"`
+	Owner  sdk.AccAddress `json:"owner"`
+	To     sdk.AccAddress `json:"to"`
+	Amount sdk.Coins      `json:"amount"`
+}
+
+func NewTransferFromMsg(from, owner, to sdk.AccAddress, amount sdk.Coins) TransferFromMsg {
+	return TransferFromMsg{
+		From:   from,
+		Owner:  owner,
+		To:     to,
+		Amount: amount,
+	}
+}
+
+func (msg TransferFromMsg) Route() string { return MsgRoute }
+func (msg TransferFromMsg) Type() string  { return ownership.TransferOwnershipMsgType }
+func (msg TransferFromMsg) String() string {
+	return fmt.Sprintf("TransferFrom{%s#%s#%s#%v}", msg.From, msg.Owner, msg.To, msg.Amount)
+}
+func (msg TransferFromMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From, msg.Owner, msg.To}
+}
+func (msg TransferFromMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg TransferFromMsg) ValidateBasic() sdk.Error {
+	if err := validateSpender(msg.Owner, msg.From); err != nil {
+		return err
+	}
+
+	if len(msg.To) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("length of to address should be %d", sdk.AddrLen))
+	}
+
+	if !msg.Amount.IsValid() || !msg.Amount.IsPositive() {
+		return sdk.ErrInvalidCoins(msg.Amount.String())
+	}
+
+	if len(msg.Amount) > MaxTransferFromCoins {
+		return sdk.ErrInvalidCoins(fmt.Sprintf("number of coins(%d) should not be larger than %d", len(msg.Amount), MaxTransferFromCoins))
+	}
+
+	for _, coin := range msg.Amount {
+		if err := validateSymbol(coin.Denom); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (msg TransferFromMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var _ sdk.Msg = RevokeAllowanceMsg{}
+
+// RevokeAllowanceMsg deletes the allowance of Spender for the token Symbol. It's charged the same as ApproveMsg.
+type RevokeAllowanceMsg struct {
+	From    sdk.AccAddress `json:"from"`
+	Spender sdk.AccAddress `json:"spender"`
+	Symbol  string         `json:"symbol"`
+}
+
+func NewRevokeAllowanceMsg(from, spender sdk.AccAddress, symbol string) RevokeAllowanceMsg {
+	return RevokeAllowanceMsg{
+		From:    from,
+		Spender: spender,
+		Symbol:  symbol,
+	}
+}
+
+func (msg RevokeAllowanceMsg) Route() string { return MsgRoute }
+func (msg RevokeAllowanceMsg) Type() string  { return ApproveMsg{}.Type() }
+func (msg RevokeAllowanceMsg) String() string {
+	return fmt.Sprintf("RevokeAllowance{%s#%s#%s}", msg.From, msg.Spender, msg.Symbol)
+}
+func (msg RevokeAllowanceMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From, msg.Spender}
+}
+func (msg RevokeAllowanceMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg RevokeAllowanceMsg) ValidateBasic() sdk.Error {
+	if err := validateSpender(msg.From, msg.Spender); err != nil {
+		return err
+	}
+
+	if err := validateSymbol(msg.Symbol); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (msg RevokeAllowanceMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func validateSpender(owner, spender sdk.AccAddress) sdk.Error {
+	if len(owner) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("length of owner address should be %d", sdk.AddrLen))
+	}
+	if len(spender) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("length of spender address should be %d", sdk.AddrLen))
+	}
+	if owner.Equals(spender) {
+		return sdk.ErrInvalidAddress("spender should not be the owner")
+	}
+	return nil
+}
+
+func validateSymbol(symbol string) sdk.Error {
+	if !types.IsValidMiniTokenSymbol(symbol) {
+		err := types.ValidateTokenSymbol(symbol)
+		if err != nil {
+			return sdk.ErrInvalidCoins(err.Error())
+		}
+	}
+	return nil
+}
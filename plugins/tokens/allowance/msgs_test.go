@@ -0,0 +1,83 @@
+package allowance
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/testutils"
+)
+
+func TestApproveMsg(t *testing.T) {
+	_, from := testutils.PrivAndAddr()
+	_, spender := testutils.PrivAndAddr()
+
+	tests := []struct {
+		msg     ApproveMsg
+		pass    bool
+		errCode sdk.CodeType
+	}{
+		{NewApproveMsg(from, spender, sdk.NewCoin("BNB", 1e8), 0), true, 0},
+		{NewApproveMsg(from, spender, sdk.NewCoin("XYZ-000M", 1e8), 1000), true, 0},
+		{NewApproveMsg(from, sdk.AccAddress{}, sdk.NewCoin("BNB", 1e8), 0), false, sdk.CodeInvalidAddress},
+		{NewApproveMsg(from, from, sdk.NewCoin("BNB", 1e8), 0), false, sdk.CodeInvalidAddress},
+		{NewApproveMsg(from, spender, sdk.NewCoin("BNB", 0), 0), false, sdk.CodeInvalidCoins},
+		{NewApproveMsg(from, spender, sdk.NewCoin("XYZ", 1e8), 0), false, sdk.CodeInvalidCoins},
+		{NewApproveMsg(from, spender, sdk.NewCoin("BNB", 1e8), -1), false, CodeInvalidExpireTime},
+	}
+
+	for i, test := range tests {
+		err := test.msg.ValidateBasic()
+		if test.pass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+			require.Equal(t, test.errCode, err.Code(), "test: %v", i)
+		}
+	}
+}
+
+func TestTransferFromMsg(t *testing.T) {
+	_, from := testutils.PrivAndAddr()
+	_, owner := testutils.PrivAndAddr()
+	_, to := testutils.PrivAndAddr()
+
+	tooManyCoins := sdk.Coins{}
+	for _, symbol := range []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K"} {
+		tooManyCoins = append(tooManyCoins, sdk.NewCoin("AA"+symbol+"-000", 1e8))
+	}
+
+	tests := []struct {
+		msg     TransferFromMsg
+		pass    bool
+		errCode sdk.CodeType
+	}{
+		{NewTransferFromMsg(from, owner, to, sdk.Coins{sdk.NewCoin("BNB", 1e8)}), true, 0},
+		{NewTransferFromMsg(from, owner, owner, sdk.Coins{sdk.NewCoin("BNB", 1e8)}), true, 0},
+		{NewTransferFromMsg(from, from, to, sdk.Coins{sdk.NewCoin("BNB", 1e8)}), false, sdk.CodeInvalidAddress},
+		{NewTransferFromMsg(from, owner, sdk.AccAddress{}, sdk.Coins{sdk.NewCoin("BNB", 1e8)}), false, sdk.CodeInvalidAddress},
+		{NewTransferFromMsg(from, owner, to, sdk.Coins{}), false, sdk.CodeInvalidCoins},
+		{NewTransferFromMsg(from, owner, to, sdk.Coins{sdk.NewCoin("BNB", -1)}), false, sdk.CodeInvalidCoins},
+		{NewTransferFromMsg(from, owner, to, tooManyCoins), false, sdk.CodeInvalidCoins},
+	}
+
+	for i, test := range tests {
+		err := test.msg.ValidateBasic()
+		if test.pass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+			require.Equal(t, test.errCode, err.Code(), "test: %v", i)
+		}
+	}
+}
+
+func TestRevokeAllowanceMsg(t *testing.T) {
+	_, from := testutils.PrivAndAddr()
+	_, spender := testutils.PrivAndAddr()
+
+	require.Nil(t, NewRevokeAllowanceMsg(from, spender, "BNB").ValidateBasic())
+	require.NotNil(t, NewRevokeAllowanceMsg(from, spender, "").ValidateBasic())
+	require.NotNil(t, NewRevokeAllowanceMsg(from, from, "BNB").ValidateBasic())
+}
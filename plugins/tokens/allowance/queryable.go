@@ -0,0 +1,118 @@
+package allowance
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
+)
+
+const (
+	QueryAllowance         = "allowance"
+	QueryAllowances        = "allowances"
+	QuerySpenderAllowances = "spenderallowances"
+)
+
+func NewQuerier(keeper Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
+		// the store is mounted since TokenAllowanceUpgrade
+		if !sdk.IsUpgrade(upgrade.TokenAllowanceUpgrade) {
+			return nil, sdk.ErrUnknownRequest("allowance is not supported before TokenAllowanceUpgrade")
+		}
+		switch path[0] {
+		case QueryAllowance:
+			return queryAllowance(ctx, req, keeper)
+		case QueryAllowances:
+			return queryAllowances(ctx, req, keeper)
+		case QuerySpenderAllowances:
+			return querySpenderAllowances(ctx, req, keeper)
+		default:
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown allowance query endpoint %s", path[0]))
+		}
+	}
+}
+
+// Params for query 'custom/allowance/allowance'
+type QueryAllowanceParams struct {
+	Owner   sdk.AccAddress
+	Spender sdk.AccAddress
+	Symbol  string
+}
+
+// nolint: unparam
+func queryAllowance(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params QueryAllowanceParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+
+	if len(params.Owner) != sdk.AddrLen || len(params.Spender) != sdk.AddrLen {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("length of address should be %d", sdk.AddrLen))
+	}
+
+	allowance, found := keeper.GetAllowance(ctx, params.Owner, params.Spender, params.Symbol)
+	if !found {
+		return nil, ErrAllowanceNotFound(DefaultCodespace, params.Owner, params.Spender, params.Symbol)
+	}
+
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, allowance)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return bz, nil
+}
+
+// Params for query 'custom/allowance/allowances' and 'custom/allowance/spenderallowances', which return the
+// allowances granted by the account and granted to the account respectively. The expired allowances are returned
+// as well.
+type QueryAllowancesParams struct {
+	Account sdk.AccAddress
+}
+
+// nolint: unparam
+func queryAllowances(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params QueryAllowancesParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+
+	if len(params.Account) != sdk.AddrLen {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("length of address should be %d", sdk.AddrLen))
+	}
+
+	allowances := keeper.GetAllowances(ctx, params.Account)
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, allowances)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return bz, nil
+}
+
+// nolint: unparam
+func querySpenderAllowances(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params QueryAllowancesParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+
+	if len(params.Account) != sdk.AddrLen {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("length of address should be %d", sdk.AddrLen))
+	}
+
+	allowances := keeper.GetSpenderAllowances(ctx, params.Account)
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, allowances)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return bz, nil
+}
@@ -0,0 +1,20 @@
+package allowance
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Allowance is the amount of a token Spender is allowed to transfer from the account of Owner. It can't be spent
+// since ExpireTime(unix time in seconds), and never expires if ExpireTime is 0.
+type Allowance struct {
+	Owner      sdk.AccAddress `json:"owner"`
+	Spender    sdk.AccAddress `json:"spender"`
+	Amount     sdk.Coin       `json:"amount"`
+	ExpireTime int64          `json:"expire_time"`
+}
+
+func (allowance Allowance) IsExpired(now time.Time) bool {
+	return allowance.ExpireTime != 0 && now.Unix() >= allowance.ExpireTime
+}
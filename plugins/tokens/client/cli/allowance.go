@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/bnb-chain/node/common/client"
+	"github.com/bnb-chain/node/plugins/tokens/allowance"
+)
+
+const (
+	flagSpender    = "spender"
+	flagOwner      = "owner"
+	flagExpireTime = "expire-time"
+)
+
+func approveCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve",
+		Short: "allow a spender to transfer an amount of tokens from the account",
+		Long: strings.TrimSpace(`
+Approve allows the spender to transfer up to the amount of tokens from the account by transfer-from, which replaces
+the previous allowance of the spender for the same token. the allowance never expires if --expire-time is not specified.
+
+$ CLI token approve --from alice --spender bnb1hn8ym9xht925jkncjpf7lhjnax6z8nv24fv2yq --amount 100:BNB --expire-time 1591341558
+`),
+		RunE: cmdr.approve,
+	}
+
+	cmd.Flags().String(flagSpender, "", "address allowed to transfer the tokens")
+	cmd.Flags().String(flagAmount, "", "amount of the token allowed to transfer, example: \"100:BNB\"")
+	cmd.Flags().Int64(flagExpireTime, 0, "timestamp since when the allowance can't be spent(second), never expires if not specified")
+
+	return cmd
+}
+
+func (c Commander) approve(cmd *cobra.Command, args []string) error {
+	cliCtx, txBldr := client.PrepareCtx(c.Cdc)
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+
+	spender, err := sdk.AccAddressFromBech32(viper.GetString(flagSpender))
+	if err != nil {
+		return err
+	}
+
+	amount, err := sdk.ParseCoins(viper.GetString(flagAmount))
+	if err != nil {
+		return err
+	}
+	if len(amount) != 1 {
+		return fmt.Errorf("amount should be of one token")
+	}
+
+	msg := allowance.NewApproveMsg(from, spender, amount[0], viper.GetInt64(flagExpireTime))
+	return client.SendOrPrintTx(cliCtx, txBldr, msg)
+}
+
+func transferFromCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-from",
+		Short: "transfer tokens from an owner within the allowances granted by the owner",
+		Long: strings.TrimSpace(`
+Transfer from is used by a spender to transfer tokens from the owner to an address, the allowances granted by
+the owner are decreased by the amount.
+
+$ CLI token transfer-from --from bob --owner bnb1hn8ym9xht925jkncjpf7lhjnax6z8nv24fv2yq --to bnb1hn8ym9xht925jkncjpf7lhjnax6z8nv24fv2yq --amount 100:BNB
+`),
+		RunE: cmdr.transferFrom,
+	}
+
+	cmd.Flags().String(flagOwner, "", "address to transfer the tokens from")
+	cmd.Flags().String(flagTo, "", "address to transfer the tokens to")
+	cmd.Flags().String(flagAmount, "", "amount to transfer, example: \"100:BNB\" or \"100:BNB,10000:BTCB-1DE\"")
+
+	return cmd
+}
+
+func (c Commander) transferFrom(cmd *cobra.Command, args []string) error {
+	cliCtx, txBldr := client.PrepareCtx(c.Cdc)
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+
+	owner, err := sdk.AccAddressFromBech32(viper.GetString(flagOwner))
+	if err != nil {
+		return err
+	}
+
+	to, err := sdk.AccAddressFromBech32(viper.GetString(flagTo))
+	if err != nil {
+		return err
+	}
+
+	amount, err := sdk.ParseCoins(viper.GetString(flagAmount))
+	if err != nil {
+		return err
+	}
+
+	msg := allowance.NewTransferFromMsg(from, owner, to, amount)
+	return client.SendOrPrintTx(cliCtx, txBldr, msg)
+}
+
+func revokeAllowanceCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke-allowance",
+		Short: "revoke the allowance of a spender for a token",
+		RunE:  cmdr.revokeAllowance,
+	}
+
+	cmd.Flags().String(flagSpender, "", "address of the spender")
+	cmd.Flags().StringP(flagSymbol, "s", "", "symbol of the token")
+
+	return cmd
+}
+
+func (c Commander) revokeAllowance(cmd *cobra.Command, args []string) error {
+	cliCtx, txBldr := client.PrepareCtx(c.Cdc)
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+
+	spender, err := sdk.AccAddressFromBech32(viper.GetString(flagSpender))
+	if err != nil {
+		return err
+	}
+
+	symbol := strings.ToUpper(viper.GetString(flagSymbol))
+	if len(symbol) == 0 {
+		return fmt.Errorf("symbol can not be empty")
+	}
+
+	msg := allowance.NewRevokeAllowanceMsg(from, spender, symbol)
+	return client.SendOrPrintTx(cliCtx, txBldr, msg)
+}
+
+func queryAllowanceCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-allowance",
+		Short: "query the allowance of a spender for a token",
+		RunE:  cmdr.queryAllowance,
+	}
+
+	cmd.Flags().String(flagOwner, "", "address of the owner")
+	cmd.Flags().String(flagSpender, "", "address of the spender")
+	cmd.Flags().StringP(flagSymbol, "s", "", "symbol of the token")
+
+	return cmd
+}
+
+func (c Commander) queryAllowance(cmd *cobra.Command, args []string) error {
+	cliCtx, _ := client.PrepareCtx(c.Cdc)
+
+	owner, err := sdk.AccAddressFromBech32(viper.GetString(flagOwner))
+	if err != nil {
+		return err
+	}
+
+	spender, err := sdk.AccAddressFromBech32(viper.GetString(flagSpender))
+	if err != nil {
+		return err
+	}
+
+	params := allowance.QueryAllowanceParams{
+		Owner:   owner,
+		Spender: spender,
+		Symbol:  strings.ToUpper(viper.GetString(flagSymbol)),
+	}
+
+	bz, err := c.Cdc.MarshalJSON(params)
+	if err != nil {
+		return err
+	}
+
+	res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", allowance.MsgRoute, allowance.QueryAllowance), bz)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+	return nil
+}
+
+func queryAllowancesCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-allowances",
+		Short: "query allowances granted by an address",
+		RunE:  cmdr.queryAllowances(allowance.QueryAllowances),
+	}
+
+	cmd.Flags().String(flagAddress, "", "address to query")
+
+	return cmd
+}
+
+func querySpenderAllowancesCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-spender-allowances",
+		Short: "query allowances granted to an address",
+		RunE:  cmdr.queryAllowances(allowance.QuerySpenderAllowances),
+	}
+
+	cmd.Flags().String(flagAddress, "", "address to query")
+
+	return cmd
+}
+
+func (c Commander) queryAllowances(path string) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cliCtx, _ := client.PrepareCtx(c.Cdc)
+
+		address, err := sdk.AccAddressFromBech32(viper.GetString(flagAddress))
+		if err != nil {
+			return err
+		}
+
+		params := allowance.QueryAllowancesParams{
+			Account: address,
+		}
+
+		bz, err := c.Cdc.MarshalJSON(params)
+		if err != nil {
+			return err
+		}
+
+		res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", allowance.MsgRoute, path), bz)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(res))
+		return nil
+	}
+}
@@ -29,6 +29,7 @@ const (
 	flagCrossChain          = "cross-chain"
 	flagLimit               = "limit"
 	flagOffset              = "offset"
+	flagStatus              = "status"
 )
 
 func initiateHTLTCmd(cmdr Commander) *cobra.Command {
@@ -156,6 +157,7 @@ func claimHTLTCmd(cmdr Commander) *cobra.Command {
 
 	cmd.Flags().String(flagSwapID, "", "ID of previously created swap, hex encoding")
 	cmd.Flags().String(flagRandomNumber, "", "The random number to unlock the locked hash, 32 bytes, hex encoding")
+	cmd.Flags().String(flagAmount, "", "The part of a cross chain swap to claim, the whole remaining amount if not specified, example: \"100:BNB\"")
 
 	return cmd
 }
@@ -178,8 +180,14 @@ func (c Commander) claimHTLT(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	amount, err := sdk.ParseCoins(viper.GetString(flagAmount))
+	if err != nil {
+		return err
+	}
+
 	// build message
 	msg := swap.NewClaimHTLTMsg(from, swapID, randomNumber)
+	msg.Amount = amount
 
 	sdkErr := msg.ValidateBasic()
 	if sdkErr != nil {
@@ -284,6 +292,7 @@ func querySwapsByCreatorCmd(cmdr Commander) *cobra.Command {
 	cmd.Flags().String(flagCreatorAddr, "", "Swap creator address, bech32 encoding")
 	cmd.Flags().Int64(flagLimit, 100, "The maximum quantity of swapIDs you want to get")
 	cmd.Flags().Int64(flagOffset, 0, "The number of swapIDs you want to skip")
+	cmd.Flags().String(flagStatus, "", "Only the swaps of the status: Open, PartiallyCompleted, Completed or Expired")
 
 	return cmd
 }
@@ -305,10 +314,16 @@ func (c Commander) querySwapsByCreator(cmd *cobra.Command, args []string) error
 		return fmt.Errorf("offset must be positive")
 	}
 
+	status, err := parseSwapStatus(viper.GetString(flagStatus))
+	if err != nil {
+		return err
+	}
+
 	params := swap.QuerySwapByCreatorParams{
 		Creator: creator,
 		Limit:   limit,
 		Offset:  offset,
+		Status:  status,
 	}
 
 	bz, err := c.Cdc.MarshalJSON(params)
@@ -335,6 +350,7 @@ func querySwapsByRecipientCmd(cmdr Commander) *cobra.Command {
 	cmd.Flags().String(flagRecipientAddr, "", "Swap recipient address, bech32 encoding")
 	cmd.Flags().Int64(flagLimit, 100, "The maximum quantity of swapIDs you want to get")
 	cmd.Flags().Int64(flagOffset, 0, "The number of swapIDs you want to skip")
+	cmd.Flags().String(flagStatus, "", "Only the swaps of the status: Open, PartiallyCompleted, Completed or Expired")
 
 	return cmd
 }
@@ -356,10 +372,16 @@ func (c Commander) querySwapsByRecipient(cmd *cobra.Command, args []string) erro
 		return fmt.Errorf("offset must be positive")
 	}
 
+	status, err := parseSwapStatus(viper.GetString(flagStatus))
+	if err != nil {
+		return err
+	}
+
 	params := swap.QuerySwapByRecipientParams{
 		Recipient: recipient,
 		Limit:     limit,
 		Offset:    offset,
+		Status:    status,
 	}
 
 	bz, err := c.Cdc.MarshalJSON(params)
@@ -375,3 +397,14 @@ func (c Commander) querySwapsByRecipient(cmd *cobra.Command, args []string) erro
 	fmt.Println(string(res))
 	return nil
 }
+
+func parseSwapStatus(str string) (swap.SwapStatus, error) {
+	if str == "" {
+		return swap.NULL, nil
+	}
+	status := swap.NewSwapStatusFromString(str)
+	if status == swap.NULL {
+		return swap.NULL, fmt.Errorf("invalid swap status %s", str)
+	}
+	return status, nil
+}
@@ -31,12 +31,16 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 			unfreezeTokenCmd(cmdr),
 			timeLockCmd(cmdr),
 			timeUnlockCmd(cmdr),
+			timeLockTransferCmd(cmdr),
 			timeRelockCmd(cmdr),
 			initiateHTLTCmd(cmdr),
 			depositHTLTCmd(cmdr),
 			claimHTLTCmd(cmdr),
 			refundHTLTCmd(cmdr),
 			transferOwnershipCmd(cmdr),
+			approveCmd(cmdr),
+			transferFromCmd(cmdr),
+			revokeAllowanceCmd(cmdr),
 		)...)
 
 	tokenCmd.AddCommand(
@@ -44,10 +48,14 @@ func AddCommands(cmd *cobra.Command, cdc *wire.Codec) {
 			listTokensCmd,
 			getTokenInfoCmd(cmdr),
 			queryTimeLocksCmd(cmdr),
+			queryCreatedTimeLocksCmd(cmdr),
 			queryTimeLockCmd(cmdr),
 			querySwapCmd(cmdr),
 			querySwapsByRecipientCmd(cmdr),
-			querySwapsByCreatorCmd(cmdr))...)
+			querySwapsByCreatorCmd(cmdr),
+			queryAllowanceCmd(cmdr),
+			queryAllowancesCmd(cmdr),
+			querySpenderAllowancesCmd(cmdr))...)
 
 	tokenCmd.AddCommand(
 		client.PostCommands(MultiSendCmd(cdc))...,
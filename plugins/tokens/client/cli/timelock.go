@@ -22,6 +22,11 @@ const (
 	flagIncreaseAmountTo = "increase-amount-to"
 	flagExtendedLockTime = "extended-lock-time"
 	flagBroadcast        = "broadcast"
+	flagVestingStartTime = "vesting-start-time"
+	flagVestingCliffTime = "vesting-cliff-time"
+	flagVestingSteps     = "vesting-steps"
+	flagBeneficiary      = "beneficiary"
+	flagTo               = "to"
 )
 
 func timeLockCmd(cmdr Commander) *cobra.Command {
@@ -38,6 +43,16 @@ this command will output the tx to broadcast, but will not broadcast to any node
 if you want to broadcast the tx to blockchain, you need to specify --broadcast manually.
 
 $ CLI token time-lock --amount 100:BNB --from alice --description "time lock for some reason" --lock-time 1559805558 --broadcast
+
+to release the tokens gradually till the lock time, specify the start of the vesting. the tokens are released linearly
+from the start, or in equal steps if --vesting-steps is specified. nothing is released before the cliff time if specified.
+the released tokens can be claimed by time-unlock at any time.
+
+$ CLI token time-lock --amount 100:BNB --from alice --description "grant" --lock-time 1591341558 --vesting-start-time 1559805558 --vesting-cliff-time 1567754358 --vesting-steps 12
+
+to lock the tokens for someone else, specify the beneficiary, who is able to unlock the tokens instead of the sender.
+
+$ CLI token time-lock --amount 100:BNB --from alice --description "grant" --lock-time 1559805558 --beneficiary bnb1hn8ym9xht925jkncjpf7lhjnax6z8nv24fv2yq
 `),
 		RunE: cmdr.timeLock,
 	}
@@ -46,6 +61,10 @@ $ CLI token time-lock --amount 100:BNB --from alice --description "time lock for
 	cmd.Flags().Int64(flagLockTime, 0, "timestamp of lock time(second)")
 	cmd.Flags().String(flagDescription, "", "description of time lock")
 	cmd.Flags().Bool(flagBroadcast, false, "broadcast tx")
+	cmd.Flags().Int64(flagVestingStartTime, 0, "timestamp of the start of vesting(second), no vesting if not specified")
+	cmd.Flags().Int64(flagVestingCliffTime, 0, "timestamp of the cliff of vesting(second), the start of vesting if not specified")
+	cmd.Flags().Int64(flagVestingSteps, 0, "number of equal steps to release the tokens, linear release if not specified")
+	cmd.Flags().String(flagBeneficiary, "", "address the tokens are released to, the sender if not specified")
 
 	return cmd
 }
@@ -80,8 +99,28 @@ func (c Commander) timeLock(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("lock time(%s) should be after now", time.Unix(lockTime, 0).UTC().String())
 	}
 
+	var beneficiary sdk.AccAddress
+	if beneficiaryStr := viper.GetString(flagBeneficiary); beneficiaryStr != "" {
+		beneficiary, err = sdk.AccAddressFromBech32(beneficiaryStr)
+		if err != nil {
+			return err
+		}
+	}
+
 	// build message
-	msg := timelock.NewTimeLockMsg(from, description, amount, lockTime)
+	lockMsg := timelock.NewTimeLockMsg(from, description, amount, lockTime)
+	lockMsg.Beneficiary = beneficiary
+	var msg sdk.Msg = lockMsg
+	if startTime := viper.GetInt64(flagVestingStartTime); startTime > 0 {
+		cliffTime := viper.GetInt64(flagVestingCliffTime)
+		if cliffTime == 0 {
+			cliffTime = startTime
+		}
+		vestingMsg := timelock.NewTimeLockVestingMsg(from, description, amount, startTime, cliffTime, lockTime,
+			viper.GetInt64(flagVestingSteps))
+		vestingMsg.Beneficiary = beneficiary
+		msg = vestingMsg
+	}
 	broadcast := viper.GetBool(flagBroadcast)
 	if !broadcast {
 		cliCtx.GenerateOnly = true
@@ -171,10 +210,56 @@ func (c Commander) timeRelock(cmd *cobra.Command, args []string) error {
 	return client.SendOrPrintTx(cliCtx, txBldr, msg)
 }
 
+func timeLockTransferCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "time-lock-transfer",
+		Short: "transfer a time lock record to a new owner",
+		Long: strings.TrimSpace(`
+Time lock transfer is used to transfer a time lock record to a new owner, who is able to unlock the tokens instead.
+the record gets a new time lock id under the new owner, which is returned in the data of the tx.
+
+$ CLI token time-lock-transfer --from alice --time-lock-id 1 --to bnb1hn8ym9xht925jkncjpf7lhjnax6z8nv24fv2yq --broadcast
+`),
+		RunE: cmdr.timeLockTransfer,
+	}
+
+	cmd.Flags().Int64(flagTimeLockId, 0, "time lock id")
+	cmd.Flags().String(flagTo, "", "address of the new owner")
+	cmd.Flags().Bool(flagBroadcast, false, "broadcast tx")
+
+	return cmd
+}
+
+func (c Commander) timeLockTransfer(cmd *cobra.Command, args []string) error {
+	cliCtx, txBldr := client.PrepareCtx(c.Cdc)
+	from, err := cliCtx.GetFromAddress()
+	if err != nil {
+		return err
+	}
+
+	timeLockId := viper.GetInt64(flagTimeLockId)
+	if timeLockId < timelock.InitialRecordId {
+		return fmt.Errorf("time lock id should not less than %d", timelock.InitialRecordId)
+	}
+
+	to, err := sdk.AccAddressFromBech32(viper.GetString(flagTo))
+	if err != nil {
+		return err
+	}
+
+	// build message
+	msg := timelock.NewTimeLockTransferMsg(from, timeLockId, to)
+	broadcast := viper.GetBool(flagBroadcast)
+	if !broadcast {
+		cliCtx.GenerateOnly = true
+	}
+	return client.SendOrPrintTx(cliCtx, txBldr, msg)
+}
+
 func timeUnlockCmd(cmdr Commander) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "time-unlock",
-		Short: "time unlock tokens",
+		Short: "time unlock tokens, or claim the released tokens of a vesting time lock",
 		RunE:  cmdr.timeUnlock,
 	}
 
@@ -240,6 +325,45 @@ func (c Commander) queryTimeLocks(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func queryCreatedTimeLocksCmd(cmdr Commander) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-created-time-locks",
+		Short: "query time locks created by an address for others",
+		RunE:  cmdr.queryCreatedTimeLocks,
+	}
+
+	cmd.Flags().String(flagAddress, "", "address to query")
+
+	return cmd
+}
+
+func (c Commander) queryCreatedTimeLocks(cmd *cobra.Command, args []string) error {
+	cliCtx, _ := client.PrepareCtx(c.Cdc)
+
+	addressStr := viper.GetString(flagAddress)
+	address, err := sdk.AccAddressFromBech32(addressStr)
+	if err != nil {
+		return err
+	}
+
+	params := timelock.QueryTimeLocksParams{
+		Account: address,
+	}
+
+	bz, err := c.Cdc.MarshalJSON(params)
+	if err != nil {
+		return err
+	}
+
+	res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", timelock.MsgRoute, timelock.QueryCreatedTimeLocks), bz)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+	return nil
+}
+
 func queryTimeLockCmd(cmdr Commander) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "query-time-lock",
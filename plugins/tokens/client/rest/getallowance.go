@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/mux"
+
+	"github.com/bnb-chain/node/plugins/tokens/allowance"
+	"github.com/bnb-chain/node/wire"
+)
+
+func getAllowance(ctx context.CLIContext, cdc *wire.Codec, owner, spender sdk.AccAddress, symbol string) (allowance.Allowance, error) {
+	params := allowance.QueryAllowanceParams{
+		Owner:   owner,
+		Spender: spender,
+		Symbol:  symbol,
+	}
+
+	bz, err := cdc.MarshalJSON(params)
+	if err != nil {
+		return allowance.Allowance{}, err
+	}
+
+	bz, err = ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", allowance.MsgRoute, allowance.QueryAllowance), bz)
+	if err != nil {
+		return allowance.Allowance{}, err
+	}
+
+	var result allowance.Allowance
+	err = cdc.UnmarshalJSON(bz, &result)
+	if err != nil {
+		return allowance.Allowance{}, err
+	}
+
+	return result, nil
+}
+
+func GetAllowanceReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	responseType := "application/json"
+
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		ownerStr := vars["owner"]
+		owner, err := sdk.AccAddressFromBech32(ownerStr)
+		if err != nil {
+			throw(w, http.StatusBadRequest, fmt.Errorf("invalid owner address, address=%s", ownerStr))
+			return
+		}
+
+		spenderStr := vars["spender"]
+		spender, err := sdk.AccAddressFromBech32(spenderStr)
+		if err != nil {
+			throw(w, http.StatusBadRequest, fmt.Errorf("invalid spender address, address=%s", spenderStr))
+			return
+		}
+
+		symbol := strings.ToUpper(vars["symbol"])
+		if len(symbol) == 0 {
+			throw(w, http.StatusBadRequest, fmt.Errorf("miss request parameter `symbol`"))
+			return
+		}
+
+		result, err := getAllowance(ctx, cdc, owner, spender, symbol)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// no need to use cdc here because we do not want amino to inject a type attribute
+		output, err := json.Marshal(result)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", responseType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(output)
+	}
+}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/mux"
+
+	"github.com/bnb-chain/node/plugins/tokens/allowance"
+	"github.com/bnb-chain/node/wire"
+)
+
+func getAllowances(ctx context.CLIContext, cdc *wire.Codec, path string, address sdk.AccAddress) ([]allowance.Allowance, error) {
+	params := allowance.QueryAllowancesParams{
+		Account: address,
+	}
+
+	bz, err := cdc.MarshalJSON(params)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err = ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", allowance.MsgRoute, path), bz)
+	if err != nil {
+		return nil, err
+	}
+
+	var allowances []allowance.Allowance
+	err = cdc.UnmarshalJSON(bz, &allowances)
+	if err != nil {
+		return nil, err
+	}
+
+	return allowances, nil
+}
+
+// GetAllowancesReqHandler returns the allowances granted by the address
+func GetAllowancesReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return getAllowancesReqHandler(cdc, ctx, allowance.QueryAllowances)
+}
+
+// GetSpenderAllowancesReqHandler returns the allowances granted to the address
+func GetSpenderAllowancesReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return getAllowancesReqHandler(cdc, ctx, allowance.QuerySpenderAllowances)
+}
+
+func getAllowancesReqHandler(cdc *wire.Codec, ctx context.CLIContext, path string) http.HandlerFunc {
+	responseType := "application/json"
+
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		addressStr, ok := vars["address"]
+		if !ok {
+			throw(w, http.StatusBadRequest, fmt.Errorf("miss request parameter `address`"))
+			return
+		}
+
+		address, err := sdk.AccAddressFromBech32(addressStr)
+		if err != nil {
+			throw(w, http.StatusBadRequest, fmt.Errorf("invalid address, address=%s", addressStr))
+			return
+		}
+
+		allowances, err := getAllowances(ctx, cdc, path, address)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// no need to use cdc here because we do not want amino to inject a type attribute
+		output, err := json.Marshal(allowances)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", responseType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(output)
+	}
+}
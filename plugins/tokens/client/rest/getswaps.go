@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/plugins/tokens/swap"
+	"github.com/bnb-chain/node/wire"
+)
+
+// QuerySwapsReqHandler creates an http request handler to query a page of AtomicSwap records by random number hash,
+// status or expire height range. The next page is queried with the `next_cursor` of the result as `cursor`.
+func QuerySwapsReqHandler(
+	cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	responseType := "application/json"
+
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+
+	parseInt := func(r *http.Request, name string) (int64, error) {
+		str := r.FormValue(name)
+		if str == "" {
+			return 0, nil
+		}
+		value, err := strconv.ParseInt(str, 10, 64)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("invalid %s", name)
+		}
+		return value, nil
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var params swap.QuerySwapsParams
+		var err error
+
+		params.RandomNumberHash, err = hex.DecodeString(r.FormValue("random_number_hash"))
+		if err != nil {
+			throw(w, http.StatusBadRequest, fmt.Errorf("invalid random_number_hash"))
+			return
+		}
+		if statusStr := r.FormValue("status"); statusStr != "" {
+			params.Status = swap.NewSwapStatusFromString(statusStr)
+			if params.Status == swap.NULL {
+				throw(w, http.StatusBadRequest, fmt.Errorf("invalid status"))
+				return
+			}
+		}
+		if params.MinExpireHeight, err = parseInt(r, "min_expire_height"); err != nil {
+			throw(w, http.StatusBadRequest, err)
+			return
+		}
+		if params.MaxExpireHeight, err = parseInt(r, "max_expire_height"); err != nil {
+			throw(w, http.StatusBadRequest, err)
+			return
+		}
+		params.Cursor, err = hex.DecodeString(r.FormValue("cursor"))
+		if err != nil {
+			throw(w, http.StatusBadRequest, fmt.Errorf("invalid cursor"))
+			return
+		}
+		if params.Limit, err = parseInt(r, "limit"); err != nil {
+			throw(w, http.StatusBadRequest, err)
+			return
+		}
+		if params.Limit == 0 {
+			params.Limit = swap.MaxQuerySwapsLimit
+		}
+
+		paramsBytes, err := cdc.MarshalJSON(params)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		bz, err := ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", swap.AtomicSwapRoute, swap.QuerySwaps), paramsBytes)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		var result swap.QuerySwapsResult
+		err = cdc.UnmarshalJSON(bz, &result)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// no need to use cdc here because we do not want amino to inject a type attribute
+		output, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", responseType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(output)
+	}
+}
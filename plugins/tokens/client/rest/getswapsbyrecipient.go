@@ -56,10 +56,20 @@ func QuerySwapIDsByRecipientReqHandler(
 			return
 		}
 
+		var status swap.SwapStatus
+		if statusStr := r.FormValue("status"); statusStr != "" {
+			status = swap.NewSwapStatusFromString(statusStr)
+			if status == swap.NULL {
+				throw(w, http.StatusBadRequest, fmt.Errorf("invalid status"))
+				return
+			}
+		}
+
 		params := swap.QuerySwapByRecipientParams{
 			Recipient: recipientAddr,
 			Limit:     int64(limit),
 			Offset:    int64(offset),
+			Status:    status,
 		}
 
 		paramsBytes, err := cdc.MarshalJSON(params)
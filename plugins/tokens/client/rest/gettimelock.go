@@ -14,7 +14,7 @@ import (
 	"github.com/bnb-chain/node/wire"
 )
 
-func getTimeLock(ctx context.CLIContext, cdc *wire.Codec, address sdk.AccAddress, id int64) (timelock.TimeLockRecord, error) {
+func getTimeLock(ctx context.CLIContext, cdc *wire.Codec, address sdk.AccAddress, id int64) (timelock.TimeLockRecordStatus, error) {
 	params := timelock.QueryTimeLockParams{
 		Account: address,
 		Id:      id,
@@ -22,18 +22,18 @@ func getTimeLock(ctx context.CLIContext, cdc *wire.Codec, address sdk.AccAddress
 
 	bz, err := cdc.MarshalJSON(params)
 	if err != nil {
-		return timelock.TimeLockRecord{}, err
+		return timelock.TimeLockRecordStatus{}, err
 	}
 
 	bz, err = ctx.QueryWithData(fmt.Sprintf("custom/%s/%s", timelock.MsgRoute, timelock.QueryTimeLock), bz)
 	if err != nil {
-		return timelock.TimeLockRecord{}, err
+		return timelock.TimeLockRecordStatus{}, err
 	}
 
-	var record timelock.TimeLockRecord
+	var record timelock.TimeLockRecordStatus
 	err = cdc.UnmarshalJSON(bz, &record)
 	if err != nil {
-		return timelock.TimeLockRecord{}, err
+		return timelock.TimeLockRecordStatus{}, err
 	}
 
 	return record, nil
@@ -14,7 +14,7 @@ import (
 	"github.com/bnb-chain/node/wire"
 )
 
-func getTimeLocks(ctx context.CLIContext, cdc *wire.Codec, address sdk.AccAddress) ([]timelock.TimeLockRecord, error) {
+func getTimeLocks(ctx context.CLIContext, cdc *wire.Codec, address sdk.AccAddress) ([]timelock.TimeLockRecordStatus, error) {
 	params := timelock.QueryTimeLocksParams{
 		Account: address,
 	}
@@ -29,7 +29,7 @@ func getTimeLocks(ctx context.CLIContext, cdc *wire.Codec, address sdk.AccAddres
 		return nil, err
 	}
 
-	var records []timelock.TimeLockRecord
+	var records []timelock.TimeLockRecordStatus
 	err = cdc.UnmarshalJSON(bz, &records)
 	if err != nil {
 		return nil, err
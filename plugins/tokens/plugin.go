@@ -12,6 +12,7 @@ import (
 	"github.com/bnb-chain/node/common/types"
 	app "github.com/bnb-chain/node/common/types"
 	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/tokens/allowance"
 	"github.com/bnb-chain/node/plugins/tokens/swap"
 	"github.com/bnb-chain/node/plugins/tokens/timelock"
 )
@@ -22,10 +23,10 @@ const miniAbciQueryPrefix = "mini-tokens"
 // InitPlugin initializes the plugin.
 func InitPlugin(
 	appp app.ChainApp, mapper Mapper, accKeeper auth.AccountKeeper, coinKeeper bank.Keeper,
-	timeLockKeeper timelock.Keeper, swapKeeper swap.Keeper) {
+	timeLockKeeper timelock.Keeper, swapKeeper swap.Keeper, allowanceKeeper allowance.Keeper) {
 	// add msg handlers
 	for route, handler := range Routes(mapper, accKeeper, coinKeeper, timeLockKeeper,
-		swapKeeper) {
+		swapKeeper, allowanceKeeper) {
 		appp.GetRouter().AddRoute(route, handler)
 	}
 
@@ -34,10 +35,10 @@ func InitPlugin(
 	miniTokenHandler := createQueryHandler(mapper, miniAbciQueryPrefix)
 	appp.RegisterQueryHandler(abciQueryPrefix, tokenHandler)
 	appp.RegisterQueryHandler(miniAbciQueryPrefix, miniTokenHandler)
-	RegisterUpgradeBeginBlocker(mapper)
+	RegisterUpgradeBeginBlocker(mapper, swapKeeper)
 }
 
-func RegisterUpgradeBeginBlocker(mapper Mapper) {
+func RegisterUpgradeBeginBlocker(mapper Mapper, swapKeeper swap.Keeper) {
 	// bind bnb smart chain contract address to bnb token
 	upgrade.Mgr.RegisterBeginBlocker(upgrade.LaunchBscUpgrade, func(ctx sdk.Context) {
 		err := mapper.UpdateBind(ctx, types.NativeTokenSymbol, "0x0000000000000000000000000000000000000000", 18)
@@ -45,6 +46,10 @@ func RegisterUpgradeBeginBlocker(mapper Mapper) {
 			panic(err)
 		}
 	})
+	// index the swaps created before the upgrade
+	upgrade.Mgr.RegisterBeginBlocker(upgrade.SwapIndexUpgrade, func(ctx sdk.Context) {
+		swapKeeper.BuildSwapIndexes(ctx)
+	})
 }
 
 func createQueryHandler(mapper Mapper, queryPrefix string) app.AbciQueryHandler {
@@ -99,7 +104,7 @@ func EndBlocker(ctx sdk.Context, timelockKeeper timelock.Keeper, swapKeeper swap
 		if swapItem == nil {
 			continue
 		}
-		if swapItem.Status != swap.Open {
+		if !swapItem.IsOpen() {
 			continue
 		}
 		result := swap.HandleRefundHashTimerLockedTransferAfterBCFusion(ctx, swapKeeper, swap.RefundHTLTMsg{
@@ -5,6 +5,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
 
+	"github.com/bnb-chain/node/plugins/tokens/allowance"
 	"github.com/bnb-chain/node/plugins/tokens/burn"
 	"github.com/bnb-chain/node/plugins/tokens/freeze"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
@@ -16,7 +17,7 @@ import (
 )
 
 func Routes(tokenMapper store.Mapper, accKeeper auth.AccountKeeper, keeper bank.Keeper,
-	timeLockKeeper timelock.Keeper, swapKeeper swap.Keeper) map[string]sdk.Handler {
+	timeLockKeeper timelock.Keeper, swapKeeper swap.Keeper, allowanceKeeper allowance.Keeper) map[string]sdk.Handler {
 	routes := make(map[string]sdk.Handler)
 	routes[issue.Route] = issue.NewHandler(tokenMapper, keeper)
 	routes[burn.BurnRoute] = burn.NewHandler(tokenMapper, keeper)
@@ -25,5 +26,6 @@ func Routes(tokenMapper store.Mapper, accKeeper auth.AccountKeeper, keeper bank.
 	routes[swap.AtomicSwapRoute] = swap.NewHandler(swapKeeper)
 	routes[seturi.SetURIRoute] = seturi.NewHandler(tokenMapper)
 	routes[ownership.Route] = ownership.NewHandler(tokenMapper, keeper)
+	routes[allowance.MsgRoute] = allowance.NewHandler(allowanceKeeper)
 	return routes
 }
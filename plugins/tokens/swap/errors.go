@@ -23,6 +23,7 @@ const (
 	CodeInvalidSingleChainSwap         sdk.CodeType = 14
 	CodeInvalidExpectedIncome          sdk.CodeType = 15
 	CodeUnexpectedClaimSingleChainSwap sdk.CodeType = 16
+	CodeInvalidClaimAmount             sdk.CodeType = 17
 )
 
 func ErrInvalidAddrOtherChain(msg string) sdk.Error {
@@ -88,3 +89,7 @@ func ErrInvalidExpectedIncome(msg string) sdk.Error {
 func ErrUnexpectedClaimSingleChainSwap(msg string) sdk.Error {
 	return sdk.NewError(DefaultCodespace, CodeUnexpectedClaimSingleChainSwap, msg)
 }
+
+func ErrInvalidClaimAmount(msg string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInvalidClaimAmount, msg)
+}
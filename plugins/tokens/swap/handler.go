@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func NewHandler(kp Keeper) sdk.Handler {
@@ -89,14 +91,22 @@ func handleDepositHashTimerLockedTransfer(ctx sdk.Context, kp Keeper, msg Deposi
 		return ErrInvalidSingleChainSwap(fmt.Sprintf("Addresses don't match, expected deposit from %s and recipient %s", swap.To.String(), swap.From.String())).Result()
 	}
 	if !swap.InAmount.IsZero() {
-		return ErrInvalidSingleChainSwap("Can't deposit a swap for multiple times").Result()
+		// a swap expecting several assets can be deposited asset by asset, up to the expected income of each asset
+		expectedIncome, ok := swap.ExpectedIncomeCoins()
+		if !ok || !sdk.IsUpgrade(upgrade.SwapMultiAssetDepositUpgrade) {
+			return ErrInvalidSingleChainSwap("Can't deposit a swap for multiple times").Result()
+		}
+		if !expectedIncome.IsGTE(swap.InAmount.Plus(msg.Amount)) {
+			return ErrInvalidSingleChainSwap(fmt.Sprintf("The deposited coins(%s) would exceed the expected income(%s)",
+				swap.InAmount.Plus(msg.Amount).String(), expectedIncome.String())).Result()
+		}
 	}
 	tags, err := kp.ck.SendCoins(ctx, msg.From, AtomicSwapCoinsAccAddr, msg.Amount)
 	if err != nil {
 		return err.Result()
 	}
 
-	swap.InAmount = msg.Amount
+	swap.InAmount = swap.InAmount.Plus(msg.Amount)
 	err = kp.UpdateSwap(ctx, msg.SwapID, swap)
 	if err != nil {
 		kp.logger.Error("Failed to update swap", "err", err.Error())
@@ -112,7 +122,7 @@ func handleClaimHashTimerLockedTransfer(ctx sdk.Context, kp Keeper, msg ClaimHTL
 	if swap == nil {
 		return ErrNonExistSwapID(fmt.Sprintf("No matched swap with swapID %v", msg.SwapID)).Result()
 	}
-	if swap.Status != Open {
+	if !swap.IsOpen() {
 		return ErrUnexpectedSwapStatus(fmt.Sprintf("Expected swap status is Open, actually it is %s", swap.Status.String())).Result()
 	}
 	if swap.ExpireHeight <= ctx.BlockHeight() {
@@ -127,11 +137,24 @@ func handleClaimHashTimerLockedTransfer(ctx sdk.Context, kp Keeper, msg ClaimHTL
 		return ErrUnexpectedClaimSingleChainSwap("Can't claim a single chain swap which has not been deposited").Result()
 	}
 
+	remainingAmount := swap.RemainingAmount()
+	claimAmount := remainingAmount
+	if len(msg.Amount) != 0 {
+		if !swap.CrossChain {
+			return ErrInvalidSingleChainSwap("Can't claim a single chain swap partially").Result()
+		}
+		if !remainingAmount.IsGTE(msg.Amount) {
+			return ErrInvalidClaimAmount(fmt.Sprintf("The claimed coins(%s) exceed the remaining coins(%s) of the swap",
+				msg.Amount.String(), remainingAmount.String())).Result()
+		}
+		claimAmount = msg.Amount
+	}
+
 	tags := sdk.EmptyTags()
-	if !swap.OutAmount.IsZero() {
-		sendCoinTags, err := kp.ck.SendCoins(ctx, AtomicSwapCoinsAccAddr, swap.To, swap.OutAmount)
+	if !claimAmount.IsZero() {
+		sendCoinTags, err := kp.ck.SendCoins(ctx, AtomicSwapCoinsAccAddr, swap.To, claimAmount)
 		if err != nil {
-			kp.logger.Error("Failed to send coins", "sender", AtomicSwapCoinsAccAddr.String(), "recipient", swap.To.String(), "amount", swap.OutAmount.String(), "err", err.Error())
+			kp.logger.Error("Failed to send coins", "sender", AtomicSwapCoinsAccAddr.String(), "recipient", swap.To.String(), "amount", claimAmount.String(), "err", err.Error())
 			return err.Result()
 		}
 		tags = tags.AppendTags(sendCoinTags)
@@ -148,12 +171,26 @@ func handleClaimHashTimerLockedTransfer(ctx sdk.Context, kp Keeper, msg ClaimHTL
 		if !bytes.Equal(msg.From, swap.From) && !swap.InAmount.IsZero() {
 			kp.addrPool.AddAddrs([]sdk.AccAddress{swap.From})
 		}
-		if !bytes.Equal(msg.From, swap.To) && !swap.OutAmount.IsZero() {
+		if !bytes.Equal(msg.From, swap.To) && !claimAmount.IsZero() {
 			kp.addrPool.AddAddrs([]sdk.AccAddress{swap.To})
 		}
 	}
 
 	swap.RandomNumber = msg.RandomNumber
+	if !claimAmount.IsEqual(remainingAmount) {
+		swap.ClaimedAmount = swap.ClaimedAmount.Plus(claimAmount)
+		swap.Status = PartiallyCompleted
+		err := kp.UpdateSwap(ctx, msg.SwapID, swap)
+		if err != nil {
+			kp.logger.Error("Failed to update swap", "err", err.Error())
+			return err.Result()
+		}
+		return sdk.Result{Tags: tags}
+	}
+
+	if !swap.ClaimedAmount.IsZero() {
+		swap.ClaimedAmount = swap.OutAmount
+	}
 	swap.Status = Completed
 	swap.ClosedTime = ctx.BlockHeader().Time.Unix()
 	err := kp.CloseSwap(ctx, msg.SwapID, swap)
@@ -173,7 +210,7 @@ func handleRefundHashTimerLockedTransfer(ctx sdk.Context, kp Keeper, msg RefundH
 	if swap == nil {
 		return ErrNonExistSwapID(fmt.Sprintf("No matched swap with swapID %v", msg.SwapID)).Result()
 	}
-	if swap.Status != Open {
+	if !swap.IsOpen() {
 		return ErrUnexpectedSwapStatus(fmt.Sprintf("Expected swap status is Open, actually it is %s", swap.Status.String())).Result()
 	}
 	if !isBCFusionRefund {
@@ -182,11 +219,13 @@ func handleRefundHashTimerLockedTransfer(ctx sdk.Context, kp Keeper, msg RefundH
 		}
 	}
 
+	// only the part of a partially completed swap which has not been claimed is refunded
+	refundAmount := swap.RemainingAmount()
 	tags := sdk.EmptyTags()
-	if !swap.OutAmount.IsZero() {
-		sendCoinTags, err := kp.ck.SendCoins(ctx, AtomicSwapCoinsAccAddr, swap.From, swap.OutAmount)
+	if !refundAmount.IsZero() {
+		sendCoinTags, err := kp.ck.SendCoins(ctx, AtomicSwapCoinsAccAddr, swap.From, refundAmount)
 		if err != nil {
-			kp.logger.Error("Failed to send coins", "sender", AtomicSwapCoinsAccAddr.String(), "recipient", swap.From.String(), "amount", swap.OutAmount.String(), "err", err.Error())
+			kp.logger.Error("Failed to send coins", "sender", AtomicSwapCoinsAccAddr.String(), "recipient", swap.From.String(), "amount", refundAmount.String(), "err", err.Error())
 			return err.Result()
 		}
 		tags = tags.AppendTags(sendCoinTags)
@@ -200,7 +239,7 @@ func handleRefundHashTimerLockedTransfer(ctx sdk.Context, kp Keeper, msg RefundH
 		tags = tags.AppendTags(sendCoinTags)
 	}
 	if ctx.IsDeliverTx() && kp.addrPool != nil {
-		if !bytes.Equal(msg.From, swap.From) && !swap.OutAmount.IsZero() {
+		if !bytes.Equal(msg.From, swap.From) && !refundAmount.IsZero() {
 			kp.addrPool.AddAddrs([]sdk.AccAddress{swap.From})
 		}
 		if !bytes.Equal(msg.From, swap.To) && !swap.InAmount.IsZero() {
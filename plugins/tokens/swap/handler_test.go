@@ -14,6 +14,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func setup() (sdk.Context, sdk.Handler, Keeper, auth.AccountKeeper) {
@@ -284,3 +285,141 @@ func TestHandleCreateAndRefundSwapForSingleChain(t *testing.T) {
 	acc2Acc := accKeeper.GetAccount(ctx, acc2.GetAddress())
 	require.Equal(t, acc2OrignalCoins, acc2Acc.GetCoins())
 }
+
+func TestHandlePartialClaimAndRefundSwap(t *testing.T) {
+	ctx, handler, swapKeeper, accKeeper := setup()
+	ctx = ctx.WithBlockTime(time.Now())
+	ctx = ctx.WithBlockHeight(10)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SwapPartialClaimUpgrade, 1)
+	upgrade.Mgr.SetHeight(1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
+	_, acc1 := testutils.NewAccount(ctx, accKeeper, 10000e8)
+	_, acc2 := testutils.NewAccount(ctx, accKeeper, 0)
+
+	acc1Coins := acc1.GetCoins().Plus(sdk.Coins{sdk.NewCoin("ABC-123", 1000e8)})
+	_ = acc1.SetCoins(acc1Coins)
+	accKeeper.SetAccount(ctx, acc1)
+
+	randomNumberStr := "52fdfc072182654f163f5f0f9a621d729566c74d10037c4d7bbb0407d1e2c649"
+	randomNumber, _ := hex.DecodeString(randomNumberStr)
+	timestamp := time.Now().Unix()
+	randomNumberHash := CalculateRandomHash(randomNumber, timestamp)
+
+	recipientOtherChain := "491e71b619878c083eaf2894718383c7eb15eb17"
+	senderOtherChain := "833914c3A745d924bf71d98F9F9Ae126993E3C88"
+	amount := sdk.Coins{sdk.NewCoin("ABC-123", 5000), sdk.NewCoin("BNB", 10000)}
+	heightSpan := int64(1000)
+
+	var msg sdk.Msg
+	msg = NewHTLTMsg(acc1.GetAddress(), acc2.GetAddress(), recipientOtherChain, senderOtherChain, randomNumberHash, timestamp, amount, "10000:ETH", heightSpan, true)
+	result := handler(ctx, msg)
+	require.Equal(t, sdk.ABCICodeOK, result.Code)
+	swapID := SwapBytes(result.Data)
+
+	claimMsg := NewClaimHTLTMsg(acc2.GetAddress(), swapID, randomNumber)
+	claimMsg.Amount = sdk.Coins{sdk.NewCoin("BNB", 20000)}
+	result = handler(ctx, claimMsg)
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidClaimAmount), result.Code)
+
+	claimMsg.Amount = sdk.Coins{sdk.NewCoin("ABC-123", 1000), sdk.NewCoin("BNB", 4000)}
+	result = handler(ctx, claimMsg)
+	require.Equal(t, sdk.ABCICodeOK, result.Code)
+
+	swap := swapKeeper.GetSwap(ctx, swapID)
+	require.Equal(t, PartiallyCompleted, swap.Status)
+	require.Equal(t, claimMsg.Amount, swap.ClaimedAmount)
+	require.Equal(t, claimMsg.Amount, accKeeper.GetAccount(ctx, acc2.GetAddress()).GetCoins())
+
+	claimMsg.Amount = sdk.Coins{sdk.NewCoin("BNB", 1000)}
+	result = handler(ctx, claimMsg)
+	require.Equal(t, sdk.ABCICodeOK, result.Code)
+
+	// the rest is refunded after the expire height
+	ctx = ctx.WithBlockHeight(2000)
+	msg = NewRefundHTLTMsg(acc1.GetAddress(), swapID)
+	result = handler(ctx, msg)
+	require.Equal(t, sdk.ABCICodeOK, result.Code)
+
+	AtomicSwapCoinsAcc := accKeeper.GetAccount(ctx, AtomicSwapCoinsAccAddr)
+	require.Equal(t, 0, len(AtomicSwapCoinsAcc.GetCoins()))
+	acc1Acc := accKeeper.GetAccount(ctx, acc1.GetAddress())
+	require.Equal(t, int64(1000e8-1000), acc1Acc.GetCoins().AmountOf("ABC-123"))
+	require.Equal(t, int64(10000e8-5000), acc1Acc.GetCoins().AmountOf("BNB"))
+
+	swap = swapKeeper.GetSwap(ctx, swapID)
+	require.Equal(t, Expired, swap.Status)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("ABC-123", 1000), sdk.NewCoin("BNB", 5000)}, swap.ClaimedAmount)
+}
+
+func TestHandleDepositMultiAssetSwapForSingleChain(t *testing.T) {
+	ctx, handler, swapKeeper, accKeeper := setup()
+	ctx = ctx.WithBlockTime(time.Now())
+	ctx = ctx.WithBlockHeight(10)
+
+	_, acc1 := testutils.NewAccount(ctx, accKeeper, 10000e8)
+	_, acc2 := testutils.NewAccount(ctx, accKeeper, 10000e8)
+
+	acc2Coins := acc2.GetCoins().Plus(sdk.Coins{sdk.NewCoin("ABC-123", 1000e8)})
+	_ = acc2.SetCoins(acc2Coins)
+	accKeeper.SetAccount(ctx, acc2)
+
+	randomNumberStr := "52fdfc072182654f163f5f0f9a621d729566c74d10037c4d7bbb0407d1e2c649"
+	randomNumber, _ := hex.DecodeString(randomNumberStr)
+	timestamp := time.Now().Unix()
+	randomNumberHash := CalculateRandomHash(randomNumber, timestamp)
+
+	amountBNB := sdk.Coins{sdk.NewCoin("BNB", 10000)}
+	heightSpan := int64(1000)
+
+	var msg sdk.Msg
+	msg = NewHTLTMsg(acc1.GetAddress(), acc2.GetAddress(), "", "", randomNumberHash, timestamp, amountBNB, "100:ABC-123,200:BNB", heightSpan, false)
+	result := handler(ctx, msg)
+	require.Equal(t, sdk.ABCICodeOK, result.Code)
+	swapID := SwapBytes(result.Data)
+
+	msg = NewDepositHTLTMsg(acc2.GetAddress(), sdk.Coins{sdk.NewCoin("ABC-123", 100)}, swapID)
+	result = handler(ctx, msg)
+	require.Equal(t, sdk.ABCICodeOK, result.Code)
+
+	// the swap can be deposited only once before the upgrade
+	msg = NewDepositHTLTMsg(acc2.GetAddress(), sdk.Coins{sdk.NewCoin("BNB", 200)}, swapID)
+	result = handler(ctx, msg)
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidSingleChainSwap), result.Code)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SwapMultiAssetDepositUpgrade, 1)
+	upgrade.Mgr.SetHeight(1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
+	msg = NewDepositHTLTMsg(acc2.GetAddress(), sdk.Coins{sdk.NewCoin("BNB", 300)}, swapID)
+	result = handler(ctx, msg)
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidSingleChainSwap), result.Code)
+
+	msg = NewDepositHTLTMsg(acc2.GetAddress(), sdk.Coins{sdk.NewCoin("BNB", 200)}, swapID)
+	result = handler(ctx, msg)
+	require.Equal(t, sdk.ABCICodeOK, result.Code)
+
+	swap := swapKeeper.GetSwap(ctx, swapID)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("ABC-123", 100), sdk.NewCoin("BNB", 200)}, swap.InAmount)
+
+	// a single chain swap can't be claimed partially
+	claimMsg := NewClaimHTLTMsg(acc2.GetAddress(), swapID, randomNumber)
+	claimMsg.Amount = sdk.Coins{sdk.NewCoin("BNB", 5000)}
+	result = handler(ctx, claimMsg)
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidSingleChainSwap), result.Code)
+
+	result = handler(ctx, NewClaimHTLTMsg(acc2.GetAddress(), swapID, randomNumber))
+	require.Equal(t, sdk.ABCICodeOK, result.Code)
+
+	swap = swapKeeper.GetSwap(ctx, swapID)
+	require.Equal(t, Completed, swap.Status)
+	require.Nil(t, swap.ClaimedAmount)
+	require.Equal(t, int64(100), accKeeper.GetAccount(ctx, acc1.GetAddress()).GetCoins().AmountOf("ABC-123"))
+}
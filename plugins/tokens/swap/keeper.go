@@ -12,6 +12,7 @@ import (
 	tmlog "github.com/tendermint/tendermint/libs/log"
 
 	bnclog "github.com/bnb-chain/node/common/log"
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 var (
@@ -66,6 +67,9 @@ func (kp *Keeper) CreateSwap(ctx sdk.Context, swapID SwapBytes, swap *AtomicSwap
 	binary.BigEndian.PutUint64(indexBytes, uint64(swap.Index+1))
 	kvStore.Set(SwapIndexKey, indexBytes)
 
+	if sdk.IsUpgrade(upgrade.SwapIndexUpgrade) {
+		setSwapIndexes(kvStore, swapID, swap)
+	}
 	return nil
 }
 
@@ -78,6 +82,7 @@ func (kp *Keeper) UpdateSwap(ctx sdk.Context, swapID SwapBytes, swap *AtomicSwap
 	if !kvStore.Has(hashKey) {
 		return sdk.ErrInternal(fmt.Sprintf("Trying to close non-exist swapID %v", swapID))
 	}
+	kp.updateSwapStatusIndex(ctx, swapID, swap)
 	kvStore.Set(hashKey, kp.cdc.MustMarshalBinaryBare(*swap))
 
 	return nil
@@ -95,6 +100,7 @@ func (kp *Keeper) CloseSwap(ctx sdk.Context, swapID SwapBytes, swap *AtomicSwap)
 	if !kvStore.Has(hashKey) {
 		return sdk.ErrInternal(fmt.Sprintf("Trying to close non-exist swapID %v", swapID))
 	}
+	kp.updateSwapStatusIndex(ctx, swapID, swap)
 	kvStore.Set(hashKey, kp.cdc.MustMarshalBinaryBare(*swap))
 
 	closeTimeKey := BuildCloseTimeKey(swap.ClosedTime, swap.Index)
@@ -120,9 +126,50 @@ func (kp *Keeper) DeleteSwap(ctx sdk.Context, swapID SwapBytes, swap *AtomicSwap
 	closeTimeKey := BuildCloseTimeKey(swap.ClosedTime, swap.Index)
 	kvStore.Delete(closeTimeKey)
 
+	if sdk.IsUpgrade(upgrade.SwapIndexUpgrade) {
+		kvStore.Delete(BuildSwapRandomNumberHashKey(swap.RandomNumberHash, swap.Index))
+		kvStore.Delete(BuildSwapStatusKey(swap.Status, swap.Index))
+		kvStore.Delete(BuildSwapExpireHeightKey(swap.ExpireHeight, swap.Index))
+	}
 	return nil
 }
 
+// updateSwapStatusIndex moves the swap to the index of its new status, it must be called before the swap is stored
+func (kp *Keeper) updateSwapStatusIndex(ctx sdk.Context, swapID SwapBytes, swap *AtomicSwap) {
+	if !sdk.IsUpgrade(upgrade.SwapIndexUpgrade) {
+		return
+	}
+	stored := kp.GetSwap(ctx, swapID)
+	if stored == nil || stored.Status == swap.Status {
+		return
+	}
+	kvStore := ctx.KVStore(kp.storeKey)
+	kvStore.Delete(BuildSwapStatusKey(stored.Status, stored.Index))
+	kvStore.Set(BuildSwapStatusKey(swap.Status, swap.Index), swapID)
+}
+
+func setSwapIndexes(kvStore sdk.KVStore, swapID SwapBytes, swap *AtomicSwap) {
+	kvStore.Set(BuildSwapRandomNumberHashKey(swap.RandomNumberHash, swap.Index), swapID)
+	kvStore.Set(BuildSwapStatusKey(swap.Status, swap.Index), swapID)
+	kvStore.Set(BuildSwapExpireHeightKey(swap.ExpireHeight, swap.Index), swapID)
+}
+
+// BuildSwapIndexes indexes the swaps stored before SwapIndexUpgrade by random number hash, status and expire height
+func (kp *Keeper) BuildSwapIndexes(ctx sdk.Context) {
+	kvStore := ctx.KVStore(kp.storeKey)
+	iterator := kp.GetSwapIterator(ctx)
+	defer iterator.Close()
+	count := 0
+	for ; iterator.Valid(); iterator.Next() {
+		var swap AtomicSwap
+		kp.cdc.MustUnmarshalBinaryBare(iterator.Value(), &swap)
+		swapID := append(SwapBytes{}, iterator.Key()[len(HashKey):]...)
+		setSwapIndexes(kvStore, swapID, &swap)
+		count++
+	}
+	kp.logger.Info("Built the indexes of swaps", "count", count)
+}
+
 func (kp *Keeper) DeleteKey(ctx sdk.Context, key []byte) {
 	kvStore := ctx.KVStore(kp.storeKey)
 	kvStore.Delete(key)
@@ -161,6 +208,12 @@ func (kp *Keeper) GetSwapCloseTimeIterator(ctx sdk.Context) (iterator store.Iter
 	return sdk.KVStorePrefixIterator(kvStore, BuildCloseTimeQueueKey())
 }
 
+// GetSwapIndexIterator iterates the swapIDs of an index in [start, end), the keys are built by the Build*Key of the index
+func (kp *Keeper) GetSwapIndexIterator(ctx sdk.Context, start, end []byte) (iterator store.Iterator) {
+	kvStore := ctx.KVStore(kp.storeKey)
+	return kvStore.Iterator(start, end)
+}
+
 func (kp *Keeper) getIndex(ctx sdk.Context) int64 {
 	kvStore := ctx.KVStore(kp.storeKey)
 	bz := kvStore.Get(SwapIndexKey)
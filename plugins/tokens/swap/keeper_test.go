@@ -20,6 +20,7 @@ import (
 	"github.com/bnb-chain/node/common"
 	"github.com/bnb-chain/node/common/testutils"
 	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/wire"
 )
 
@@ -262,3 +263,101 @@ func TestKeeper_DeleteSwap(t *testing.T) {
 	closeTimeIterator.Close()
 
 }
+
+func TestKeeper_SwapIndexes(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SwapIndexUpgrade, 100)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accountCache := getAccountCache(cdc, cms)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeDeliver, logger).WithAccountCache(accountCache)
+
+	_, acc1 := testutils.NewAccount(ctx, accKeeper, 10000e8)
+	_, acc2 := testutils.NewAccount(ctx, accKeeper, 10000e8)
+
+	newSwap := func(index int64, expireHeight int64) (SwapBytes, *AtomicSwap) {
+		randomNumberHash := make([]byte, RandomNumberHashLength)
+		randomNumberHash[0] = byte(index + 1)
+		swap := &AtomicSwap{
+			From:             acc1.GetAddress(),
+			To:               acc2.GetAddress(),
+			OutAmount:        sdk.Coins{sdk.NewCoin("BNB", 10000)},
+			ExpectedIncome:   "10000:BNB",
+			RandomNumberHash: randomNumberHash,
+			Timestamp:        1564471835,
+			ExpireHeight:     expireHeight,
+			Status:           Open,
+			Index:            index,
+		}
+		return CalculateSwapID(randomNumberHash, swap.From, ""), swap
+	}
+	query := func(params QuerySwapsParams) QuerySwapsResult {
+		bz, err := cdc.MarshalJSON(params)
+		require.NoError(t, err)
+		res, sdkErr := NewQuerier(keeper)(ctx, []string{QuerySwaps}, abci.RequestQuery{Data: bz})
+		require.Nil(t, sdkErr)
+		var result QuerySwapsResult
+		require.NoError(t, cdc.UnmarshalJSON(res, &result))
+		return result
+	}
+
+	// swaps created before the upgrade are not indexed
+	upgrade.Mgr.SetHeight(10)
+	swapID0, swap0 := newSwap(0, 1000)
+	require.NoError(t, keeper.CreateSwap(ctx, swapID0, swap0))
+	_, err := NewQuerier(keeper)(ctx, []string{QuerySwaps}, abci.RequestQuery{})
+	require.NotNil(t, err)
+
+	upgrade.Mgr.SetHeight(100)
+	keeper.BuildSwapIndexes(ctx)
+	swapID1, swap1 := newSwap(1, 2000)
+	require.NoError(t, keeper.CreateSwap(ctx, swapID1, swap1))
+	swapID2, swap2 := newSwap(2, 3000)
+	require.NoError(t, keeper.CreateSwap(ctx, swapID2, swap2))
+
+	swap0.Status = Completed
+	swap0.ClosedTime = 1564471900
+	require.NoError(t, keeper.CloseSwap(ctx, swapID0, swap0))
+
+	result := query(QuerySwapsParams{Status: Completed, Limit: 10})
+	require.Len(t, result.Swaps, 1)
+	require.Equal(t, swapID0, result.Swaps[0].SwapID)
+	require.Empty(t, result.NextCursor)
+
+	result = query(QuerySwapsParams{Status: Open, Limit: 1})
+	require.Len(t, result.Swaps, 1)
+	require.Equal(t, swapID1, result.Swaps[0].SwapID)
+	require.NotEmpty(t, result.NextCursor)
+	result = query(QuerySwapsParams{Status: Open, Cursor: result.NextCursor, Limit: 1})
+	require.Len(t, result.Swaps, 1)
+	require.Equal(t, swapID2, result.Swaps[0].SwapID)
+	require.Empty(t, result.NextCursor)
+
+	result = query(QuerySwapsParams{RandomNumberHash: swap2.RandomNumberHash, Limit: 10})
+	require.Len(t, result.Swaps, 1)
+	require.Equal(t, swapID2, result.Swaps[0].SwapID)
+
+	result = query(QuerySwapsParams{MinExpireHeight: 1000, MaxExpireHeight: 2000, Limit: 10})
+	require.Len(t, result.Swaps, 2)
+	require.Equal(t, swapID0, result.Swaps[0].SwapID)
+	require.Equal(t, swapID1, result.Swaps[1].SwapID)
+
+	result = query(QuerySwapsParams{Status: Open, MinExpireHeight: 2500, Limit: 10})
+	require.Len(t, result.Swaps, 1)
+	require.Equal(t, swapID2, result.Swaps[0].SwapID)
+
+	require.NoError(t, keeper.DeleteSwap(ctx, swapID1, swap1))
+	result = query(QuerySwapsParams{Status: Open, Limit: 10})
+	require.Len(t, result.Swaps, 1)
+	require.Equal(t, swapID2, result.Swaps[0].SwapID)
+	iterator := keeper.GetSwapIndexIterator(ctx, BuildSwapRandomNumberHashQueueKey(swap1.RandomNumberHash),
+		sdk.PrefixEndBytes(BuildSwapRandomNumberHashQueueKey(swap1.RandomNumberHash)))
+	require.False(t, iterator.Valid())
+	iterator.Close()
+}
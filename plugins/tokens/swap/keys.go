@@ -16,6 +16,11 @@ var (
 	SwapRecipientQueueKey = []byte{0x03}
 	SwapCloseTimeKey      = []byte{0x04}
 	SwapIndexKey          = []byte{0x05}
+
+	// the indexes below are kept since SwapIndexUpgrade
+	SwapRandomNumberHashKey = []byte{0x06}
+	SwapStatusKey           = []byte{0x07}
+	SwapExpireHeightKey     = []byte{0x08}
 )
 
 func BuildHashKey(randomNumberHash []byte) []byte {
@@ -66,3 +71,45 @@ func BuildCloseTimeKey(unixTime int64, index int64) []byte {
 func BuildCloseTimeQueueKey() []byte {
 	return SwapCloseTimeKey
 }
+
+func BuildSwapRandomNumberHashKey(randomNumberHash []byte, index int64) []byte {
+	// prefix + randomNumberHash + index
+	key := make([]byte, 1+RandomNumberHashLength+Int64Size)
+	copy(key[:1], SwapRandomNumberHashKey)
+	copy(key[1:1+RandomNumberHashLength], randomNumberHash)
+	binary.BigEndian.PutUint64(key[1+RandomNumberHashLength:], uint64(index))
+	return key
+}
+
+func BuildSwapRandomNumberHashQueueKey(randomNumberHash []byte) []byte {
+	key := make([]byte, 1+RandomNumberHashLength)
+	copy(key[:1], SwapRandomNumberHashKey)
+	copy(key[1:], randomNumberHash)
+	return key
+}
+
+func BuildSwapStatusKey(status SwapStatus, index int64) []byte {
+	// prefix + status + index
+	key := make([]byte, 1+1+Int64Size)
+	copy(key[:1], SwapStatusKey)
+	key[1] = byte(status)
+	binary.BigEndian.PutUint64(key[2:], uint64(index))
+	return key
+}
+
+func BuildSwapStatusQueueKey(status SwapStatus) []byte {
+	return []byte{SwapStatusKey[0], byte(status)}
+}
+
+func BuildSwapExpireHeightKey(height int64, index int64) []byte {
+	// prefix + expireHeight + index
+	key := make([]byte, 1+Int64Size+Int64Size)
+	copy(key[:1], SwapExpireHeightKey)
+	binary.BigEndian.PutUint64(key[1:1+Int64Size], uint64(height))
+	binary.BigEndian.PutUint64(key[1+Int64Size:], uint64(index))
+	return key
+}
+
+func BuildSwapExpireHeightQueueKey() []byte {
+	return SwapExpireHeightKey
+}
@@ -177,10 +177,13 @@ func (msg DepositHTLTMsg) GetSignBytes() []byte {
 
 var _ sdk.Msg = ClaimHTLTMsg{}
 
+// ClaimHTLTMsg claims the swap with the random number. The whole remaining amount of the swap is claimed
+// if Amount is empty, otherwise only Amount is claimed from a cross chain swap.
 type ClaimHTLTMsg struct {
 	From         sdk.AccAddress `json:"from"`
 	SwapID       SwapBytes      `json:"swap_id"`
 	RandomNumber SwapBytes      `json:"random_number"`
+	Amount       sdk.Coins      `json:"amount,omitempty"`
 }
 
 func NewClaimHTLTMsg(from sdk.AccAddress, swapID, randomNumber SwapBytes) ClaimHTLTMsg {
@@ -213,6 +216,18 @@ func (msg ClaimHTLTMsg) ValidateBasic() sdk.Error {
 	if len(msg.RandomNumber) != RandomNumberLength {
 		return ErrInvalidRandomNumber(fmt.Sprintf("The length of random number should be %d", RandomNumberLength))
 	}
+	if len(msg.Amount) != 0 {
+		if !sdk.IsUpgrade(upgrade.SwapPartialClaimUpgrade) {
+			return sdk.ErrMsgNotSupported("partial claim of a swap is not supported yet")
+		}
+		if !msg.Amount.IsValid() || !msg.Amount.IsPositive() {
+			return ErrInvalidClaimAmount("The claimed coins must be positive")
+		}
+		symbolError := types.ValidateTokenSymbols(msg.Amount)
+		if symbolError != nil {
+			return sdk.ErrInvalidCoins(symbolError.Error())
+		}
+	}
 	return nil
 }
 
@@ -8,6 +8,8 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/mock"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func TestHTLTMsg(t *testing.T) {
@@ -245,6 +247,7 @@ func TestClaimHTLTMsg(t *testing.T) {
 		From         sdk.AccAddress
 		SwapID       string
 		RandomNumber string
+		Amount       sdk.Coins
 		Pass         bool
 		ErrorCode    sdk.CodeType
 	}{
@@ -276,12 +279,44 @@ func TestClaimHTLTMsg(t *testing.T) {
 			Pass:         false,
 			ErrorCode:    CodeInvalidSwapID,
 		},
+		{
+			From:         addrs[0],
+			RandomNumber: "52fdfc072182654f163f5f0f9a621d729566c74d10037c4d7bbb0407d1e2c649",
+			SwapID:       "be543130668282f267580badb1c956dacd4502be3b57846443c9921118ffa167",
+			Amount:       sdk.Coins{sdk.NewCoin("BNB", 10000)},
+			Pass:         true,
+			ErrorCode:    0,
+		},
+		{
+			From:         addrs[0],
+			RandomNumber: "52fdfc072182654f163f5f0f9a621d729566c74d10037c4d7bbb0407d1e2c649",
+			SwapID:       "be543130668282f267580badb1c956dacd4502be3b57846443c9921118ffa167",
+			Amount:       sdk.Coins{sdk.NewCoin("BNB", 0)},
+			Pass:         false,
+			ErrorCode:    CodeInvalidClaimAmount,
+		},
 	}
 
+	randomNumber, _ := hex.DecodeString(tests[0].RandomNumber)
+	swapID, _ := hex.DecodeString(tests[0].SwapID)
+	partialClaimMsg := NewClaimHTLTMsg(addrs[0], swapID, randomNumber)
+	partialClaimMsg.Amount = sdk.Coins{sdk.NewCoin("BNB", 10000)}
+	err := partialClaimMsg.ValidateBasic()
+	require.NotNil(t, err)
+	require.Equal(t, sdk.CodeMsgNotSupported, err.Code())
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.SwapPartialClaimUpgrade, 1)
+	upgrade.Mgr.SetHeight(1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
 	for i, tc := range tests {
 		randomNumber, _ := hex.DecodeString(tc.RandomNumber)
 		swapID, _ := hex.DecodeString(tc.SwapID)
 		msg := NewClaimHTLTMsg(tc.From, swapID, randomNumber)
+		msg.Amount = tc.Amount
 
 		err := msg.ValidateBasic()
 		if tc.Pass {
@@ -1,17 +1,24 @@
 package swap
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 const (
 	QuerySwapID        = "swapid"
 	QuerySwapCreator   = "swapcreator"
 	QuerySwapRecipient = "swaprecipient"
+	QuerySwaps         = "swaps"
+
+	MaxQuerySwapsLimit = 100
 )
 
 func NewQuerier(keeper Keeper) sdk.Querier {
@@ -23,6 +30,8 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return querySwapByCreator(ctx, req, keeper)
 		case QuerySwapRecipient:
 			return querySwapByRecipient(ctx, req, keeper)
+		case QuerySwaps:
+			return querySwaps(ctx, req, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown atomic swap query endpoint %s", path[0]))
 		}
@@ -64,6 +73,8 @@ type QuerySwapByCreatorParams struct {
 	Creator sdk.AccAddress
 	Limit   int64
 	Offset  int64
+	// Status filters the swaps by their status, all the swaps are returned if it's NULL
+	Status SwapStatus
 }
 
 // nolint: unparam
@@ -87,18 +98,7 @@ func querySwapByCreator(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (
 	iterator := keeper.GetSwapCreatorIterator(ctx, params.Creator)
 	defer iterator.Close()
 
-	count := int64(0)
-	swapIDList := make([]SwapBytes, 0, params.Limit)
-	for ; iterator.Valid(); iterator.Next() {
-		count++
-		if count <= params.Offset {
-			continue
-		}
-		if int64(len(swapIDList)) >= params.Limit {
-			break
-		}
-		swapIDList = append(swapIDList, iterator.Value())
-	}
+	swapIDList := listSwapIDs(ctx, keeper, iterator, params.Status, params.Limit, params.Offset)
 
 	bz, err := codec.MarshalJSONIndent(keeper.cdc, swapIDList)
 	if err != nil {
@@ -113,6 +113,8 @@ type QuerySwapByRecipientParams struct {
 	Recipient sdk.AccAddress
 	Limit     int64
 	Offset    int64
+	// Status filters the swaps by their status, all the swaps are returned if it's NULL
+	Status SwapStatus
 }
 
 // nolint: unparam
@@ -136,20 +138,145 @@ func querySwapByRecipient(ctx sdk.Context, req abci.RequestQuery, keeper Keeper)
 	iterator := keeper.GetSwapRecipientIterator(ctx, params.Recipient)
 	defer iterator.Close()
 
+	swapIDList := listSwapIDs(ctx, keeper, iterator, params.Status, params.Limit, params.Offset)
+
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, swapIDList)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error()))
+	}
+
+	return bz, nil
+}
+
+// listSwapIDs returns the page of the swapIDs of the iterator, which are of `status` unless it's NULL
+func listSwapIDs(ctx sdk.Context, keeper Keeper, iterator store.Iterator, status SwapStatus, limit, offset int64) []SwapBytes {
 	count := int64(0)
-	swapIDList := make([]SwapBytes, 0, params.Limit)
+	swapIDList := make([]SwapBytes, 0, limit)
 	for ; iterator.Valid(); iterator.Next() {
+		if status != NULL {
+			swap := keeper.GetSwap(ctx, iterator.Value())
+			if swap == nil || swap.Status != status {
+				continue
+			}
+		}
 		count++
-		if count <= params.Offset {
+		if count <= offset {
 			continue
 		}
-		if int64(len(swapIDList)) >= params.Limit {
+		if int64(len(swapIDList)) >= limit {
 			break
 		}
 		swapIDList = append(swapIDList, iterator.Value())
 	}
+	return swapIDList
+}
 
-	bz, err := codec.MarshalJSONIndent(keeper.cdc, swapIDList)
+// Params for query 'custom/atomicswap/swaps'. The swaps are looked up by the index of the first filter set among
+// RandomNumberHash, Status and the expire height range [MinExpireHeight, MaxExpireHeight], and are filtered by the
+// rest of them. MaxExpireHeight is unbounded if it's 0. Cursor is the NextCursor of the previous page, or empty for
+// the first page.
+type QuerySwapsParams struct {
+	RandomNumberHash SwapBytes
+	Status           SwapStatus
+	MinExpireHeight  int64
+	MaxExpireHeight  int64
+	Cursor           SwapBytes
+	Limit            int64
+}
+
+type SwapWithID struct {
+	SwapID SwapBytes  `json:"swap_id"`
+	Swap   AtomicSwap `json:"swap"`
+}
+
+// QuerySwapsResult is a page of swaps, NextCursor is empty on the last page
+type QuerySwapsResult struct {
+	Swaps      []SwapWithID `json:"swaps"`
+	NextCursor SwapBytes    `json:"next_cursor"`
+}
+
+func (params QuerySwapsParams) match(swap *AtomicSwap) bool {
+	if len(params.RandomNumberHash) != 0 && !bytes.Equal(params.RandomNumberHash, swap.RandomNumberHash) {
+		return false
+	}
+	if params.Status != NULL && params.Status != swap.Status {
+		return false
+	}
+	if swap.ExpireHeight < params.MinExpireHeight {
+		return false
+	}
+	return params.MaxExpireHeight == 0 || swap.ExpireHeight <= params.MaxExpireHeight
+}
+
+// indexRange returns the range of the index to look up the swaps
+func (params QuerySwapsParams) indexRange() (start, end []byte) {
+	switch {
+	case len(params.RandomNumberHash) != 0:
+		start = BuildSwapRandomNumberHashQueueKey(params.RandomNumberHash)
+		return start, sdk.PrefixEndBytes(start)
+	case params.Status != NULL:
+		start = BuildSwapStatusQueueKey(params.Status)
+		return start, sdk.PrefixEndBytes(start)
+	default:
+		start = BuildSwapExpireHeightKey(params.MinExpireHeight, 0)
+		if params.MaxExpireHeight == 0 {
+			return start, sdk.PrefixEndBytes(BuildSwapExpireHeightQueueKey())
+		}
+		return start, BuildSwapExpireHeightKey(params.MaxExpireHeight+1, 0)
+	}
+}
+
+// nolint: unparam
+func querySwaps(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	if !sdk.IsUpgrade(upgrade.SwapIndexUpgrade) {
+		return nil, sdk.ErrUnknownRequest("the swaps are not indexed before SwapIndexUpgrade")
+	}
+
+	var params QuerySwapsParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("incorrectly formatted request data: %s", err.Error()))
+	}
+
+	if len(params.RandomNumberHash) != 0 && len(params.RandomNumberHash) != RandomNumberHashLength {
+		return nil, ErrInvalidRandomNumberHash(fmt.Sprintf("length of random number hash should be %d", RandomNumberHashLength))
+	}
+	if len(params.RandomNumberHash) == 0 && params.Status == NULL && params.MinExpireHeight == 0 && params.MaxExpireHeight == 0 {
+		return nil, sdk.ErrUnknownRequest("one of random number hash, status and expire height range should be specified")
+	}
+	if params.MinExpireHeight < 0 || params.MaxExpireHeight < 0 ||
+		(params.MaxExpireHeight != 0 && params.MaxExpireHeight < params.MinExpireHeight) {
+		return nil, sdk.ErrUnknownRequest("invalid expire height range")
+	}
+	if params.Limit <= 0 || params.Limit > MaxQuerySwapsLimit {
+		return nil, ErrInvalidPaginationParameters(fmt.Sprintf("limit should be in (0, %d]", MaxQuerySwapsLimit))
+	}
+
+	start, end := params.indexRange()
+	if len(params.Cursor) != 0 {
+		if bytes.Compare(params.Cursor, start) < 0 || bytes.Compare(params.Cursor, end) >= 0 {
+			return nil, ErrInvalidPaginationParameters("cursor is out of the range of the query")
+		}
+		start = params.Cursor
+	}
+
+	iterator := keeper.GetSwapIndexIterator(ctx, start, end)
+	defer iterator.Close()
+
+	result := QuerySwapsResult{Swaps: make([]SwapWithID, 0, params.Limit)}
+	for ; iterator.Valid(); iterator.Next() {
+		swap := keeper.GetSwap(ctx, iterator.Value())
+		if swap == nil || !params.match(swap) {
+			continue
+		}
+		if int64(len(result.Swaps)) >= params.Limit {
+			result.NextCursor = iterator.Key()
+			break
+		}
+		result.Swaps = append(result.Swaps, SwapWithID{SwapID: iterator.Value(), Swap: *swap})
+	}
+
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, result)
 	if err != nil {
 		return nil, sdk.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error()))
 	}
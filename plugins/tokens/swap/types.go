@@ -15,6 +15,9 @@ const (
 	Open      SwapStatus = 0x01
 	Completed SwapStatus = 0x02
 	Expired   SwapStatus = 0x03
+	// PartiallyCompleted is a swap claimed in part, the rest of it can be claimed until the expire height,
+	// and is refunded after that
+	PartiallyCompleted SwapStatus = 0x04
 )
 
 func NewSwapStatusFromString(str string) SwapStatus {
@@ -25,6 +28,8 @@ func NewSwapStatusFromString(str string) SwapStatus {
 		return Completed
 	case "Expired", "expired":
 		return Expired
+	case "PartiallyCompleted", "partiallyCompleted":
+		return PartiallyCompleted
 	default:
 		return NULL
 	}
@@ -38,6 +43,8 @@ func (status SwapStatus) String() string {
 		return "Completed"
 	case Expired:
 		return "Expired"
+	case PartiallyCompleted:
+		return "PartiallyCompleted"
 	default:
 		return "NULL"
 	}
@@ -108,4 +115,27 @@ type AtomicSwap struct {
 	Index        int64      `json:"index"`
 	ClosedTime   int64      `json:"closed_time"`
 	Status       SwapStatus `json:"status"`
+
+	// ClaimedAmount is the amount claimed by the partial claims so far, it's left empty if the swap is claimed at once
+	ClaimedAmount sdk.Coins `json:"claimed_amount"`
+}
+
+// IsOpen returns true if the swap can still be claimed or refunded
+func (swap AtomicSwap) IsOpen() bool {
+	return swap.Status == Open || swap.Status == PartiallyCompleted
+}
+
+// RemainingAmount returns the amount of the swap which has not been claimed
+func (swap AtomicSwap) RemainingAmount() sdk.Coins {
+	return swap.OutAmount.Minus(swap.ClaimedAmount)
+}
+
+// ExpectedIncomeCoins returns the expected income of the swap as coins. The expected income is a free text for a
+// cross chain swap, so false is returned if it's not a list of coins.
+func (swap AtomicSwap) ExpectedIncomeCoins() (sdk.Coins, bool) {
+	coins, err := sdk.ParseCoins(swap.ExpectedIncome)
+	if err != nil || coins.IsZero() {
+		return nil, false
+	}
+	return coins, true
 }
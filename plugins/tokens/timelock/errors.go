@@ -18,6 +18,8 @@ const (
 	CodeCanNotUnlock               sdk.CodeType = 7
 	CodeUnknownTimeLock            sdk.CodeType = 8
 	CodeTimeLockRecordAlreadyExist sdk.CodeType = 9
+	CodeInvalidVestingSteps        sdk.CodeType = 10
+	CodeInvalidTransfer            sdk.CodeType = 11
 )
 
 //----------------------------------------
@@ -60,3 +62,11 @@ func ErrTimeLockRecordAlreadyExist(codespace sdk.CodespaceType, addr sdk.AccAddr
 	return sdk.NewError(codespace, CodeTimeLockRecordAlreadyExist,
 		fmt.Sprintf("Time lock already exists, address=%s, id=%d", addr.String(), id))
 }
+
+func ErrInvalidVestingSteps(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidVestingSteps, fmt.Sprintf("Invalid vesting steps: %s", msg))
+}
+
+func ErrInvalidTransfer(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidTransfer, fmt.Sprintf("Invalid transfer: %s", msg))
+}
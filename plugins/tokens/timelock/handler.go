@@ -5,6 +5,8 @@ import (
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func NewHandler(keeper Keeper) sdk.Handler {
@@ -15,6 +17,16 @@ func NewHandler(keeper Keeper) sdk.Handler {
 				return sdk.ErrMsgNotSupported("").Result()
 			}
 			return handleTimeLock(ctx, keeper, msg)
+		case TimeLockVestingMsg:
+			if !sdk.IsUpgrade(upgrade.VestingTimeLockUpgrade) || sdk.IsUpgrade(sdk.FirstSunsetFork) {
+				return sdk.ErrMsgNotSupported("").Result()
+			}
+			return handleTimeLockVesting(ctx, keeper, msg)
+		case TimeLockTransferMsg:
+			if !sdk.IsUpgrade(upgrade.TimeLockTransferUpgrade) || sdk.IsUpgrade(sdk.FirstSunsetFork) {
+				return sdk.ErrMsgNotSupported("").Result()
+			}
+			return handleTimeLockTransfer(ctx, keeper, msg)
 		case TimeUnlockMsg:
 			return handleTimeUnlock(ctx, keeper, msg)
 		case TimeRelockMsg:
@@ -30,7 +42,24 @@ func NewHandler(keeper Keeper) sdk.Handler {
 }
 
 func handleTimeLock(ctx sdk.Context, keeper Keeper, msg TimeLockMsg) sdk.Result {
-	record, err := keeper.TimeLock(ctx, msg.From, msg.Description, msg.Amount, time.Unix(msg.LockTime, 0))
+	record, err := keeper.TimeLockFor(ctx, msg.From, msg.Owner(), msg.Description, msg.Amount, time.Unix(msg.LockTime, 0), nil)
+	if err != nil {
+		return err.Result()
+	}
+
+	timeLockId := []byte(fmt.Sprintf("%d", record.Id))
+	return sdk.Result{
+		Data: timeLockId,
+	}
+}
+
+func handleTimeLockVesting(ctx sdk.Context, keeper Keeper, msg TimeLockVestingMsg) sdk.Result {
+	vesting := VestingSchedule{
+		StartTime: time.Unix(msg.StartTime, 0),
+		CliffTime: time.Unix(msg.CliffTime, 0),
+		Steps:     msg.Steps,
+	}
+	record, err := keeper.TimeLockFor(ctx, msg.From, msg.Owner(), msg.Description, msg.Amount, time.Unix(msg.LockTime, 0), &vesting)
 	if err != nil {
 		return err.Result()
 	}
@@ -59,6 +88,18 @@ func handleTimeRelock(ctx sdk.Context, keeper Keeper, msg TimeRelockMsg) sdk.Res
 	}
 }
 
+func handleTimeLockTransfer(ctx sdk.Context, keeper Keeper, msg TimeLockTransferMsg) sdk.Result {
+	record, err := keeper.TimeLockTransfer(ctx, msg.From, msg.Id, msg.To)
+	if err != nil {
+		return err.Result()
+	}
+
+	timeLockId := []byte(fmt.Sprintf("%d", record.Id))
+	return sdk.Result{
+		Data: timeLockId,
+	}
+}
+
 func handleTimeUnlock(ctx sdk.Context, keeper Keeper, msg TimeUnlockMsg) sdk.Result {
 	err := keeper.TimeUnlock(ctx, msg.From, msg.Id, false)
 	if err != nil {
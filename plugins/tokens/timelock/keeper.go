@@ -1,6 +1,7 @@
 package timelock
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 	"time"
@@ -15,6 +16,7 @@ import (
 	tmlog "github.com/tendermint/tendermint/libs/log"
 
 	bnclog "github.com/bnb-chain/node/common/log"
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 const InitialRecordId = 1
@@ -50,11 +52,17 @@ func (keeper Keeper) setTimeLockRecord(ctx sdk.Context, addr sdk.AccAddress, rec
 	store := ctx.KVStore(keeper.storeKey)
 	bz := keeper.cdc.MustMarshalBinaryLengthPrefixed(record)
 	store.Set(KeyRecord(addr, record.Id), bz)
+	if len(record.Creator) != 0 {
+		store.Set(KeyCreatedRecord(record.Creator, addr, record.Id), []byte{1})
+	}
 }
 
-func (keeper Keeper) deleteTimeLockRecord(ctx sdk.Context, addr sdk.AccAddress, recordId int64) {
+func (keeper Keeper) deleteTimeLockRecord(ctx sdk.Context, addr sdk.AccAddress, record TimeLockRecord) {
 	store := ctx.KVStore(keeper.storeKey)
-	store.Delete(KeyRecord(addr, recordId))
+	store.Delete(KeyRecord(addr, record.Id))
+	if len(record.Creator) != 0 {
+		store.Delete(KeyCreatedRecord(record.Creator, addr, record.Id))
+	}
 }
 
 func (keeper Keeper) getTimeLockRecordsIterator(ctx sdk.Context, addr sdk.AccAddress) sdk.Iterator {
@@ -89,17 +97,77 @@ func (keeper Keeper) GetTimeLockRecords(ctx sdk.Context, addr sdk.AccAddress) []
 	return records
 }
 
+// GetCreatedTimeLockRecords returns the records locked by `creator` for other addresses at the block time,
+// ordered by their owners and ids
+func (keeper Keeper) GetCreatedTimeLockRecords(ctx sdk.Context, creator sdk.AccAddress) []TimeLockRecordStatus {
+	var records []TimeLockRecordStatus
+	store := ctx.KVStore(keeper.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, KeyCreatedRecordSubSpace(creator))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		owner, id, err := ParseKeyCreatedRecord(iterator.Key())
+		if err != nil {
+			keeper.logger.Error("failed to parse created time lock key", "key", string(iterator.Key()), "err", err)
+			continue
+		}
+		if record, found := keeper.GetTimeLockRecord(ctx, owner, id); found {
+			records = append(records, NewTimeLockRecordStatus(owner, record, ctx.BlockHeader().Time))
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if c := bytes.Compare(records[i].Owner, records[j].Owner); c != 0 {
+			return c < 0
+		}
+		return records[i].Id < records[j].Id
+	})
+	return records
+}
+
 func (kp *Keeper) GetTimeLockRecordIterator(ctx sdk.Context) (iterator store.Iterator) {
 	kvStore := ctx.KVStore(kp.storeKey)
-	return sdk.KVStorePrefixIterator(kvStore, []byte{})
+	return sdk.KVStorePrefixIterator(kvStore, RecordPrefix)
 }
 
-func (keeper Keeper) getTimeLockId(ctx sdk.Context, from sdk.AccAddress) int64 {
-	acc := keeper.ak.GetAccount(ctx, from)
-	return acc.GetSequence()
+// getTimeLockId returns the id of a new record of `owner`, which is the sequence of the owner's account as the records
+// locked by the owners themselves have always had. The records locked for the owner by others or transferred to the
+// owner may have taken the sequence, the next free id is returned then.
+func (keeper Keeper) getTimeLockId(ctx sdk.Context, owner sdk.AccAddress) int64 {
+	id := int64(InitialRecordId)
+	if acc := keeper.ak.GetAccount(ctx, owner); acc != nil && acc.GetSequence() > id {
+		id = acc.GetSequence()
+	}
+	for {
+		if _, found := keeper.GetTimeLockRecord(ctx, owner, id); !found {
+			return id
+		}
+		id++
+	}
 }
 
 func (keeper Keeper) TimeLock(ctx sdk.Context, from sdk.AccAddress, description string, amount sdk.Coins, lockTime time.Time) (TimeLockRecord, sdk.Error) {
+	return keeper.timeLock(ctx, from, from, description, amount, lockTime, nil)
+}
+
+// TimeLockVesting locks the amount in a record released by the vesting schedule, the whole amount is released at lockTime
+func (keeper Keeper) TimeLockVesting(ctx sdk.Context, from sdk.AccAddress, description string, amount sdk.Coins,
+	lockTime time.Time, vesting VestingSchedule) (TimeLockRecord, sdk.Error) {
+	return keeper.TimeLockFor(ctx, from, from, description, amount, lockTime, &vesting)
+}
+
+// TimeLockFor locks the amount of `from` in a record of the beneficiary, which is released to the beneficiary.
+// The record is released by the vesting schedule if it's not nil.
+func (keeper Keeper) TimeLockFor(ctx sdk.Context, from, beneficiary sdk.AccAddress, description string, amount sdk.Coins,
+	lockTime time.Time, vesting *VestingSchedule) (TimeLockRecord, sdk.Error) {
+	if vesting != nil {
+		vesting = &VestingSchedule{StartTime: vesting.StartTime, CliffTime: vesting.CliffTime, Steps: vesting.Steps,
+			Claimed: sdk.Coins{}}
+	}
+	return keeper.timeLock(ctx, from, beneficiary, description, amount, lockTime, vesting)
+}
+
+func (keeper Keeper) timeLock(ctx sdk.Context, from, owner sdk.AccAddress, description string, amount sdk.Coins,
+	lockTime time.Time, vesting *VestingSchedule) (TimeLockRecord, sdk.Error) {
 	if !lockTime.After(ctx.BlockHeader().Time.Add(MinLockTime)) {
 		return TimeLockRecord{}, ErrInvalidLockTime(DefaultCodespace,
 			fmt.Sprintf("lock time(%s) should be %d minute(s) after now(%s)", lockTime.UTC().String(),
@@ -111,10 +179,14 @@ func (keeper Keeper) TimeLock(ctx sdk.Context, from sdk.AccAddress, description
 		return TimeLockRecord{}, err
 	}
 
-	recordId := keeper.getTimeLockId(ctx, from)
-	_, found := keeper.GetTimeLockRecord(ctx, from, recordId)
-	if found {
-		return TimeLockRecord{}, ErrTimeLockRecordAlreadyExist(DefaultCodespace, from, recordId)
+	var recordId int64
+	if sdk.IsUpgrade(upgrade.TimeLockTransferUpgrade) {
+		recordId = keeper.getTimeLockId(ctx, owner)
+	} else {
+		recordId = keeper.ak.GetAccount(ctx, from).GetSequence()
+		if _, found := keeper.GetTimeLockRecord(ctx, from, recordId); found {
+			return TimeLockRecord{}, ErrTimeLockRecordAlreadyExist(DefaultCodespace, from, recordId)
+		}
 	}
 
 	record := TimeLockRecord{
@@ -122,8 +194,12 @@ func (keeper Keeper) TimeLock(ctx sdk.Context, from sdk.AccAddress, description
 		Description: description,
 		Amount:      amount,
 		LockTime:    lockTime,
+		Vesting:     vesting,
 	}
-	keeper.setTimeLockRecord(ctx, from, record)
+	if !from.Equals(owner) {
+		record.Creator = from
+	}
+	keeper.setTimeLockRecord(ctx, owner, record)
 	return record, nil
 }
 
@@ -133,17 +209,45 @@ func (keeper Keeper) TimeUnlock(ctx sdk.Context, from sdk.AccAddress, recordId i
 		return ErrTimeLockRecordDoesNotExist(DefaultCodespace, from, recordId)
 	}
 
+	if !isBCFusionRefund && record.Vesting != nil {
+		return keeper.claimVested(ctx, from, record)
+	}
+
 	if !isBCFusionRefund && ctx.BlockHeader().Time.Before(record.LockTime) {
 		return ErrCanNotUnlock(DefaultCodespace, fmt.Sprintf("lock time(%s) is after now(%s)",
 			record.LockTime.UTC().String(), ctx.BlockHeader().Time.UTC().String()))
 	}
 
-	_, err := keeper.ck.SendCoins(ctx, TimeLockCoinsAccAddr, from, record.Amount)
+	_, err := keeper.ck.SendCoins(ctx, TimeLockCoinsAccAddr, from, record.Amount.Minus(record.ClaimedAmount()))
 	if err != nil {
 		return err
 	}
 
-	keeper.deleteTimeLockRecord(ctx, from, recordId)
+	keeper.deleteTimeLockRecord(ctx, from, record)
+	return nil
+}
+
+// claimVested releases the amount vested and not claimed yet of a vesting record,
+// the record is deleted once the whole amount is claimed
+func (keeper Keeper) claimVested(ctx sdk.Context, from sdk.AccAddress, record TimeLockRecord) sdk.Error {
+	now := ctx.BlockHeader().Time
+	claimable := record.ClaimableAmount(now)
+	if claimable.IsZero() {
+		return ErrCanNotUnlock(DefaultCodespace, fmt.Sprintf("nothing is vested since the last claim till now(%s)",
+			now.UTC().String()))
+	}
+
+	_, err := keeper.ck.SendCoins(ctx, TimeLockCoinsAccAddr, from, claimable)
+	if err != nil {
+		return err
+	}
+
+	record.Vesting.Claimed = record.Vesting.Claimed.Plus(claimable)
+	if record.Vesting.Claimed.IsEqual(record.Amount) {
+		keeper.deleteTimeLockRecord(ctx, from, record)
+	} else {
+		keeper.setTimeLockRecord(ctx, from, record)
+	}
 	return nil
 }
 
@@ -153,6 +257,10 @@ func (keeper Keeper) TimeRelock(ctx sdk.Context, from sdk.AccAddress, recordId i
 		return ErrTimeLockRecordDoesNotExist(DefaultCodespace, from, recordId)
 	}
 
+	if record.Vesting != nil {
+		return ErrInvalidRelock(DefaultCodespace, "a vesting time lock can not be relocked")
+	}
+
 	if newRecord.Description != "" {
 		record.Description = newRecord.Description
 	}
@@ -192,3 +300,28 @@ func (keeper Keeper) TimeRelock(ctx sdk.Context, from sdk.AccAddress, recordId i
 	keeper.setTimeLockRecord(ctx, from, record)
 	return nil
 }
+
+// TimeLockTransfer transfers the record `recordId` of `from` to the new owner `to`, who gets the record with a new id.
+// The creator of the record is kept, and is `from` if `from` locked the coins of the record itself.
+func (keeper Keeper) TimeLockTransfer(ctx sdk.Context, from sdk.AccAddress, recordId int64, to sdk.AccAddress) (TimeLockRecord, sdk.Error) {
+	record, found := keeper.GetTimeLockRecord(ctx, from, recordId)
+	if !found {
+		return TimeLockRecord{}, ErrTimeLockRecordDoesNotExist(DefaultCodespace, from, recordId)
+	}
+
+	if from.Equals(to) {
+		return TimeLockRecord{}, ErrInvalidTransfer(DefaultCodespace, "a time lock can not be transferred to its owner")
+	}
+
+	keeper.deleteTimeLockRecord(ctx, from, record)
+
+	if len(record.Creator) == 0 {
+		record.Creator = from
+	}
+	if record.Creator.Equals(to) {
+		record.Creator = nil
+	}
+	record.Id = keeper.getTimeLockId(ctx, to)
+	keeper.setTimeLockRecord(ctx, to, record)
+	return record, nil
+}
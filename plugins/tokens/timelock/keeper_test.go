@@ -18,6 +18,7 @@ import (
 	"github.com/bnb-chain/node/common"
 	"github.com/bnb-chain/node/common/testutils"
 	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/wire"
 )
 
@@ -201,6 +202,290 @@ func TestKeeper_TimeUnlock_Success(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestKeeper_TimeLockVesting(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accountCache := getAccountCache(cdc, cms)
+	now := time.Unix(1600000000, 0)
+	ctx := sdk.NewContext(cms, abci.Header{Time: now}, sdk.RunTxModeDeliver, logger).WithAccountCache(accountCache)
+
+	_, acc := testutils.NewAccount(ctx, accKeeper, 0)
+	_ = acc.SetCoins(sdk.Coins{
+		sdk.NewCoin("BNB", 1000e8),
+	}.Sort())
+	accKeeper.SetAccount(ctx, acc)
+
+	lockCoins := sdk.Coins{
+		sdk.NewCoin("BNB", 800e8),
+	}.Sort()
+
+	// released in 4 steps of 1000 seconds, with a cliff at the middle of the first step
+	vesting := VestingSchedule{
+		StartTime: now,
+		CliffTime: now.Add(500 * time.Second),
+		Steps:     4,
+	}
+	record, err := keeper.TimeLockVesting(ctx, acc.GetAddress(), "Test", lockCoins, now.Add(4000*time.Second), vesting)
+	require.Nil(t, err)
+
+	err = keeper.TimeRelock(ctx, acc.GetAddress(), record.Id, TimeLockRecord{Description: "Relock"})
+	require.NotNil(t, err)
+	require.Equal(t, CodeInvalidRelock, err.Code())
+
+	err = keeper.TimeUnlock(ctx.WithBlockTime(now.Add(999*time.Second)), acc.GetAddress(), record.Id, false)
+	require.NotNil(t, err)
+	require.Equal(t, CodeCanNotUnlock, err.Code())
+
+	status := NewTimeLockRecordStatus(acc.GetAddress(), record, now.Add(2500*time.Second))
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 400e8)}, status.Vested)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 400e8)}, status.Unvested)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 400e8)}, status.Claimable)
+
+	err = keeper.TimeUnlock(ctx.WithBlockTime(now.Add(2500*time.Second)), acc.GetAddress(), record.Id, false)
+	require.Nil(t, err)
+	require.Equal(t, int64(600e8), accKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("BNB"))
+	record, found := keeper.GetTimeLockRecord(ctx, acc.GetAddress(), record.Id)
+	require.True(t, found)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 400e8)}, record.Vesting.Claimed)
+
+	// nothing more is released till the next step
+	err = keeper.TimeUnlock(ctx.WithBlockTime(now.Add(2999*time.Second)), acc.GetAddress(), record.Id, false)
+	require.NotNil(t, err)
+
+	err = keeper.TimeUnlock(ctx.WithBlockTime(now.Add(5000*time.Second)), acc.GetAddress(), record.Id, false)
+	require.Nil(t, err)
+	require.Equal(t, int64(1000e8), accKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("BNB"))
+	_, found = keeper.GetTimeLockRecord(ctx, acc.GetAddress(), record.Id)
+	require.False(t, found)
+}
+
+func TestHandler_TimeLockVesting(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accountCache := getAccountCache(cdc, cms)
+	now := time.Unix(1600000000, 0)
+	ctx := sdk.NewContext(cms, abci.Header{Time: now}, sdk.RunTxModeDeliver, logger).WithAccountCache(accountCache)
+	handler := NewHandler(keeper)
+
+	_, acc := testutils.NewAccount(ctx, accKeeper, 1000e8)
+	msg := NewTimeLockVestingMsg(acc.GetAddress(), "Test", sdk.Coins{sdk.NewCoin("BNB", 800e8)},
+		now.Unix(), now.Unix()+500, now.Unix()+4000, 4)
+	result := handler(ctx, msg)
+	require.Equal(t, sdk.ErrMsgNotSupported("").ABCICode(), result.Code)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.VestingTimeLockUpgrade, 1)
+	upgrade.Mgr.SetHeight(1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
+	result = handler(ctx, msg)
+	require.True(t, result.IsOK())
+	require.Equal(t, int64(200e8), accKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins().AmountOf("BNB"))
+}
+
+func TestKeeper_TimeLockFor(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accountCache := getAccountCache(cdc, cms)
+	now := time.Unix(1600000000, 0)
+	ctx := sdk.NewContext(cms, abci.Header{Time: now}, sdk.RunTxModeDeliver, logger).WithAccountCache(accountCache)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TimeLockTransferUpgrade, 1)
+	upgrade.Mgr.SetHeight(1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
+	_, treasury := testutils.NewAccount(ctx, accKeeper, 0)
+	_ = treasury.SetCoins(sdk.Coins{
+		sdk.NewCoin("BNB", 1000e8),
+	}.Sort())
+	accKeeper.SetAccount(ctx, treasury)
+	_, beneficiary := testutils.NewAccount(ctx, accKeeper, 0)
+	_ = beneficiary.SetSequence(3)
+	accKeeper.SetAccount(ctx, beneficiary)
+
+	lockCoins := sdk.Coins{
+		sdk.NewCoin("BNB", 300e8),
+	}.Sort()
+
+	// the ids are allocated under the beneficiary, skipping the ids taken
+	record1, err := keeper.TimeLockFor(ctx, treasury.GetAddress(), beneficiary.GetAddress(), "Grant", lockCoins,
+		now.Add(1000*time.Second), nil)
+	require.Nil(t, err)
+	require.Equal(t, int64(3), record1.Id)
+	require.Equal(t, treasury.GetAddress(), record1.Creator)
+	record2, err := keeper.TimeLockFor(ctx, treasury.GetAddress(), beneficiary.GetAddress(), "Grant", lockCoins,
+		now.Add(1000*time.Second), nil)
+	require.Nil(t, err)
+	require.Equal(t, int64(4), record2.Id)
+	require.Equal(t, int64(400e8), accKeeper.GetAccount(ctx, treasury.GetAddress()).GetCoins().AmountOf("BNB"))
+
+	require.Len(t, keeper.GetTimeLockRecords(ctx, beneficiary.GetAddress()), 2)
+	require.Len(t, keeper.GetTimeLockRecords(ctx, treasury.GetAddress()), 0)
+	created := keeper.GetCreatedTimeLockRecords(ctx, treasury.GetAddress())
+	require.Len(t, created, 2)
+	require.Equal(t, beneficiary.GetAddress(), created[0].Owner)
+	require.Equal(t, record1.Id, created[0].Id)
+
+	// only the beneficiary can unlock the record
+	ctx = ctx.WithBlockTime(now.Add(2000 * time.Second))
+	err = keeper.TimeUnlock(ctx, treasury.GetAddress(), record1.Id, false)
+	require.NotNil(t, err)
+	require.Equal(t, CodeTimeLockRecordDoesNotExist, err.Code())
+	err = keeper.TimeUnlock(ctx, beneficiary.GetAddress(), record1.Id, false)
+	require.Nil(t, err)
+	require.Equal(t, int64(300e8), accKeeper.GetAccount(ctx, beneficiary.GetAddress()).GetCoins().AmountOf("BNB"))
+	require.Len(t, keeper.GetCreatedTimeLockRecords(ctx, treasury.GetAddress()), 1)
+
+	// the record locked by the owner itself is not created by anyone else
+	record3, err := keeper.TimeLock(ctx, beneficiary.GetAddress(), "Self", lockCoins, now.Add(3000*time.Second))
+	require.Nil(t, err)
+	require.Equal(t, int64(3), record3.Id)
+	require.Nil(t, record3.Creator)
+}
+
+func TestKeeper_TimeLockTransfer(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accountCache := getAccountCache(cdc, cms)
+	now := time.Unix(1600000000, 0)
+	ctx := sdk.NewContext(cms, abci.Header{Time: now}, sdk.RunTxModeDeliver, logger).WithAccountCache(accountCache)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TimeLockTransferUpgrade, 1)
+	upgrade.Mgr.SetHeight(1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
+	_, acc1 := testutils.NewAccount(ctx, accKeeper, 0)
+	_ = acc1.SetCoins(sdk.Coins{
+		sdk.NewCoin("BNB", 1000e8),
+	}.Sort())
+	_ = acc1.SetSequence(1)
+	accKeeper.SetAccount(ctx, acc1)
+	_, acc2 := testutils.NewAccount(ctx, accKeeper, 0)
+
+	lockCoins := sdk.Coins{
+		sdk.NewCoin("BNB", 300e8),
+	}.Sort()
+
+	record, err := keeper.TimeLock(ctx, acc1.GetAddress(), "Test", lockCoins, now.Add(1000*time.Second))
+	require.Nil(t, err)
+
+	_, err = keeper.TimeLockTransfer(ctx, acc1.GetAddress(), record.Id+1, acc2.GetAddress())
+	require.NotNil(t, err)
+	require.Equal(t, CodeTimeLockRecordDoesNotExist, err.Code())
+
+	_, err = keeper.TimeLockTransfer(ctx, acc1.GetAddress(), record.Id, acc1.GetAddress())
+	require.NotNil(t, err)
+	require.Equal(t, CodeInvalidTransfer, err.Code())
+
+	transferred, err := keeper.TimeLockTransfer(ctx, acc1.GetAddress(), record.Id, acc2.GetAddress())
+	require.Nil(t, err)
+	require.Equal(t, acc1.GetAddress(), transferred.Creator)
+	_, found := keeper.GetTimeLockRecord(ctx, acc1.GetAddress(), record.Id)
+	require.False(t, found)
+	require.Len(t, keeper.GetCreatedTimeLockRecords(ctx, acc1.GetAddress()), 1)
+
+	// the record transferred back to its creator is no longer indexed as created for others
+	back, err := keeper.TimeLockTransfer(ctx, acc2.GetAddress(), transferred.Id, acc1.GetAddress())
+	require.Nil(t, err)
+	require.Nil(t, back.Creator)
+	require.Len(t, keeper.GetCreatedTimeLockRecords(ctx, acc1.GetAddress()), 0)
+	require.Len(t, keeper.GetTimeLockRecords(ctx, acc1.GetAddress()), 1)
+
+	err = keeper.TimeUnlock(ctx.WithBlockTime(now.Add(2000*time.Second)), acc1.GetAddress(), back.Id, false)
+	require.Nil(t, err)
+	require.Equal(t, int64(1000e8), accKeeper.GetAccount(ctx, acc1.GetAddress()).GetCoins().AmountOf("BNB"))
+}
+
+func TestKeeper_TimeUnlock_RecordBeforeTimeLockTransferUpgrade(t *testing.T) {
+	cdc := MakeCodec()
+	accKeeper, keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	logger := log.NewTMLogger(os.Stdout)
+	accountCache := getAccountCache(cdc, cms)
+	now := time.Unix(1600000000, 0)
+	ctx := sdk.NewContext(cms, abci.Header{Time: now}, sdk.RunTxModeDeliver, logger).WithAccountCache(accountCache)
+
+	_, acc := testutils.NewAccount(ctx, accKeeper, 0)
+	timeLockAcc := accKeeper.NewAccountWithAddress(ctx, TimeLockCoinsAccAddr)
+	_ = timeLockAcc.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 1000e8)})
+	accKeeper.SetAccount(ctx, timeLockAcc)
+
+	// the record as it was stored before the vesting schedules and the creators were added
+	type legacyRecord struct {
+		Id          int64     `json:"id"`
+		Description string    `json:"description"`
+		Amount      sdk.Coins `json:"amount"`
+		LockTime    time.Time `json:"lock_time"`
+	}
+	lockCoins := sdk.Coins{sdk.NewCoin("BNB", 1000e8)}
+	bz := cdc.MustMarshalBinaryLengthPrefixed(legacyRecord{Id: 5, Description: "Test", Amount: lockCoins, LockTime: now})
+	ctx.KVStore(common.TimeLockStoreKey).Set(KeyRecord(acc.GetAddress(), 5), bz)
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TimeLockTransferUpgrade, 1)
+	upgrade.Mgr.SetHeight(1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
+	records := keeper.GetTimeLockRecords(ctx, acc.GetAddress())
+	require.Equal(t, 1, len(records))
+	require.Equal(t, int64(5), records[0].Id)
+	require.Equal(t, lockCoins, records[0].Amount)
+	require.Nil(t, records[0].Vesting)
+	require.Empty(t, records[0].Creator)
+
+	iterator := keeper.GetTimeLockRecordIterator(ctx)
+	require.True(t, iterator.Valid())
+	addr, id, err := ParseKeyRecord(iterator.Key())
+	require.NoError(t, err)
+	require.Equal(t, acc.GetAddress(), addr)
+	require.Equal(t, int64(5), id)
+	iterator.Next()
+	require.False(t, iterator.Valid())
+	iterator.Close()
+
+	sdkErr := keeper.TimeUnlock(ctx, acc.GetAddress(), 5, false)
+	require.Nil(t, sdkErr)
+	require.Equal(t, lockCoins, accKeeper.GetAccount(ctx, acc.GetAddress()).GetCoins())
+	_, found := keeper.GetTimeLockRecord(ctx, acc.GetAddress(), 5)
+	require.False(t, found)
+}
+
+func TestTimeLockRecord_VestedAmount(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	record := TimeLockRecord{
+		Amount:   sdk.Coins{sdk.NewCoin("BNB", 100), sdk.NewCoin("XYZ-000", 3)},
+		LockTime: start.Add(300 * time.Second),
+		Vesting:  &VestingSchedule{StartTime: start, CliffTime: start},
+	}
+	require.Equal(t, sdk.Coins{}, record.VestedAmount(start))
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 33), sdk.NewCoin("XYZ-000", 1)}, record.VestedAmount(start.Add(100*time.Second)))
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 16)}, record.VestedAmount(start.Add(50*time.Second)))
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 66), sdk.NewCoin("XYZ-000", 2)}, record.VestedAmount(start.Add(200*time.Second)))
+	require.Equal(t, record.Amount, record.VestedAmount(start.Add(300*time.Second)))
+
+	record.Vesting = nil
+	require.Equal(t, sdk.Coins{}, record.VestedAmount(start.Add(200*time.Second)))
+	require.Equal(t, record.Amount, record.VestedAmount(start.Add(300*time.Second)))
+}
+
 func TestKeeper_TimeRelock_RecordNotExist(t *testing.T) {
 	cdc := MakeCodec()
 	accKeeper, keeper := MakeKeeper(cdc)
@@ -9,6 +9,15 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// The records are kept under their owners, who can unlock them, as "record:<owner>:<id>", which is the key the records
+// have had since the first time locks, so the records stored before TimeLockTransferUpgrade are read as they are.
+// A record locked for a beneficiary is kept under the beneficiary, and is also indexed by its creator
+// as "created:<creator>:<owner>:<id>". The records locked by the owners themselves have no index.
+var (
+	RecordPrefix  = []byte("record:")
+	CreatedPrefix = []byte("created:")
+)
+
 func KeyRecord(addr sdk.AccAddress, id int64) []byte {
 	return []byte(fmt.Sprintf("record:%d:%d", addr, id))
 }
@@ -17,8 +26,25 @@ func KeyRecordSubSpace(addr sdk.AccAddress) []byte {
 	return []byte(fmt.Sprintf("record:%d", addr))
 }
 
+func KeyCreatedRecord(creator, owner sdk.AccAddress, id int64) []byte {
+	return []byte(fmt.Sprintf("created:%d:%d:%d", creator, owner, id))
+}
+
+func KeyCreatedRecordSubSpace(creator sdk.AccAddress) []byte {
+	return []byte(fmt.Sprintf("created:%d", creator))
+}
+
+// ParseKeyCreatedRecord returns the owner and the id of the record indexed by the key
+func ParseKeyCreatedRecord(key []byte) (sdk.AccAddress, int64, error) {
+	key = bytes.TrimPrefix(key, CreatedPrefix)
+	if len(key) <= sdk.AddrLen*2+1 {
+		return []byte{}, 0, fmt.Errorf("invalid created record key: %s", key)
+	}
+	return ParseKeyRecord(key[sdk.AddrLen*2+1:])
+}
+
 func ParseKeyRecord(key []byte) (sdk.AccAddress, int64, error) {
-	key = bytes.TrimPrefix(key, []byte("record:"))
+	key = bytes.TrimPrefix(key, RecordPrefix)
 	accKeyStr := key[:sdk.AddrLen*2]
 	accKeyBytes, err := hex.DecodeString(string(accKeyStr))
 	if err != nil {
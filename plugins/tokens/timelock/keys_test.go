@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseKeyRecord(t *testing.T) {
@@ -30,3 +31,18 @@ func TestParseKeyRecord(t *testing.T) {
 		return
 	}
 }
+
+func TestParseKeyCreatedRecord(t *testing.T) {
+	creator, err := sdk.AccAddressFromHex("5B38Da6a701c568545dCfcB03FcB875f56beddC4")
+	require.Nil(t, err)
+	owner, err := sdk.AccAddressFromHex("Ab8483F64d9C6d1EcF9b849Ae677dD3315835cb2")
+	require.Nil(t, err)
+
+	acc, id, err := ParseKeyCreatedRecord(KeyCreatedRecord(creator, owner, 1513))
+	require.Nil(t, err)
+	require.Equal(t, owner, acc)
+	require.Equal(t, int64(1513), id)
+
+	_, _, err = ParseKeyCreatedRecord(KeyCreatedRecordSubSpace(creator))
+	require.NotNil(t, err)
+}
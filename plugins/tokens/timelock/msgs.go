@@ -8,6 +8,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 const (
@@ -16,15 +17,18 @@ const (
 	MaxDescriptionLength       = 128
 	MinLockTime                = 60 * time.Second
 	MaxLockTime          int64 = 253402300800 //seconds of 10000-01-01, which is required by amino
+	MaxVestingSteps      int64 = 1000
 )
 
 var _ sdk.Msg = TimeLockMsg{}
 
+// TimeLockMsg locks the amount of From till LockTime. The record is released to Beneficiary if it's set, otherwise to From.
 type TimeLockMsg struct {
 	From        sdk.AccAddress `json:"from"`
 	Description string         `json:"description"`
 	Amount      sdk.Coins      `json:"amount"`
 	LockTime    int64          `json:"lock_time"`
+	Beneficiary sdk.AccAddress `json:"beneficiary,omitempty"`
 }
 
 func NewTimeLockMsg(from sdk.AccAddress, description string, amount sdk.Coins, lockTime int64) TimeLockMsg {
@@ -42,11 +46,18 @@ func (msg TimeLockMsg) String() string {
 	return fmt.Sprintf("TimeLock{%s#%v#%v#%v}", msg.From, msg.Description, msg.Amount, msg.LockTime)
 }
 func (msg TimeLockMsg) GetInvolvedAddresses() []sdk.AccAddress {
-	return []sdk.AccAddress{msg.From, TimeLockCoinsAccAddr}
+	return appendBeneficiary([]sdk.AccAddress{msg.From, TimeLockCoinsAccAddr}, msg.From, msg.Beneficiary)
 }
 func (msg TimeLockMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
 
 func (msg TimeLockMsg) ValidateBasic() sdk.Error {
+	if len(msg.Beneficiary) != 0 && len(msg.Beneficiary) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("length of beneficiary address should be %d", sdk.AddrLen))
+	}
+	if len(msg.Beneficiary) != 0 && !sdk.IsUpgrade(upgrade.TimeLockTransferUpgrade) {
+		return sdk.ErrMsgNotSupported("time lock for a beneficiary is not supported yet")
+	}
+
 	if len(msg.Description) == 0 || len(msg.Description) > MaxDescriptionLength {
 		return ErrInvalidDescription(DefaultCodespace,
 			fmt.Sprintf("length of description(%d) should be larger than 0 and be less than or equal to %d",
@@ -87,6 +98,103 @@ func (msg TimeLockMsg) GetSignBytes() []byte {
 	return b
 }
 
+// Owner returns the address the record is released to
+func (msg TimeLockMsg) Owner() sdk.AccAddress {
+	if len(msg.Beneficiary) != 0 {
+		return msg.Beneficiary
+	}
+	return msg.From
+}
+
+func appendBeneficiary(addrs []sdk.AccAddress, from, beneficiary sdk.AccAddress) []sdk.AccAddress {
+	if len(beneficiary) != 0 && !beneficiary.Equals(from) {
+		return append(addrs, beneficiary)
+	}
+	return addrs
+}
+
+var _ sdk.Msg = TimeLockVestingMsg{}
+
+// TimeLockVestingMsg locks the amount in a vesting record, which is released gradually from StartTime till LockTime
+// as described by VestingSchedule. As TimeLockMsg, the record is released to Beneficiary if it's set.
+// It's charged the same as TimeLockMsg.
+type TimeLockVestingMsg struct {
+	From        sdk.AccAddress `json:"from"`
+	Description string         `json:"description"`
+	Amount      sdk.Coins      `json:"amount"`
+	StartTime   int64          `json:"start_time"`
+	CliffTime   int64          `json:"cliff_time"`
+	LockTime    int64          `json:"lock_time"`
+	Steps       int64          `json:"steps"`
+	Beneficiary sdk.AccAddress `json:"beneficiary,omitempty"`
+}
+
+func NewTimeLockVestingMsg(from sdk.AccAddress, description string, amount sdk.Coins,
+	startTime, cliffTime, lockTime, steps int64) TimeLockVestingMsg {
+	return TimeLockVestingMsg{
+		From:        from,
+		Description: description,
+		Amount:      amount,
+		StartTime:   startTime,
+		CliffTime:   cliffTime,
+		LockTime:    lockTime,
+		Steps:       steps,
+	}
+}
+
+func (msg TimeLockVestingMsg) Route() string { return MsgRoute }
+func (msg TimeLockVestingMsg) Type() string  { return TimeLockMsg{}.Type() }
+func (msg TimeLockVestingMsg) String() string {
+	return fmt.Sprintf("TimeLockVesting{%s#%v#%v#%v#%v#%v#%v}", msg.From, msg.Description, msg.Amount,
+		msg.StartTime, msg.CliffTime, msg.LockTime, msg.Steps)
+}
+func (msg TimeLockVestingMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return appendBeneficiary([]sdk.AccAddress{msg.From, TimeLockCoinsAccAddr}, msg.From, msg.Beneficiary)
+}
+func (msg TimeLockVestingMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg TimeLockVestingMsg) ValidateBasic() sdk.Error {
+	lockMsg := NewTimeLockMsg(msg.From, msg.Description, msg.Amount, msg.LockTime)
+	lockMsg.Beneficiary = msg.Beneficiary
+	err := lockMsg.ValidateBasic()
+	if err != nil {
+		return err
+	}
+
+	if msg.StartTime <= 0 || msg.StartTime >= msg.LockTime {
+		return ErrInvalidLockTime(DefaultCodespace,
+			fmt.Sprintf("start time(%d) should be larger than 0 and be less than lock time(%d)", msg.StartTime, msg.LockTime))
+	}
+
+	if msg.CliffTime < msg.StartTime || msg.CliffTime > msg.LockTime {
+		return ErrInvalidLockTime(DefaultCodespace,
+			fmt.Sprintf("cliff time(%d) should be between start time(%d) and lock time(%d)",
+				msg.CliffTime, msg.StartTime, msg.LockTime))
+	}
+
+	if msg.Steps < 0 || msg.Steps > MaxVestingSteps {
+		return ErrInvalidVestingSteps(DefaultCodespace,
+			fmt.Sprintf("steps(%d) should not be less than 0 or be larger than %d", msg.Steps, MaxVestingSteps))
+	}
+	return nil
+}
+
+func (msg TimeLockVestingMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Owner returns the address the record is released to
+func (msg TimeLockVestingMsg) Owner() sdk.AccAddress {
+	if len(msg.Beneficiary) != 0 {
+		return msg.Beneficiary
+	}
+	return msg.From
+}
+
 var _ sdk.Msg = TimeRelockMsg{}
 
 type TimeRelockMsg struct {
@@ -167,6 +275,57 @@ func (msg TimeRelockMsg) GetSignBytes() []byte {
 	return b
 }
 
+var _ sdk.Msg = TimeLockTransferMsg{}
+
+// TimeLockTransferMsg transfers the record Id of From to the new owner To, who can unlock it then.
+// It's charged the same as TimeRelockMsg.
+type TimeLockTransferMsg struct {
+	From sdk.AccAddress `json:"from"`
+	Id   int64          `json:"time_lock_id"`
+	To   sdk.AccAddress `json:"to"`
+}
+
+func NewTimeLockTransferMsg(from sdk.AccAddress, id int64, to sdk.AccAddress) TimeLockTransferMsg {
+	return TimeLockTransferMsg{
+		From: from,
+		Id:   id,
+		To:   to,
+	}
+}
+
+func (msg TimeLockTransferMsg) Route() string { return MsgRoute }
+func (msg TimeLockTransferMsg) Type() string  { return TimeRelockMsg{}.Type() }
+func (msg TimeLockTransferMsg) String() string {
+	return fmt.Sprintf("TimeLockTransfer{%v#%s#%s}", msg.Id, msg.From, msg.To)
+}
+func (msg TimeLockTransferMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.From, msg.To}
+}
+func (msg TimeLockTransferMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.From} }
+
+func (msg TimeLockTransferMsg) ValidateBasic() sdk.Error {
+	if msg.Id < InitialRecordId {
+		return ErrInvalidTimeLockId(DefaultCodespace, fmt.Sprintf("time lock id should not be less than %d", InitialRecordId))
+	}
+
+	if len(msg.To) != sdk.AddrLen {
+		return sdk.ErrInvalidAddress(fmt.Sprintf("length of address should be %d", sdk.AddrLen))
+	}
+
+	if msg.To.Equals(msg.From) {
+		return ErrInvalidTransfer(DefaultCodespace, "a time lock can not be transferred to its owner")
+	}
+	return nil
+}
+
+func (msg TimeLockTransferMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
 type TimeUnlockMsg struct {
 	From sdk.AccAddress `json:"from"`
 	Id   int64          `json:"time_lock_id"`
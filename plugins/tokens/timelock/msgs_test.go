@@ -7,6 +7,8 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func TestTimeLockMsg(t *testing.T) {
@@ -103,6 +105,87 @@ func TestTimeLockMsg(t *testing.T) {
 	}
 }
 
+func TestTimeLockMsg_Beneficiary(t *testing.T) {
+	_, addrs, _, _ := mock.CreateGenAccounts(2, sdk.Coins{})
+	msg := NewTimeLockMsg(addrs[0], "Test", sdk.Coins{sdk.NewCoin("BNB", 1000)}, 1000)
+	msg.Beneficiary = addrs[1]
+
+	err := msg.ValidateBasic()
+	require.NotNil(t, err)
+	require.Equal(t, sdk.CodeMsgNotSupported, err.Code())
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.TimeLockTransferUpgrade, 1)
+	upgrade.Mgr.SetHeight(1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+		upgrade.Mgr.SetHeight(0)
+	}()
+
+	require.Nil(t, msg.ValidateBasic())
+	require.Equal(t, addrs[1], msg.Owner())
+}
+
+func TestTimeLockVestingMsg(t *testing.T) {
+	_, addrs, _, _ := mock.CreateGenAccounts(1, sdk.Coins{})
+	amount := sdk.Coins{sdk.NewCoin("BNB", 1000)}
+	tests := []struct {
+		msg       TimeLockVestingMsg
+		pass      bool
+		errorCode sdk.CodeType
+	}{
+		{
+			msg:       NewTimeLockVestingMsg(addrs[0], "", amount, 1000, 1000, 2000, 0),
+			pass:      false,
+			errorCode: CodeInvalidDescription,
+		},
+		{
+			msg:       NewTimeLockVestingMsg(addrs[0], "Test", amount, 0, 1000, 2000, 0),
+			pass:      false,
+			errorCode: CodeInvalidLockTime,
+		},
+		{
+			msg:       NewTimeLockVestingMsg(addrs[0], "Test", amount, 2000, 2000, 2000, 0),
+			pass:      false,
+			errorCode: CodeInvalidLockTime,
+		},
+		{
+			msg:       NewTimeLockVestingMsg(addrs[0], "Test", amount, 1000, 999, 2000, 0),
+			pass:      false,
+			errorCode: CodeInvalidLockTime,
+		},
+		{
+			msg:       NewTimeLockVestingMsg(addrs[0], "Test", amount, 1000, 2001, 2000, 0),
+			pass:      false,
+			errorCode: CodeInvalidLockTime,
+		},
+		{
+			msg:       NewTimeLockVestingMsg(addrs[0], "Test", amount, 1000, 1000, 2000, -1),
+			pass:      false,
+			errorCode: CodeInvalidVestingSteps,
+		},
+		{
+			msg:       NewTimeLockVestingMsg(addrs[0], "Test", amount, 1000, 1000, 2000, MaxVestingSteps+1),
+			pass:      false,
+			errorCode: CodeInvalidVestingSteps,
+		},
+		{
+			msg:       NewTimeLockVestingMsg(addrs[0], "Test", amount, 1000, 1500, 2000, 10),
+			pass:      true,
+			errorCode: sdk.CodeType(0),
+		},
+	}
+
+	for i, tc := range tests {
+		err := tc.msg.ValidateBasic()
+		if tc.pass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+			require.Equal(t, tc.errorCode, err.Code(), "test: %v", i)
+		}
+	}
+}
+
 func TestTimeRelockMsg(t *testing.T) {
 	_, addrs, _, _ := mock.CreateGenAccounts(1, sdk.Coins{})
 	tests := []struct {
@@ -262,3 +345,55 @@ func TestTimeUnlockMsg(t *testing.T) {
 		}
 	}
 }
+
+func TestTimeLockTransferMsg(t *testing.T) {
+	_, addrs, _, _ := mock.CreateGenAccounts(2, sdk.Coins{})
+	tests := []struct {
+		from      sdk.AccAddress
+		id        int64
+		to        sdk.AccAddress
+		pass      bool
+		errorCode sdk.CodeType
+	}{
+		{
+			from:      addrs[0],
+			id:        0,
+			to:        addrs[1],
+			pass:      false,
+			errorCode: CodeInvalidTimeLockId,
+		},
+		{
+			from:      addrs[0],
+			id:        1,
+			to:        sdk.AccAddress{},
+			pass:      false,
+			errorCode: sdk.CodeInvalidAddress,
+		},
+		{
+			from:      addrs[0],
+			id:        1,
+			to:        addrs[0],
+			pass:      false,
+			errorCode: CodeInvalidTransfer,
+		},
+		{
+			from:      addrs[0],
+			id:        1,
+			to:        addrs[1],
+			pass:      true,
+			errorCode: sdk.CodeType(0),
+		},
+	}
+
+	for i, tc := range tests {
+		msg := NewTimeLockTransferMsg(tc.from, tc.id, tc.to)
+
+		err := msg.ValidateBasic()
+		if tc.pass {
+			require.Nil(t, err, "test: %v", i)
+		} else {
+			require.NotNil(t, err, "test: %v", i)
+			require.Equal(t, err.Code(), tc.errorCode)
+		}
+	}
+}
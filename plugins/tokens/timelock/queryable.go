@@ -10,8 +10,9 @@ import (
 )
 
 const (
-	QueryTimeLocks = "timelocks"
-	QueryTimeLock  = "timelock"
+	QueryTimeLocks        = "timelocks"
+	QueryTimeLock         = "timelock"
+	QueryCreatedTimeLocks = "createdtimelocks"
 )
 
 func NewQuerier(keeper Keeper) sdk.Querier {
@@ -21,13 +22,15 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return queryTimeLocks(ctx, req, keeper)
 		case QueryTimeLock:
 			return queryTimeLock(ctx, req, keeper)
+		case QueryCreatedTimeLocks:
+			return queryCreatedTimeLocks(ctx, req, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown time lock query endpoint %s", path[0]))
 		}
 	}
 }
 
-// Params for query 'custom/timelock/timelocks'
+// Params for query 'custom/timelock/timelocks', the records are returned with their vested amounts at the block time
 type QueryTimeLocksParams struct {
 	Account sdk.AccAddress
 }
@@ -44,7 +47,34 @@ func queryTimeLocks(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]by
 		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("length of address should be %d", sdk.AddrLen))
 	}
 
-	timeLocks := keeper.GetTimeLockRecords(ctx, params.Account)
+	records := keeper.GetTimeLockRecords(ctx, params.Account)
+	var timeLocks []TimeLockRecordStatus
+	for _, record := range records {
+		timeLocks = append(timeLocks, NewTimeLockRecordStatus(params.Account, record, ctx.BlockHeader().Time))
+	}
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, timeLocks)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+
+	return bz, nil
+}
+
+// query 'custom/timelock/createdtimelocks' takes QueryTimeLocksParams, and returns the records locked by the account
+// for other addresses, ordered by their owners and ids
+// nolint: unparam
+func queryCreatedTimeLocks(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params QueryTimeLocksParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+
+	if len(params.Account) != sdk.AddrLen {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("length of address should be %d", sdk.AddrLen))
+	}
+
+	timeLocks := keeper.GetCreatedTimeLockRecords(ctx, params.Account)
 	bz, err := codec.MarshalJSONIndent(keeper.cdc, timeLocks)
 	if err != nil {
 		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
@@ -76,11 +106,12 @@ func queryTimeLock(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byt
 			fmt.Sprintf("time lock id(%d) should not be less than %d", params.Id, InitialRecordId))
 	}
 
-	timeLock, found := keeper.GetTimeLockRecord(ctx, params.Account, params.Id)
+	record, found := keeper.GetTimeLockRecord(ctx, params.Account, params.Id)
 	if !found {
 		return nil, ErrUnknownTimeLock(DefaultCodespace, params.Account, params.Id)
 	}
 
+	timeLock := NewTimeLockRecordStatus(params.Account, record, ctx.BlockHeader().Time)
 	bz, err := codec.MarshalJSONIndent(keeper.cdc, timeLock)
 	if err != nil {
 		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
@@ -1,16 +1,104 @@
 package timelock
 
 import (
+	"math/big"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// TimeLockRecord is kept under the address it's released to. Creator is the address which locked the coins for that
+// address, it's empty if the coins are locked by the owner of the record itself.
 type TimeLockRecord struct {
-	Id          int64     `json:"id"`
-	Description string    `json:"description"`
-	Amount      sdk.Coins `json:"amount"`
-	LockTime    time.Time `json:"lock_time"`
+	Id          int64            `json:"id"`
+	Description string           `json:"description"`
+	Amount      sdk.Coins        `json:"amount"`
+	LockTime    time.Time        `json:"lock_time"`
+	Vesting     *VestingSchedule `json:"vesting,omitempty"`
+	Creator     sdk.AccAddress   `json:"creator,omitempty"`
+}
+
+// VestingSchedule releases the amount of a time lock record gradually instead of all at the LockTime.
+// Nothing is released before CliffTime, and the whole amount is released at the LockTime. In between, the amount is
+// released linearly from StartTime, or in Steps equal steps if Steps > 0. The released amount can be claimed
+// by TimeUnlockMsg at any time, Claimed is the amount claimed so far.
+type VestingSchedule struct {
+	StartTime time.Time `json:"start_time"`
+	CliffTime time.Time `json:"cliff_time"`
+	Steps     int64     `json:"steps"`
+	Claimed   sdk.Coins `json:"claimed"`
+}
+
+// VestedAmount returns the amount released by the record at `now`, including the amount claimed
+func (record TimeLockRecord) VestedAmount(now time.Time) sdk.Coins {
+	if !now.Before(record.LockTime) {
+		return record.Amount
+	}
+	if record.Vesting == nil || now.Before(record.Vesting.CliffTime) {
+		return sdk.Coins{}
+	}
+
+	total := record.LockTime.Unix() - record.Vesting.StartTime.Unix()
+	elapsed := now.Unix() - record.Vesting.StartTime.Unix()
+	if elapsed <= 0 {
+		return sdk.Coins{}
+	}
+	if record.Vesting.Steps > 0 {
+		// the elapsed time is rounded down to the last step
+		elapsed = elapsed * record.Vesting.Steps / total * total / record.Vesting.Steps
+	}
+	vested := sdk.Coins{}
+	for _, coin := range record.Amount {
+		amount := new(big.Int).Mul(big.NewInt(coin.Amount), big.NewInt(elapsed))
+		amount.Quo(amount, big.NewInt(total))
+		if amount.Int64() > 0 {
+			vested = append(vested, sdk.NewCoin(coin.Denom, amount.Int64()))
+		}
+	}
+	return vested
+}
+
+// ClaimableAmount returns the amount released by the record at `now`, which has not been claimed
+func (record TimeLockRecord) ClaimableAmount(now time.Time) sdk.Coins {
+	return record.VestedAmount(now).Minus(record.ClaimedAmount())
+}
+
+// ClaimedAmount returns the amount claimed from a vesting record
+func (record TimeLockRecord) ClaimedAmount() sdk.Coins {
+	if record.Vesting == nil {
+		return sdk.Coins{}
+	}
+	return record.Vesting.Claimed
+}
+
+// TimeLockRecordStatus is a time lock record as returned by the queries, along with its amounts at the block time
+type TimeLockRecordStatus struct {
+	Owner       sdk.AccAddress   `json:"owner"`
+	Id          int64            `json:"id"`
+	Description string           `json:"description"`
+	Amount      sdk.Coins        `json:"amount"`
+	LockTime    time.Time        `json:"lock_time"`
+	Vesting     *VestingSchedule `json:"vesting,omitempty"`
+	Creator     sdk.AccAddress   `json:"creator,omitempty"`
+	Vested      sdk.Coins        `json:"vested"`
+	Unvested    sdk.Coins        `json:"unvested"`
+	Claimable   sdk.Coins        `json:"claimable"`
+}
+
+func NewTimeLockRecordStatus(owner sdk.AccAddress, record TimeLockRecord, now time.Time) TimeLockRecordStatus {
+	vested := record.VestedAmount(now)
+	return TimeLockRecordStatus{
+		Owner:       owner,
+		Id:          record.Id,
+		Description: record.Description,
+		Amount:      record.Amount,
+		LockTime:    record.LockTime,
+		Vesting:     record.Vesting,
+		Creator:     record.Creator,
+		Vested:      vested,
+		Unvested:    record.Amount.Minus(vested),
+		Claimable:   vested.Minus(record.ClaimedAmount()),
+	}
 }
 
 type TimeLockRecords []TimeLockRecord
@@ -1,6 +1,7 @@
 package tokens
 
 import (
+	"github.com/bnb-chain/node/plugins/tokens/allowance"
 	"github.com/bnb-chain/node/plugins/tokens/burn"
 	"github.com/bnb-chain/node/plugins/tokens/freeze"
 	"github.com/bnb-chain/node/plugins/tokens/issue"
@@ -21,6 +22,8 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(timelock.TimeLockMsg{}, "tokens/TimeLockMsg", nil)
 	cdc.RegisterConcrete(timelock.TimeUnlockMsg{}, "tokens/TimeUnlockMsg", nil)
 	cdc.RegisterConcrete(timelock.TimeRelockMsg{}, "tokens/TimeRelockMsg", nil)
+	cdc.RegisterConcrete(timelock.TimeLockVestingMsg{}, "tokens/TimeLockVestingMsg", nil)
+	cdc.RegisterConcrete(timelock.TimeLockTransferMsg{}, "tokens/TimeLockTransferMsg", nil)
 	cdc.RegisterConcrete(swap.HTLTMsg{}, "tokens/HTLTMsg", nil)
 	cdc.RegisterConcrete(swap.DepositHTLTMsg{}, "tokens/DepositHTLTMsg", nil)
 	cdc.RegisterConcrete(swap.ClaimHTLTMsg{}, "tokens/ClaimHTLTMsg", nil)
@@ -29,4 +32,7 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(issue.IssueTinyMsg{}, "tokens/IssueTinyMsg", nil)
 	cdc.RegisterConcrete(seturi.SetURIMsg{}, "tokens/SetURIMsg", nil)
 	cdc.RegisterConcrete(ownership.TransferOwnershipMsg{}, "tokens/TransferOwnershipMsg", nil)
+	cdc.RegisterConcrete(allowance.ApproveMsg{}, "tokens/ApproveMsg", nil)
+	cdc.RegisterConcrete(allowance.TransferFromMsg{}, "tokens/TransferFromMsg", nil)
+	cdc.RegisterConcrete(allowance.RevokeAllowanceMsg{}, "tokens/RevokeAllowanceMsg", nil)
 }